@@ -0,0 +1,67 @@
+package mowgli
+
+// Annotations record the decisions validation made, for callers that act on
+// them, e.g. routing documents whose "premium" condition held to a premium
+// pipeline. Paths are those of errors, "" being the document.
+type Annotations struct {
+	// Conditions holds the conditions that held for each object, by name, or
+	// by expression if they have no name, in spec order
+	Conditions map[string][]string
+	// Branches holds the discriminator values that selected the branches
+	// each object was validated against, outermost first
+	Branches map[string][]string
+	// Specs holds the effective spec each value was validated against, after
+	// $ref resolution, discriminator branches and condition overrides
+	Specs map[string]*Spec
+}
+
+// Matched reports whether the condition named name, or with expression
+// name, held for the object at path
+func (a *Annotations) Matched(path, name string) bool {
+	for _, condition := range a.Conditions[path] {
+		if condition == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithAnnotations records Annotations during validation, returned by
+// ValidationResult.Annotations
+func WithAnnotations() Option {
+	return func(v *Validator) {
+		v.annotating = true
+	}
+}
+
+// Annotations returns the decisions made during validation, or nil if the
+// Validator wasn't created WithAnnotations
+func (r *ValidationResult) Annotations() *Annotations {
+	return r.annotations
+}
+
+// annotateSpec records the effective spec of the value at path
+func (r *ValidationResult) annotateSpec(path string, spec *Spec) {
+	if r.annotations != nil && !r.propertyName {
+		r.annotations.Specs[path] = spec
+	}
+}
+
+// annotateCondition records that condition held for the object at path
+func (r *ValidationResult) annotateCondition(path string, condition Condition) {
+	if r.annotations != nil {
+		name := condition.Name
+		if name == "" {
+			name = condition.Expression()
+		}
+		r.annotations.Conditions[path] = append(r.annotations.Conditions[path], name)
+	}
+}
+
+// annotateBranch records that the object at path selected a discriminator
+// branch with value
+func (r *ValidationResult) annotateBranch(path, value string) {
+	if r.annotations != nil {
+		r.annotations.Branches[path] = append(r.annotations.Branches[path], value)
+	}
+}
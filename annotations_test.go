@@ -0,0 +1,79 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithAnnotations(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"plan": {"type": "string"},
+			"seats": {"type": "integer", "max": 10},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}}
+		},
+		"conditions": [
+			{"name": "premium", "if": "plan == 'premium'", "then": {"seats": {"max": 100}}},
+			{"if": "seats > 5", "required": ["items"]},
+			{"name": "free", "if": "plan == 'free'"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	v := NewValidator(WithAnnotations())
+	result := v.Validate(map[string]any{"plan": "premium", "seats": 50.0, "items": []any{map[string]any{"sku": "a"}}}, spec)
+	if !result.Valid {
+		t.Fatalf("unexpected errors: %v", result.Errors)
+	}
+
+	a := result.Annotations()
+	if want := []string{"premium", "seats > 5"}; !reflect.DeepEqual(a.Conditions[""], want) {
+		t.Errorf("expected conditions %v, got %v", want, a.Conditions[""])
+	}
+	if !a.Matched("", "premium") || a.Matched("", "free") || a.Matched("items[0]", "premium") {
+		t.Error("unexpected Matched results")
+	}
+	if seats := a.Specs["seats"]; seats == nil || seats.Max == nil || *seats.Max != 100 {
+		t.Errorf("expected the premium seats spec, got %+v", seats)
+	}
+	for _, path := range []string{"", "plan", "items", "items[0]", "items[0].sku"} {
+		if a.Specs[path] == nil {
+			t.Errorf("expected a spec for %q", path)
+		}
+	}
+	if len(a.Branches) != 0 {
+		t.Errorf("expected no branches, got %v", a.Branches)
+	}
+
+	if Validate(map[string]any{}, spec).Annotations() != nil {
+		t.Error("expected no annotations without WithAnnotations")
+	}
+}
+
+func TestAnnotationsDiscriminator(t *testing.T) {
+	spec := &Spec{
+		Type: "object",
+		Properties: map[string]*Spec{
+			"payment": {
+				Type:       "object",
+				Properties: map[string]*Spec{"method": {Type: "string"}},
+				Discriminator: &Discriminator{PropertyName: "method", Mapping: map[string]*Spec{
+					"card": {Type: "object", Properties: map[string]*Spec{"number": {Type: "string"}}},
+					"iban": {Type: "object", Properties: map[string]*Spec{"iban": {Type: "string"}}},
+				}},
+			},
+		},
+	}
+
+	result := NewValidator(WithAnnotations()).Validate(map[string]any{"payment": map[string]any{"method": "card", "number": "4242"}}, spec)
+	a := result.Annotations()
+	if want := map[string][]string{"payment": {"card"}}; !reflect.DeepEqual(a.Branches, want) {
+		t.Errorf("expected branches %v, got %v", want, a.Branches)
+	}
+	if payment := a.Specs["payment"]; payment == nil || payment.Properties["number"] == nil {
+		t.Errorf("expected the card branch spec, got %+v", payment)
+	}
+}
@@ -0,0 +1,126 @@
+package mowgli
+
+import "strings"
+
+// validateAsserts checks that the spec's asserts hold for obj. A failed
+// assert is reported at its path, or at the object if it has none.
+func (r *ValidationResult) validateAsserts(path string, obj map[string]any, spec *Spec) {
+	if len(spec.Asserts) == 0 {
+		return
+	}
+
+	env := r.conditionEnv(obj)
+	for _, assert := range spec.Asserts {
+		if assert.Select != "" || assert.Spec != nil {
+			r.validateSelected(path, obj, env, assert)
+			continue
+		}
+		at := buildPath(path, assert.Path)
+		result, err := r.evalObjectExpr(at, TraceAssert, assert.Expr, env)
+		if err != nil {
+			r.logCondition(at, assert.Expr, err)
+			r.conditionFailed(at, "assert", assert.Expr, err)
+			continue
+		}
+		if !result {
+			r.assertFailed(at, assert)
+		}
+	}
+}
+
+// validateSelected checks an assert against each value its JSONPath selects
+// from obj, or obj itself without one. A value failing the assert's spec is
+// reported with the spec's errors, unless the assert has a message, and
+// failed asserts at the value unless the assert has a path.
+func (r *ValidationResult) validateSelected(path string, obj map[string]any, env map[string]any, assert Assert) {
+	selector := assert.Select
+	if selector == "" {
+		selector = "$"
+	}
+	steps, err := parseJSONPath(selector)
+	if err != nil {
+		r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"select": assert.Select})
+		return
+	}
+
+	// The selected value is "@", evaluated along with the object's fields
+	eval := func(expression string, value any) (bool, error) {
+		scoped := make(map[string]any, len(env)+1)
+		for k, v := range env {
+			scoped[k] = v
+		}
+		if r.useNumber {
+			value = exprValue(value)
+		}
+		scoped[currentIdentifier] = value
+		return r.evalCondition(translateCurrent(expression), scoped)
+	}
+	selected, err := selectJSONPath(path, obj, steps, eval)
+	if err != nil {
+		r.logCondition(path, selector, err)
+		r.conditionFailed(path, "assert", selector, err)
+		return
+	}
+
+	for _, match := range selected {
+		at := match.path
+		if assert.Path != "" {
+			at = buildPath(path, assert.Path)
+		}
+		if assert.Spec != nil {
+			if assert.Message == "" {
+				before := len(r.Errors)
+				r.validate(match.path, match.value, assert.Spec)
+				if len(r.Errors) > before {
+					continue
+				}
+			} else if !r.matches(match.path, match.value, assert.Spec) {
+				r.assertFailed(at, assert)
+				continue
+			}
+		}
+		if assert.Expr == "" {
+			continue
+		}
+		result, err := eval(assert.Expr, match.value)
+		r.addTrace(at, TraceAssert, assert.Expr, traceResult(result, err))
+		if err != nil {
+			r.logCondition(at, assert.Expr, err)
+			r.conditionFailed(at, "assert", assert.Expr, err)
+			continue
+		}
+		if !result {
+			r.assertFailed(at, assert)
+		}
+	}
+}
+
+// assertFailed reports a failed assert at path
+func (r *ValidationResult) assertFailed(path string, assert Assert) {
+	expression := assert.Expr
+	if expression == "" {
+		expression = assert.Select
+	}
+	message := assert.Message
+	if message == "" {
+		message = "assertion failed: " + expression
+	}
+	params := map[string]any{"assert": expression}
+	if assert.Select != "" {
+		params["select"] = assert.Select
+	}
+	r.addError(path, CodeAssert, message, params)
+}
+
+// assertExprs returns the expressions of asserts, preceded by their
+// JSONPath if they have one
+func assertExprs(asserts []Assert) []string {
+	exprs := make([]string, len(asserts))
+	for i, assert := range asserts {
+		exprs[i] = assert.Expr
+		if assert.Select != "" {
+			exprs[i] = strings.TrimSpace(assert.Select + " " + assert.Expr)
+		}
+	}
+	return exprs
+}
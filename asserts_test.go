@@ -0,0 +1,169 @@
+package mowgli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsserts(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"price": {"type": "number"},
+			"discount": {"type": "number"},
+			"startDate": {"type": "string"},
+			"endDate": {"type": "string"}
+		},
+		"required": ["price", "startDate", "endDate"],
+		"asserts": [
+			{"expr": "discount == null OR discount <= price", "message": "discount can't exceed the price", "path": "discount"},
+			{"expr": "endDate > startDate"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   map[string]any
+		errors []string // path: message
+	}{
+		{"valid", map[string]any{"price": 10.0, "discount": 5.0, "startDate": "2026-01-01", "endDate": "2026-02-01"}, nil},
+		{"no discount", map[string]any{"price": 10.0, "startDate": "2026-01-01", "endDate": "2026-02-01"}, nil},
+		{"discount too high", map[string]any{"price": 10.0, "discount": 15.0, "startDate": "2026-01-01", "endDate": "2026-02-01"},
+			[]string{"discount: discount can't exceed the price"}},
+		{"dates reversed", map[string]any{"price": 10.0, "startDate": "2026-02-01", "endDate": "2026-01-01"},
+			[]string{": assertion failed: endDate > startDate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var got []string
+			for _, e := range result.Errors {
+				if e.Code != CodeAssert {
+					t.Errorf("unexpected error: %v", e)
+				}
+				got = append(got, e.Path+": "+e.Message)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.errors, "; ") {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestAssertsNested(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"conditionDefs": {"inStock": "quantity <= $root.stock"},
+		"properties": {
+			"stock": {"type": "integer"},
+			"lines": {"type": "array", "items": {
+				"type": "object",
+				"properties": {"quantity": {"type": "integer"}},
+				"asserts": [{"expr": "inStock", "path": "quantity"}]
+			}}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"stock": 5.0, "lines": []any{
+		map[string]any{"quantity": 2.0},
+		map[string]any{"quantity": 7.0},
+	}}, spec)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "lines[1].quantity" || !errors.Is(result.Errors[0], ErrAssert) {
+		t.Errorf("expected one assert error at lines[1].quantity, got %v", result.Errors)
+	}
+}
+
+func TestAssertsInvalidExpression(t *testing.T) {
+	spec := &Spec{Type: "object", Asserts: []Assert{{Expr: "a >"}}}
+	if _, err := Compile(spec); err == nil {
+		t.Error("expected Compile to reject the expression")
+	}
+
+	result := Validate(map[string]any{"a": 1.0}, &Spec{Type: "object", Asserts: []Assert{{Expr: "a > b"}}})
+	if result.Valid || result.Errors[0].Code != CodeCondition {
+		t.Errorf("expected a condition error, got %v", result.Errors)
+	}
+}
+
+func TestAssertsSelect(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"orders": {"type": "array"}, "minTotal": {"type": "number"}},
+		"asserts": [
+			{"select": "$.orders[?(@.status == 'paid')].total", "expr": "@ > 0"},
+			{"select": "$.orders[*].total", "expr": "minTotal == null OR @ >= minTotal", "message": "order below the minimum", "path": "minTotal"},
+			{"select": "$..sku", "spec": {"type": "string", "pattern": "^[A-Z]+-[0-9]+$"}},
+			{"select": "$.orders[-1]", "spec": {"type": "object", "required": ["total"]}, "message": "the last order needs a total"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string // path: code
+	}{
+		{"valid", `{"orders": [{"status": "paid", "total": 5, "sku": "AB-1"}, {"status": "open", "total": 0}]}`, nil},
+		{"no orders", `{}`, nil},
+		{"paid order without total", `{"orders": [{"status": "open", "total": 0}, {"status": "paid", "total": 0}]}`,
+			[]string{"orders[1].total: assert"}},
+		{"below minimum", `{"minTotal": 10, "orders": [{"status": "open", "total": 12}, {"status": "open", "total": 3}]}`,
+			[]string{"minTotal: assert"}},
+		{"nested skus", `{"orders": [{"status": "open", "total": 1, "lines": [{"sku": "AB-1"}, {"sku": "ab"}]}]}`,
+			[]string{"orders[0].lines[1].sku: pattern"}},
+		{"last order", `{"orders": [{"status": "open", "total": 1}, {"status": "open"}]}`,
+			[]string{"orders[1]: assert"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateJSON([]byte(tt.data), spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Path+": "+e.Code)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.errors, "; ") {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestAssertsSelectInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		assert Assert
+	}{
+		{"no expr or spec", Assert{Select: "$.items[*]"}},
+		{"bad JSONPath", Assert{Select: "items[*]", Expr: "@ > 0"}},
+		{"unclosed bracket", Assert{Select: "$.items[*", Expr: "@ > 0"}},
+		{"bad filter", Assert{Select: "$.items[?(@.a >)]", Expr: "@ > 0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(&Spec{Type: "object", Asserts: []Assert{tt.assert}}); err == nil {
+				t.Error("expected Compile to reject the assert")
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package mowgli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AsyncCheck is a validation that needs an external service, such as a
+// uniqueness lookup, an MX record check or a webhook reachability probe.
+// Specs refer to registered checks by name with the "checks" keyword.
+type AsyncCheck struct {
+	// Check validates a value. It returns nil if the value is valid, an error
+	// describing why it is invalid, or an error wrapped with Temporary if the
+	// service could not answer and the check should be retried.
+	Check func(ctx context.Context, value any) error
+
+	// Host returns the external host contacted for a value, used for per-host
+	// rate limiting. If nil, all calls of the check share one rate limit.
+	Host func(value any) string
+}
+
+// errTemporary marks errors returned by Temporary
+var errTemporary = errors.New("temporary failure")
+
+// Temporary marks an async check error as transient, so the scheduler retries it
+func Temporary(err error) error {
+	return fmt.Errorf("%w: %w", errTemporary, err)
+}
+
+// IsTemporary reports whether err was marked with Temporary
+func IsTemporary(err error) bool {
+	return errors.Is(err, errTemporary)
+}
+
+// RegisterAsyncCheck makes check available to specs under name
+func (v *Validator) RegisterAsyncCheck(name string, check AsyncCheck) error {
+	if name == "" {
+		return fmt.Errorf("async check name must not be empty")
+	}
+	if check.Check == nil {
+		return fmt.Errorf("async check %s has no Check function", name)
+	}
+
+	// Copy on write so validations in flight keep a consistent set of checks
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	checks := make(map[string]AsyncCheck, len(v.asyncChecks)+1)
+	for k, c := range v.asyncChecks {
+		checks[k] = c
+	}
+	checks[name] = check
+	v.asyncChecks = checks
+	return nil
+}
+
+// ValidateAsync validates data like Validate and then runs the spec's async
+// checks through sched. Checks only run for values that passed synchronous
+// validation. Pass the same Scheduler for every document in a batch to share
+// its rate limits and memoized results; a nil sched uses a default one for
+// this call only.
+func (v *Validator) ValidateAsync(ctx context.Context, data any, spec *Spec, sched *Scheduler) *ValidationResult {
+	if sched == nil {
+		sched = NewScheduler(SchedulerConfig{})
+	}
+
+	start := time.Now()
+	result := v.newResult(data)
+	result.pendingChecks = []pendingCheck{}
+	result.run(spec)
+
+	errs := make([]error, len(result.pendingChecks))
+	var wg sync.WaitGroup
+	for i, pending := range result.pendingChecks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = sched.run(ctx, pending)
+		}()
+	}
+	wg.Wait()
+
+	// Report errors in document order regardless of completion order
+	for i, pending := range result.pendingChecks {
+		if errs[i] == nil {
+			continue
+		}
+		message := fmt.Sprintf("check %s failed: %v", pending.name, errs[i])
+		if IsTemporary(errs[i]) || ctx.Err() != nil {
+			message = fmt.Sprintf("check %s could not be completed: %v", pending.name, errs[i])
+		}
+		result.addError(pending.path, CodeCheck, message, map[string]any{"check": pending.name})
+	}
+	result.pendingChecks = nil
+	result.observe(specName(spec), start)
+
+	return result
+}
+
+// pendingCheck is an async check queued during synchronous validation
+type pendingCheck struct {
+	path  string
+	name  string
+	value any
+	check AsyncCheck
+}
+
+// queueChecks records the named async checks to run against value at path
+func (r *ValidationResult) queueChecks(path string, value any, names []string) {
+	for _, name := range names {
+		check, ok := r.asyncChecks[name]
+		if !ok {
+			r.addError(path, CodeInvalidSpec, fmt.Sprintf("unknown async check: %s", name), nil)
+			continue
+		}
+		r.pendingChecks = append(r.pendingChecks, pendingCheck{path: path, name: name, value: value, check: check})
+	}
+}
+
+// SchedulerConfig configures a Scheduler. Zero values select the defaults.
+type SchedulerConfig struct {
+	MaxConcurrency int           // Maximum checks running at once (default 4)
+	PerHostRate    float64       // Maximum calls per second to a single host (default unlimited)
+	MaxRetries     int           // Retries of a check returning a Temporary error (default 0)
+	RetryBackoff   time.Duration // Delay before the first retry, doubled for each further retry (default 100ms)
+}
+
+// Scheduler runs async checks with a concurrency cap, per-host rate limits
+// and retries, and memoizes results so that each distinct value is checked
+// once. A Scheduler is meant to live for one validation batch; it is safe for
+// concurrent use.
+type Scheduler struct {
+	config SchedulerConfig
+	slots  chan struct{}
+
+	mu    sync.Mutex
+	hosts map[string]*hostLimiter
+	memo  map[string]*memoEntry
+}
+
+// memoEntry holds the outcome of a check, shared by concurrent callers
+type memoEntry struct {
+	done chan struct{}
+	err  error
+	// abandoned is set if the check failed because the context of the
+	// caller running it ended, in which case the outcome isn't shared
+	abandoned bool
+}
+
+// NewScheduler creates a Scheduler
+func NewScheduler(config SchedulerConfig) *Scheduler {
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = 4
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 100 * time.Millisecond
+	}
+	return &Scheduler{
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrency),
+		hosts:  make(map[string]*hostLimiter),
+		memo:   make(map[string]*memoEntry),
+	}
+}
+
+// run executes a check, or waits for an identical check already in progress
+func (s *Scheduler) run(ctx context.Context, pending pendingCheck) error {
+	key, err := memoKey(pending)
+	if err != nil {
+		return s.execute(ctx, pending)
+	}
+
+	for {
+		s.mu.Lock()
+		entry, ok := s.memo[key]
+		if !ok {
+			entry = &memoEntry{done: make(chan struct{})}
+			s.memo[key] = entry
+		}
+		s.mu.Unlock()
+
+		if !ok {
+			entry.err = s.execute(ctx, pending)
+			if entry.err != nil && ctx.Err() != nil {
+				// The failure says nothing about the value, so callers
+				// with a live context check it again
+				entry.abandoned = true
+				s.mu.Lock()
+				delete(s.memo, key)
+				s.mu.Unlock()
+			}
+			close(entry.done)
+			return entry.err
+		}
+
+		select {
+		case <-entry.done:
+			if !entry.abandoned {
+				return entry.err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// execute runs a check with retries, honoring the concurrency cap and host rate
+func (s *Scheduler) execute(ctx context.Context, pending pendingCheck) error {
+	host := pending.name
+	if pending.check.Host != nil {
+		host = pending.check.Host(pending.value)
+	}
+	limiter := s.limiter(host)
+
+	backoff := s.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		err := pending.check.Check(ctx, pending.value)
+		<-s.slots
+
+		if err == nil || !IsTemporary(err) || attempt >= s.config.MaxRetries {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+func (s *Scheduler) limiter(host string) *hostLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limiter, ok := s.hosts[host]
+	if !ok {
+		limiter = &hostLimiter{}
+		if s.config.PerHostRate > 0 {
+			limiter.interval = time.Duration(float64(time.Second) / s.config.PerHostRate)
+		}
+		s.hosts[host] = limiter
+	}
+	return limiter
+}
+
+// memoKey identifies a check of a specific value
+func memoKey(pending pendingCheck) (string, error) {
+	value, err := json.Marshal(pending.value)
+	if err != nil {
+		return "", err
+	}
+	return pending.name + "\x00" + string(value), nil
+}
+
+// hostLimiter spaces calls to one host at least interval apart
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the caller may contact the host
+func (l *hostLimiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,278 @@
+package mowgli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidateAsync(t *testing.T) {
+	var calls atomic.Int32
+	v := NewValidator()
+	if err := v.RegisterAsyncCheck("uniqueEmail", AsyncCheck{
+		Check: func(ctx context.Context, value any) error {
+			calls.Add(1)
+			if value == "taken@example.com" {
+				return errors.New("email is already registered")
+			}
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string", "minLength": 3, "checks": ["uniqueEmail"]},
+			"aliases": {"type": "array", "items": {"type": "string", "checks": ["uniqueEmail"]}},
+			"other": {"type": "string", "checks": ["missing"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]any
+		wantCalls int32
+		wantCodes map[string]string
+	}{
+		{
+			name:      "available email",
+			data:      map[string]any{"email": "new@example.com"},
+			wantCalls: 1,
+		},
+		{
+			name:      "taken email",
+			data:      map[string]any{"email": "taken@example.com"},
+			wantCalls: 1,
+			wantCodes: map[string]string{"email": CodeCheck},
+		},
+		{
+			name:      "check skipped when synchronous validation fails",
+			data:      map[string]any{"email": "a"},
+			wantCalls: 0,
+			wantCodes: map[string]string{"email": CodeMinLength},
+		},
+		{
+			name:      "duplicate values are checked once",
+			data:      map[string]any{"email": "taken@example.com", "aliases": []any{"taken@example.com", "x@example.com", "x@example.com"}},
+			wantCalls: 2,
+			wantCodes: map[string]string{"email": CodeCheck, "aliases[0]": CodeCheck},
+		},
+		{
+			name:      "unknown check",
+			data:      map[string]any{"other": "x"},
+			wantCalls: 0,
+			wantCodes: map[string]string{"other": CodeInvalidSpec},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls.Store(0)
+			result := v.ValidateAsync(context.Background(), tt.data, spec, nil)
+
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d check calls, got %d", tt.wantCalls, got)
+			}
+			if len(result.Errors) != len(tt.wantCodes) {
+				t.Fatalf("expected %d errors, got %v", len(tt.wantCodes), result.Errors)
+			}
+			for _, err := range result.Errors {
+				if tt.wantCodes[err.Path] != err.Code {
+					t.Errorf("unexpected error %s (code %s)", err, err.Code)
+				}
+			}
+			if result.Valid != (len(tt.wantCodes) == 0) {
+				t.Errorf("expected valid=%v", len(tt.wantCodes) == 0)
+			}
+		})
+	}
+}
+
+func TestSchedulerConcurrencyCap(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	v := NewValidator()
+	if err := v.RegisterAsyncCheck("slow", AsyncCheck{
+		Check: func(ctx context.Context, value any) error {
+			n := running.Add(1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+			return nil
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec, err := ParseSpecString(`{"type": "array", "items": {"type": "integer", "checks": ["slow"]}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	data := make([]any, 10)
+	for i := range data {
+		data[i] = i
+	}
+	result := v.ValidateAsync(context.Background(), data, spec, NewScheduler(SchedulerConfig{MaxConcurrency: 2}))
+	if !result.Valid {
+		t.Fatalf("expected validation to pass, got %v", result.Errors)
+	}
+	if got := maxRunning.Load(); got > 2 {
+		t.Errorf("expected at most 2 concurrent checks, got %d", got)
+	}
+}
+
+func TestSchedulerPerHostRate(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string][]time.Time)
+	v := NewValidator()
+	if err := v.RegisterAsyncCheck("mx", AsyncCheck{
+		Check: func(ctx context.Context, value any) error {
+			mu.Lock()
+			defer mu.Unlock()
+			host := value.(string)[strings.Index(value.(string), "@")+1:]
+			calls[host] = append(calls[host], time.Now())
+			return nil
+		},
+		Host: func(value any) string {
+			return value.(string)[strings.Index(value.(string), "@")+1:]
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec, err := ParseSpecString(`{"type": "array", "items": {"type": "string", "checks": ["mx"]}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	data := []any{"a@one.test", "b@one.test", "c@one.test", "a@two.test"}
+	sched := NewScheduler(SchedulerConfig{MaxConcurrency: 10, PerHostRate: 50})
+	if result := v.ValidateAsync(context.Background(), data, spec, sched); !result.Valid {
+		t.Fatalf("expected validation to pass, got %v", result.Errors)
+	}
+
+	if len(calls["one.test"]) != 3 || len(calls["two.test"]) != 1 {
+		t.Fatalf("unexpected calls per host: %v", calls)
+	}
+	first, last := calls["one.test"][0], calls["one.test"][0]
+	for _, at := range calls["one.test"] {
+		if at.Before(first) {
+			first = at
+		}
+		if at.After(last) {
+			last = at
+		}
+	}
+	// Three calls at 50/s need at least two 20ms intervals
+	if elapsed := last.Sub(first); elapsed < 35*time.Millisecond {
+		t.Errorf("expected calls to one host to be spaced out, took %s", elapsed)
+	}
+}
+
+func TestSchedulerRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxRetries int
+		wantValid  bool
+		wantCalls  int32
+	}{
+		{name: "succeeds after retries", maxRetries: 2, wantValid: true, wantCalls: 3},
+		{name: "gives up after max retries", maxRetries: 1, wantValid: false, wantCalls: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			v := NewValidator()
+			if err := v.RegisterAsyncCheck("flaky", AsyncCheck{
+				Check: func(ctx context.Context, value any) error {
+					if calls.Add(1) <= 2 {
+						return Temporary(fmt.Errorf("connection reset"))
+					}
+					return nil
+				},
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			spec, err := ParseSpecString(`{"type": "string", "checks": ["flaky"]}`)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+
+			sched := NewScheduler(SchedulerConfig{MaxRetries: tt.maxRetries, RetryBackoff: time.Millisecond})
+			result := v.ValidateAsync(context.Background(), "value", spec, sched)
+			if result.Valid != tt.wantValid {
+				t.Errorf("expected valid=%v, got %v", tt.wantValid, result.Errors)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+			if !tt.wantValid && !strings.Contains(result.Errors[0].Message, "could not be completed") {
+				t.Errorf("expected unavailable message, got %q", result.Errors[0].Message)
+			}
+		})
+	}
+}
+
+func TestRegisterAsyncCheckErrors(t *testing.T) {
+	v := NewValidator()
+	if err := v.RegisterAsyncCheck("", AsyncCheck{Check: func(context.Context, any) error { return nil }}); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := v.RegisterAsyncCheck("x", AsyncCheck{}); err == nil {
+		t.Error("expected error for missing Check")
+	}
+}
+
+func TestSchedulerCancelledCallerNotMemoized(t *testing.T) {
+	var calls atomic.Int32
+	v := NewValidator()
+	if err := v.RegisterAsyncCheck("slow", AsyncCheck{
+		Check: func(ctx context.Context, value any) error {
+			calls.Add(1)
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec, err := ParseSpecString(`{"type": "string", "checks": ["slow"]}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	sched := NewScheduler(SchedulerConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if result := v.ValidateAsync(ctx, "value", spec, sched); result.Valid {
+		t.Fatal("expected the timed out check to fail")
+	}
+
+	if result := v.ValidateAsync(context.Background(), "value", spec, sched); !result.Valid {
+		t.Errorf("expected the check to pass with a live context, got %v", result.Errors)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected the check to run again, got %d calls", got)
+	}
+}
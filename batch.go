@@ -0,0 +1,172 @@
+package mowgli
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"sync"
+)
+
+// BatchOption configures ValidateBatch
+type BatchOption func(*batchConfig)
+
+type batchConfig struct {
+	parallelism int
+	top         int
+}
+
+// WithParallelism validates up to n documents at once (default 1)
+func WithParallelism(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.parallelism = n
+	}
+}
+
+// WithTop lists the n most frequent error codes and paths in the summary
+// (default 10)
+func WithTop(n int) BatchOption {
+	return func(c *batchConfig) {
+		c.top = n
+	}
+}
+
+// BatchResult is the outcome of validating a batch of documents
+type BatchResult struct {
+	Results []*ValidationResult `json:"-"` // One per document, in order
+	Summary BatchSummary        `json:"summary"`
+}
+
+// BatchSummary aggregates the results of a batch
+type BatchSummary struct {
+	Documents int     `json:"documents"`
+	Valid     int     `json:"valid"`
+	Invalid   int     `json:"invalid"`
+	Errors    int     `json:"errors"`   // Errors across all documents
+	PassRate  float64 `json:"passRate"` // Percentage of valid documents, from 0 to 100
+	// TopCodes are the most frequent error codes, most frequent first
+	TopCodes []BatchCount `json:"topCodes"`
+	// WorstPaths are the paths with the most errors, most first, with "[]"
+	// for array items, e.g. "items[].price"
+	WorstPaths []BatchCount `json:"worstPaths"`
+}
+
+// BatchCount counts the errors with an error code or at a path
+type BatchCount struct {
+	Key       string `json:"key"`
+	Errors    int    `json:"errors"`
+	Documents int    `json:"documents"` // Documents with at least one of the errors
+}
+
+// Invalid returns the indexes of the invalid documents
+func (b *BatchResult) Invalid() []int {
+	var invalid []int
+	for i, result := range b.Results {
+		if !result.Valid {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid
+}
+
+// ValidateBatch validates each of docs against spec. See
+// Validator.ValidateBatch.
+func ValidateBatch(docs []any, spec *Spec, opts ...BatchOption) *BatchResult {
+	return defaultValidator.ValidateBatch(docs, spec, opts...)
+}
+
+// ValidateBatch validates each of docs against spec, such as the records
+// of an export, and summarizes the results: the pass rate and the error
+// codes and paths with the most errors. WithParallelism spreads the
+// documents over several goroutines; results stay in document order.
+func (v *Validator) ValidateBatch(docs []any, spec *Spec, opts ...BatchOption) *BatchResult {
+	config := batchConfig{parallelism: 1, top: 10}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	results := make([]*ValidationResult, len(docs))
+	workers := min(max(config.parallelism, 1), len(docs))
+	if workers <= 1 {
+		for i, doc := range docs {
+			results[i] = v.Validate(doc, spec)
+		}
+	} else {
+		indexes := make(chan int)
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := range indexes {
+					results[i] = v.Validate(docs[i], spec)
+				}
+			}()
+		}
+		for i := range docs {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+	}
+
+	return &BatchResult{Results: results, Summary: summarizeBatch(results, config.top)}
+}
+
+// arrayIndexPattern matches the array indexes in error paths
+var arrayIndexPattern = regexp.MustCompile(`\[\d+\]`)
+
+// summarizeBatch aggregates results, listing the top codes and paths
+func summarizeBatch(results []*ValidationResult, top int) BatchSummary {
+	summary := BatchSummary{Documents: len(results), PassRate: 100}
+	codes := make(map[string]*BatchCount)
+	paths := make(map[string]*BatchCount)
+	count := func(counts map[string]*BatchCount, key string, seen map[string]bool) {
+		c, ok := counts[key]
+		if !ok {
+			c = &BatchCount{Key: key}
+			counts[key] = c
+		}
+		c.Errors++
+		if !seen[key] {
+			seen[key] = true
+			c.Documents++
+		}
+	}
+
+	for _, result := range results {
+		if result.Valid {
+			summary.Valid++
+		} else {
+			summary.Invalid++
+		}
+		summary.Errors += len(result.Errors)
+		seenCodes := make(map[string]bool)
+		seenPaths := make(map[string]bool)
+		for _, err := range result.Errors {
+			count(codes, err.Code, seenCodes)
+			count(paths, arrayIndexPattern.ReplaceAllString(err.Path, "[]"), seenPaths)
+		}
+	}
+	if summary.Documents > 0 {
+		summary.PassRate = 100 * float64(summary.Valid) / float64(summary.Documents)
+	}
+	summary.TopCodes = topCounts(codes, top)
+	summary.WorstPaths = topCounts(paths, top)
+	return summary
+}
+
+// topCounts returns the n counts with the most errors, most first and then
+// by key
+func topCounts(counts map[string]*BatchCount, n int) []BatchCount {
+	sorted := make([]BatchCount, 0, len(counts))
+	for _, c := range counts {
+		sorted = append(sorted, *c)
+	}
+	slices.SortFunc(sorted, func(a, b BatchCount) int {
+		return cmp.Or(b.Errors-a.Errors, cmp.Compare(a.Key, b.Key))
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
@@ -0,0 +1,85 @@
+package mowgli
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestValidateBatch(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["sku"],
+		"properties": {
+			"sku": {"type": "string"},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"price": {"type": "number", "min": 0}}}}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	docs := []any{
+		map[string]any{"sku": "a"},
+		map[string]any{"items": []any{map[string]any{"price": -1.0}, map[string]any{"price": -2.0}}},
+		map[string]any{"sku": "c", "items": []any{map[string]any{"price": -3.0}}},
+		map[string]any{"sku": "d"},
+	}
+
+	for _, parallelism := range []int{1, 3, 10} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			batch := ValidateBatch(docs, spec, WithParallelism(parallelism))
+			if len(batch.Results) != len(docs) {
+				t.Fatalf("expected %d results, got %d", len(docs), len(batch.Results))
+			}
+			if !slices.Equal(batch.Invalid(), []int{1, 2}) {
+				t.Errorf("expected documents 1 and 2 invalid, got %v", batch.Invalid())
+			}
+
+			summary := batch.Summary
+			if summary.Documents != 4 || summary.Valid != 2 || summary.Invalid != 2 || summary.Errors != 4 {
+				t.Errorf("unexpected counts: %+v", summary)
+			}
+			if summary.PassRate != 50 {
+				t.Errorf("expected pass rate 50, got %v", summary.PassRate)
+			}
+			expectedCodes := []BatchCount{{Key: CodeMin, Errors: 3, Documents: 2}, {Key: CodeRequired, Errors: 1, Documents: 1}}
+			if !slices.Equal(summary.TopCodes, expectedCodes) {
+				t.Errorf("expected top codes %v, got %v", expectedCodes, summary.TopCodes)
+			}
+			expectedPaths := []BatchCount{{Key: "items[].price", Errors: 3, Documents: 2}, {Key: "sku", Errors: 1, Documents: 1}}
+			if !slices.Equal(summary.WorstPaths, expectedPaths) {
+				t.Errorf("expected worst paths %v, got %v", expectedPaths, summary.WorstPaths)
+			}
+		})
+	}
+}
+
+func TestValidateBatchSummary(t *testing.T) {
+	spec := &Spec{Type: "integer", Min: float64Ref(0)}
+
+	tests := []struct {
+		name     string
+		docs     []any
+		opts     []BatchOption
+		passRate float64
+		codes    int
+	}{
+		{"empty batch", nil, nil, 100, 0},
+		{"all valid", []any{1.0, 2.0}, nil, 100, 0},
+		{"all codes", []any{"a", -1.0}, nil, 0, 2},
+		{"top limited", []any{"a", -1.0}, []BatchOption{WithTop(1)}, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary := ValidateBatch(tt.docs, spec, tt.opts...).Summary
+			if summary.PassRate != tt.passRate {
+				t.Errorf("expected pass rate %v, got %v", tt.passRate, summary.PassRate)
+			}
+			if len(summary.TopCodes) != tt.codes {
+				t.Errorf("expected %d top codes, got %v", tt.codes, summary.TopCodes)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package mowgli
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// binaryEncMode encodes compiled specs deterministically, so that the same
+// spec always produces the same artifact, e.g. for content-addressed caches
+var binaryEncMode = func() cbor.EncMode {
+	mode, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// binaryDecMode decodes JSON values within specs, such as enum values, to
+// the types encoding/json produces
+var binaryDecMode = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{
+		DefaultMapType: reflect.TypeFor[map[string]any](),
+		IntDec:         cbor.IntDecConvertSigned,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// ExportBinary serializes the compiled spec like Export, but in CBOR, which
+// is smaller and faster to load than JSON, e.g. for specs embedded in
+// binaries or kept in a cache. Load it with ImportCompiledBinary.
+func (c *CompiledSpec) ExportBinary() ([]byte, error) {
+	artifact, err := c.artifact()
+	if err != nil {
+		return nil, err
+	}
+	data, err := binaryEncMode.Marshal(artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode compiled spec: %w", err)
+	}
+	return data, nil
+}
+
+// ImportCompiledBinary loads a compiled spec produced by ExportBinary. Like
+// ImportCompiled, it checks the spec's digest rather than compiling it again.
+func ImportCompiledBinary(data []byte) (*CompiledSpec, error) {
+	var artifact compiledArtifact
+	if err := binaryDecMode.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("invalid compiled spec: %w", err)
+	}
+	return importArtifact(artifact)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler with ExportBinary, so
+// compiled specs can be stored with encoding/gob and in caches that use the
+// interface
+func (c *CompiledSpec) MarshalBinary() ([]byte, error) {
+	return c.ExportBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler with
+// ImportCompiledBinary
+func (c *CompiledSpec) UnmarshalBinary(data []byte) error {
+	imported, err := ImportCompiledBinary(data)
+	if err != nil {
+		return err
+	}
+	*c = *imported
+	return nil
+}
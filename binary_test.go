@@ -0,0 +1,146 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompiledSpecExportBinary(t *testing.T) {
+	spec, err := LoadSpec("advanced_conditional.json")
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("failed to compile spec: %v", err)
+	}
+
+	exported, err := compiled.ExportBinary()
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	if again, _ := compiled.ExportBinary(); !bytes.Equal(again, exported) {
+		t.Error("expected exports to be deterministic")
+	}
+	if asJSON, _ := compiled.Export(); len(exported) >= len(asJSON) {
+		t.Errorf("expected the binary export (%d bytes) to be smaller than JSON (%d bytes)", len(exported), len(asJSON))
+	}
+
+	imported, err := ImportCompiledBinary(exported)
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if !SpecEqual(imported.spec, compiled.spec) {
+		t.Error("expected the imported spec to equal the compiled spec")
+	}
+	if !reflect.DeepEqual(imported.patterns, compiled.patterns) {
+		t.Errorf("expected patterns %v, got %v", compiled.patterns, imported.patterns)
+	}
+	if !reflect.DeepEqual(imported.expressions, compiled.expressions) {
+		t.Errorf("expected expressions %v, got %v", compiled.expressions, imported.expressions)
+	}
+
+	testCases, err := LoadTestCases("advanced_conditional.json")
+	if err != nil {
+		t.Fatalf("failed to load test cases: %v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if result := imported.Validate(tc.Data); result.Valid != tc.ExpectedValid {
+				t.Errorf("expected valid=%v, got %v: %v", tc.ExpectedValid, result.Valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestCompiledSpecBinaryKeywords(t *testing.T) {
+	// Zero values and JSON values must survive, as they change validation
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"count": {"type": "integer", "min": 0, "maxInt": "18446744073709551615"},
+			"mode": {"enum": [1, "a", true, null, {"k": [2.5]}]},
+			"note": {"type": "string", "allowEmpty": false, "examples": [""]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("failed to compile spec: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(compiled); err != nil {
+		t.Fatalf("failed to gob-encode: %v", err)
+	}
+	var decoded CompiledSpec
+	if err := gob.NewDecoder(&buf).Decode(&decoded); err != nil {
+		t.Fatalf("failed to gob-decode: %v", err)
+	}
+	if !reflect.DeepEqual(decoded.spec, spec) {
+		t.Errorf("expected %+v, got %+v", spec, decoded.spec)
+	}
+	if result := decoded.Validate(map[string]any{"count": -1.0}); result.Valid {
+		t.Error("expected min 0 to be kept")
+	}
+}
+
+func TestImportCompiledBinaryErrors(t *testing.T) {
+	compiled, err := Compile(&Spec{Type: "string"})
+	if err != nil {
+		t.Fatalf("failed to compile spec: %v", err)
+	}
+	artifact, err := compiled.artifact()
+	if err != nil {
+		t.Fatalf("failed to build artifact: %v", err)
+	}
+	artifact.Spec = &Spec{Type: "string", MinLength: intPtr(1)}
+	changed, err := binaryEncMode.Marshal(artifact)
+	if err != nil {
+		t.Fatalf("failed to encode artifact: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{name: "not CBOR", data: []byte("{"), wantErr: "invalid compiled spec"},
+		{name: "wrong version", data: []byte{0xa1, 0x67, 'v', 'e', 'r', 's', 'i', 'o', 'n', 0x09}, wantErr: "unsupported compiled spec version 9"},
+		{name: "spec changed", data: changed, wantErr: "digest mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportCompiledBinary(tt.data)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func BenchmarkImportCompiledBinary(b *testing.B) {
+	spec, err := LoadSpec("advanced_conditional.json")
+	if err != nil {
+		b.Fatalf("failed to load spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		b.Fatalf("failed to compile spec: %v", err)
+	}
+	exported, err := compiled.ExportBinary()
+	if err != nil {
+		b.Fatalf("failed to export: %v", err)
+	}
+
+	for b.Loop() {
+		if _, err := ImportCompiledBinary(exported); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package mowgli
+
+import "strings"
+
+// ibanLengths is the length of IBANs per country code, from the SWIFT IBAN
+// registry
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31,
+	"SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// isCreditCard accepts 12 to 19 digit card numbers that pass the Luhn check.
+// Digits may be grouped with single spaces or hyphens, e.g.
+// "4111 1111 1111 1111".
+func isCreditCard(value string) bool {
+	digits := stripSeparators(value, " -")
+	if len(digits) < 12 || len(digits) > 19 {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return luhnValid(digits)
+}
+
+// luhnValid reports whether a string of digits has a valid Luhn check digit
+func luhnValid(digits string) bool {
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// isIBAN accepts an International Bank Account Number of the right length
+// for its country with valid ISO 7064 check digits, in the electronic form
+// ("GB82WEST12345698765432") or printed in groups of four
+func isIBAN(value string) bool {
+	iban := strings.ToUpper(stripSeparators(value, " "))
+	if len(iban) < 4 || ibanLengths[iban[:2]] != len(iban) {
+		return false
+	}
+
+	// Move the country code and check digits to the end and read letters
+	// as numbers from 10 (A) to 35 (Z); the remainder mod 97 must be 1
+	remainder := 0
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// stripSeparators removes single separator characters between groups of
+// characters, returning "" if separators are doubled, leading or trailing
+func stripSeparators(value, separators string) string {
+	var b strings.Builder
+	previousSeparator := true
+	for _, c := range value {
+		if strings.ContainsRune(separators, c) {
+			if previousSeparator {
+				return ""
+			}
+			previousSeparator = true
+			continue
+		}
+		previousSeparator = false
+		b.WriteRune(c)
+	}
+	if previousSeparator {
+		return ""
+	}
+	return b.String()
+}
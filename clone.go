@@ -0,0 +1,117 @@
+package mowgli
+
+import "reflect"
+
+// Clone returns a deep copy of the spec that shares no pointers, slices or
+// maps with it, so variants can be derived from a base spec without
+// changing it, e.g. per tenant. MergeSpecs, by contrast, shares the parts
+// of its inputs it doesn't change. Clone of a nil spec is nil.
+func (s *Spec) Clone() *Spec {
+	if s == nil {
+		return nil
+	}
+	return cloneValue(reflect.ValueOf(s)).Interface().(*Spec)
+}
+
+// cloneValue deep copies v. Values held in interfaces, such as enum values,
+// are copied too.
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(cloneValue(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		for i := range v.NumField() {
+			copied.Field(i).Set(cloneValue(v.Field(i)))
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			copied.Index(i).Set(cloneValue(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			copied.SetMapIndex(iter.Key(), cloneValue(iter.Value()))
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(cloneValue(v.Elem()))
+		return copied
+	default:
+		return v
+	}
+}
+
+// SpecEqual reports whether two specs are structurally equal: whether they
+// have the same keywords with the same values, regardless of which pointers
+// they share. Nil and empty lists and maps are equal, as they mean the same
+// in a spec, and enum and example values are compared as JSON values, so 1
+// equals 1.0.
+func SpecEqual(a, b *Spec) bool {
+	return valueEqual(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// valueEqual reports whether a and b, of the same type, are structurally
+// equal
+func valueEqual(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return a.Pointer() == b.Pointer() || valueEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if !valueEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !valueEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for iter := a.MapRange(); iter.Next(); {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !valueEqual(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return jsonEqual(a.Elem().Interface(), b.Elem().Interface())
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
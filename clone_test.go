@@ -0,0 +1,87 @@
+package mowgli
+
+import "testing"
+
+func TestSpecClone(t *testing.T) {
+	base, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"plan": {"type": "string", "enum": ["free", "pro"], "maxLength": 10}},
+		"required": ["plan"],
+		"conditions": [{"if": "plan == 'pro'", "then": {"seats": {"type": "integer", "min": 1}}}],
+		"discriminator": {"propertyName": "plan", "mapping": {"pro": {"required": ["seats"]}}},
+		"examples": [{"plan": "free", "tags": ["a"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	original := base.Clone()
+
+	tenant := base.Clone()
+	if !SpecEqual(tenant, base) {
+		t.Fatal("expected the clone to equal the original")
+	}
+
+	// Change every level of the clone
+	tenant.Properties["plan"].Enum = append(tenant.Properties["plan"].Enum, "enterprise")
+	*tenant.Properties["plan"].MaxLength = 20
+	tenant.Required[0] = "seats"
+	tenant.Conditions[0].Then["seats"].Type = "number"
+	tenant.Discriminator.Mapping["pro"].Required = nil
+	tenant.Examples[0].(map[string]any)["tags"].([]any)[0] = "b"
+
+	if !SpecEqual(base, original) {
+		t.Error("expected changes to the clone to leave the original unchanged")
+	}
+	if SpecEqual(tenant, base) {
+		t.Error("expected the changed clone to differ from the original")
+	}
+	if (*Spec)(nil).Clone() != nil {
+		t.Error("expected the clone of nil to be nil")
+	}
+}
+
+func TestSpecEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{name: "identical", a: `{"type": "string", "minLength": 1}`, b: `{"minLength": 1, "type": "string"}`, want: true},
+		{name: "different value", a: `{"type": "string", "minLength": 1}`, b: `{"type": "string", "minLength": 2}`},
+		{name: "missing keyword", a: `{"type": "string", "minLength": 1}`, b: `{"type": "string"}`},
+		{name: "empty and missing lists", a: `{"type": "object", "required": []}`, b: `{"type": "object"}`, want: true},
+		{name: "empty and missing maps", a: `{"type": "object", "properties": {}}`, b: `{"type": "object"}`, want: true},
+		{name: "nested", a: `{"properties": {"a": {"type": "string"}}}`, b: `{"properties": {"a": {"type": "number"}}}`},
+		{name: "required order", a: `{"required": ["a", "b"]}`, b: `{"required": ["b", "a"]}`},
+		{name: "conditions", a: `{"conditions": [{"if": "a", "then": {}}]}`, b: `{"conditions": [{"if": "b", "then": {}}]}`},
+		{name: "false and unset", a: `{"nullable": false}`, b: `{}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseSpecString(tt.a)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			b, err := ParseSpecString(tt.b)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			if got := SpecEqual(a, b); got != tt.want {
+				t.Errorf("expected SpecEqual %v, got %v", tt.want, got)
+			}
+			if got := SpecEqual(b, a); got != tt.want {
+				t.Errorf("expected SpecEqual to be symmetric, got %v", got)
+			}
+		})
+	}
+
+	numbers := &Spec{Enum: []any{1, map[string]any{"n": 2}}}
+	floats := &Spec{Enum: []any{1.0, map[string]any{"n": 2.0}}}
+	if !SpecEqual(numbers, floats) {
+		t.Error("expected enum values to be compared as JSON values")
+	}
+	if !SpecEqual(nil, nil) || SpecEqual(nil, &Spec{}) {
+		t.Error("expected nil to equal only nil")
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/token"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// genspecProgram is the program genspec runs to generate the spec. Running
+// SpecFromStruct on the real type keeps the output identical to the runtime
+// spec, including registered type mappings and MowgliSpec methods.
+var genspecProgram = template.Must(template.New("genspec").Parse(`package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/matjam/mowgli"
+	target {{printf "%q" .ImportPath}}
+)
+
+func main() {
+	var value target.{{.Type}}
+	spec, err := mowgli.SpecFromStruct(value)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(spec)
+}
+`))
+
+func runGenspec(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("genspec", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mowgli genspec [package] --type Name")
+		fmt.Fprintln(stderr, "\nPrints the spec SpecFromStruct generates for an exported struct type.")
+		fmt.Fprintln(stderr, "The package defaults to \".\" and its module must require github.com/matjam/mowgli.")
+		flags.PrintDefaults()
+	}
+	typeName := flags.String("type", "", "name of the struct `type` (required)")
+
+	// Accept flags both before and after the package argument
+	if err := flags.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+	pkg := "."
+	if flags.NArg() > 0 {
+		pkg = flags.Arg(0)
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			return flagExitCode(err)
+		}
+		if flags.NArg() > 0 {
+			flags.Usage()
+			return exitError
+		}
+	}
+
+	if !token.IsIdentifier(*typeName) || !token.IsExported(*typeName) {
+		fmt.Fprintln(stderr, "mowgli genspec: --type must name an exported type")
+		return exitError
+	}
+
+	spec, err := generateSpec(pkg, *typeName)
+	if err != nil {
+		fmt.Fprintf(stderr, "mowgli genspec: %v\n", err)
+		return exitError
+	}
+	stdout.Write(spec)
+	return exitOK
+}
+
+// generateSpec builds and runs genspecProgram for the type in pkg
+func generateSpec(pkg, typeName string) ([]byte, error) {
+	list, err := goCommand("", "list", "-f", "{{.ImportPath}}\t{{.Name}}\t{{with .Module}}{{.Dir}}{{end}}", pkg)
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.Split(strings.TrimSpace(string(list)), "\t")
+	if len(fields) != 3 || fields[2] == "" {
+		return nil, fmt.Errorf("package %s is not part of a module", pkg)
+	}
+	importPath, name, moduleDir := fields[0], fields[1], fields[2]
+	if name == "main" {
+		return nil, fmt.Errorf("package %s is a command and cannot be imported", pkg)
+	}
+
+	// The program has to live inside the package's module to import it; the
+	// leading underscore keeps it out of ./... patterns while it exists
+	dir, err := os.MkdirTemp(moduleDir, "_mowgli_genspec")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	var program bytes.Buffer
+	genspecProgram.Execute(&program, struct{ ImportPath, Type string }{importPath, typeName})
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), program.Bytes(), 0o644); err != nil {
+		return nil, err
+	}
+
+	return goCommand(moduleDir, "run", "./"+filepath.Base(dir))
+}
+
+// goCommand runs the go tool in dir, returning its standard output
+func goCommand(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("go %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("go %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// flagExitCode is the exit code for a flag parsing error
+func flagExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitError
+}
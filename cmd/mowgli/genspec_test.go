@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matjam/mowgli"
+	"github.com/matjam/mowgli/examples"
+)
+
+func TestGenspec(t *testing.T) {
+	if testing.Short() {
+		t.Skip("genspec builds a program with the go tool")
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"genspec", "../../examples", "--type", "Product"}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("expected exit code %d, got %d: %s", exitOK, code, stderr.String())
+	}
+
+	want, err := mowgli.SpecFromStruct(examples.Product{})
+	if err != nil {
+		t.Fatalf("SpecFromStruct failed: %v", err)
+	}
+	wantJSON, _ := json.Marshal(want)
+	var got mowgli.Spec
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, stdout.String())
+	}
+	gotJSON, _ := json.Marshal(&got)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("expected %s, got %s", wantJSON, gotJSON)
+	}
+}
+
+func TestGenspecErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+		slow       bool
+	}{
+		{name: "missing type", args: []string{"../../examples"}, wantStderr: "--type must name an exported type"},
+		{name: "unexported type", args: []string{"--type", "product"}, wantStderr: "--type must name an exported type"},
+		{name: "extra arguments", args: []string{"a", "b", "--type", "Product"}, wantStderr: "Usage"},
+		{name: "unknown type", args: []string{"../../examples", "--type", "Missing"}, wantStderr: "undefined: target.Missing", slow: true},
+		{name: "command package", args: []string{".", "--type", "Product"}, wantStderr: "is a command", slow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.slow && testing.Short() {
+				t.Skip("genspec runs the go tool")
+			}
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"genspec"}, tt.args...), nil, &stdout, &stderr); code != exitError {
+				t.Errorf("expected exit code %d, got %d", exitError, code)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got:\n%s", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Command mowgli validates JSON documents against mowgli specs from the
+// command line, e.g. to check configuration files in CI, and generates specs
+// from Go struct types:
+//
+//	mowgli validate --spec spec.json config/*.json
+//	mowgli genspec ./pkg --type Product > product.json
+//	mowgli watch --spec app.schema.json config.yaml
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes
+const (
+	exitOK      = 0 // Every document is valid
+	exitInvalid = 1 // At least one document is invalid
+	exitError   = 2 // Bad usage, or a spec or document could not be read
+)
+
+// command runs a subcommand with its arguments
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"validate": runValidate,
+	"genspec":  runGenspec,
+	"watch":    runWatch,
+}
+
+const usage = `Usage: mowgli <command> [flags] [arguments]
+
+Commands:
+  validate   Validate JSON documents against a spec
+  genspec    Generate a spec from a Go struct type
+  watch      Revalidate documents whenever they or the spec change
+
+Run "mowgli <command> -h" for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitError
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "mowgli: unknown command %q\n\n%s", args[0], usage)
+		return exitError
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
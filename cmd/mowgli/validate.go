@@ -0,0 +1,256 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matjam/mowgli"
+	"gopkg.in/yaml.v3"
+)
+
+// fileResult is the outcome of validating one document
+type fileResult struct {
+	File     string                    `json:"file"`
+	Valid    bool                      `json:"valid"`
+	Errors   []*mowgli.ValidationError `json:"errors,omitempty"`   // Validation errors
+	Warnings []*mowgli.ValidationError `json:"warnings,omitempty"` // Problems that don't fail validation, e.g. deprecated fields
+	Error    string                    `json:"error,omitempty"`    // Why the document could not be validated
+
+	result *mowgli.ValidationResult
+	doc    document
+}
+
+// document is a file as read and as the JSON validated for it
+type document struct {
+	json   []byte
+	source []byte         // The file's contents, e.g. YAML
+	locate mowgli.Locator // Locates errors in source
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mowgli validate --spec spec.json [--output text|pretty|json|sarif] [file or glob ...]")
+		fmt.Fprintln(stderr, "\nValidates each file, or stdin if no files are given or a file is \"-\".")
+		fmt.Fprintln(stderr, "Files ending in .yaml or .yml are read as YAML.")
+		flags.PrintDefaults()
+	}
+	specPath := flags.String("spec", "", "path to the spec `file` (required)")
+	output := flags.String("output", "text", "output `format`: text, pretty, json or sarif")
+	if err := flags.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	if *specPath == "" {
+		fmt.Fprintln(stderr, "mowgli validate: --spec is required")
+		return exitError
+	}
+	switch *output {
+	case "text", "pretty", "json", "sarif":
+	default:
+		fmt.Fprintf(stderr, "mowgli validate: unknown output format %q\n", *output)
+		return exitError
+	}
+
+	spec, err := loadSpec(*specPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "mowgli validate: %v\n", err)
+		return exitError
+	}
+
+	files, err := expandFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "mowgli validate: %v\n", err)
+		return exitError
+	}
+
+	results := validateFiles(files, stdin, spec)
+
+	switch *output {
+	case "json":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(results)
+	case "sarif":
+		writeSARIF(stdout, results)
+	case "pretty":
+		writePretty(stdout, results, isTerminal(stdout))
+	default:
+		writeText(stdout, results)
+	}
+	return exitCode(results)
+}
+
+// loadSpec reads and compiles the spec at path
+func loadSpec(path string) (*mowgli.Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec: %w", err)
+	}
+	spec, err := mowgli.ParseSpec(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec %s: %w", path, err)
+	}
+	if _, err := mowgli.Compile(spec); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+	return spec, nil
+}
+
+// expandFiles expands glob patterns, for shells that don't and for quoted
+// patterns. No arguments means stdin.
+func expandFiles(args []string) ([]string, error) {
+	if len(args) == 0 {
+		return []string{"-"}, nil
+	}
+
+	var files []string
+	for _, arg := range args {
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			// Not a pattern, or one matching nothing: report the path as given
+			matches = []string{arg}
+		}
+		files = append(files, matches...)
+	}
+	return files, nil
+}
+
+// validateFiles validates each file against spec
+func validateFiles(files []string, stdin io.Reader, spec *mowgli.Spec) []fileResult {
+	results := make([]fileResult, 0, len(files))
+	for _, file := range files {
+		results = append(results, validateFile(file, stdin, spec))
+	}
+	return results
+}
+
+func validateFile(file string, stdin io.Reader, spec *mowgli.Spec) fileResult {
+	doc, err := readDocument(file, stdin)
+	if err != nil {
+		return fileResult{File: file, Error: err.Error()}
+	}
+
+	result, err := mowgli.ValidateJSON(doc.json, spec)
+	if err != nil {
+		return fileResult{File: file, Error: err.Error()}
+	}
+	return fileResult{File: file, Valid: result.Valid, Errors: result.Errors, Warnings: result.Warnings, result: result, doc: doc}
+}
+
+// readDocument reads file, or stdin for "-", as JSON. Files with a .yaml or
+// .yml extension are converted from YAML.
+func readDocument(file string, stdin io.Reader) (document, error) {
+	if file == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return document{}, err
+		}
+		return document{json: data, source: data, locate: mowgli.JSONLocator(data)}, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return document{}, err
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		var doc any
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return document{}, fmt.Errorf("invalid YAML: %w", err)
+		}
+		converted, err := json.Marshal(doc)
+		if err != nil {
+			return document{}, fmt.Errorf("YAML document can't be represented as JSON: %w", err)
+		}
+		return document{json: converted, source: data, locate: mowgli.YAMLLocator(data)}, nil
+	}
+	return document{json: data, source: data, locate: mowgli.JSONLocator(data)}, nil
+}
+
+// exitCode is the exit code for a set of results
+func exitCode(results []fileResult) int {
+	code := exitOK
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			code = exitError
+		case !result.Valid && code == exitOK:
+			code = exitInvalid
+		}
+	}
+	return code
+}
+
+func writeText(w io.Writer, results []fileResult) {
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(w, "%s: error: %s\n", result.File, result.Error)
+		case result.Valid:
+			fmt.Fprintf(w, "%s: ok\n", result.File)
+		default:
+			fmt.Fprintf(w, "%s: invalid\n", result.File)
+			for _, e := range result.Errors {
+				fmt.Fprintf(w, "  %s\n", e.Error())
+			}
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintf(w, "  warning: %s\n", warning.Error())
+		}
+	}
+}
+
+// writeSARIF writes the results as a SARIF log, for code scanning views
+func writeSARIF(w io.Writer, results []fileResult) {
+	docs := make([]mowgli.SARIFDocument, 0, len(results))
+	for _, result := range results {
+		doc := mowgli.SARIFDocument{URI: filepath.ToSlash(result.File), Result: result.result, Locate: result.doc.locate}
+		if result.Error != "" {
+			doc.Err = errors.New(result.Error)
+		}
+		docs = append(docs, doc)
+	}
+	mowgli.WriteSARIF(w, docs...)
+}
+
+// writePretty writes the results for a person to read, with the offending
+// lines of each file, in color if color is set
+func writePretty(w io.Writer, results []fileResult, color bool) {
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			fmt.Fprintf(w, "%s: error: %s\n", result.File, result.Error)
+			continue
+		case result.Valid && len(result.Warnings) == 0:
+			fmt.Fprintf(w, "%s: ok\n", result.File)
+			continue
+		}
+		mowgli.WritePretty(w, result.doc.source, result.result, mowgli.PrettyConfig{
+			File:   result.File,
+			Locate: result.doc.locate,
+			Color:  color,
+		})
+	}
+}
+
+// isTerminal reports whether w is a terminal that colors should be written
+// to, which NO_COLOR turns off
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
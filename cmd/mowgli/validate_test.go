@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files into a temporary directory and returns its path
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestValidate(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.json":     `{"type": "object", "properties": {"port": {"type": "integer", "min": 1}, "host": {"type": "string", "deprecated": true}}, "required": ["port"]}`,
+		"bad_spec.json": `{"type": "string", "pattern": "("}`,
+		"a.json":        `{"port": 80}`,
+		"b.json":        `{"port": 443}`,
+		"invalid.json":  `{"port": 0}`,
+		"broken.txt":    `{"port":`,
+		"config.yaml":   "port: 0\n",
+		"legacy.json":   `{"port": 80, "host": "a"}`,
+	})
+	spec := filepath.Join(dir, "spec.json")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantStdout []string
+		wantStderr string
+	}{
+		{
+			name:       "valid files",
+			args:       []string{"--spec", spec, filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")},
+			wantCode:   exitOK,
+			wantStdout: []string{"a.json: ok", "b.json: ok"},
+		},
+		{
+			name:       "glob",
+			args:       []string{"--spec", spec, filepath.Join(dir, "[ab].json")},
+			wantCode:   exitOK,
+			wantStdout: []string{"a.json: ok", "b.json: ok"},
+		},
+		{
+			name:       "invalid file",
+			args:       []string{"--spec", spec, filepath.Join(dir, "*.json")},
+			wantCode:   exitInvalid,
+			wantStdout: []string{"invalid.json: invalid", "  port: integer 0 is less than minimum 1"},
+		},
+		{
+			name:       "deprecated field",
+			args:       []string{"--spec", spec, filepath.Join(dir, "legacy.json")},
+			wantCode:   exitOK,
+			wantStdout: []string{"legacy.json: ok", "  warning: host: field is deprecated"},
+		},
+		{
+			name:       "yaml",
+			args:       []string{"--spec", spec, filepath.Join(dir, "config.yaml")},
+			wantCode:   exitInvalid,
+			wantStdout: []string{"config.yaml: invalid", "  port: integer 0 is less than minimum 1"},
+		},
+		{
+			name:       "stdin",
+			args:       []string{"--spec", spec},
+			stdin:      `{}`,
+			wantCode:   exitInvalid,
+			wantStdout: []string{"-: invalid", "  port: required field is missing"},
+		},
+		{
+			name:       "unreadable documents",
+			args:       []string{"--spec", spec, filepath.Join(dir, "broken.txt"), filepath.Join(dir, "missing.json")},
+			wantCode:   exitError,
+			wantStdout: []string{"broken.txt: error: invalid JSON", "missing.json: error:"},
+		},
+		{
+			name:       "missing spec flag",
+			args:       []string{filepath.Join(dir, "a.json")},
+			wantCode:   exitError,
+			wantStderr: "--spec is required",
+		},
+		{
+			name:       "invalid spec",
+			args:       []string{"--spec", filepath.Join(dir, "bad_spec.json")},
+			wantCode:   exitError,
+			wantStderr: "invalid pattern",
+		},
+		{
+			name:       "unknown output",
+			args:       []string{"--spec", spec, "--output", "xml"},
+			wantCode:   exitError,
+			wantStderr: "unknown output format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(append([]string{"validate"}, tt.args...), strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", tt.wantCode, code, stderr.String())
+			}
+			for _, want := range tt.wantStdout {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("expected stdout to contain %q, got:\n%s", want, stdout.String())
+				}
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got:\n%s", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestValidateJSONOutput(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.json": `{"type": "string", "minLength": 3}`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"validate", "--spec", filepath.Join(dir, "spec.json"), "--output", "json"},
+		strings.NewReader(`"ab"`), &stdout, &stderr)
+	if code != exitInvalid {
+		t.Fatalf("expected exit code %d, got %d", exitInvalid, code)
+	}
+
+	var results []fileResult
+	if err := json.Unmarshal(stdout.Bytes(), &results); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, stdout.String())
+	}
+	if len(results) != 1 || results[0].File != "-" || results[0].Valid || len(results[0].Errors) != 1 || results[0].Errors[0].Code != "minLength" {
+		t.Errorf("unexpected results: %s", stdout.String())
+	}
+}
+
+func TestValidateSARIFOutput(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.json":   `{"type": "object", "properties": {"port": {"type": "integer", "max": 65535}}}`,
+		"config.yaml": "name: api\nport: 70000\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"validate", "--spec", filepath.Join(dir, "spec.json"), "--output", "sarif", filepath.Join(dir, "config.yaml")},
+		nil, &stdout, &stderr)
+	if code != exitInvalid {
+		t.Fatalf("expected exit code %d, got %d: %s", exitInvalid, code, stderr.String())
+	}
+
+	var log struct {
+		Runs []struct {
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &log); err != nil {
+		t.Fatalf("Failed to decode output: %v\n%s", err, stdout.String())
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected one result, got:\n%s", stdout.String())
+	}
+	result := log.Runs[0].Results[0]
+	location := result.Locations[0].PhysicalLocation
+	if result.RuleID != "max" || location.Region.StartLine != 2 || !strings.HasSuffix(location.ArtifactLocation.URI, "/config.yaml") {
+		t.Errorf("unexpected result: %s", stdout.String())
+	}
+}
+
+func TestValidatePrettyOutput(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.json":   `{"type": "object", "properties": {"port": {"type": "integer", "max": 65535}}}`,
+		"config.yaml": "name: api\nport: 70000\n",
+		"good.yaml":   "port: 80\n",
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"validate", "--spec", filepath.Join(dir, "spec.json"), "--output", "pretty",
+		filepath.Join(dir, "config.yaml"), filepath.Join(dir, "good.yaml")}, nil, &stdout, &stderr)
+	if code != exitInvalid {
+		t.Fatalf("expected exit code %d, got %d: %s", exitInvalid, code, stderr.String())
+	}
+	for _, want := range []string{"error[max]: port:", "config.yaml:2:1", "2 | port: 70000", "^^^^^ max 65535", "good.yaml: ok"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, stdout.String())
+		}
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("expected no colors when not writing to a terminal, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, nil, &stdout, &stderr); code != exitError || !strings.Contains(stderr.String(), "Usage") {
+		t.Errorf("expected usage and exit code %d, got %d", exitError, code)
+	}
+	stderr.Reset()
+	if code := run([]string{"bogus"}, nil, &stdout, &stderr); code != exitError || !strings.Contains(stderr.String(), "unknown command") {
+		t.Errorf("expected unknown command error, got %d %s", code, stderr.String())
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+func runWatch(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: mowgli watch --spec spec.json [--interval 500ms] file or glob ...")
+		fmt.Fprintln(stderr, "\nValidates the files, and again whenever they or the spec change, until interrupted.")
+		fmt.Fprintln(stderr, "Files ending in .yaml or .yml are read as YAML.")
+		flags.PrintDefaults()
+	}
+	specPath := flags.String("spec", "", "path to the spec `file` (required)")
+	interval := flags.Duration("interval", 500*time.Millisecond, "how often to check the files for changes")
+	if err := flags.Parse(args); err != nil {
+		return flagExitCode(err)
+	}
+
+	if *specPath == "" {
+		fmt.Fprintln(stderr, "mowgli watch: --spec is required")
+		return exitError
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprintln(stderr, "mowgli watch: no files to watch")
+		return exitError
+	}
+	for _, arg := range flags.Args() {
+		if arg == "-" {
+			fmt.Fprintln(stderr, "mowgli watch: cannot watch stdin")
+			return exitError
+		}
+	}
+	if *interval <= 0 {
+		fmt.Fprintln(stderr, "mowgli watch: --interval must be positive")
+		return exitError
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	watch(ctx, *specPath, flags.Args(), *interval, stdout)
+	return exitOK
+}
+
+// watch reports on the documents matching patterns whenever they or the spec
+// change, polling every interval until ctx is done. Globs are expanded on
+// every poll so new files are picked up.
+func watch(ctx context.Context, specPath string, patterns []string, interval time.Duration, w io.Writer) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		files, err := expandFiles(patterns)
+		if current := fingerprint(append([]string{specPath}, files...)...); current != last {
+			last = current
+			if err != nil {
+				fmt.Fprintf(w, "[%s] error: %v\n", time.Now().Format(time.TimeOnly), err)
+			} else {
+				writeReport(w, specPath, files)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fingerprint identifies the state of the files by their sizes and
+// modification times
+func fingerprint(files ...string) string {
+	var b strings.Builder
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d\n", file, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&b, "%s:missing\n", file)
+		}
+	}
+	return b.String()
+}
+
+// writeReport validates the files and writes a summary line followed by the
+// result for each file
+func writeReport(w io.Writer, specPath string, files []string) {
+	now := time.Now().Format(time.TimeOnly)
+
+	spec, err := loadSpec(specPath)
+	if err != nil {
+		fmt.Fprintf(w, "[%s] FAIL: %v\n", now, err)
+		return
+	}
+
+	results := validateFiles(files, nil, spec)
+	var ok, invalid, failed int
+	for _, result := range results {
+		switch {
+		case result.Error != "":
+			failed++
+		case result.Valid:
+			ok++
+		default:
+			invalid++
+		}
+	}
+
+	status := "PASS"
+	if invalid > 0 || failed > 0 {
+		status = "FAIL"
+	}
+	fmt.Fprintf(w, "[%s] %s: %d ok, %d invalid, %d unreadable\n", now, status, ok, invalid, failed)
+	writeText(w, results)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatch(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"spec.json":   `{"type": "object", "properties": {"port": {"type": "integer", "min": 1}}}`,
+		"config.yaml": "port: 80\n",
+	})
+	config := filepath.Join(dir, "config.yaml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	done := make(chan struct{})
+	go func() {
+		watch(ctx, filepath.Join(dir, "spec.json"), []string{filepath.Join(dir, "*.yaml")}, 5*time.Millisecond, &out)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for !strings.Contains(out.String(), want) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %q, output:\n%s", want, out.String())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	waitFor("PASS: 1 ok, 0 invalid, 0 unreadable")
+	if err := os.WriteFile(config, []byte("port: 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("FAIL: 0 ok, 1 invalid, 0 unreadable")
+	waitFor("  port: integer 0 is less than minimum 1")
+
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("port: 8080\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("FAIL: 1 ok, 1 invalid, 0 unreadable")
+
+	if err := os.WriteFile(filepath.Join(dir, "spec.json"), []byte(`{"type": "object"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("PASS: 2 ok, 0 invalid, 0 unreadable")
+}
+
+func TestWatchUsage(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantStderr string
+	}{
+		{name: "missing spec", args: []string{"config.yaml"}, wantStderr: "--spec is required"},
+		{name: "no files", args: []string{"--spec", "spec.json"}, wantStderr: "no files to watch"},
+		{name: "stdin", args: []string{"--spec", "spec.json", "-"}, wantStderr: "cannot watch stdin"},
+		{name: "bad interval", args: []string{"--spec", "spec.json", "--interval", "0s", "a.json"}, wantStderr: "--interval must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(append([]string{"watch"}, tt.args...), nil, &stdout, &stderr); code != exitError {
+				t.Errorf("expected exit code %d, got %d", exitError, code)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("expected stderr to contain %q, got:\n%s", tt.wantStderr, stderr.String())
+			}
+		})
+	}
+}
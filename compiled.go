@@ -0,0 +1,399 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// compiledFormatVersion is the version of the Export format
+const compiledFormatVersion = 2
+
+// CompiledSpec is a spec that has been checked and prepared for validation:
+// its regular expressions and condition expressions are compiled, so
+// mistakes in the spec surface once at compile time rather than as
+// validation errors on every document. The compiled spec keeps the programs
+// of its expressions, which validation reuses. Validators with ExprLimits
+// or custom functions compile each expression again on its first
+// evaluation, and keep that program too.
+//
+// A CompiledSpec is immutable: Compile copies the spec, so changes to it
+// afterwards don't affect the compiled spec, and Spec returns a copy.
+// Validation never modifies the spec either; conditions, discriminators and
+// $refs build new effective specs for each document, sharing the unchanged
+// parts. A CompiledSpec is therefore safe for concurrent use by any number
+// of goroutines, as is a plain Spec as long as nothing modifies it.
+type CompiledSpec struct {
+	spec        *Spec
+	patterns    []string
+	expressions []compiledExpression
+	programs    *programSet
+}
+
+// compiledExpression is a condition expression prepared for evaluation
+type compiledExpression struct {
+	Source     string   `json:"source"`     // The expression as written in the spec
+	Translated string   `json:"translated"` // The expression in expr syntax
+	References []string `json:"references"` // Fields the expression reads
+}
+
+// compiledArtifact is the serialized form of a CompiledSpec
+type compiledArtifact struct {
+	Version     int                  `json:"version"`
+	Digest      string               `json:"digest"` // SpecDigest of Spec
+	Spec        *Spec                `json:"spec"`
+	Patterns    []string             `json:"patterns"`
+	Expressions []compiledExpression `json:"expressions"`
+}
+
+// Compile checks a spec and prepares it for validation
+func Compile(spec *Spec) (*CompiledSpec, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("spec is nil")
+	}
+
+	c := &compiler{
+		patterns:    make(map[string]bool),
+		expressions: make(map[string]compiledExpression),
+		programs:    &programSet{},
+	}
+	if err := c.compile("", spec); err != nil {
+		return nil, err
+	}
+
+	compiled := &CompiledSpec{spec: spec.Clone(), programs: c.programs}
+	for pattern := range c.patterns {
+		compiled.patterns = append(compiled.patterns, pattern)
+	}
+	sort.Strings(compiled.patterns)
+	for _, expression := range c.expressions {
+		compiled.expressions = append(compiled.expressions, expression)
+	}
+	sort.Slice(compiled.expressions, func(i, j int) bool {
+		return compiled.expressions[i].Source < compiled.expressions[j].Source
+	})
+
+	return compiled, nil
+}
+
+// Spec returns a copy of the spec that was compiled, which the caller may
+// modify, e.g. to derive a variant to compile
+func (c *CompiledSpec) Spec() *Spec {
+	return c.spec.Clone()
+}
+
+// Validate validates data against the compiled spec
+func (c *CompiledSpec) Validate(data any) *ValidationResult {
+	return defaultValidator.ValidateCompiled(data, c)
+}
+
+// ValidateCompiled validates data against a compiled spec with the
+// Validator's settings
+func (v *Validator) ValidateCompiled(data any, c *CompiledSpec) *ValidationResult {
+	return v.validateNamed(specName(c.spec), data, c.spec, c.programs)
+}
+
+// Export serializes the compiled spec so that it can be stored and loaded
+// later with ImportCompiled, e.g. by cold-starting serverless functions.
+// The artifact holds the spec and its digest together with its regular
+// expression sources and translated condition expressions. expr programs
+// cannot be serialized, so expressions are stored in source form rather
+// than as bytecode.
+func (c *CompiledSpec) Export() ([]byte, error) {
+	artifact, err := c.artifact()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(artifact)
+}
+
+// artifact returns the serializable form of the compiled spec
+func (c *CompiledSpec) artifact() (compiledArtifact, error) {
+	digest, err := SpecDigest(c.spec)
+	if err != nil {
+		return compiledArtifact{}, err
+	}
+	return compiledArtifact{
+		Version:     compiledFormatVersion,
+		Digest:      digest,
+		Spec:        c.spec,
+		Patterns:    c.patterns,
+		Expressions: c.expressions,
+	}, nil
+}
+
+// ImportCompiled loads a compiled spec produced by Export. Rather than
+// checking the spec again as Compile does, it checks that the spec matches
+// the digest it was exported with. Its patterns and expressions are
+// compiled as validation first uses them, so loading costs little more than
+// decoding the JSON.
+func ImportCompiled(data []byte) (*CompiledSpec, error) {
+	var artifact compiledArtifact
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, fmt.Errorf("invalid compiled spec: %w", err)
+	}
+	return importArtifact(artifact)
+}
+
+// importArtifact checks a deserialized artifact's version and digest
+func importArtifact(artifact compiledArtifact) (*CompiledSpec, error) {
+	if artifact.Version != compiledFormatVersion {
+		return nil, fmt.Errorf("unsupported compiled spec version %d (expected %d)", artifact.Version, compiledFormatVersion)
+	}
+	if artifact.Spec == nil {
+		return nil, fmt.Errorf("invalid compiled spec: missing spec")
+	}
+	digest, err := SpecDigest(artifact.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compiled spec: %w", err)
+	}
+	if digest != artifact.Digest {
+		return nil, fmt.Errorf("invalid compiled spec: digest mismatch: expected %s, got %s", artifact.Digest, digest)
+	}
+	return &CompiledSpec{
+		spec:        artifact.Spec,
+		patterns:    artifact.Patterns,
+		expressions: artifact.Expressions,
+		programs:    &programSet{},
+	}, nil
+}
+
+// knownTypes are the types a spec may declare
+var knownTypes = map[string]bool{
+	"string":  true,
+	"number":  true,
+	"integer": true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"null":    true,
+}
+
+// compiler walks a spec collecting and checking its patterns and expressions
+type compiler struct {
+	patterns    map[string]bool
+	expressions map[string]compiledExpression
+	formats     formatScope
+	defs        conditionDefScope
+	programs    *programSet
+}
+
+func (c *compiler) compile(path string, spec *Spec) error {
+	if spec == nil {
+		return nil
+	}
+
+	if spec.Type != "" && !knownTypes[spec.Type] {
+		return fmt.Errorf("%s: unknown type: %s", displayPath(path), spec.Type)
+	}
+
+	if spec.Pattern != nil {
+		if _, err := compilePattern(*spec.Pattern); err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", displayPath(path), *spec.Pattern, err)
+		}
+		c.patterns[*spec.Pattern] = true
+	}
+
+	if spec.Formats != nil {
+		if err := checkFormats(spec.Formats); err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		for _, pattern := range spec.Formats {
+			c.patterns[pattern] = true
+		}
+		defer c.formats.push(spec.Formats)()
+	}
+
+	if spec.ConditionDefs != nil {
+		defer c.defs.push(spec.ConditionDefs)()
+		if err := c.defs.check(); err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+	}
+
+	if spec.Format != nil {
+		if _, err := c.formats.lookup(*spec.Format); err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+	}
+
+	if err := checkContent(spec); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkIntBounds(spec); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkURISchemes(spec.URISchemes); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkSemverRange(spec.SemverRange); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkTimeFormat(spec.TimeFormat); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkLengthUnit(spec.LengthUnit); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkTransforms(spec.Transform); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkRefIntegrity(spec.RefIntegrity); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+
+	if err := checkOverrides(spec.Overrides); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+	for _, selector := range sortedKeys(spec.Overrides) {
+		if err := c.compile(buildPath(path, selector), spec.Overrides[selector]); err != nil {
+			return err
+		}
+	}
+
+	if err := checkDiscriminator(spec.Discriminator); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+	if spec.Discriminator != nil {
+		// Branches describe the same object
+		for _, key := range sortedKeys(spec.Discriminator.Mapping) {
+			if err := c.compile(path, spec.Discriminator.Mapping[key]); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := checkConditional(spec); err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+	// Conditional subschemas describe the same value
+	for _, sub := range []*Spec{spec.If, spec.Then, spec.Else} {
+		if err := c.compile(path, sub); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(spec.Properties) {
+		if err := c.compile(buildPath(path, name), spec.Properties[name]); err != nil {
+			return err
+		}
+	}
+	if err := c.compile(path+"[]", spec.Items); err != nil {
+		return err
+	}
+	if err := c.compile(buildPath(path, "*"), spec.AdditionalProperties); err != nil {
+		return err
+	}
+	if err := c.compile(path+"{}", spec.PropertyNames); err != nil {
+		return err
+	}
+	if err := c.compile(path+"(content)", spec.ContentSchema); err != nil {
+		return err
+	}
+
+	for _, condition := range spec.Conditions {
+		if err := c.addExpression(path, condition.Expression()); err != nil {
+			return err
+		}
+		if err := checkReshape(condition); err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		for _, shape := range []*Spec{condition.ThenSpec, condition.ElseSpec} {
+			if err := c.compile(path, shape); err != nil {
+				return err
+			}
+		}
+		for _, overrides := range []map[string]*Spec{condition.Then, condition.Else} {
+			for _, name := range sortedKeys(overrides) {
+				if err := c.compile(buildPath(path, name), overrides[name]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, assert := range spec.Asserts {
+		if assert.Select != "" || assert.Spec != nil {
+			if err := c.compileSelected(path, assert); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.addExpression(buildPath(path, assert.Path), assert.Expr); err != nil {
+			return err
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.RequiredIf)) {
+		if err := c.addExpression(buildPath(path, name), spec.RequiredIf[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compileSelected checks an assert with a JSONPath or spec: its JSONPath,
+// the expressions of its filters and itself, in which "@" is the selected
+// value, and its spec
+func (c *compiler) compileSelected(path string, assert Assert) error {
+	if assert.Expr == "" && assert.Spec == nil {
+		return fmt.Errorf("%s: assert needs an expr or a spec", displayPath(path))
+	}
+	if assert.Select != "" {
+		steps, err := parseJSONPath(assert.Select)
+		if err != nil {
+			return fmt.Errorf("%s: %w", displayPath(path), err)
+		}
+		for _, step := range steps {
+			if step.kind == jsonPathFilter {
+				if err := c.addExpression(path, translateCurrent(step.filter)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if assert.Expr != "" {
+		if err := c.addExpression(buildPath(path, assert.Path), translateCurrent(assert.Expr)); err != nil {
+			return err
+		}
+	}
+	return c.compile(buildPath(path, assert.Path), assert.Spec)
+}
+
+// addExpression checks an expression and records it for the artifact
+func (c *compiler) addExpression(path, expr string) error {
+	references, err := c.defs.references(expr)
+	if err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), err)
+	}
+	// The program is kept for validators without ExprLimits or custom
+	// functions, such as the one CompiledSpec.Validate uses
+	if compiled := compileExpression(strings.TrimSpace(expr), ExprLimits{}, c.defs, nil, c.programs); compiled.err != nil {
+		return fmt.Errorf("%s: %w", displayPath(path), compiled.err)
+	}
+	if _, seen := c.expressions[expr]; !seen {
+		c.expressions[expr] = compiledExpression{
+			Source:     expr,
+			Translated: translateToExpr(expr),
+			References: references,
+		}
+	}
+	return nil
+}
+
+// displayPath renders a spec path for error messages
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
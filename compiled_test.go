@@ -0,0 +1,323 @@
+package mowgli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name     string
+		specJSON string
+		wantErr  string
+	}{
+		{
+			name:     "valid spec",
+			specJSON: `{"type": "object", "properties": {"a": {"type": "string", "pattern": "^a+$"}}}`,
+		},
+		{
+			name:     "invalid pattern",
+			specJSON: `{"type": "object", "properties": {"a": {"type": "string", "pattern": "("}}}`,
+			wantErr:  "a: invalid pattern",
+		},
+		{
+			name:     "invalid pattern in condition override",
+			specJSON: `{"type": "object", "conditions": [{"if": "x", "then": {"b": {"pattern": "["}}}]}`,
+			wantErr:  "b: invalid pattern",
+		},
+		{
+			name:     "unparsable expression",
+			specJSON: `{"type": "object", "conditions": [{"if": "a ==", "then": {}}]}`,
+			wantErr:  "(root): failed to parse expression",
+		},
+		{
+			name:     "unparsable requiredIf",
+			specJSON: `{"type": "object", "requiredIf": {"b": "a =="}}`,
+			wantErr:  "b: failed to parse expression",
+		},
+		{
+			name:     "unknown type",
+			specJSON: `{"type": "array", "items": {"type": "strnig"}}`,
+			wantErr:  "[]: unknown type: strnig",
+		},
+		{
+			name:     "unknown length unit",
+			specJSON: `{"type": "object", "properties": {"a": {"type": "string", "lengthUnit": "words"}}}`,
+			wantErr:  "a: unknown length unit: words",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.specJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+
+			_, err = Compile(spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCompiledSpecExportImport(t *testing.T) {
+	spec, err := LoadSpec("advanced_conditional.json")
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("failed to compile spec: %v", err)
+	}
+
+	exported, err := compiled.Export()
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+
+	imported, err := ImportCompiled(exported)
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+
+	if !reflect.DeepEqual(imported.patterns, compiled.patterns) {
+		t.Errorf("expected patterns %v, got %v", compiled.patterns, imported.patterns)
+	}
+	if !reflect.DeepEqual(imported.expressions, compiled.expressions) {
+		t.Errorf("expected expressions %v, got %v", compiled.expressions, imported.expressions)
+	}
+
+	testCases, err := LoadTestCases("advanced_conditional.json")
+	if err != nil {
+		t.Fatalf("failed to load test cases: %v", err)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			if result := imported.Validate(tc.Data); result.Valid != tc.ExpectedValid {
+				t.Errorf("expected valid=%v, got %v: %v", tc.ExpectedValid, result.Valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestImportCompiledErrors(t *testing.T) {
+	digest, err := SpecDigest(&Spec{Type: "string"})
+	if err != nil {
+		t.Fatalf("SpecDigest failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "not JSON", data: `{`, wantErr: "invalid compiled spec"},
+		{name: "wrong version", data: `{"version": 99, "spec": {"type": "string"}}`, wantErr: "unsupported compiled spec version 99"},
+		{name: "earlier version", data: `{"version": 1, "spec": {"type": "string"}}`, wantErr: "unsupported compiled spec version 1"},
+		{name: "missing spec", data: `{"version": 2}`, wantErr: "missing spec"},
+		{name: "missing digest", data: `{"version": 2, "spec": {"type": "string"}}`, wantErr: "digest mismatch"},
+		{name: "spec changed", data: `{"version": 2, "digest": "` + digest + `", "spec": {"type": "string", "minLength": 1}}`, wantErr: "digest mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportCompiled([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCompiledSpecPrograms(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"conditionDefs": {"big": "a > 100"},
+		"requiredIf": {"b": "big AND a < 1000"}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	var defs conditionDefScope
+	defs.push(spec.ConditionDefs)
+	key := programKey("big AND a < 1000", ExprLimits{}, defs, nil)
+	program, ok := compiled.programs.programs.Load(key)
+	if !ok {
+		t.Fatal("expected Compile to keep the program")
+	}
+
+	// Programs kept by the compiled spec outlive those in the shared cache
+	for i := range programCacheSize {
+		programCache.add(fmt.Sprint(i), &exprProgram{})
+	}
+	result := compiled.Validate(map[string]any{"a": 500.0})
+	if result.Valid || result.Errors[0].Code != CodeRequired {
+		t.Errorf("expected b to be required, got %v", result.Errors)
+	}
+	if reused, _ := compiled.programs.programs.Load(key); reused != program {
+		t.Error("expected validation to reuse the compiled program")
+	}
+
+	// Imported specs compile their programs on first use and keep them
+	exported, err := compiled.Export()
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	imported, err := ImportCompiled(exported)
+	if err != nil {
+		t.Fatalf("ImportCompiled failed: %v", err)
+	}
+	if _, ok := imported.programs.programs.Load(key); ok {
+		t.Error("expected ImportCompiled not to compile expressions")
+	}
+	if result := imported.Validate(map[string]any{"a": 500.0}); result.Valid {
+		t.Error("expected b to be required")
+	}
+	if _, ok := imported.programs.programs.Load(key); !ok {
+		t.Error("expected validation to keep the imported spec's program")
+	}
+}
+
+func TestCompiledSpecImmutable(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"name": {"type": "string", "maxLength": 3, "enum": ["Ada", "Bob"]}},
+		"required": ["name"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+
+	// Neither the original nor the returned copy changes the compiled spec
+	*spec.Properties["name"].MaxLength = 1
+	spec.Properties["name"].Enum[0] = "Eve"
+	spec.Required = nil
+	copied := compiled.Spec()
+	copied.Properties["name"] = &Spec{Type: "integer"}
+
+	if result := compiled.Validate(map[string]any{"name": "Ada"}); !result.Valid {
+		t.Errorf("expected the compiled spec to be unchanged, got errors: %v", result.Errors)
+	}
+	if result := compiled.Validate(map[string]any{}); result.Valid {
+		t.Error("expected name to still be required")
+	}
+	if copied == compiled.Spec() || copied.Properties["name"].Type != "integer" {
+		t.Error("expected Spec to return a modifiable copy")
+	}
+}
+
+// TestCompiledSpecConcurrent validates documents exercising conditions,
+// discriminators and transforms from many goroutines; run with -race
+func TestCompiledSpecConcurrent(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"kind": {"type": "string"},
+			"name": {"type": "string", "transform": ["trim"], "minLength": 1},
+			"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}}
+		},
+		"conditions": [{"if": "kind == 'person'", "then": {"name": {"maxLength": 5}}, "required": ["name"]}],
+		"discriminator": {"propertyName": "kind", "mapping": {
+			"person": {"properties": {"age": {"type": "integer", "min": 0}}},
+			"robot": {"properties": {"model": {"type": "string"}}, "required": ["model"]}
+		}}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("Compile returned error: %v", err)
+	}
+	v := NewValidator(WithTrace(), WithAnnotations())
+
+	documents := []struct {
+		doc   map[string]any
+		valid bool
+	}{
+		{doc: map[string]any{"kind": "person", "name": " Ada ", "age": 36.0, "tags": []any{"math"}}, valid: true},
+		{doc: map[string]any{"kind": "person", "name": "Augusta"}, valid: false},
+		{doc: map[string]any{"kind": "robot", "model": "R2", "tags": []any{"Droid"}}, valid: false},
+		{doc: map[string]any{"kind": "robot", "model": "R2"}, valid: true},
+	}
+
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				tt := documents[(i+j)%len(documents)]
+				if result := v.ValidateCompiled(tt.doc, compiled); result.Valid != tt.valid {
+					t.Errorf("expected valid %v for %v, got errors: %v", tt.valid, tt.doc, result.Errors)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := compiled.Spec(); !reflect.DeepEqual(got, spec) {
+		t.Error("expected validation to leave the compiled spec unchanged")
+	}
+}
+
+func BenchmarkLoadCompiledSpec(b *testing.B) {
+	data, err := os.ReadFile("testdata/specs/advanced_conditional.json")
+	if err != nil {
+		b.Fatalf("failed to read spec: %v", err)
+	}
+	spec, err := ParseSpec(data)
+	if err != nil {
+		b.Fatalf("failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		b.Fatalf("failed to compile spec: %v", err)
+	}
+	exported, err := compiled.Export()
+	if err != nil {
+		b.Fatalf("failed to export: %v", err)
+	}
+
+	b.Run("ParseSpec+Compile", func(b *testing.B) {
+		for b.Loop() {
+			spec, err := ParseSpec(data)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if _, err := Compile(spec); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ImportCompiled", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := ImportCompiled(exported); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package mowgli
+
+import (
+	"fmt"
+	"slices"
+)
+
+// conditional returns the spec to validate value against: spec combined with
+// its then branch if value is valid against its if subschema, or else with
+// its else branch, repeatedly if the branch has an if of its own
+func (r *ValidationResult) conditional(path string, value any, spec *Spec) *Spec {
+	var seen []*Spec
+	for spec.If != nil && !slices.Contains(seen, spec.If) {
+		seen = append(seen, spec.If)
+
+		branch, name := spec.Else, "else"
+		if r.matches(path, value, inheritTypes(spec.If, spec)) {
+			branch, name = spec.Then, "then"
+		}
+		r.addTrace(path, TraceIf, name, nil)
+		if branch == nil {
+			return withoutConditional(spec)
+		}
+		if branch.Ref != "" {
+			resolved, err := r.resolveRef(branch)
+			if err != nil {
+				r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"ref": branch.Ref})
+				return withoutConditional(spec)
+			}
+			branch = resolved
+		}
+		spec = r.combineConditional(spec, branch)
+	}
+	return spec
+}
+
+// combineConditional merges the branch an if selected into its spec like a
+// discriminator branch: the branch's keywords replace the spec's, and its
+// required properties, conditions and asserts add to the spec's
+func (r *ValidationResult) combineConditional(spec, branch *Spec) *Spec {
+	combined := r.mergeSpecs(withoutConditional(spec), branch)
+	combined.Required = append(slices.Clip(spec.Required), branch.Required...)
+	combined.Conditions = append(slices.Clip(spec.Conditions), branch.Conditions...)
+	combined.Asserts = append(slices.Clip(spec.Asserts), branch.Asserts...)
+	return combined
+}
+
+// withoutConditional returns a copy of spec without its if, then and else
+func withoutConditional(spec *Spec) *Spec {
+	copied := *spec
+	copied.If, copied.Then, copied.Else = nil, nil, nil
+	return &copied
+}
+
+// matches reports whether value is valid against spec without recording
+// anything, except for errors in the spec itself and exceeded limits
+func (r *ValidationResult) matches(path string, value any, spec *Spec) bool {
+	errors, warnings, valid := len(r.Errors), len(r.Warnings), r.Valid
+	transformed, times := len(r.transformed), len(r.times)
+	tracing, annotations, pending := r.tracing, r.annotations, r.pendingChecks
+	r.tracing, r.annotations, r.pendingChecks = false, nil, nil
+	defer func() {
+		r.tracing, r.annotations, r.pendingChecks = tracing, annotations, pending
+	}()
+
+	r.validate(path, value, spec)
+
+	found := slices.Clone(r.Errors[errors:])
+	r.Errors, r.Warnings, r.Valid = r.Errors[:errors], r.Warnings[:warnings], valid
+	r.transformed, r.times = r.transformed[:transformed], r.times[:times]
+	for _, err := range found {
+		if err.Code == CodeInvalidSpec || err.Code == CodeLimitExceeded {
+			r.Errors = append(r.Errors, err)
+			r.Valid = false
+		}
+	}
+	return len(found) == 0
+}
+
+// inheritTypes returns a copy of sub taking the types it leaves out, and
+// those of its properties, from spec
+func inheritTypes(sub, spec *Spec) *Spec {
+	if sub == nil || spec == nil {
+		return sub
+	}
+	inherited := *sub
+	if inherited.Type == "" && inherited.Ref == "" {
+		inherited.Type = spec.Type
+		if inherited.Nullable == nil {
+			inherited.Nullable = spec.Nullable
+		}
+	}
+	if sub.Properties != nil {
+		inherited.Properties = make(map[string]*Spec, len(sub.Properties))
+		for name, prop := range sub.Properties {
+			inherited.Properties[name] = inheritTypes(prop, spec.Properties[name])
+		}
+	}
+	return &inherited
+}
+
+// checkConditional reports then and else without if, and subschemas of
+// another type than their spec
+func checkConditional(spec *Spec) error {
+	if spec.If == nil {
+		if spec.Then != nil || spec.Else != nil {
+			return fmt.Errorf("then and else require if")
+		}
+		return nil
+	}
+	for _, sub := range []struct {
+		keyword string
+		spec    *Spec
+	}{{"if", spec.If}, {"then", spec.Then}, {"else", spec.Else}} {
+		if sub.spec != nil && sub.spec.Type != "" && spec.Type != "" && sub.spec.Type != spec.Type {
+			return fmt.Errorf("%s must be of type %s, not %s", sub.keyword, spec.Type, sub.spec.Type)
+		}
+	}
+	return nil
+}
+
+// reshaped returns the spec to validate obj against: the thenSpec or elseSpec
+// of the first condition selecting one, repeatedly if that spec has such
+// conditions of its own, or else spec itself
+func (r *ValidationResult) reshaped(path string, obj map[string]any, spec *Spec) *Spec {
+	var seen []*Spec
+	for {
+		shape := r.selectShape(path, obj, spec)
+		if shape == nil || slices.Contains(seen, shape) {
+			return spec
+		}
+		seen = append(seen, shape)
+
+		if shape.Ref != "" {
+			resolved, err := r.resolveRef(shape)
+			if err != nil {
+				r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"ref": shape.Ref})
+				return spec
+			}
+			shape = resolved
+		}
+		if shape.Type == "" {
+			copied := *shape
+			copied.Type = "object"
+			shape = &copied
+		}
+		spec = shape
+	}
+}
+
+// selectShape evaluates the conditions of spec that replace it, returning
+// the spec the first of them selects, or nil if none does
+func (r *ValidationResult) selectShape(path string, obj map[string]any, spec *Spec) *Spec {
+	if !slices.ContainsFunc(spec.Conditions, Condition.reshapes) {
+		return nil
+	}
+
+	env := r.conditionEnv(obj)
+	for _, condition := range spec.Conditions {
+		if !condition.reshapes() {
+			continue
+		}
+		expression := condition.Expression()
+		result, err := r.evalObjectExpr(path, TraceCondition, expression, env)
+		if err != nil {
+			r.logCondition(path, expression, err)
+			r.conditionFailed(path, "condition", expression, err)
+			continue
+		}
+
+		if result {
+			r.annotateCondition(path, condition)
+			if condition.ThenSpec != nil {
+				return condition.ThenSpec
+			}
+		} else if condition.ElseSpec != nil {
+			return condition.ElseSpec
+		}
+	}
+	return nil
+}
+
+// checkReshape reports conditions combining a replacement spec with
+// overrides, and replacement specs that aren't objects
+func checkReshape(condition Condition) error {
+	if !condition.reshapes() {
+		return nil
+	}
+	if condition.Then != nil || condition.Else != nil || condition.Required != nil {
+		return fmt.Errorf("condition '%s' can't have then, else or required along with thenSpec or elseSpec", condition.Expression())
+	}
+	for _, shape := range []*Spec{condition.ThenSpec, condition.ElseSpec} {
+		if shape != nil && shape.Type != "" && shape.Type != "object" {
+			return fmt.Errorf("condition '%s' must select an object spec, not %s", condition.Expression(), shape.Type)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package mowgli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIfThenElse(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"country": {"type": "string"},
+			"zip": {"type": "string"},
+			"postcode": {"type": "string"}
+		},
+		"if": {"properties": {"country": {"enum": ["US"]}}, "required": ["country"]},
+		"then": {"required": ["zip"], "properties": {"zip": {"pattern": "^[0-9]{5}$"}}},
+		"else": {"required": ["postcode"]}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		codes []string
+	}{
+		{"then applies", map[string]any{"country": "US", "zip": "12345"}, nil},
+		{"then required", map[string]any{"country": "US"}, []string{CodeRequired}},
+		{"then constraint", map[string]any{"country": "US", "zip": "1234"}, []string{CodePattern}},
+		{"else applies", map[string]any{"country": "GB", "postcode": "SW1A 1AA"}, nil},
+		{"else required", map[string]any{"country": "GB", "zip": "1234"}, []string{CodeRequired}},
+		{"if requires country", map[string]any{"postcode": "SW1A 1AA"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var codes []string
+			for _, e := range result.Errors {
+				codes = append(codes, e.Code)
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.codes, ",") {
+				t.Errorf("expected errors %v, got %v", tt.codes, result.Errors)
+			}
+		})
+	}
+}
+
+func TestIfThenElseScalars(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "integer",
+		"if": {"max": 99},
+		"then": {"messages": {"enum": "small values must be even"}, "enum": [0, 2, 4, 6, 8]},
+		"else": {"max": 1000}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		value any
+		valid bool
+	}{
+		{4.0, true},
+		{5.0, false},
+		{500.0, true},
+		{5000.0, false},
+		{"4", false},
+	}
+
+	for _, tt := range tests {
+		result := Validate(tt.value, spec)
+		if result.Valid != tt.valid {
+			t.Errorf("%v: expected valid=%v, got %v", tt.value, tt.valid, result.Errors)
+		}
+		if len(result.Errors) > 1 {
+			t.Errorf("%v: expected at most one error, got %v", tt.value, result.Errors)
+		}
+	}
+}
+
+func TestIfThenElseRecordsOnlyBranch(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "string",
+		"if": {"transform": ["upper"], "deprecated": true, "minLength": 3},
+		"then": {"pattern": "^[a-z]+$"}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := NewValidator(WithTrace()).Validate("abcd", spec)
+	if !result.Valid || len(result.Warnings) != 0 {
+		t.Fatalf("expected valid without warnings, got %v %v", result.Errors, result.Warnings)
+	}
+	if result.Document != "abcd" {
+		t.Errorf("expected the if subschema's transforms to be discarded, got %v", result.Document)
+	}
+
+	var branches []string
+	for _, event := range result.Trace() {
+		if event.Kind == TraceIf {
+			branches = append(branches, event.Detail)
+		}
+	}
+	if strings.Join(branches, ",") != "then" {
+		t.Errorf("expected the then branch to be traced, got %v", branches)
+	}
+}
+
+func TestCheckConditional(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"then without if", `{"type": "string", "then": {"minLength": 1}}`, "then and else require if"},
+		{"branch of other type", `{"type": "string", "if": {"minLength": 1}, "else": {"type": "number"}}`, "else must be of type string, not number"},
+		{"invalid branch", `{"type": "string", "if": {"minLength": 1}, "then": {"pattern": "("}}`, "invalid pattern"},
+		{"valid", `{"type": "string", "if": {"type": "string", "minLength": 1}, "then": {"pattern": "^a"}}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			_, err = Compile(spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestIfThenElseFromJSONSchema(t *testing.T) {
+	spec, err := FromJSONSchema([]byte(`{
+		"type": "object",
+		"properties": {"kind": {"type": "string"}, "amount": {"type": "number"}},
+		"if": {"properties": {"kind": {"const": "refund"}}},
+		"then": {"properties": {"amount": {"maximum": 0}}},
+		"else": {"properties": {"amount": {"minimum": 0}}}
+	}`))
+	if err != nil {
+		t.Fatalf("FromJSONSchema failed: %v", err)
+	}
+
+	if result := Validate(map[string]any{"kind": "refund", "amount": -5.0}, spec); !result.Valid {
+		t.Errorf("expected refund to be valid, got %v", result.Errors)
+	}
+	if result := Validate(map[string]any{"kind": "sale", "amount": -5.0}, spec); result.Valid {
+		t.Error("expected negative sale to be invalid")
+	}
+}
+
+func TestConditionThenSpec(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"kind": {"type": "string", "enum": ["order", "refund"]}},
+		"required": ["kind"],
+		"conditions": [
+			{"if": "kind == \"order\"", "thenSpec": {
+				"properties": {"kind": {"type": "string"}, "items": {"type": "array", "minLength": 1}},
+				"required": ["kind", "items"]
+			}},
+			{"if": "kind == \"refund\"", "thenSpec": {
+				"type": "object",
+				"properties": {"kind": {"type": "string"}, "orderId": {"type": "string"}, "amount": {"type": "number", "min": 0}},
+				"required": ["kind", "orderId", "amount"]
+			}}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		codes []string
+	}{
+		{"order", map[string]any{"kind": "order", "items": []any{"a"}}, nil},
+		{"order without items", map[string]any{"kind": "order"}, []string{CodeRequired}},
+		{"empty order", map[string]any{"kind": "order", "items": []any{}}, []string{CodeMinLength}},
+		{"refund", map[string]any{"kind": "refund", "orderId": "o1", "amount": 5.0}, nil},
+		{"refund with order fields", map[string]any{"kind": "refund", "items": []any{"a"}}, []string{CodeRequired, CodeRequired}},
+		{"unknown kind keeps the envelope", map[string]any{"kind": "other"}, []string{CodeEnum}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewValidator(WithAnnotations()).Validate(tt.data, spec)
+			var codes []string
+			for _, e := range result.Errors {
+				codes = append(codes, e.Code)
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.codes, ",") {
+				t.Errorf("expected errors %v, got %v", tt.codes, result.Errors)
+			}
+		})
+	}
+
+	result := NewValidator(WithAnnotations()).Validate(map[string]any{"kind": "refund", "orderId": "o1", "amount": 5.0}, spec)
+	if effective := result.Annotations().Specs[""]; effective == nil || effective.Properties["orderId"] == nil {
+		t.Errorf("expected the refund spec to be annotated, got %+v", effective)
+	}
+}
+
+func TestConditionElseSpecNested(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"legacy": {"type": "boolean"}},
+		"conditions": [{"if": "legacy == true", "elseSpec": {
+			"properties": {"version": {"type": "integer", "min": 2}},
+			"required": ["version"],
+			"conditions": [{"if": "version > 2", "thenSpec": {"properties": {"payload": {"type": "object"}}, "required": ["payload"]}}]
+		}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		valid bool
+	}{
+		{"legacy", map[string]any{"legacy": true}, true},
+		{"current without version", map[string]any{"legacy": false}, false},
+		{"version 2", map[string]any{"legacy": false, "version": 2.0}, true},
+		{"version 3 without payload", map[string]any{"legacy": false, "version": 3.0}, false},
+		{"version 3", map[string]any{"legacy": false, "version": 3.0, "payload": map[string]any{}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Validate(tt.data, spec); result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestCheckReshape(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"with overrides", `{"type": "object", "conditions": [{"if": "a", "thenSpec": {}, "required": ["b"]}]}`, "can't have then, else or required"},
+		{"not an object", `{"type": "object", "conditions": [{"if": "a", "elseSpec": {"type": "string"}}]}`, "must select an object spec, not string"},
+		{"invalid spec", `{"type": "object", "conditions": [{"if": "a", "thenSpec": {"properties": {"b": {"type": "string", "pattern": "("}}}}]}`, "invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			if _, err := Compile(spec); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
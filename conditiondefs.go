@@ -0,0 +1,188 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/conf"
+	"github.com/expr-lang/expr/parser"
+)
+
+// conditionDefScope holds the "conditionDefs" sections of the specs
+// enclosing the one being walked, innermost last
+type conditionDefScope []conditionDefs
+
+// conditionDefs is the "conditionDefs" section of a spec
+type conditionDefs struct {
+	defs map[string]string
+	// key identifies the definitions of the section and those enclosing it,
+	// for caching compiled expressions
+	key string
+}
+
+// push enters a spec's condition definitions, returning a func that leaves them
+func (s *conditionDefScope) push(defs map[string]string) func() {
+	encoded, _ := json.Marshal(defs) // maps of strings always marshal, with sorted keys
+	*s = append(*s, conditionDefs{defs: defs, key: s.key() + string(encoded)})
+	return func() { *s = (*s)[:len(*s)-1] }
+}
+
+// key identifies the definitions in the scope
+func (s conditionDefScope) key() string {
+	if len(s) == 0 {
+		return ""
+	}
+	return s[len(s)-1].key
+}
+
+// lookup returns the expression of a condition defined in the scope
+func (s conditionDefScope) lookup(name string) (string, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if def, ok := s[i].defs[name]; ok {
+			return def, true
+		}
+	}
+	return "", false
+}
+
+// expand replaces the names of the defined conditions node uses with their
+// expressions, within limits: definitions may be at most limits.MaxLength
+// bytes long, and node may have at most limits.MaxNodes nodes (expr's
+// default if 0) once expanded
+func (s conditionDefScope) expand(node *ast.Node, limits ExprLimits) error {
+	expander := s.expander(limits)
+	ast.Walk(node, expander)
+	return expander.err
+}
+
+// expander returns a patch expanding the defined conditions of an expression
+// within limits, see expand
+func (s conditionDefScope) expander(limits ExprLimits) *conditionDefExpander {
+	return &conditionDefExpander{defs: s, budget: newExpansionBudget(limits)}
+}
+
+// parse parses the named condition with the conditions it uses expanded,
+// failing if the definitions refer to each other in a cycle
+func (s conditionDefScope) parse(name string, expanding []string, budget *expansionBudget) (ast.Node, error) {
+	if slices.Contains(expanding, name) {
+		return nil, fmt.Errorf("condition %s refers to itself: %s", name, strings.Join(append(expanding, name), " -> "))
+	}
+	def, _ := s.lookup(name)
+	if budget.maxLength > 0 && len(def) > budget.maxLength {
+		return nil, fmt.Errorf("condition %s is %d bytes long, exceeding the limit of %d", name, len(def), budget.maxLength)
+	}
+	tree, err := parser.Parse(translateToExpr(def))
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %s '%s': %w", name, def, err)
+	}
+	expander := &conditionDefExpander{defs: s, expanding: append(slices.Clip(expanding), name), budget: budget}
+	ast.Walk(&tree.Node, expander)
+	if expander.err != nil {
+		return nil, expander.err
+	}
+	return tree.Node, nil
+}
+
+// check reports definitions in the innermost section that don't parse,
+// refer to each other in a cycle or expand to too large an expression
+func (s conditionDefScope) check() error {
+	if len(s) == 0 {
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(s[len(s)-1].defs)) {
+		if !identifierPattern.MatchString(name) {
+			return fmt.Errorf("invalid condition name %q", name)
+		}
+		if _, err := s.parse(name, nil, newExpansionBudget(ExprLimits{})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// references returns the field references of an expression, with those of
+// the defined conditions it uses in place of their names
+func (s conditionDefScope) references(exprStr string) ([]string, error) {
+	tree, err := parser.Parse(translateToExpr(exprStr))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse expression '%s': %w", exprStr, err)
+	}
+	if err := s.expand(&tree.Node, ExprLimits{}); err != nil {
+		return nil, err
+	}
+	return nodeReferences(tree.Node), nil
+}
+
+// evalCondition evaluates an expression of the spec being validated in env,
+// with the conditions defined by it and its enclosing specs. In strict mode,
+// expressions referencing missing fields fail with a MissingFieldError.
+func (r *ValidationResult) evalCondition(expression string, env map[string]any) (bool, error) {
+	if r.strictExpressions {
+		missing, err := r.missingReference(strings.TrimSpace(expression), env)
+		if err != nil {
+			return false, err
+		}
+		if missing != "" {
+			return false, &MissingFieldError{Field: missing}
+		}
+	}
+	return evalExpressionDefs(expression, env, r.exprLimits, r.conditionDefs, r.exprFuncs, r.programs)
+}
+
+// expansionBudget bounds the expansion of defined conditions. Conditions
+// using others more than once grow exponentially when expanded, e.g.
+// "d1": "d0 AND d0", "d2": "d1 AND d1" and so on, so the expansion stops as
+// soon as it exceeds the budget rather than after building it.
+type expansionBudget struct {
+	maxLength int
+	maxNodes  uint
+	nodes     uint
+}
+
+// newExpansionBudget returns the budget for expanding an expression within limits
+func newExpansionBudget(limits ExprLimits) *expansionBudget {
+	maxNodes := limits.MaxNodes
+	if maxNodes == 0 {
+		maxNodes = conf.DefaultMaxNodes
+	}
+	return &expansionBudget{maxLength: limits.MaxLength, maxNodes: maxNodes}
+}
+
+// conditionDefExpander replaces the names of defined conditions in an
+// expression with their expressions
+type conditionDefExpander struct {
+	defs      conditionDefScope
+	expanding []string
+	budget    *expansionBudget
+	err       error
+}
+
+func (e *conditionDefExpander) Visit(node *ast.Node) {
+	if e.err != nil {
+		return
+	}
+	// Nodes are visited once each, those of expanded conditions while
+	// parsing them
+	e.budget.nodes++
+	if e.budget.nodes > e.budget.maxNodes {
+		e.err = fmt.Errorf("expression with its conditions expanded exceeds maximum allowed nodes (%d)", e.budget.maxNodes)
+		return
+	}
+	identifier, ok := (*node).(*ast.IdentifierNode)
+	if !ok {
+		return
+	}
+	if _, defined := e.defs.lookup(identifier.Value); !defined {
+		return
+	}
+	expanded, err := e.defs.parse(identifier.Value, e.expanding, e.budget)
+	if err != nil {
+		e.err = err
+		return
+	}
+	ast.Patch(node, expanded)
+}
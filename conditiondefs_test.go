@@ -0,0 +1,147 @@
+package mowgli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConditionDefs(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"conditionDefs": {
+			"isMinorUS": "age < 18 AND country == \"US\"",
+			"needsGuardian": "isMinorUS AND emancipated != true"
+		},
+		"properties": {
+			"age": {"type": "integer"},
+			"country": {"type": "string"},
+			"emancipated": {"type": "boolean"},
+			"guardian": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "phone": {"type": "string"}},
+				"requiredIf": {"phone": "$parent.country == \"US\" AND isAdult"},
+				"conditionDefs": {"isAdult": "$parent.age >= 18"}
+			}
+		},
+		"requiredIf": {"guardian": "needsGuardian"},
+		"conditions": [{"if": "isMinorUS", "then": {"age": {"min": 13}}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	compiled, err := Compile(spec)
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		codes []string
+	}{
+		{"adult", map[string]any{"age": 30.0, "country": "US", "emancipated": false}, nil},
+		{"minor without guardian", map[string]any{"age": 15.0, "country": "US", "emancipated": false}, []string{CodeRequired}},
+		{"emancipated minor", map[string]any{"age": 15.0, "country": "US", "emancipated": true}, nil},
+		{"young minor", map[string]any{"age": 10.0, "country": "US", "emancipated": true}, []string{CodeMin}},
+		{"minor elsewhere", map[string]any{"age": 10.0, "country": "FR", "emancipated": false}, nil},
+		{"adult with guardian", map[string]any{"age": 30.0, "country": "US", "emancipated": false, "guardian": map[string]any{}}, []string{CodeRequired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := compiled.Validate(tt.data)
+			var codes []string
+			for _, e := range result.Errors {
+				codes = append(codes, e.Code)
+			}
+			if strings.Join(codes, ",") != strings.Join(tt.codes, ",") {
+				t.Errorf("expected errors %v, got %v", tt.codes, result.Errors)
+			}
+		})
+	}
+}
+
+func TestConditionDefsCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		defs    string
+		wantErr string
+	}{
+		{"cycle", `{"a": "b AND x", "b": "!a"}`, "condition a refers to itself: a -> b -> a"},
+		{"self reference", `{"a": "a OR x"}`, "condition a refers to itself"},
+		{"invalid expression", `{"a": "x >"}`, "invalid condition a"},
+		{"invalid name", `{"is-minor": "age < 18"}`, `invalid condition name "is-minor"`},
+		{"valid", `{"a": "x > 1", "b": "a OR y"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(`{"type": "object", "conditionDefs": ` + tt.defs + `}`)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			_, err = Compile(spec)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConditionDefsReferences(t *testing.T) {
+	var defs conditionDefScope
+	defs.push(map[string]string{"isMinorUS": "age < 18 AND country == \"US\""})
+	defs.push(map[string]string{"flagged": "isMinorUS OR $root.review"})
+	refs, err := defs.references("flagged AND any(items, .price > 0)")
+	if err != nil {
+		t.Fatalf("references failed: %v", err)
+	}
+	if got := strings.Join(refs, ","); got != "$root.review,age,country,items" {
+		t.Errorf("unexpected references: %s", got)
+	}
+}
+
+func TestConditionDefsExpansionLimits(t *testing.T) {
+	// Each condition uses the previous one twice, doubling the size of the
+	// expansion at every level
+	defs := map[string]string{"d0": "x > 0 AND y > 0"}
+	for i := 1; i < 18; i++ {
+		defs[fmt.Sprintf("d%d", i)] = fmt.Sprintf("d%d AND d%d", i-1, i-1)
+	}
+	spec := &Spec{
+		Type:          "object",
+		ConditionDefs: defs,
+		Properties:    map[string]*Spec{"z": {Type: "string"}},
+		RequiredIf:    map[string]string{"z": "d17"},
+	}
+
+	if _, err := Compile(spec); err == nil || !strings.Contains(err.Error(), "exceeds maximum allowed nodes") {
+		t.Errorf("expected Compile to fail on the expansion size, got %v", err)
+	}
+
+	v := NewValidator(WithExprLimits(ExprLimits{MaxLength: 100, MaxNodes: 500, Timeout: 100 * time.Millisecond}))
+	start := time.Now()
+	result := v.Validate(map[string]any{"x": 1.0, "y": 1.0}, spec)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expansion took %s", elapsed)
+	}
+	if result.Valid || result.Errors[0].Code != CodeCondition ||
+		!strings.Contains(result.Errors[0].Message, "exceeds maximum allowed nodes (500)") {
+		t.Errorf("expected a condition error on the expansion size, got %v", result.Errors)
+	}
+
+	defs["long"] = strings.Repeat("x > 0 AND ", 20) + "y > 0"
+	spec.RequiredIf["z"] = "long"
+	result = v.Validate(map[string]any{"x": 1.0, "y": 1.0}, spec)
+	if result.Valid || !strings.Contains(result.Errors[0].Message, "condition long is 205 bytes long, exceeding the limit of 100") {
+		t.Errorf("expected a condition error on the definition length, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,176 @@
+// Package configval validates application configuration loaded with viper,
+// koanf or similar libraries against a mowgli spec.
+//
+// Both libraries expose the merged configuration as a nested map:
+// viper.AllSettings() and koanf.Raw(). That map is validated as an object,
+// and errors are addressed by config key, e.g. "server.port" or
+// "upstreams.0.url", so they can be reported with the key the user has to
+// fix. The package doesn't import either library; any value with the
+// right method will do.
+package configval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/matjam/mowgli"
+)
+
+// KeyError is a validation error for one config key
+type KeyError struct {
+	Key string // Config key of the invalid value, "" for errors about the whole config
+	*mowgli.ValidationError
+}
+
+func (e *KeyError) Error() string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// Errors is the list of errors of an invalid config. It is an error itself,
+// so Validate can be returned straight from a config loading function.
+type Errors []*KeyError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return "invalid config: " + strings.Join(messages, "; ")
+}
+
+// Viper is the part of *viper.Viper that ValidateViper uses
+type Viper interface {
+	AllSettings() map[string]any
+}
+
+// Koanf is the part of *koanf.Koanf that ValidateKoanf uses
+type Koanf interface {
+	Raw() map[string]any
+}
+
+// Option configures validation
+type Option func(*options)
+
+type options struct {
+	validator *mowgli.Validator
+	delimiter string
+}
+
+// WithValidator validates with v instead of the default validator, e.g. to
+// use its registry or expression functions
+func WithValidator(v *mowgli.Validator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// WithDelimiter sets the delimiter between the parts of config keys. The
+// default is ".", as in both viper and koanf.
+func WithDelimiter(delim string) Option {
+	return func(o *options) {
+		o.delimiter = delim
+	}
+}
+
+// ValidateViper validates the settings of a viper instance. Viper lowercases
+// keys, so the spec's property names must be lowercase.
+func ValidateViper(v Viper, spec *mowgli.Spec, opts ...Option) error {
+	return Validate(v.AllSettings(), spec, opts...)
+}
+
+// ValidateKoanf validates the settings of a koanf instance. If it was created
+// with a delimiter other than ".", pass it with WithDelimiter.
+func ValidateKoanf(k Koanf, spec *mowgli.Spec, opts ...Option) error {
+	return Validate(k.Raw(), spec, opts...)
+}
+
+// Validate validates a nested settings map and returns its errors as Errors,
+// or nil if it's valid.
+//
+// Values set from environment variables or flags are often strings whatever
+// their type, so strings are converted to the number, integer or boolean a
+// property declares before validation. Strings that can't be converted are
+// left as they are and reported as type errors.
+func Validate(settings map[string]any, spec *mowgli.Spec, opts ...Option) error {
+	o := options{validator: mowgli.NewValidator(), delimiter: "."}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if settings == nil {
+		settings = map[string]any{}
+	}
+
+	result := o.validator.Validate(convert(settings, spec), spec)
+	if result.Valid {
+		return nil
+	}
+	errs := make(Errors, len(result.Errors))
+	for i, err := range result.Errors {
+		errs[i] = &KeyError{Key: Key(err.Path, o.delimiter), ValidationError: err}
+	}
+	return errs
+}
+
+// Key converts a mowgli error path such as "upstreams[0].url" to a config
+// key such as "upstreams.0.url", with parts separated by delim
+func Key(path, delim string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; c {
+		case ']':
+		case '.':
+			b.WriteString(delim)
+		case '[':
+			if i > 0 {
+				b.WriteString(delim)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// convert returns a copy of value with strings converted to the scalar types
+// spec declares for them
+func convert(value any, spec *mowgli.Spec) any {
+	if spec == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		converted := make(map[string]any, len(v))
+		for key, item := range v {
+			propSpec := spec.Properties[key]
+			if propSpec == nil {
+				propSpec = spec.AdditionalProperties
+			}
+			converted[key] = convert(item, propSpec)
+		}
+		return converted
+	case []any:
+		converted := make([]any, len(v))
+		for i, item := range v {
+			converted[i] = convert(item, spec.Items)
+		}
+		return converted
+	case string:
+		text := strings.TrimSpace(v)
+		switch spec.Type {
+		case "number", "integer":
+			if f, err := strconv.ParseFloat(text, 64); err == nil {
+				return f
+			}
+		case "boolean":
+			if b, err := strconv.ParseBool(text); err == nil {
+				return b
+			}
+		}
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package configval
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/matjam/mowgli"
+)
+
+const configSpec = `{
+	"type": "object",
+	"properties": {
+		"server": {
+			"type": "object",
+			"properties": {
+				"port": {"type": "integer", "min": 1, "max": 65535},
+				"tls": {"type": "boolean"}
+			},
+			"required": ["port"]
+		},
+		"upstreams": {
+			"type": "array",
+			"items": {"type": "object", "properties": {"url": {"type": "string", "format": "uri"}}, "required": ["url"]}
+		}
+	},
+	"required": ["server"]
+}`
+
+// fakeViper and fakeKoanf stand in for *viper.Viper and *koanf.Koanf
+type fakeViper map[string]any
+
+func (v fakeViper) AllSettings() map[string]any { return v }
+
+type fakeKoanf map[string]any
+
+func (k fakeKoanf) Raw() map[string]any { return k }
+
+func TestValidate(t *testing.T) {
+	spec, err := mowgli.ParseSpecString(configSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     []string // "key: code"
+	}{
+		{
+			name: "valid",
+			settings: map[string]any{
+				"server":    map[string]any{"port": 8080, "tls": true},
+				"upstreams": []any{map[string]any{"url": "https://example.com"}},
+			},
+		},
+		{
+			name:     "strings from the environment",
+			settings: map[string]any{"server": map[string]any{"port": "8080", "tls": "false"}},
+		},
+		{name: "empty", settings: nil, want: []string{"server: required"}},
+		{name: "nested", settings: map[string]any{"server": map[string]any{"port": 70000}}, want: []string{"server.port: max"}},
+		{name: "not a number", settings: map[string]any{"server": map[string]any{"port": "http"}}, want: []string{"server.port: type"}},
+		{
+			name: "array item",
+			settings: map[string]any{
+				"server":    map[string]any{"port": 80},
+				"upstreams": []any{map[string]any{"url": "https://example.com"}, map[string]any{}},
+			},
+			want: []string{"upstreams.1.url: required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.settings, spec)
+			var got []string
+			var errs Errors
+			if errors.As(err, &errs) {
+				for _, e := range errs {
+					got = append(got, e.Key+": "+e.Code)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateSources(t *testing.T) {
+	spec, err := mowgli.ParseSpecString(configSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	err = ValidateViper(fakeViper{"server": map[string]any{"port": 0}}, spec)
+	if err == nil || err.Error() != "invalid config: server.port: integer 0 is less than minimum 1" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err = ValidateKoanf(fakeKoanf{"server": map[string]any{}}, spec, WithDelimiter("/"))
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Key != "server/port" {
+		t.Errorf("expected an error for server/port, got %v", err)
+	}
+
+	if err := ValidateKoanf(fakeKoanf{"server": map[string]any{"port": 443}}, spec); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		path, delim, want string
+	}{
+		{"", ".", ""},
+		{"server.port", ".", "server.port"},
+		{"upstreams[0].url", ".", "upstreams.0.url"},
+		{"matrix[1][2]", ".", "matrix.1.2"},
+		{"[3].name", ".", "3.name"},
+		{"server.port", "/", "server/port"},
+	}
+	for _, tt := range tests {
+		if got := Key(tt.path, tt.delim); got != tt.want {
+			t.Errorf("Key(%q, %q) = %q, want %q", tt.path, tt.delim, got, tt.want)
+		}
+	}
+}
+
+func TestValidateKeepsSettings(t *testing.T) {
+	spec, err := mowgli.ParseSpecString(configSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	settings := map[string]any{"server": map[string]any{"port": "8080"}}
+	if err := Validate(settings, spec); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if port := settings["server"].(map[string]any)["port"]; port != "8080" {
+		t.Errorf("expected settings to be left unchanged, got %v", port)
+	}
+}
@@ -0,0 +1,81 @@
+package mowgli
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// contentEncodings decode the encodings supported by "contentEncoding"
+var contentEncodings = map[string]func(string) ([]byte, error){
+	"base64":    base64.StdEncoding.DecodeString,
+	"base64url": base64.URLEncoding.DecodeString,
+}
+
+// isJSONMediaType reports whether mediaType is application/json or a
+// +json type such as application/problem+json
+func isJSONMediaType(mediaType string) bool {
+	mediaType, _, _ = strings.Cut(mediaType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	return mediaType == "application/json" || (strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+// validateContent decodes a string carrying an encoded or embedded document
+// and validates the content against the spec's contentSchema. Errors in the
+// content have paths below the string's path.
+func (r *ValidationResult) validateContent(path, str string, spec *Spec) {
+	content := []byte(str)
+	if spec.ContentEncoding != "" {
+		decode, ok := contentEncodings[spec.ContentEncoding]
+		if !ok {
+			r.addError(path, CodeInvalidSpec, fmt.Sprintf("unknown content encoding: %s", spec.ContentEncoding), nil)
+			return
+		}
+		decoded, err := decode(str)
+		if err != nil {
+			r.addError(path, CodeContentEncoding, fmt.Sprintf("string is not valid %s", spec.ContentEncoding),
+				map[string]any{"encoding": spec.ContentEncoding})
+			return
+		}
+		content = decoded
+	}
+
+	var document any = string(content)
+	if spec.ContentMediaType != "" {
+		if !isJSONMediaType(spec.ContentMediaType) {
+			r.addError(path, CodeInvalidSpec, fmt.Sprintf("unsupported content media type: %s", spec.ContentMediaType), nil)
+			return
+		}
+		var err error
+		if document, err = decodeJSON(content, r.useNumber); err != nil {
+			r.addError(path, CodeContentMediaType, fmt.Sprintf("content is not valid %s: %v", spec.ContentMediaType, err),
+				map[string]any{"mediaType": spec.ContentMediaType})
+			return
+		}
+	}
+
+	if spec.ContentSchema != nil {
+		r.validateDocument(path, document, spec.ContentSchema)
+	}
+}
+
+// validateDocument validates a document embedded in the one being
+// validated. The embedded document is its own $root, and transforms and
+// time layouts in its spec don't change the result's Document.
+func (r *ValidationResult) validateDocument(path string, document any, spec *Spec) {
+	root, objects, segments, transformed, times := r.root, r.objects, r.segments, r.transformed, r.times
+	r.root, r.objects, r.segments, r.transformed, r.times = document, nil, nil, nil, nil
+	r.validate(path, document, spec)
+	r.root, r.objects, r.segments, r.transformed, r.times = root, objects, segments, transformed, times
+}
+
+// checkContent reports unknown content encodings and media types
+func checkContent(spec *Spec) error {
+	if _, ok := contentEncodings[spec.ContentEncoding]; spec.ContentEncoding != "" && !ok {
+		return fmt.Errorf("unknown content encoding: %s", spec.ContentEncoding)
+	}
+	if spec.ContentMediaType != "" && !isJSONMediaType(spec.ContentMediaType) {
+		return fmt.Errorf("unsupported content media type: %s", spec.ContentMediaType)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package mowgli
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateContent(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"payload": {
+				"type": "string",
+				"contentEncoding": "base64",
+				"contentMediaType": "application/json",
+				"contentSchema": {
+					"type": "object",
+					"properties": {"user": {"type": "string", "minLength": 2}},
+					"required": ["user"]
+				}
+			},
+			"token": {"type": "string", "contentEncoding": "base64url"},
+			"raw": {"type": "string", "contentMediaType": "application/vnd.api+json", "contentSchema": {"type": "array"}}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	encode := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name       string
+		data       map[string]any
+		wantErrors []string // path:code
+	}{
+		{name: "valid content", data: map[string]any{"payload": encode(`{"user": "ada"}`), "token": "_-8=", "raw": `[1, 2]`}},
+		{name: "invalid base64", data: map[string]any{"payload": "not base64!"}, wantErrors: []string{"payload:contentEncoding"}},
+		{name: "invalid base64url", data: map[string]any{"token": "+/8="}, wantErrors: []string{"token:contentEncoding"}},
+		{name: "invalid JSON", data: map[string]any{"payload": encode(`{"user":`)}, wantErrors: []string{"payload:contentMediaType"}},
+		{name: "invalid inner document", data: map[string]any{"payload": encode(`{"user": "a"}`)}, wantErrors: []string{"payload.user:minLength"}},
+		{name: "missing inner field", data: map[string]any{"payload": encode(`{}`)}, wantErrors: []string{"payload.user:required"}},
+		{name: "embedded JSON without encoding", data: map[string]any{"raw": `{}`}, wantErrors: []string{"raw:type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+":"+err.Code)
+			}
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateContentRoot(t *testing.T) {
+	// The content is its own $root for conditions
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"kind": {"type": "string"},
+			"body": {
+				"type": "string",
+				"contentMediaType": "application/json",
+				"contentSchema": {
+					"type": "object",
+					"properties": {"kind": {"type": "string"}, "n": {"type": "integer"}},
+					"conditions": [{"if": "$root.kind == \"inner\"", "required": ["n"]}]
+				}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	if result := Validate(map[string]any{"kind": "inner", "body": `{"kind": "other"}`}, spec); !result.Valid {
+		t.Errorf("expected valid, got %v", result.Errors)
+	}
+	if result := Validate(map[string]any{"kind": "other", "body": `{"kind": "inner"}`}, spec); result.Valid {
+		t.Error("expected the inner document's condition to apply")
+	}
+}
+
+func TestCompileContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *Spec
+		wantErr string
+	}{
+		{name: "unknown encoding", spec: &Spec{Type: "string", ContentEncoding: "rot13"}, wantErr: "unknown content encoding: rot13"},
+		{name: "unsupported media type", spec: &Spec{Type: "string", ContentMediaType: "text/csv"}, wantErr: "unsupported content media type: text/csv"},
+		{name: "invalid content schema", spec: &Spec{Type: "string", ContentSchema: &Spec{Type: "strnig"}}, wantErr: "(content): unknown type: strnig"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile(tt.spec); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if result := Validate("e30=", tt.spec); result.Valid {
+				t.Error("expected validation to fail")
+			}
+		})
+	}
+}
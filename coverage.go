@@ -0,0 +1,338 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kinds of coverage items
+const (
+	CoverageConstraint    = "constraint"    // Covered when a document violates it
+	CoverageEnum          = "enum"          // Covered when a document has the value
+	CoverageCondition     = "condition"     // Covered when a condition or requiredIf expression evaluates to the outcome
+	CoverageDiscriminator = "discriminator" // Covered when a document selects the branch
+)
+
+// CoverageItem is a part of a spec that documents can exercise
+type CoverageItem struct {
+	// Path is the path of the value, with "[]" for array items and "*" for
+	// map values, as in Describe, e.g. "items[].sku"
+	Path string `json:"path"`
+	Kind string `json:"kind"` // One of the Coverage* kinds
+	// Detail identifies the item at its path: the error code of a
+	// constraint, the JSON of an enum value, an expression with its outcome
+	// such as "age < 18 is true", or the value selecting a branch
+	Detail  string `json:"detail"`
+	Covered bool   `json:"covered"`
+}
+
+func (c CoverageItem) String() string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s %s", path, c.Kind, c.Detail)
+}
+
+// CoverageReport reports which parts of a spec a set of documents exercised
+type CoverageReport struct {
+	Documents int            `json:"documents"`
+	Items     []CoverageItem `json:"items"` // In spec order, depth-first
+}
+
+// Uncovered returns the items no document exercised
+func (c *CoverageReport) Uncovered() []CoverageItem {
+	var uncovered []CoverageItem
+	for _, item := range c.Items {
+		if !item.Covered {
+			uncovered = append(uncovered, item)
+		}
+	}
+	return uncovered
+}
+
+// Percent returns the share of items covered, from 0 to 100. A spec without
+// items is fully covered.
+func (c *CoverageReport) Percent() float64 {
+	if len(c.Items) == 0 {
+		return 100
+	}
+	return 100 * float64(len(c.Items)-len(c.Uncovered())) / float64(len(c.Items))
+}
+
+// Coverage runs documents through spec and reports which of its
+// constraints, enum values, condition outcomes and discriminator branches
+// they exercised. See Validator.Coverage.
+func Coverage(spec *Spec, documents []any) *CoverageReport {
+	return defaultValidator.Coverage(spec, documents)
+}
+
+// Coverage runs documents, such as test fixtures, through spec and reports
+// which parts of the contract they exercise: a constraint is covered when a
+// document violates it, an enum value when a document uses it, each outcome
+// of a condition or requiredIf expression when a document produces it, and
+// a discriminator branch when a document selects it. Fixtures covering every
+// item check both sides of the contract.
+func (v *Validator) Coverage(spec *Spec, documents []any) *CoverageReport {
+	c := &coverage{resolver: v.newResult(nil), index: make(map[coverageKey]int)}
+	if spec != nil {
+		c.walk("", spec, nil)
+	}
+
+	for _, doc := range documents {
+		r := v.newResult(doc)
+		r.tracing = true
+		r.run(spec)
+
+		for _, err := range slices.Concat(r.Errors, r.Warnings) {
+			c.cover(c.normalize(spec, err.Path), CoverageConstraint, err.Code)
+		}
+		for _, event := range r.trace {
+			path := c.normalize(spec, event.Path)
+			switch event.Kind {
+			case TraceSpec:
+				if value, ok := valueAtPath(doc, event.Path); ok {
+					c.cover(path, CoverageEnum, coverageValue(value))
+				}
+			case TraceCondition, TraceRequiredIf:
+				if result, ok := event.Result.(bool); ok {
+					c.cover(path, CoverageCondition, conditionOutcome(event.Detail, result))
+				}
+			case TraceDiscriminator:
+				_, value, _ := strings.Cut(event.Detail, "=")
+				c.cover(path, CoverageDiscriminator, value)
+			}
+		}
+	}
+
+	return &CoverageReport{Documents: len(documents), Items: c.items}
+}
+
+// coverageKey identifies a coverage item
+type coverageKey struct {
+	path, kind, detail string
+}
+
+// coverage collects the items of a spec and marks those documents cover
+type coverage struct {
+	resolver *ValidationResult // Resolves $ref with the Validator's registry
+	items    []CoverageItem
+	index    map[coverageKey]int
+}
+
+// add adds an item unless the spec already has it, e.g. from another
+// condition overriding the same property
+func (c *coverage) add(path, kind, detail string) {
+	key := coverageKey{path, kind, detail}
+	if _, exists := c.index[key]; !exists {
+		c.index[key] = len(c.items)
+		c.items = append(c.items, CoverageItem{Path: path, Kind: kind, Detail: detail})
+	}
+}
+
+// cover marks an item covered, if the spec has it
+func (c *coverage) cover(path, kind, detail string) {
+	if i, exists := c.index[coverageKey{path, kind, detail}]; exists {
+		c.items[i].Covered = true
+	}
+}
+
+// walk adds the items of spec at path and of the specs below it. refs holds
+// the references being walked, so recursive specs end.
+func (c *coverage) walk(path string, spec *Spec, refs []string) {
+	if spec.Ref != "" {
+		if slices.Contains(refs, spec.Ref) {
+			return
+		}
+		resolved, err := c.resolver.resolveRef(spec)
+		if err != nil {
+			return
+		}
+		refs = append(refs, spec.Ref)
+		spec = resolved
+	}
+
+	for _, constraint := range describeConstraints(spec) {
+		if _, isCode := errorKinds[constraint.Kind]; !isCode || constraint.Value == false {
+			continue
+		}
+		switch constraint.Kind {
+		case CodeRequired, CodeDiscriminator, CodeAssert:
+			// Reported at the property's path, added below
+		case CodeEnum:
+			c.add(path, CoverageConstraint, CodeEnum)
+			for _, value := range spec.Enum {
+				c.add(path, CoverageEnum, coverageValue(value))
+			}
+		default:
+			c.add(path, CoverageConstraint, constraint.Kind)
+		}
+	}
+	for _, assert := range spec.Asserts {
+		c.add(buildPath(path, assert.Path), CoverageConstraint, CodeAssert)
+	}
+	for _, name := range spec.Required {
+		c.add(buildPath(path, name), CoverageConstraint, CodeRequired)
+	}
+	for _, name := range slices.Sorted(maps.Keys(spec.RequiredIf)) {
+		namePath := buildPath(path, name)
+		c.add(namePath, CoverageCondition, conditionOutcome(spec.RequiredIf[name], true))
+		c.add(namePath, CoverageCondition, conditionOutcome(spec.RequiredIf[name], false))
+		c.add(namePath, CoverageConstraint, CodeRequired)
+	}
+
+	for _, name := range sortedKeys(spec.Properties) {
+		if prop := spec.Properties[name]; prop != nil {
+			c.walk(buildPath(path, name), prop, refs)
+		}
+	}
+	if spec.Items != nil {
+		c.walk(path+"[]", spec.Items, refs)
+	}
+	if spec.AdditionalProperties != nil {
+		c.walk(buildPath(path, "*"), spec.AdditionalProperties, refs)
+	}
+
+	for _, condition := range spec.Conditions {
+		c.add(path, CoverageCondition, conditionOutcome(condition.Expression(), true))
+		c.add(path, CoverageCondition, conditionOutcome(condition.Expression(), false))
+		for _, name := range condition.Required {
+			c.add(buildPath(path, name), CoverageConstraint, CodeRequired)
+		}
+		for _, overrides := range []map[string]*Spec{condition.Then, condition.Else} {
+			for _, name := range sortedKeys(overrides) {
+				if override := overrides[name]; override != nil {
+					c.walk(buildPath(path, name), override, refs)
+				}
+			}
+		}
+		for _, shape := range []*Spec{condition.ThenSpec, condition.ElseSpec} {
+			if shape != nil {
+				c.walk(path, shape, refs)
+			}
+		}
+	}
+
+	if d := spec.Discriminator; d != nil {
+		c.add(buildPath(path, d.PropertyName), CoverageConstraint, CodeDiscriminator)
+		for _, key := range sortedKeys(d.Mapping) {
+			c.add(path, CoverageDiscriminator, key)
+		}
+		for _, key := range sortedKeys(d.Mapping) {
+			if branch := d.Mapping[key]; branch != nil {
+				c.walk(path, branch, refs)
+			}
+		}
+	}
+}
+
+// normalize converts the path of a value in a document to the path of the
+// spec describing it, e.g. "items[2].sku" to "items[].sku" and "labels.env"
+// to "labels.*" for a map
+func (c *coverage) normalize(spec *Spec, path string) string {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return path
+	}
+
+	normalized := ""
+	for _, segment := range segments {
+		if spec != nil && spec.Ref != "" {
+			spec, _ = c.resolver.resolveRef(spec)
+		}
+		if spec == nil {
+			normalized = buildPath(normalized, segment)
+			continue
+		}
+		if _, err := strconv.Atoi(segment); err == nil && spec.Items != nil {
+			normalized += "[]"
+			spec = spec.Items
+			continue
+		}
+
+		child, declared := declaredProperty(spec, segment)
+		switch {
+		case declared:
+			normalized = buildPath(normalized, segment)
+		case spec.AdditionalProperties != nil:
+			normalized = buildPath(normalized, "*")
+			child = spec.AdditionalProperties
+		default:
+			normalized = buildPath(normalized, segment)
+		}
+		spec = child
+	}
+	return normalized
+}
+
+// declaredProperty returns the spec of a property declared by spec, its
+// conditions or its discriminator branches
+func declaredProperty(spec *Spec, name string) (*Spec, bool) {
+	if prop, ok := spec.Properties[name]; ok {
+		return prop, true
+	}
+	for _, condition := range spec.Conditions {
+		if prop, ok := condition.Then[name]; ok {
+			return prop, true
+		}
+		if prop, ok := condition.Else[name]; ok {
+			return prop, true
+		}
+	}
+	if d := spec.Discriminator; d != nil {
+		for _, key := range sortedKeys(d.Mapping) {
+			if branch := d.Mapping[key]; branch != nil {
+				if prop, ok := declaredProperty(branch, name); ok {
+					return prop, true
+				}
+			}
+		}
+	}
+	return nil, false
+}
+
+// valueAtPath returns the value at a path in a document
+func valueAtPath(doc any, path string) (any, bool) {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return nil, false
+	}
+	value := doc
+	for _, segment := range segments {
+		switch container := value.(type) {
+		case map[string]any:
+			child, exists := container[segment]
+			if !exists {
+				return nil, false
+			}
+			value = child
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, false
+			}
+			value = container[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// coverageValue renders a value for comparison with enum values
+func coverageValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// conditionOutcome describes an outcome of an expression
+func conditionOutcome(expr string, result bool) string {
+	return fmt.Sprintf("%s is %t", expr, result)
+}
@@ -0,0 +1,83 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"role": {"type": "string", "enum": ["admin", "user"]},
+			"age": {"type": "integer"},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string", "pattern": "^[A-Z]+$"}}}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string", "maxLength": 3}}
+		},
+		"required": ["name"],
+		"requiredIf": {"guardian": "age < 18"},
+		"conditions": [{"if": "role == 'admin'", "then": {"name": {"maxLength": 10}}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	documents := []any{
+		map[string]any{"name": "Ada", "role": "admin", "age": 30.0},
+		map[string]any{"name": "", "age": 12.0, "items": []any{map[string]any{"sku": "abc"}}},
+		map[string]any{"labels": map[string]any{"env": "production"}},
+	}
+	report := Coverage(spec, documents)
+
+	var uncovered []string
+	for _, item := range report.Uncovered() {
+		uncovered = append(uncovered, item.String())
+	}
+	want := []string{
+		"role: constraint enum",
+		`role: enum "user"`,
+		"name: constraint maxLength",
+	}
+	if !reflect.DeepEqual(uncovered, want) {
+		t.Errorf("expected uncovered %q, got %q", want, uncovered)
+	}
+	if report.Documents != 3 || len(report.Items) != 13 {
+		t.Errorf("expected 13 items for 3 documents, got %d for %d: %v", len(report.Items), report.Documents, report.Items)
+	}
+	if got := report.Percent(); got < 76 || got > 77 {
+		t.Errorf("expected 10 of 13 items covered, got %.1f%%", got)
+	}
+}
+
+func TestCoverageDiscriminator(t *testing.T) {
+	spec, err := ParseSpecString(paymentSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	report := Coverage(spec, []any{
+		map[string]any{"type": "card", "amount": 10.0, "number": "4242424242424242"},
+		map[string]any{"type": "cash", "amount": 10.0},
+	})
+
+	covered := map[string]bool{}
+	for _, item := range report.Items {
+		covered[item.String()] = item.Covered
+	}
+	for item, want := range map[string]bool{
+		"(root): discriminator card":     true,
+		"(root): discriminator paypal":   false,
+		"type: constraint discriminator": true,
+	} {
+		if got, ok := covered[item]; !ok || got != want {
+			t.Errorf("expected %s covered to be %v, got %v (present %v)", item, want, got, ok)
+		}
+	}
+}
+
+func TestCoverageEmpty(t *testing.T) {
+	report := Coverage(&Spec{Type: "string"}, nil)
+	if len(report.Items) != 0 || report.Percent() != 100 {
+		t.Errorf("expected an empty, fully covered report, got %+v", report)
+	}
+}
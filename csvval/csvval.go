@@ -0,0 +1,257 @@
+// Package csvval validates CSV records against a mowgli spec, so bulk
+// imports from spreadsheets obey the same contracts as API payloads.
+//
+// The header row names the columns. Each column maps to the spec property of
+// the same name (matched case-insensitively if there's no exact match), and
+// its cells are converted to the property's type before the record is
+// validated as an object. Errors are addressed by line and column.
+package csvval
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matjam/mowgli"
+)
+
+// RowError is a validation error in one CSV record
+type RowError struct {
+	Line   int    // Line of the record in the input, the header being line 1
+	Column string // Header of the column the error is in, "" for errors about the whole record
+	*mowgli.ValidationError
+}
+
+func (e *RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.ValidationError.Error())
+	}
+	return fmt.Sprintf("line %d, column %s: %s", e.Line, e.Column, e.ValidationError.Error())
+}
+
+// Option configures a Reader
+type Option func(*Reader)
+
+// WithValidator validates records with v instead of the default validator,
+// e.g. to use its registry or expression functions
+func WithValidator(v *mowgli.Validator) Option {
+	return func(r *Reader) {
+		r.validator = v
+	}
+}
+
+// WithColumns maps column headers to property names, for headers that don't
+// match their property, e.g. {"E-mail address": "email"}
+func WithColumns(columns map[string]string) Option {
+	return func(r *Reader) {
+		r.columns = columns
+	}
+}
+
+// WithComma sets the field delimiter, e.g. ';' or '\t'. The default is ','.
+func WithComma(comma rune) Option {
+	return func(r *Reader) {
+		r.csv.Comma = comma
+	}
+}
+
+// WithListSeparator sets the separator between the items of array cells.
+// The default is ";".
+func WithListSeparator(sep string) Option {
+	return func(r *Reader) {
+		r.listSeparator = sep
+	}
+}
+
+// Reader reads and validates CSV records one at a time
+type Reader struct {
+	csv           *csv.Reader
+	spec          *mowgli.Spec
+	validator     *mowgli.Validator
+	columns       map[string]string
+	listSeparator string
+
+	headers    []string
+	properties []string // Property per column
+}
+
+// NewReader reads the header row of r and returns a Reader for the records
+// that follow it
+func NewReader(r io.Reader, spec *mowgli.Spec, opts ...Option) (*Reader, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("spec is nil")
+	}
+	reader := &Reader{
+		csv:           csv.NewReader(r),
+		spec:          spec,
+		validator:     mowgli.NewValidator(),
+		listSeparator: ";",
+	}
+	for _, opt := range opts {
+		opt(reader)
+	}
+
+	headers, err := reader.csv.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("CSV has no header row")
+		}
+		return nil, fmt.Errorf("failed to read header row: %w", err)
+	}
+	reader.headers = headers
+	reader.properties = make([]string, len(headers))
+	seen := make(map[string]string, len(headers))
+	for i, header := range headers {
+		property := reader.property(header)
+		if previous, ok := seen[property]; ok {
+			return nil, fmt.Errorf("columns %q and %q both map to property %s", previous, header, property)
+		}
+		seen[property] = header
+		reader.properties[i] = property
+	}
+	return reader, nil
+}
+
+// property returns the property a column header maps to
+func (r *Reader) property(header string) string {
+	header = strings.TrimSpace(header)
+	if name, ok := r.columns[header]; ok {
+		return name
+	}
+	if _, ok := r.spec.Properties[header]; ok {
+		return header
+	}
+	for name := range r.spec.Properties {
+		if strings.EqualFold(name, header) {
+			return name
+		}
+	}
+	return header
+}
+
+// Read reads the next record, converts its cells and validates it. It
+// returns the record as validated, its line, and its validation errors,
+// which are nil if the record is valid. At the end of the input err is
+// io.EOF; other errors are CSV syntax errors.
+func (r *Reader) Read() (record map[string]any, line int, errs []*RowError, err error) {
+	cells, err := r.csv.Read()
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	line, _ = r.csv.FieldPos(0)
+
+	record = make(map[string]any, len(cells))
+	for i, cell := range cells {
+		// Empty cells are missing values, so required catches them
+		if i >= len(r.properties) || cell == "" {
+			continue
+		}
+		record[r.properties[i]] = r.convert(cell, r.spec.Properties[r.properties[i]])
+	}
+
+	result := r.validator.Validate(record, r.spec)
+	positions := make(map[*RowError]int, len(result.Errors))
+	for _, verr := range result.Errors {
+		column, position := r.column(verr.Path)
+		rowErr := &RowError{Line: line, Column: column, ValidationError: verr}
+		positions[rowErr] = position
+		errs = append(errs, rowErr)
+	}
+	// Report errors in column order
+	sort.SliceStable(errs, func(i, j int) bool { return positions[errs[i]] < positions[errs[j]] })
+	return record, line, errs, nil
+}
+
+// column returns the header and index of the column an error path is in.
+// Errors about properties without a column come last, named by property.
+func (r *Reader) column(path string) (string, int) {
+	property, _, _ := strings.Cut(path, ".")
+	property, _, _ = strings.Cut(property, "[")
+	for i, name := range r.properties {
+		if name == property {
+			return r.headers[i], i
+		}
+	}
+	return property, len(r.properties)
+}
+
+// convert turns a cell into a value of the spec's type. Cells that can't be
+// converted are left as strings, so validation reports a type error.
+func (r *Reader) convert(cell string, spec *mowgli.Spec) any {
+	if spec == nil {
+		return cell
+	}
+	text := strings.TrimSpace(cell)
+
+	switch spec.Type {
+	case "number", "integer":
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(text); err == nil {
+			return b
+		}
+	case "array":
+		if strings.HasPrefix(text, "[") {
+			var arr []any
+			if err := json.Unmarshal([]byte(text), &arr); err == nil {
+				return arr
+			}
+		}
+		parts := strings.Split(cell, r.listSeparator)
+		arr := make([]any, len(parts))
+		for i, part := range parts {
+			arr[i] = r.convert(strings.TrimSpace(part), spec.Items)
+		}
+		return arr
+	case "object":
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(text), &obj); err == nil {
+			return obj
+		}
+	}
+	return cell
+}
+
+// Result summarizes a validated CSV file
+type Result struct {
+	Records int         // Records read, not counting the header
+	Invalid int         // Records with at least one error
+	Errors  []*RowError // Errors of all records, in order
+}
+
+// Valid reports whether every record is valid
+func (r *Result) Valid() bool {
+	return len(r.Errors) == 0
+}
+
+// Validate validates every record of a CSV file. err is only set for CSV
+// that can't be read; validation errors are in the result.
+func Validate(r io.Reader, spec *mowgli.Spec, opts ...Option) (*Result, error) {
+	reader, err := NewReader(r, spec, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for {
+		_, _, errs, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Records++
+		if len(errs) > 0 {
+			result.Invalid++
+			result.Errors = append(result.Errors, errs...)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package csvval
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/matjam/mowgli"
+)
+
+const productSpec = `{
+	"type": "object",
+	"properties": {
+		"sku": {"type": "string", "pattern": "^[A-Z]{3}-[0-9]+$"},
+		"price": {"type": "number", "min": 0},
+		"stock": {"type": "integer"},
+		"active": {"type": "boolean"},
+		"tags": {"type": "array", "items": {"type": "string", "minLength": 1}}
+	},
+	"required": ["sku", "price"]
+}`
+
+func TestValidate(t *testing.T) {
+	spec, err := mowgli.ParseSpecString(productSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	input := "SKU,Price,stock,active,tags\n" +
+		"ABC-1,9.99,3,true,new;sale\n" +
+		"abc,free,1.5,maybe,\n" +
+		"\"XYZ-2\",,0,false,a;;b\n"
+
+	result, err := Validate(strings.NewReader(input), spec)
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if result.Records != 3 || result.Invalid != 2 || result.Valid() {
+		t.Errorf("expected 3 records with 2 invalid, got %+v", result)
+	}
+
+	var got []string
+	for _, err := range result.Errors {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		"line 3, column SKU: sku: string does not match pattern: ^[A-Z]{3}-[0-9]+$",
+		"line 3, column Price: price: expected number, got string",
+		"line 3, column stock: stock: expected integer, got float: 1.5",
+		"line 3, column active: active: expected boolean, got string",
+		"line 4, column Price: price: required field is missing",
+		"line 4, column tags: tags[1]: string length 0 is less than minimum 1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestReader(t *testing.T) {
+	spec, err := mowgli.ParseSpecString(productSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	input := "Product code\tprice\ttags\n" +
+		"ABC-1\t5\t\"[\"\"x\"\", \"\"y\"\"]\"\n"
+	reader, err := NewReader(strings.NewReader(input), spec, WithComma('\t'), WithColumns(map[string]string{"Product code": "sku"}))
+	if err != nil {
+		t.Fatalf("NewReader failed: %v", err)
+	}
+
+	record, line, errs, err := reader.Read()
+	if err != nil || errs != nil {
+		t.Fatalf("expected a valid record, got %v, %v", errs, err)
+	}
+	want := map[string]any{"sku": "ABC-1", "price": 5.0, "tags": []any{"x", "y"}}
+	if line != 2 || !reflect.DeepEqual(record, want) {
+		t.Errorf("expected %v on line 2, got %v on line %d", want, record, line)
+	}
+	if _, _, _, err := reader.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	spec := &mowgli.Spec{Type: "object", Properties: map[string]*mowgli.Spec{"sku": {Type: "string"}}}
+
+	if _, err := NewReader(strings.NewReader(""), spec); err == nil || !strings.Contains(err.Error(), "no header row") {
+		t.Errorf("expected missing header error, got %v", err)
+	}
+	if _, err := NewReader(strings.NewReader("sku,SKU\n"), spec); err == nil || !strings.Contains(err.Error(), "both map to property sku") {
+		t.Errorf("expected duplicate column error, got %v", err)
+	}
+}
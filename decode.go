@@ -0,0 +1,111 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// DecodeOption configures DecodeAndValidate
+type DecodeOption func(*decodeConfig)
+
+type decodeConfig struct {
+	spec      *Spec
+	validator *Validator
+	maxBytes  int64
+	useNumber bool
+
+	unknownFields   bool
+	integerOverflow bool
+}
+
+// WithSpec validates against spec instead of the spec generated from T
+func WithSpec(spec *Spec) DecodeOption {
+	return func(c *decodeConfig) {
+		c.spec = spec
+	}
+}
+
+// WithValidator validates with v instead of the default validator, e.g. to
+// use its registered expression functions
+func WithValidator(v *Validator) DecodeOption {
+	return func(c *decodeConfig) {
+		c.validator = v
+	}
+}
+
+// WithMaxBytes fails decoding of bodies larger than n bytes
+func WithMaxBytes(n int64) DecodeOption {
+	return func(c *decodeConfig) {
+		c.maxBytes = n
+	}
+}
+
+// DecodeUseNumber decodes numbers in the validated document as
+// json.Number, like WithUseNumber, so 64-bit integers are range-checked
+// exactly
+func DecodeUseNumber() DecodeOption {
+	return func(c *decodeConfig) {
+		c.useNumber = true
+	}
+}
+
+// DecodeAndValidate reads a JSON document from r, validates it and decodes it
+// into T. The body is read once and unmarshaled straight into both the
+// generic tree used for validation and T, avoiding the marshal/unmarshal
+// round trip of ValidateStruct. Unless WithSpec is given, the spec is
+// generated from T with SpecFromStruct.
+//
+// Values changed by the spec's transforms are decoded as transformed.
+//
+// As with ValidateJSONAs, an invalid document returns the result and the zero
+// value of T with a nil error; errors are reserved for unreadable input.
+func DecodeAndValidate[T any](r io.Reader, opts ...DecodeOption) (*ValidationResult, T, error) {
+	var zero T
+
+	config := decodeConfig{validator: defaultValidator}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	spec := config.spec
+	if spec == nil {
+		var err error
+		if spec, err = SpecFromStruct(zero); err != nil {
+			return nil, zero, err
+		}
+	}
+
+	if config.maxBytes > 0 {
+		r = io.LimitReader(r, config.maxBytes+1)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, zero, fmt.Errorf("failed to read body: %w", err)
+	}
+	if config.maxBytes > 0 && int64(len(body)) > config.maxBytes {
+		return nil, zero, fmt.Errorf("body exceeds %d bytes", config.maxBytes)
+	}
+
+	result, err := config.validator.validateJSON(specName(spec), body, spec, config.useNumber || config.validator.useNumber,
+		config.strict(reflect.TypeOf(&zero).Elem()))
+	if err != nil {
+		return nil, zero, err
+	}
+	if !result.Valid {
+		return result, zero, nil
+	}
+
+	// T receives the values as transformed by the spec
+	if body, err = result.typedJSON(body, reflect.TypeOf(&zero).Elem()); err != nil {
+		return nil, zero, fmt.Errorf("failed to encode transformed document: %w", err)
+	}
+
+	var typed T
+	if err := json.Unmarshal(body, &typed); err != nil {
+		return nil, zero, fmt.Errorf("failed to decode JSON into %T: %w", zero, err)
+	}
+
+	return result, typed, nil
+}
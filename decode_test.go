@@ -0,0 +1,106 @@
+package mowgli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type decodeTestUser struct {
+	Name  string `json:"name" mowgli:"required,minLength=2"`
+	Email string `json:"email" mowgli:"required,format=email"`
+	Age   int    `json:"age" mowgli:"min=0"`
+}
+
+func TestDecodeAndValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		opts      []DecodeOption
+		wantValid bool
+		wantErr   bool
+		wantName  string
+	}{
+		{
+			name:      "valid body",
+			body:      `{"name": "Ann", "email": "ann@example.com", "age": 30}`,
+			wantValid: true,
+			wantName:  "Ann",
+		},
+		{
+			name: "invalid body",
+			body: `{"name": "A", "email": "nope"}`,
+		},
+		{
+			name:    "malformed JSON",
+			body:    `{"name": `,
+			wantErr: true,
+		},
+		{
+			name:      "custom spec",
+			body:      `{"name": "A"}`,
+			opts:      []DecodeOption{WithSpec(&Spec{Type: "object"})},
+			wantValid: true,
+			wantName:  "A",
+		},
+		{
+			name:    "body too large",
+			body:    `{"name": "Ann", "email": "ann@example.com"}`,
+			opts:    []DecodeOption{WithMaxBytes(10)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, user, err := DecodeAndValidate[decodeTestUser](strings.NewReader(tt.body), tt.opts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("expected valid=%v, got %v", tt.wantValid, result.Errors)
+			}
+			if user.Name != tt.wantName {
+				t.Errorf("expected name %q, got %q", tt.wantName, user.Name)
+			}
+		})
+	}
+}
+
+func TestDecodeAndValidateWithValidator(t *testing.T) {
+	v := NewValidator()
+	if err := v.RegisterExprFunc("isReserved", func(name string) bool { return name == "admin" }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	spec := &Spec{
+		Type:       "object",
+		Properties: map[string]*Spec{"name": {Type: "string"}},
+		Conditions: []Condition{{If: "isReserved(name)", Then: map[string]*Spec{"name": {MaxLength: intPtr(0)}}}},
+	}
+
+	result, _, err := DecodeAndValidate[decodeTestUser](strings.NewReader(`{"name": "admin"}`), WithSpec(spec), WithValidator(v))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid {
+		t.Error("expected validation with the custom validator to fail")
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestDecodeAndValidateReadError(t *testing.T) {
+	if _, _, err := DecodeAndValidate[decodeTestUser](failingReader{}); err == nil {
+		t.Error("expected read error")
+	}
+}
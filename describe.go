@@ -0,0 +1,389 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"sort"
+)
+
+// DescriptionVersion is the version of the Describe output format. It is bumped
+// whenever the shape of the description changes incompatibly.
+const DescriptionVersion = 1
+
+// Description is a normalized, machine-readable description of a spec.
+// Unlike the raw Spec serialization it is flat, fully ordered and has
+// conditions broken down into structured parts, so programmatic consumers
+// such as admin dashboards don't have to walk the spec tree themselves.
+type Description struct {
+	Version        int                        `json:"version"`
+	Fields         []FieldDescription         `json:"fields"`
+	Conditions     []ConditionDescription     `json:"conditions"`
+	Conditionals   []ConditionalDescription   `json:"conditionals"`
+	ConditionDefs  []ConditionDefDescription  `json:"conditionDefs"`
+	Overrides      []OverrideDescription      `json:"overrides"`
+	Discriminators []DiscriminatorDescription `json:"discriminators"`
+
+	// defs are the condition definitions in scope while describing
+	defs conditionDefScope
+}
+
+// FieldDescription describes a single node of the spec tree
+type FieldDescription struct {
+	Path        string                  `json:"path"` // "" for the root, "address.city", "items[]" for array items, "labels.*" for map values, "labels{}" for map keys, "payload(content)" for decoded content
+	Type        string                  `json:"type"`
+	Required    bool                    `json:"required"`
+	Constraints []ConstraintDescription `json:"constraints"`
+	Examples    []any                   `json:"examples,omitempty"`
+	Default     any                     `json:"default,omitempty"`
+}
+
+// ConstraintDescription describes one constraint on a field. Kind uses the same
+// names as the error codes reported when the constraint fails.
+type ConstraintDescription struct {
+	Kind  string `json:"kind"`
+	Value any    `json:"value"`
+}
+
+// ConditionDescription describes a condition declared on an object
+type ConditionDescription struct {
+	Path       string             `json:"path"`               // Path of the object declaring the condition
+	Name       string             `json:"name,omitempty"`     // Label of the condition, if any
+	If         string             `json:"if"`                 // The expression as written in the spec
+	Unless     string             `json:"unless,omitempty"`   // The negated expression as written in the spec
+	References []string           `json:"references"`         // Fields the expression reads with defined conditions expanded, e.g. "age", "$root.country"
+	Then       []FieldDescription `json:"then"`               // Overrides applied when the expression is true
+	Else       []FieldDescription `json:"else"`               // Overrides applied when the expression is false
+	Required   []string           `json:"required"`           // Properties required when the expression is true
+	ThenSpec   []FieldDescription `json:"thenSpec,omitempty"` // Fields of the spec replacing the object's when the expression is true
+	ElseSpec   []FieldDescription `json:"elseSpec,omitempty"` // Fields of the spec replacing the object's when the expression is false
+}
+
+// ConditionDefDescription describes a named condition defined by a spec's
+// conditionDefs
+type ConditionDefDescription struct {
+	Path       string   `json:"path"`       // Path of the object defining the condition
+	Name       string   `json:"name"`       // Name conditions use the definition by, e.g. "isMinorUS"
+	Expression string   `json:"expression"` // The expression as written in the spec
+	References []string `json:"references"` // Fields the expression reads with defined conditions expanded
+}
+
+// OverrideDescription describes a spec an object's overrides combine with
+// the values a path selector matches
+type OverrideDescription struct {
+	Path     string             `json:"path"`     // Path of the object declaring the override
+	Selector string             `json:"selector"` // Path selector relative to the object, e.g. "items[*].price"
+	Fields   []FieldDescription `json:"fields"`   // Fields of the override, at paths below Path joined with Selector
+}
+
+// DiscriminatorDescription describes the branches of an object's
+// discriminator
+type DiscriminatorDescription struct {
+	Path         string                `json:"path"`         // Path of the object
+	PropertyName string                `json:"propertyName"` // Property selecting the branch
+	Branches     []DiscriminatorBranch `json:"branches"`     // Branches by value, in sorted order
+}
+
+// DiscriminatorBranch describes the spec a discriminator value selects
+type DiscriminatorBranch struct {
+	Value  string             `json:"value"`
+	Fields []FieldDescription `json:"fields"` // Fields of the spec combined with the object's
+}
+
+// ConditionalDescription describes the if/then/else subschemas of a spec
+type ConditionalDescription struct {
+	Path string             `json:"path"` // Path of the value the subschemas apply to
+	If   []FieldDescription `json:"if"`   // Fields of the spec the value is tested against
+	Then []FieldDescription `json:"then"` // Fields combined with the spec when the value is valid against If
+	Else []FieldDescription `json:"else"` // Fields combined with the spec when the value isn't valid against If
+}
+
+// Describe returns the JSON encoding of DescribeSpec(spec)
+func Describe(spec *Spec) ([]byte, error) {
+	return json.Marshal(DescribeSpec(spec))
+}
+
+// DescribeSpec builds a normalized description of spec. Fields and conditions
+// are listed depth-first with object properties in sorted order, so the output
+// is stable for a given spec.
+func DescribeSpec(spec *Spec) *Description {
+	desc := &Description{
+		Version:        DescriptionVersion,
+		Fields:         []FieldDescription{},
+		Conditions:     []ConditionDescription{},
+		Conditionals:   []ConditionalDescription{},
+		ConditionDefs:  []ConditionDefDescription{},
+		Overrides:      []OverrideDescription{},
+		Discriminators: []DiscriminatorDescription{},
+	}
+	if spec != nil {
+		desc.describe("", spec, false)
+	}
+	return desc
+}
+
+func (d *Description) describe(path string, spec *Spec, required bool) {
+	d.Fields = append(d.Fields, describeField(path, spec, required))
+
+	if spec.ConditionDefs != nil {
+		defer d.defs.push(spec.ConditionDefs)()
+		for _, name := range slices.Sorted(maps.Keys(spec.ConditionDefs)) {
+			d.ConditionDefs = append(d.ConditionDefs, ConditionDefDescription{
+				Path:       path,
+				Name:       name,
+				Expression: spec.ConditionDefs[name],
+				References: d.references(spec.ConditionDefs[name]),
+			})
+		}
+	}
+
+	requiredSet := make(map[string]bool, len(spec.Required))
+	for _, name := range spec.Required {
+		requiredSet[name] = true
+	}
+	for _, name := range sortedKeys(spec.Properties) {
+		if prop := spec.Properties[name]; prop != nil {
+			d.describe(buildPath(path, name), prop, requiredSet[name])
+		}
+	}
+
+	if spec.Items != nil {
+		d.describe(path+"[]", spec.Items, false)
+	}
+	if spec.AdditionalProperties != nil {
+		d.describe(buildPath(path, "*"), spec.AdditionalProperties, false)
+	}
+	if spec.PropertyNames != nil {
+		d.describe(path+"{}", spec.PropertyNames, false)
+	}
+	if spec.ContentSchema != nil {
+		d.describe(path+"(content)", spec.ContentSchema, false)
+	}
+
+	for _, selector := range sortedKeys(spec.Overrides) {
+		i := len(d.Overrides)
+		d.Overrides = append(d.Overrides, OverrideDescription{Path: path, Selector: selector})
+		d.Overrides[i].Fields = d.describeBranch(buildPath(path, selector), spec.Overrides[selector])
+	}
+
+	if discriminator := spec.Discriminator; discriminator != nil {
+		i := len(d.Discriminators)
+		d.Discriminators = append(d.Discriminators, DiscriminatorDescription{
+			Path:         path,
+			PropertyName: discriminator.PropertyName,
+			Branches:     make([]DiscriminatorBranch, 0, len(discriminator.Mapping)),
+		})
+		for _, value := range sortedKeys(discriminator.Mapping) {
+			fields := d.describeBranch(path, discriminator.Mapping[value])
+			d.Discriminators[i].Branches = append(d.Discriminators[i].Branches, DiscriminatorBranch{Value: value, Fields: fields})
+		}
+	}
+
+	// Conditions and conditionals within branches are listed after the one
+	// declaring the branches
+	if spec.If != nil || spec.Then != nil || spec.Else != nil {
+		i := len(d.Conditionals)
+		d.Conditionals = append(d.Conditionals, ConditionalDescription{Path: path})
+		d.Conditionals[i].If = d.describeBranch(path, spec.If)
+		d.Conditionals[i].Then = d.describeBranch(path, spec.Then)
+		d.Conditionals[i].Else = d.describeBranch(path, spec.Else)
+	}
+
+	for _, condition := range spec.Conditions {
+		i := len(d.Conditions)
+		d.Conditions = append(d.Conditions, d.describeCondition(path, condition))
+		if condition.ThenSpec != nil {
+			d.Conditions[i].ThenSpec = d.describeBranch(path, condition.ThenSpec)
+		}
+		if condition.ElseSpec != nil {
+			d.Conditions[i].ElseSpec = d.describeBranch(path, condition.ElseSpec)
+		}
+	}
+	// requiredIf entries are described as conditions requiring one property
+	for _, name := range slices.Sorted(maps.Keys(spec.RequiredIf)) {
+		d.Conditions = append(d.Conditions, d.describeCondition(path, Condition{If: spec.RequiredIf[name], Required: []string{name}}))
+	}
+}
+
+// describeBranch describes the fields of a spec applying to the value at
+// path in some case, such as a condition's thenSpec, adding its conditions
+// to d's. A nil spec has no fields.
+func (d *Description) describeBranch(path string, spec *Spec) []FieldDescription {
+	branch := &Description{Fields: []FieldDescription{}, defs: d.defs}
+	if spec != nil {
+		branch.describe(path, spec, false)
+	}
+	d.Conditions = append(d.Conditions, branch.Conditions...)
+	d.Conditionals = append(d.Conditionals, branch.Conditionals...)
+	d.ConditionDefs = append(d.ConditionDefs, branch.ConditionDefs...)
+	d.Overrides = append(d.Overrides, branch.Overrides...)
+	d.Discriminators = append(d.Discriminators, branch.Discriminators...)
+	return branch.Fields
+}
+
+func (d *Description) describeCondition(path string, condition Condition) ConditionDescription {
+	return ConditionDescription{
+		Path:       path,
+		Name:       condition.Name,
+		If:         condition.If,
+		Unless:     condition.Unless,
+		References: d.references(condition.Expression()),
+		Then:       describeOverrides(path, condition.Then),
+		Else:       describeOverrides(path, condition.Else),
+		Required:   append([]string{}, condition.Required...),
+	}
+}
+
+// references returns the fields an expression reads, with the conditions
+// defined in scope expanded
+func (d *Description) references(expression string) []string {
+	references, err := d.defs.references(expression)
+	if err != nil || references == nil {
+		// An unparsable expression is reported when validating; describe it without references
+		return []string{}
+	}
+	return references
+}
+
+func describeOverrides(path string, overrides map[string]*Spec) []FieldDescription {
+	fields := []FieldDescription{}
+	for _, name := range sortedKeys(overrides) {
+		if override := overrides[name]; override != nil {
+			fields = append(fields, describeField(buildPath(path, name), override, false))
+		}
+	}
+	return fields
+}
+
+func describeField(path string, spec *Spec, required bool) FieldDescription {
+	return FieldDescription{
+		Path:        path,
+		Type:        spec.Type,
+		Required:    required,
+		Constraints: describeConstraints(spec),
+		Examples:    spec.Examples,
+		Default:     spec.Default,
+	}
+}
+
+// describeConstraints lists the constraints set on spec in a fixed order
+func describeConstraints(spec *Spec) []ConstraintDescription {
+	constraints := []ConstraintDescription{}
+	add := func(kind string, value any) {
+		constraints = append(constraints, ConstraintDescription{Kind: kind, Value: value})
+	}
+
+	if spec.Ref != "" {
+		add("$ref", spec.Ref)
+	}
+	if spec.Min != nil {
+		add(CodeMin, *spec.Min)
+	}
+	if spec.Max != nil {
+		add(CodeMax, *spec.Max)
+	}
+	if spec.MinInt != "" {
+		add(CodeMinInt, spec.MinInt)
+	}
+	if spec.MaxInt != "" {
+		add(CodeMaxInt, spec.MaxInt)
+	}
+	if spec.MinLength != nil {
+		add(CodeMinLength, *spec.MinLength)
+	}
+	if spec.MaxLength != nil {
+		add(CodeMaxLength, *spec.MaxLength)
+	}
+	if spec.MinBytes != nil {
+		add(CodeMinBytes, *spec.MinBytes)
+	}
+	if spec.MaxBytes != nil {
+		add(CodeMaxBytes, *spec.MaxBytes)
+	}
+	if spec.ContentEncoding != "" {
+		add(CodeContentEncoding, spec.ContentEncoding)
+	}
+	if spec.ContentMediaType != "" {
+		add(CodeContentMediaType, spec.ContentMediaType)
+	}
+	if spec.LengthUnit != "" {
+		add("lengthUnit", spec.LengthUnit)
+	}
+	if spec.Pattern != nil {
+		add(CodePattern, *spec.Pattern)
+	}
+	if spec.Format != nil {
+		add(CodeFormat, *spec.Format)
+	}
+	if spec.TimeFormat != "" {
+		add(CodeTimeFormat, spec.TimeFormat)
+	}
+	if spec.SemverRange != "" {
+		add(CodeSemverRange, spec.SemverRange)
+	}
+	if len(spec.URISchemes) > 0 {
+		add(CodeURISchemes, spec.URISchemes)
+	}
+	if spec.PublicHost != nil && *spec.PublicHost {
+		add(CodePublicHost, true)
+	}
+	if len(spec.Transform) > 0 {
+		add("transform", spec.Transform)
+	}
+	if len(spec.Enum) > 0 {
+		add(CodeEnum, spec.Enum)
+	}
+	if spec.AllowEmpty != nil {
+		add("allowEmpty", *spec.AllowEmpty)
+	}
+	if spec.UniqueItems != nil {
+		add(CodeUniqueItems, *spec.UniqueItems)
+	}
+	if spec.Nullable != nil {
+		add("nullable", *spec.Nullable)
+	}
+	if spec.ReadOnly != nil {
+		add(CodeReadOnly, *spec.ReadOnly)
+	}
+	if spec.WriteOnly != nil {
+		add(CodeWriteOnly, *spec.WriteOnly)
+	}
+	if spec.Deprecated != nil {
+		add(CodeDeprecated, *spec.Deprecated)
+	}
+	if len(spec.Required) > 0 {
+		required := append([]string(nil), spec.Required...)
+		sort.Strings(required)
+		add(CodeRequired, required)
+	}
+	if d := spec.Discriminator; d != nil {
+		add(CodeDiscriminator, map[string]any{"propertyName": d.PropertyName, "values": sortedKeys(d.Mapping)})
+	}
+	if len(spec.AnyRequired) > 0 {
+		add(CodeAnyRequired, spec.AnyRequired)
+	}
+	if len(spec.OneRequired) > 0 {
+		add(CodeOneRequired, spec.OneRequired)
+	}
+	if len(spec.Asserts) > 0 {
+		add(CodeAssert, assertExprs(spec.Asserts))
+	}
+	if len(spec.RefIntegrity) > 0 {
+		add(CodeRefIntegrity, refIntegrityRules(spec.RefIntegrity))
+	}
+	if len(spec.Checks) > 0 {
+		add(CodeCheck, spec.Checks)
+	}
+
+	return constraints
+}
+
+// sortedKeys returns the keys of a spec map in sorted order
+func sortedKeys(specs map[string]*Spec) []string {
+	keys := make([]string, 0, len(specs))
+	for key := range specs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
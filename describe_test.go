@@ -0,0 +1,300 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestDescribeSpec(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 100, "examples": ["Ada"]},
+			"age": {"type": "integer", "min": 0},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}}
+		},
+		"required": ["name"],
+		"conditions": [
+			{
+				"if": "age < 18 AND $root.country == \"US\"",
+				"then": {"name": {"maxLength": 20}},
+				"else": {"tags": {"minLength": 1}}
+			}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	desc := DescribeSpec(spec)
+	if desc.Version != DescriptionVersion {
+		t.Errorf("expected version %d, got %d", DescriptionVersion, desc.Version)
+	}
+
+	paths := make([]string, len(desc.Fields))
+	for i, field := range desc.Fields {
+		paths[i] = field.Path
+	}
+	wantPaths := []string{"", "age", "name", "tags", "tags[]"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("expected paths %v, got %v", wantPaths, paths)
+	}
+
+	name := desc.Fields[2]
+	if !name.Required {
+		t.Error("expected name to be required")
+	}
+	wantConstraints := []ConstraintDescription{
+		{Kind: CodeMinLength, Value: 1},
+		{Kind: CodeMaxLength, Value: 100},
+	}
+	if !reflect.DeepEqual(name.Constraints, wantConstraints) {
+		t.Errorf("expected constraints %v, got %v", wantConstraints, name.Constraints)
+	}
+	if len(name.Examples) != 1 || name.Examples[0] != "Ada" {
+		t.Errorf("expected examples [Ada], got %v", name.Examples)
+	}
+
+	if len(desc.Conditions) != 1 {
+		t.Fatalf("expected 1 condition, got %d", len(desc.Conditions))
+	}
+	condition := desc.Conditions[0]
+	wantRefs := []string{"$root.country", "age"}
+	if !reflect.DeepEqual(condition.References, wantRefs) {
+		t.Errorf("expected references %v, got %v", wantRefs, condition.References)
+	}
+	if len(condition.Then) != 1 || condition.Then[0].Path != "name" {
+		t.Errorf("unexpected then overrides: %+v", condition.Then)
+	}
+	if len(condition.Else) != 1 || condition.Else[0].Path != "tags" {
+		t.Errorf("unexpected else overrides: %+v", condition.Else)
+	}
+}
+
+func TestDescribeRequiredIf(t *testing.T) {
+	spec := &Spec{Type: "object", RequiredIf: map[string]string{"guardian": "age < 18", "code": "country == \"US\""}}
+
+	desc := DescribeSpec(spec)
+	want := []ConditionDescription{
+		{If: `country == "US"`, References: []string{"country"}, Then: []FieldDescription{}, Else: []FieldDescription{}, Required: []string{"code"}},
+		{If: "age < 18", References: []string{"age"}, Then: []FieldDescription{}, Else: []FieldDescription{}, Required: []string{"guardian"}},
+	}
+	if !reflect.DeepEqual(desc.Conditions, want) {
+		t.Errorf("expected %+v, got %+v", want, desc.Conditions)
+	}
+}
+
+func TestDescribeIsStable(t *testing.T) {
+	spec, err := LoadSpec("advanced_conditional.json")
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	first, err := Describe(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := Describe(spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("expected identical output for the same spec")
+		}
+	}
+
+	var decoded Description
+	if err := json.Unmarshal(first, &decoded); err != nil {
+		t.Fatalf("output is not a valid description: %v", err)
+	}
+}
+
+func TestDescribeBranchSpecs(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"kind": {"type": "string"}, "country": {"type": "string"}},
+		"conditions": [{
+			"if": "kind == \"company\"",
+			"thenSpec": {
+				"type": "object",
+				"properties": {"vatId": {"type": "string", "minLength": 8}},
+				"conditions": [{"if": "vatId != null", "required": ["country"]}]
+			},
+			"elseSpec": {"type": "object", "properties": {"name": {"type": "string"}}}
+		}],
+		"if": {"properties": {"country": {"enum": ["US"]}}},
+		"then": {"properties": {"zip": {"pattern": "^[0-9]{5}$"}}, "required": ["zip"]}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	desc := DescribeSpec(spec)
+	if len(desc.Conditions) != 2 {
+		t.Fatalf("expected the condition and the one in its thenSpec, got %+v", desc.Conditions)
+	}
+	condition := desc.Conditions[0]
+	wantThen := []FieldDescription{
+		{Path: "", Type: "object", Constraints: []ConstraintDescription{}},
+		{Path: "vatId", Type: "string", Constraints: []ConstraintDescription{{Kind: CodeMinLength, Value: 8}}},
+	}
+	if !reflect.DeepEqual(condition.ThenSpec, wantThen) {
+		t.Errorf("expected thenSpec %+v, got %+v", wantThen, condition.ThenSpec)
+	}
+	if len(condition.ElseSpec) != 2 || condition.ElseSpec[1].Path != "name" {
+		t.Errorf("unexpected elseSpec: %+v", condition.ElseSpec)
+	}
+	if nested := desc.Conditions[1]; nested.If != "vatId != null" || !reflect.DeepEqual(nested.Required, []string{"country"}) {
+		t.Errorf("unexpected condition of the thenSpec: %+v", nested)
+	}
+
+	if len(desc.Conditionals) != 1 {
+		t.Fatalf("expected 1 conditional, got %+v", desc.Conditionals)
+	}
+	conditional := desc.Conditionals[0]
+	if len(conditional.If) != 2 || conditional.If[1].Path != "country" || conditional.If[1].Constraints[0].Kind != CodeEnum {
+		t.Errorf("unexpected if: %+v", conditional.If)
+	}
+	wantZip := FieldDescription{Path: "zip", Required: true, Constraints: []ConstraintDescription{{Kind: CodePattern, Value: "^[0-9]{5}$"}}}
+	if len(conditional.Then) != 2 || !reflect.DeepEqual(conditional.Then[1], wantZip) {
+		t.Errorf("unexpected then: %+v", conditional.Then)
+	}
+	if len(conditional.Else) != 0 {
+		t.Errorf("expected no else fields, got %+v", conditional.Else)
+	}
+}
+
+func TestDescribeConditionDefs(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"conditionDefs": {"isMinor": "age < 18", "isMinorUS": "isMinor AND $root.country == \"US\""},
+		"properties": {
+			"guardian": {
+				"type": "object",
+				"conditionDefs": {"isMinor": "age < 21"},
+				"requiredIf": {"consent": "isMinorUS"}
+			}
+		},
+		"conditions": [{"if": "isMinorUS", "required": ["guardian"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	desc := DescribeSpec(spec)
+	wantDefs := []ConditionDefDescription{
+		{Path: "", Name: "isMinor", Expression: "age < 18", References: []string{"age"}},
+		{Path: "", Name: "isMinorUS", Expression: `isMinor AND $root.country == "US"`, References: []string{"$root.country", "age"}},
+		{Path: "guardian", Name: "isMinor", Expression: "age < 21", References: []string{"age"}},
+	}
+	if !reflect.DeepEqual(desc.ConditionDefs, wantDefs) {
+		t.Errorf("expected definitions %+v, got %+v", wantDefs, desc.ConditionDefs)
+	}
+
+	if len(desc.Conditions) != 2 {
+		t.Fatalf("expected 2 conditions, got %+v", desc.Conditions)
+	}
+	for _, condition := range desc.Conditions {
+		if want := []string{"$root.country", "age"}; !reflect.DeepEqual(condition.References, want) {
+			t.Errorf("condition at %q: expected references %v, got %v", condition.Path, want, condition.References)
+		}
+	}
+}
+
+func TestDescribeOverrides(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"items": {"type": "array", "items": {"type": "object", "properties": {"price": {"type": "number"}}}}},
+		"overrides": {"items[*].price": {"min": 0}}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	want := []OverrideDescription{{
+		Path:     "",
+		Selector: "items[*].price",
+		Fields:   []FieldDescription{{Path: "items[*].price", Constraints: []ConstraintDescription{{Kind: CodeMin, Value: 0.0}}}},
+	}}
+	if desc := DescribeSpec(spec); !reflect.DeepEqual(desc.Overrides, want) {
+		t.Errorf("expected overrides %+v, got %+v", want, desc.Overrides)
+	}
+}
+
+func TestDescribeContentSchema(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"payload": {
+				"type": "string",
+				"contentMediaType": "application/json",
+				"contentSchema": {"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	desc := DescribeSpec(spec)
+	paths := make([]string, len(desc.Fields))
+	for i, field := range desc.Fields {
+		paths[i] = field.Path
+	}
+	wantPaths := []string{"", "payload", "payload(content)", "payload(content).id"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Errorf("expected paths %v, got %v", wantPaths, paths)
+	}
+	if id := desc.Fields[3]; id.Type != "integer" || !id.Required {
+		t.Errorf("expected a required integer id, got %+v", id)
+	}
+}
+
+func TestDescribeDiscriminator(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"type": {"type": "string"}},
+		"discriminator": {
+			"propertyName": "type",
+			"mapping": {
+				"card": {"properties": {"number": {"type": "string", "format": "credit-card"}}, "required": ["number"]},
+				"bank": {"$ref": "bank_account"}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	desc := DescribeSpec(spec)
+	if len(desc.Discriminators) != 1 {
+		t.Fatalf("expected 1 discriminator, got %+v", desc.Discriminators)
+	}
+	discriminator := desc.Discriminators[0]
+	if discriminator.Path != "" || discriminator.PropertyName != "type" || len(discriminator.Branches) != 2 {
+		t.Fatalf("unexpected discriminator: %+v", discriminator)
+	}
+	bank, card := discriminator.Branches[0], discriminator.Branches[1]
+	if bank.Value != "bank" || len(bank.Fields) != 1 || bank.Fields[0].Constraints[0] != (ConstraintDescription{Kind: "$ref", Value: "bank_account"}) {
+		t.Errorf("unexpected bank branch: %+v", bank)
+	}
+	wantNumber := FieldDescription{Path: "number", Type: "string", Required: true, Constraints: []ConstraintDescription{{Kind: CodeFormat, Value: "credit-card"}}}
+	if card.Value != "card" || len(card.Fields) != 2 || !reflect.DeepEqual(card.Fields[1], wantNumber) {
+		t.Errorf("unexpected card branch: %+v", card)
+	}
+}
+
+func TestDescribeChecks(t *testing.T) {
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{
+		"email": {Type: "string", Checks: []string{"emailDeliverable", "notBlocked"}},
+	}}
+
+	email := DescribeSpec(spec).Fields[1]
+	want := []ConstraintDescription{{Kind: CodeCheck, Value: []string{"emailDeliverable", "notBlocked"}}}
+	if !reflect.DeepEqual(email.Constraints, want) {
+		t.Errorf("expected constraints %+v, got %+v", want, email.Constraints)
+	}
+}
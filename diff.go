@@ -0,0 +1,528 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// Kinds of SpecChange
+const (
+	ChangeAdded     = "added"     // The constraint or property is new
+	ChangeRemoved   = "removed"   // The constraint or property was removed
+	ChangeTightened = "tightened" // The constraint accepts fewer values
+	ChangeLoosened  = "loosened"  // The constraint accepts more values
+	ChangeModified  = "modified"  // The constraint changed in a way that can't be ordered
+)
+
+// SpecChange is one difference between two specs
+type SpecChange struct {
+	Path       string // Field path, "" for the root and "items[]" for array items
+	Constraint string // Constraint that changed, named like error codes ("required", "min", "enum", ...) or "property", "condition"
+	Change     string // One of the Change* constants
+	Breaking   bool   // Whether documents valid under the old spec may be invalid under the new one
+	Old        any    `json:",omitempty"`
+	New        any    `json:",omitempty"`
+}
+
+func (c SpecChange) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("%s: %s %s (%s)", displayPath(c.Path), c.Constraint, c.Change, kind)
+}
+
+// SpecDiff lists the changes between two specs
+type SpecDiff struct {
+	Changes []SpecChange
+}
+
+// Breaking returns the breaking changes
+func (d *SpecDiff) Breaking() []SpecChange {
+	var breaking []SpecChange
+	for _, change := range d.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// HasBreaking reports whether any change is breaking
+func (d *SpecDiff) HasBreaking() bool {
+	return len(d.Breaking()) > 0
+}
+
+// DiffSpecs compares two versions of a spec for payloads a service accepts.
+// A change is breaking if a document that was valid under old may be invalid
+// under new: a new required field, a tightened range or length, a removed
+// enum value, a new pattern and so on. Loosened constraints are non-breaking.
+// Changes whose effect can't be determined, such as a modified pattern or
+// condition, are treated as breaking.
+func DiffSpecs(old, new *Spec) *SpecDiff {
+	d := &SpecDiff{Changes: []SpecChange{}}
+	d.diff("", old, new)
+	return d
+}
+
+func (d *SpecDiff) add(path, constraint, change string, breaking bool, old, new any) {
+	d.Changes = append(d.Changes, SpecChange{
+		Path: path, Constraint: constraint, Change: change, Breaking: breaking, Old: old, New: new,
+	})
+}
+
+func (d *SpecDiff) diff(path string, old, new *Spec) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		// A spec where there was none constrains values that were unchecked
+		d.add(path, "spec", ChangeAdded, true, nil, new.Type)
+		return
+	case new == nil:
+		d.add(path, "spec", ChangeRemoved, false, old.Type, nil)
+		return
+	}
+
+	if old.Type != new.Type {
+		switch {
+		case new.Type == "":
+			d.add(path, CodeType, ChangeLoosened, false, old.Type, new.Type)
+		case old.Type == "integer" && new.Type == "number":
+			d.add(path, CodeType, ChangeLoosened, false, old.Type, new.Type)
+		default:
+			d.add(path, CodeType, ChangeModified, true, old.Type, new.Type)
+		}
+	}
+
+	if old.Ref != new.Ref {
+		d.add(path, "$ref", ChangeModified, true, old.Ref, new.Ref)
+	}
+	d.diffFormats(path, old.Formats, new.Formats)
+	d.diffLowerBound(path, CodeMin, old.Min, new.Min)
+	d.diffUpperBound(path, CodeMax, old.Max, new.Max)
+	d.diffIntBound(path, CodeMinInt, old.MinInt, new.MinInt, true)
+	d.diffIntBound(path, CodeMaxInt, old.MaxInt, new.MaxInt, false)
+	d.diffLowerBound(path, CodeMinLength, intValue(old.MinLength), intValue(new.MinLength))
+	d.diffUpperBound(path, CodeMaxLength, intValue(old.MaxLength), intValue(new.MaxLength))
+	d.diffLowerBound(path, CodeMinBytes, intValue(old.MinBytes), intValue(new.MinBytes))
+	d.diffUpperBound(path, CodeMaxBytes, intValue(old.MaxBytes), intValue(new.MaxBytes))
+	if old.LengthUnit != new.LengthUnit {
+		// Whether more or fewer strings fit depends on the strings
+		d.add(path, "lengthUnit", ChangeModified, true, old.LengthUnit, new.LengthUnit)
+	}
+	d.diffExact(path, CodePattern, old.Pattern, new.Pattern)
+	d.diffExact(path, CodeFormat, old.Format, new.Format)
+	if old.TimeFormat != new.TimeFormat {
+		d.add(path, CodeTimeFormat, ChangeModified, true, old.TimeFormat, new.TimeFormat)
+	}
+	if old.SemverRange != new.SemverRange {
+		d.add(path, CodeSemverRange, ChangeModified, true, old.SemverRange, new.SemverRange)
+	}
+	d.diffAllowed(path, CodeURISchemes, old.URISchemes, new.URISchemes)
+	d.diffRestriction(path, CodePublicHost, boolValue(old.PublicHost), boolValue(new.PublicHost))
+	if old.ContentEncoding != new.ContentEncoding {
+		d.add(path, CodeContentEncoding, ChangeModified, true, old.ContentEncoding, new.ContentEncoding)
+	}
+	if old.ContentMediaType != new.ContentMediaType {
+		d.add(path, CodeContentMediaType, ChangeModified, true, old.ContentMediaType, new.ContentMediaType)
+	}
+	if !slices.Equal(old.Transform, new.Transform) {
+		// Transforms change the value every other constraint sees
+		d.add(path, "transform", ChangeModified, true, old.Transform, new.Transform)
+	}
+	d.diffFlag(path, "allowEmpty", boolValue(old.AllowEmpty), boolValue(new.AllowEmpty))
+	d.diffFlag(path, "nullable", boolValue(old.Nullable), boolValue(new.Nullable))
+	if oldUnique, newUnique := boolValue(old.UniqueItems), boolValue(new.UniqueItems); oldUnique != newUnique {
+		if newUnique {
+			d.add(path, CodeUniqueItems, ChangeAdded, true, nil, true)
+		} else {
+			d.add(path, CodeUniqueItems, ChangeRemoved, false, true, nil)
+		}
+	}
+	d.diffRestriction(path, CodeReadOnly, boolValue(old.ReadOnly), boolValue(new.ReadOnly))
+	d.diffRestriction(path, CodeWriteOnly, boolValue(old.WriteOnly), boolValue(new.WriteOnly))
+	if oldDeprecated, newDeprecated := boolValue(old.Deprecated), boolValue(new.Deprecated); oldDeprecated != newDeprecated {
+		// Deprecation only produces warnings
+		if newDeprecated {
+			d.add(path, CodeDeprecated, ChangeAdded, false, nil, true)
+		} else {
+			d.add(path, CodeDeprecated, ChangeRemoved, false, true, nil)
+		}
+	}
+	d.diffEnum(path, old.Enum, new.Enum)
+	d.diffStrings(path, CodeCheck, old.Checks, new.Checks)
+	d.diffStrings(path, CodeRequired, old.Required, new.Required)
+	// Any listed property satisfies anyRequired, so dropping one from the list is breaking
+	d.diffAllowed(path, CodeAnyRequired, old.AnyRequired, new.AnyRequired)
+	d.diffOneRequired(path, old.OneRequired, new.OneRequired)
+	d.diffStrings(path, CodeAssert, assertExprs(old.Asserts), assertExprs(new.Asserts))
+	d.diffStrings(path, CodeRefIntegrity, refIntegrityRules(old.RefIntegrity), refIntegrityRules(new.RefIntegrity))
+
+	d.diffProperties(path, old.Properties, new.Properties)
+	d.diffNested(path+"[]", "items", old.Items, new.Items)
+	d.diffNested(buildPath(path, "*"), "additionalProperties", old.AdditionalProperties, new.AdditionalProperties)
+	d.diffNested(path+"{}", "propertyNames", old.PropertyNames, new.PropertyNames)
+	d.diffNested(path+"(content)", "contentSchema", old.ContentSchema, new.ContentSchema)
+	d.diffConditions(path, old.Conditions, new.Conditions)
+	d.diffDiscriminator(path, old.Discriminator, new.Discriminator)
+	d.diffNested(path, "if", old.If, new.If)
+	d.diffNested(path, "then", old.Then, new.Then)
+	d.diffNested(path, "else", old.Else, new.Else)
+	d.diffRequiredIf(path, old.RequiredIf, new.RequiredIf)
+}
+
+// diffLowerBound compares minimums; raising one is breaking
+func (d *SpecDiff) diffLowerBound(path, constraint string, old, new *float64) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.add(path, constraint, ChangeAdded, true, nil, *new)
+	case new == nil:
+		d.add(path, constraint, ChangeRemoved, false, *old, nil)
+	case *new > *old:
+		d.add(path, constraint, ChangeTightened, true, *old, *new)
+	case *new < *old:
+		d.add(path, constraint, ChangeLoosened, false, *old, *new)
+	}
+}
+
+// diffUpperBound compares maximums; lowering one is breaking
+func (d *SpecDiff) diffUpperBound(path, constraint string, old, new *float64) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.add(path, constraint, ChangeAdded, true, nil, *new)
+	case new == nil:
+		d.add(path, constraint, ChangeRemoved, false, *old, nil)
+	case *new < *old:
+		d.add(path, constraint, ChangeTightened, true, *old, *new)
+	case *new > *old:
+		d.add(path, constraint, ChangeLoosened, false, *old, *new)
+	}
+}
+
+// diffIntBound compares exact integer minimums (lower is true) or maximums
+func (d *SpecDiff) diffIntBound(path, constraint string, old, new json.Number, lower bool) {
+	cmp := compareIntBounds(new, old)
+	if !lower {
+		cmp = -cmp
+	}
+	switch {
+	case old == "" && new == "":
+	case old == "":
+		d.add(path, constraint, ChangeAdded, true, nil, new)
+	case new == "":
+		d.add(path, constraint, ChangeRemoved, false, old, nil)
+	case cmp > 0:
+		d.add(path, constraint, ChangeTightened, true, old, new)
+	case cmp < 0:
+		d.add(path, constraint, ChangeLoosened, false, old, new)
+	}
+}
+
+// diffExact compares constraints that can only be equal or different
+func (d *SpecDiff) diffExact(path, constraint string, old, new *string) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.add(path, constraint, ChangeAdded, true, nil, *new)
+	case new == nil:
+		d.add(path, constraint, ChangeRemoved, false, *old, nil)
+	case *old != *new:
+		d.add(path, constraint, ChangeModified, true, *old, *new)
+	}
+}
+
+// diffFlag compares flags that accept more values when true
+func (d *SpecDiff) diffFlag(path, constraint string, old, new bool) {
+	switch {
+	case old && !new:
+		d.add(path, constraint, ChangeTightened, true, old, new)
+	case !old && new:
+		d.add(path, constraint, ChangeLoosened, false, old, new)
+	}
+}
+
+// diffRestriction compares flags that reject values when true
+func (d *SpecDiff) diffRestriction(path, constraint string, old, new bool) {
+	switch {
+	case !old && new:
+		d.add(path, constraint, ChangeAdded, true, nil, true)
+	case old && !new:
+		d.add(path, constraint, ChangeRemoved, false, true, nil)
+	}
+}
+
+// diffFormats compares format definitions. Removing one is breaking, as
+// specs using it no longer validate, and so is changing its pattern.
+func (d *SpecDiff) diffFormats(path string, old, new map[string]string) {
+	names := slices.Sorted(maps.Keys(old))
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		oldPattern, inOld := old[name]
+		newPattern, inNew := new[name]
+		constraint := "formats." + name
+		switch {
+		case !inOld:
+			d.add(path, constraint, ChangeAdded, false, nil, newPattern)
+		case !inNew:
+			d.add(path, constraint, ChangeRemoved, true, oldPattern, nil)
+		case oldPattern != newPattern:
+			d.add(path, constraint, ChangeModified, true, oldPattern, newPattern)
+		}
+	}
+}
+
+// diffAllowed compares lists of allowed values, where an empty list allows
+// any value; removing a value is breaking
+func (d *SpecDiff) diffAllowed(path, constraint string, old, new []string) {
+	switch {
+	case len(old) == 0 && len(new) == 0:
+		return
+	case len(old) == 0:
+		d.add(path, constraint, ChangeAdded, true, nil, new)
+		return
+	case len(new) == 0:
+		d.add(path, constraint, ChangeRemoved, false, old, nil)
+		return
+	}
+	for _, value := range sortedDifference(old, new) {
+		d.add(path, constraint, ChangeTightened, true, value, nil)
+	}
+	for _, value := range sortedDifference(new, old) {
+		d.add(path, constraint, ChangeLoosened, false, nil, value)
+	}
+}
+
+func (d *SpecDiff) diffEnum(path string, old, new []any) {
+	switch {
+	case len(old) == 0 && len(new) == 0:
+		return
+	case len(old) == 0:
+		d.add(path, CodeEnum, ChangeAdded, true, nil, new)
+		return
+	case len(new) == 0:
+		d.add(path, CodeEnum, ChangeRemoved, false, old, nil)
+		return
+	}
+
+	for _, value := range old {
+		if !containsEnumValue(new, value) {
+			d.add(path, CodeEnum, ChangeTightened, true, value, nil)
+		}
+	}
+	for _, value := range new {
+		if !containsEnumValue(old, value) {
+			d.add(path, CodeEnum, ChangeLoosened, false, nil, value)
+		}
+	}
+}
+
+func containsEnumValue(enum []any, value any) bool {
+	for _, v := range enum {
+		if valuesEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// diffStrings compares lists such as required and checks, where each added
+// entry is breaking. Required fields are reported at the field's path.
+func (d *SpecDiff) diffStrings(path, constraint string, old, new []string) {
+	at := func(name string) string {
+		if constraint == CodeRequired {
+			return buildPath(path, name)
+		}
+		return path
+	}
+	for _, name := range sortedDifference(new, old) {
+		d.add(at(name), constraint, ChangeAdded, true, nil, name)
+	}
+	for _, name := range sortedDifference(old, new) {
+		d.add(at(name), constraint, ChangeRemoved, false, name, nil)
+	}
+}
+
+// sortedDifference returns the entries of a missing from b, sorted
+func sortedDifference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, s := range b {
+		inB[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !inB[s] {
+			diff = append(diff, s)
+			inB[s] = true
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+func (d *SpecDiff) diffProperties(path string, old, new map[string]*Spec) {
+	names := make(map[string]*Spec, len(old)+len(new))
+	for name, spec := range old {
+		names[name] = spec
+	}
+	for name, spec := range new {
+		names[name] = spec
+	}
+
+	for _, name := range sortedKeys(names) {
+		oldProp, inOld := old[name]
+		newProp, inNew := new[name]
+		switch {
+		case !inOld:
+			// New optional properties only constrain values that were unchecked
+			d.add(buildPath(path, name), "property", ChangeAdded, false, nil, newProp.Type)
+		case !inNew:
+			d.add(buildPath(path, name), "property", ChangeRemoved, false, oldProp.Type, nil)
+		default:
+			d.diff(buildPath(path, name), oldProp, newProp)
+		}
+	}
+}
+
+// diffNested compares nested specs such as items, where adding one constrains
+// values that were previously unchecked
+func (d *SpecDiff) diffNested(path, constraint string, old, new *Spec) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		d.add(path, constraint, ChangeAdded, true, nil, new.Type)
+	case new == nil:
+		d.add(path, constraint, ChangeRemoved, false, old.Type, nil)
+	default:
+		d.diff(path, old, new)
+	}
+}
+
+// diffConditions matches conditions by expression. Any change to a condition
+// is treated as breaking, except removing it and renaming it, as names only
+// label annotations.
+func (d *SpecDiff) diffConditions(path string, old, new []Condition) {
+	oldByIf := make(map[string]Condition, len(old))
+	for _, c := range old {
+		oldByIf[c.Expression()] = c
+	}
+	newByIf := make(map[string]Condition, len(new))
+	for _, c := range new {
+		newByIf[c.Expression()] = c
+	}
+
+	for _, c := range new {
+		previous, existed := oldByIf[c.Expression()]
+		switch {
+		case !existed:
+			d.add(path, CodeCondition, ChangeAdded, true, nil, c.Expression())
+		case !reflect.DeepEqual(unnamed(previous), unnamed(c)):
+			d.add(path, CodeCondition, ChangeModified, true, c.Expression(), c.Expression())
+		}
+	}
+	for _, c := range old {
+		if _, exists := newByIf[c.Expression()]; !exists {
+			d.add(path, CodeCondition, ChangeRemoved, false, c.Expression(), nil)
+		}
+	}
+}
+
+// unnamed returns a copy of c without its name
+func unnamed(c Condition) Condition {
+	c.Name = ""
+	return c
+}
+
+// diffDiscriminator compares discriminators and their branches. Removing a
+// branch is breaking, as objects selecting it are then rejected; adding one
+// isn't. Changes within a branch are reported at the object's path.
+func (d *SpecDiff) diffDiscriminator(path string, old, new *Discriminator) {
+	switch {
+	case old == nil && new == nil:
+		return
+	case old == nil:
+		d.add(path, CodeDiscriminator, ChangeAdded, true, nil, new.PropertyName)
+		return
+	case new == nil:
+		d.add(path, CodeDiscriminator, ChangeRemoved, false, old.PropertyName, nil)
+		return
+	case old.PropertyName != new.PropertyName:
+		d.add(path, CodeDiscriminator, ChangeModified, true, old.PropertyName, new.PropertyName)
+		return
+	}
+
+	for _, key := range sortedKeys(old.Mapping) {
+		if _, exists := new.Mapping[key]; !exists {
+			d.add(path, CodeDiscriminator, ChangeTightened, true, key, nil)
+		}
+	}
+	for _, key := range sortedKeys(new.Mapping) {
+		oldBranch, existed := old.Mapping[key]
+		switch {
+		case !existed:
+			d.add(path, CodeDiscriminator, ChangeLoosened, false, nil, key)
+		case oldBranch != nil && new.Mapping[key] != nil:
+			d.diff(path, oldBranch, new.Mapping[key])
+		}
+	}
+}
+
+// diffOneRequired compares oneRequired groups. Documents may set any one of
+// the properties but no other, so every change is breaking except removing
+// the group.
+func (d *SpecDiff) diffOneRequired(path string, old, new []string) {
+	if len(sortedDifference(old, new)) == 0 && len(sortedDifference(new, old)) == 0 {
+		return
+	}
+	switch {
+	case len(old) == 0:
+		d.add(path, CodeOneRequired, ChangeAdded, true, nil, new)
+	case len(new) == 0:
+		d.add(path, CodeOneRequired, ChangeRemoved, false, old, nil)
+	default:
+		d.add(path, CodeOneRequired, ChangeModified, true, old, new)
+	}
+}
+
+// diffRequiredIf compares conditionally required properties. Adding one or
+// changing its expression is breaking; removing one isn't.
+func (d *SpecDiff) diffRequiredIf(path string, old, new map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(new)) {
+		oldExpr, existed := old[name]
+		switch {
+		case !existed:
+			d.add(buildPath(path, name), "requiredIf", ChangeAdded, true, nil, new[name])
+		case oldExpr != new[name]:
+			d.add(buildPath(path, name), "requiredIf", ChangeModified, true, oldExpr, new[name])
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(old)) {
+		if _, exists := new[name]; !exists {
+			d.add(buildPath(path, name), "requiredIf", ChangeRemoved, false, old[name], nil)
+		}
+	}
+}
+
+func intValue(p *int) *float64 {
+	if p == nil {
+		return nil
+	}
+	f := float64(*p)
+	return &f
+}
+
+func boolValue(p *bool) bool {
+	return p != nil && *p
+}
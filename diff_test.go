@@ -0,0 +1,186 @@
+package mowgli
+
+import "testing"
+
+func TestDiffSpecs(t *testing.T) {
+	tests := []struct {
+		name     string
+		oldJSON  string
+		newJSON  string
+		want     []SpecChange // Old and New are not compared
+		breaking bool
+	}{
+		{
+			name:    "identical",
+			oldJSON: `{"type": "object", "properties": {"a": {"type": "string", "minLength": 1}}}`,
+			newJSON: `{"type": "object", "properties": {"a": {"type": "string", "minLength": 1}}}`,
+		},
+		{
+			name:     "byte limits",
+			oldJSON:  `{"type": "string", "maxBytes": 255}`,
+			newJSON:  `{"type": "string", "minBytes": 1, "maxBytes": 1024}`,
+			want:     []SpecChange{{Constraint: CodeMinBytes, Change: ChangeAdded, Breaking: true}, {Constraint: CodeMaxBytes, Change: ChangeLoosened}},
+			breaking: true,
+		},
+		{
+			name:     "format definitions",
+			oldJSON:  `{"type": "string", "format": "sku", "formats": {"sku": "^[A-Z]+$", "old": "^o$"}}`,
+			newJSON:  `{"type": "string", "format": "sku", "formats": {"sku": "^[A-Z0-9]+$", "new": "^n$"}}`,
+			want:     []SpecChange{{Constraint: "formats.new", Change: ChangeAdded}, {Constraint: "formats.old", Change: ChangeRemoved, Breaking: true}, {Constraint: "formats.sku", Change: ChangeModified, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:     "exact integer limits",
+			oldJSON:  `{"type": "integer", "minInt": 0, "maxInt": 18446744073709551615}`,
+			newJSON:  `{"type": "integer", "minInt": 0, "maxInt": 18446744073709551614}`,
+			want:     []SpecChange{{Constraint: CodeMaxInt, Change: ChangeTightened, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:     "field made read-only",
+			oldJSON:  `{"type": "object", "properties": {"id": {"type": "string", "writeOnly": true}}}`,
+			newJSON:  `{"type": "object", "properties": {"id": {"type": "string", "readOnly": true}}}`,
+			want:     []SpecChange{{Path: "id", Constraint: CodeReadOnly, Change: ChangeAdded, Breaking: true}, {Path: "id", Constraint: CodeWriteOnly, Change: ChangeRemoved}},
+			breaking: true,
+		},
+		{
+			name:     "new required field",
+			oldJSON:  `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			newJSON:  `{"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]}`,
+			want:     []SpecChange{{Path: "a", Constraint: CodeRequired, Change: ChangeAdded, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:     "discriminator branches",
+			oldJSON:  `{"type": "object", "discriminator": {"propertyName": "type", "mapping": {"card": {"required": ["number"]}, "cash": {}}}}`,
+			newJSON:  `{"type": "object", "discriminator": {"propertyName": "type", "mapping": {"card": {"required": ["number", "cvc"]}, "paypal": {}}}}`,
+			want:     []SpecChange{{Constraint: CodeDiscriminator, Change: ChangeTightened, Breaking: true}, {Path: "cvc", Constraint: CodeRequired, Change: ChangeAdded, Breaking: true}, {Constraint: CodeDiscriminator, Change: ChangeLoosened}},
+			breaking: true,
+		},
+		{
+			name:     "property groups",
+			oldJSON:  `{"type": "object", "anyRequired": ["email", "phone"], "oneRequired": ["card", "iban"]}`,
+			newJSON:  `{"type": "object", "anyRequired": ["email", "fax"], "oneRequired": ["card", "iban", "cash"]}`,
+			want:     []SpecChange{{Constraint: CodeAnyRequired, Change: ChangeTightened, Breaking: true}, {Constraint: CodeAnyRequired, Change: ChangeLoosened}, {Constraint: CodeOneRequired, Change: ChangeModified, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:    "property group dropped",
+			oldJSON: `{"type": "object", "oneRequired": ["card", "iban"]}`,
+			newJSON: `{"type": "object"}`,
+			want:    []SpecChange{{Constraint: CodeOneRequired, Change: ChangeRemoved}},
+		},
+		{
+			name:     "conditionally required fields",
+			oldJSON:  `{"type": "object", "requiredIf": {"a": "x > 1", "b": "x > 2"}}`,
+			newJSON:  `{"type": "object", "requiredIf": {"a": "x > 0", "c": "x > 3"}}`,
+			want:     []SpecChange{{Path: "a", Constraint: "requiredIf", Change: ChangeModified, Breaking: true}, {Path: "c", Constraint: "requiredIf", Change: ChangeAdded, Breaking: true}, {Path: "b", Constraint: "requiredIf", Change: ChangeRemoved}},
+			breaking: true,
+		},
+		{
+			name:    "required field dropped",
+			oldJSON: `{"type": "object", "required": ["a"]}`,
+			newJSON: `{"type": "object"}`,
+			want:    []SpecChange{{Path: "a", Constraint: CodeRequired, Change: ChangeRemoved}},
+		},
+		{
+			name:     "tightened range",
+			oldJSON:  `{"type": "integer", "min": 0, "max": 100}`,
+			newJSON:  `{"type": "integer", "min": 1, "max": 200}`,
+			want:     []SpecChange{{Constraint: CodeMin, Change: ChangeTightened, Breaking: true}, {Constraint: CodeMax, Change: ChangeLoosened}},
+			breaking: true,
+		},
+		{
+			name:     "added max length",
+			oldJSON:  `{"type": "string"}`,
+			newJSON:  `{"type": "string", "maxLength": 10}`,
+			want:     []SpecChange{{Constraint: CodeMaxLength, Change: ChangeAdded, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:     "removed enum value",
+			oldJSON:  `{"type": "string", "enum": ["a", "b"]}`,
+			newJSON:  `{"type": "string", "enum": ["a", "c"]}`,
+			want:     []SpecChange{{Constraint: CodeEnum, Change: ChangeTightened, Breaking: true}, {Constraint: CodeEnum, Change: ChangeLoosened}},
+			breaking: true,
+		},
+		{
+			name:    "widened integer to number",
+			oldJSON: `{"type": "integer"}`,
+			newJSON: `{"type": "number"}`,
+			want:    []SpecChange{{Constraint: CodeType, Change: ChangeLoosened}},
+		},
+		{
+			name:     "changed type",
+			oldJSON:  `{"type": "object", "properties": {"id": {"type": "integer"}}}`,
+			newJSON:  `{"type": "object", "properties": {"id": {"type": "string"}}}`,
+			want:     []SpecChange{{Path: "id", Constraint: CodeType, Change: ChangeModified, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:    "optional property added and removed",
+			oldJSON: `{"type": "object", "properties": {"a": {"type": "string"}}}`,
+			newJSON: `{"type": "object", "properties": {"b": {"type": "string"}}}`,
+			want: []SpecChange{
+				{Path: "a", Constraint: "property", Change: ChangeRemoved},
+				{Path: "b", Constraint: "property", Change: ChangeAdded},
+			},
+		},
+		{
+			name:     "nested array items",
+			oldJSON:  `{"type": "array", "items": {"type": "string", "pattern": "^a"}}`,
+			newJSON:  `{"type": "array", "items": {"type": "string", "pattern": "^b"}, "uniqueItems": true}`,
+			want:     []SpecChange{{Constraint: CodeUniqueItems, Change: ChangeAdded, Breaking: true}, {Path: "[]", Constraint: CodePattern, Change: ChangeModified, Breaking: true}},
+			breaking: true,
+		},
+		{
+			name:    "conditions",
+			oldJSON: `{"type": "object", "conditions": [{"if": "a == 1", "required": ["b"]}, {"if": "c == 1"}]}`,
+			newJSON: `{"type": "object", "conditions": [{"if": "a == 1", "required": ["b", "d"]}, {"if": "e == 1"}]}`,
+			want: []SpecChange{
+				{Constraint: CodeCondition, Change: ChangeModified, Breaking: true},
+				{Constraint: CodeCondition, Change: ChangeAdded, Breaking: true},
+				{Constraint: CodeCondition, Change: ChangeRemoved},
+			},
+			breaking: true,
+		},
+		{
+			name:    "condition renamed",
+			oldJSON: `{"type": "object", "conditions": [{"if": "a == 1", "required": ["b"]}]}`,
+			newJSON: `{"type": "object", "conditions": [{"name": "a", "if": "a == 1", "required": ["b"]}]}`,
+		},
+		{
+			name:    "nullable loosened",
+			oldJSON: `{"type": "string"}`,
+			newJSON: `{"type": "string", "nullable": true}`,
+			want:    []SpecChange{{Constraint: "nullable", Change: ChangeLoosened}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldSpec, err := ParseSpecString(tt.oldJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse old spec: %v", err)
+			}
+			newSpec, err := ParseSpecString(tt.newJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse new spec: %v", err)
+			}
+
+			diff := DiffSpecs(oldSpec, newSpec)
+			if len(diff.Changes) != len(tt.want) {
+				t.Fatalf("expected %d changes, got %v", len(tt.want), diff.Changes)
+			}
+			for i, want := range tt.want {
+				got := diff.Changes[i]
+				if got.Path != want.Path || got.Constraint != want.Constraint || got.Change != want.Change || got.Breaking != want.Breaking {
+					t.Errorf("change %d: expected %s, got %s", i, want, got)
+				}
+			}
+			if diff.HasBreaking() != tt.breaking {
+				t.Errorf("expected HasBreaking()=%v", tt.breaking)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package mowgli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// discriminated returns the spec to validate obj against: spec combined with
+// the branch its discriminator selects, repeatedly if the branch has a
+// discriminator of its own. If obj selects no branch, an error is reported at
+// the discriminator property and the spec without a branch is returned.
+func (r *ValidationResult) discriminated(path string, obj map[string]any, spec *Spec) *Spec {
+	var seen []string
+	for spec.Discriminator != nil && !slices.Contains(seen, spec.Discriminator.PropertyName) {
+		d := spec.Discriminator
+		seen = append(seen, d.PropertyName)
+		propPath := buildPath(path, d.PropertyName)
+
+		value, exists := obj[d.PropertyName]
+		if !exists {
+			// required reports it already
+			if !slices.Contains(spec.Required, d.PropertyName) {
+				r.addError(propPath, CodeRequired, "required field is missing", nil)
+			}
+			return withoutDiscriminator(spec)
+		}
+
+		key, isString := value.(string)
+		branch, ok := d.Mapping[key]
+		if !isString || !ok || branch == nil {
+			allowed := sortedKeys(d.Mapping)
+			r.addError(propPath, CodeDiscriminator, fmt.Sprintf("%s must be one of %s", d.PropertyName, strings.Join(allowed, ", ")),
+				map[string]any{"actual": value, "allowed": allowed})
+			return withoutDiscriminator(spec)
+		}
+
+		r.addTrace(path, TraceDiscriminator, d.PropertyName+"="+key, nil)
+		r.annotateBranch(path, key)
+		if branch.Ref != "" {
+			resolved, err := r.resolveRef(branch)
+			if err != nil {
+				r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"ref": branch.Ref})
+				return withoutDiscriminator(spec)
+			}
+			branch = resolved
+		}
+		spec = r.combineBranch(spec, branch)
+	}
+	return spec
+}
+
+// combineBranch merges a discriminator branch into the object's spec. The
+// branch's properties replace those of the same name, and its required
+// properties, conditions and asserts add to the object's.
+func (r *ValidationResult) combineBranch(spec, branch *Spec) *Spec {
+	combined := r.mergeSpecs(withoutDiscriminator(spec), branch)
+	combined.Discriminator = branch.Discriminator
+	combined.Required = append(slices.Clip(spec.Required), branch.Required...)
+	combined.Conditions = append(slices.Clip(spec.Conditions), branch.Conditions...)
+	combined.Asserts = append(slices.Clip(spec.Asserts), branch.Asserts...)
+	return combined
+}
+
+// withoutDiscriminator returns a copy of spec without its discriminator
+func withoutDiscriminator(spec *Spec) *Spec {
+	copied := *spec
+	copied.Discriminator = nil
+	return &copied
+}
+
+// checkDiscriminator reports discriminators without a property name or
+// branches, and branches that aren't objects
+func checkDiscriminator(d *Discriminator) error {
+	if d == nil {
+		return nil
+	}
+	if d.PropertyName == "" {
+		return fmt.Errorf("discriminator has no propertyName")
+	}
+	if len(d.Mapping) == 0 {
+		return fmt.Errorf("discriminator has no mapping")
+	}
+	for _, key := range sortedKeys(d.Mapping) {
+		branch := d.Mapping[key]
+		if branch == nil {
+			return fmt.Errorf("discriminator branch %s is null", key)
+		}
+		if branch.Type != "" && branch.Type != "object" {
+			return fmt.Errorf("discriminator branch %s must be an object, not %s", key, branch.Type)
+		}
+	}
+	return nil
+}
+
+// generateDiscriminated generates an object for a random branch of spec's
+// discriminator. Branches that are references can't be generated without a
+// registry and are skipped; ok is false if there are no others.
+func (g *generator) generateDiscriminated(spec *Spec) (map[string]any, bool) {
+	d := spec.Discriminator
+	var keys []string
+	for _, key := range sortedKeys(d.Mapping) {
+		if branch := d.Mapping[key]; branch != nil && branch.Ref == "" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, false
+	}
+
+	key := keys[g.intn(len(keys))]
+	obj := g.generateObject(defaultValidator.newResult(nil).combineBranch(spec, d.Mapping[key]))
+	obj[d.PropertyName] = key
+	return obj, true
+}
@@ -0,0 +1,157 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const paymentSpec = `{
+	"type": "object",
+	"properties": {
+		"type": {"type": "string"},
+		"amount": {"type": "number", "min": 0}
+	},
+	"required": ["amount"],
+	"discriminator": {
+		"propertyName": "type",
+		"mapping": {
+			"card": {
+				"properties": {"number": {"type": "string", "pattern": "^[0-9]{16}$"}},
+				"required": ["number"]
+			},
+			"paypal": {
+				"properties": {"email": {"type": "string", "format": "email"}},
+				"required": ["email"]
+			}
+		}
+	}
+}`
+
+func TestValidateDiscriminator(t *testing.T) {
+	spec, err := ParseSpecString(paymentSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value map[string]any
+		want  []string // "path: code"
+	}{
+		{name: "card", value: map[string]any{"type": "card", "amount": 10, "number": "4111111111111111"}},
+		{name: "paypal", value: map[string]any{"type": "paypal", "amount": 10, "email": "a@example.com"}},
+		{name: "only the selected branch", value: map[string]any{"type": "card", "amount": 10}, want: []string{"number: required"}},
+		{name: "branch constraints", value: map[string]any{"type": "paypal", "amount": 10, "email": "nope"}, want: []string{"email: format"}},
+		{name: "base constraints", value: map[string]any{"type": "card", "amount": -1, "number": "4111111111111111"}, want: []string{"amount: min"}},
+		{name: "unknown value", value: map[string]any{"type": "cash", "amount": 10}, want: []string{"type: discriminator"}},
+		{name: "not a string", value: map[string]any{"type": 1, "amount": 10}, want: []string{"type: discriminator", "type: type"}},
+		{name: "missing", value: map[string]any{"amount": 10}, want: []string{"type: required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.value, spec)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+": "+err.Code)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+
+	result := Validate(map[string]any{"type": "cash", "amount": 10}, spec)
+	if want := "type must be one of card, paypal"; result.Errors[0].Message != want {
+		t.Errorf("expected %q, got %q", want, result.Errors[0].Message)
+	}
+}
+
+func TestValidateDiscriminatorRef(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.Register("card@1", &Spec{Type: "object", Required: []string{"number"}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	spec := &Spec{Type: "object", Required: []string{"type"}, Discriminator: &Discriminator{
+		PropertyName: "type",
+		Mapping:      map[string]*Spec{"card": {Ref: "card@1"}},
+	}}
+
+	result := NewValidator(WithRegistry(reg)).Validate(map[string]any{"type": "card"}, spec)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "number" || result.Errors[0].Code != CodeRequired {
+		t.Errorf("expected number to be required, got %v", result.Errors)
+	}
+
+	result = Validate(map[string]any{}, spec)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "type" {
+		t.Errorf("expected one required error, got %v", result.Errors)
+	}
+}
+
+func TestValidateAtDiscriminator(t *testing.T) {
+	spec, err := ParseSpecString(paymentSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result, err := ValidateAt(map[string]any{"type": "paypal", "email": "nope"}, spec, "email")
+	if err != nil {
+		t.Fatalf("ValidateAt failed: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Code != CodeFormat {
+		t.Errorf("expected a format error, got %v", result.Errors)
+	}
+}
+
+func TestCompileDiscriminator(t *testing.T) {
+	tests := []struct {
+		specJSON string
+		wantErr  string
+	}{
+		{`{"type": "object", "discriminator": {"mapping": {"a": {}}}}`, "(root): discriminator has no propertyName"},
+		{`{"type": "object", "discriminator": {"propertyName": "kind"}}`, "(root): discriminator has no mapping"},
+		{`{"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"a": {"type": "string"}}}}`, "branch a must be an object"},
+		{`{"type": "object", "discriminator": {"propertyName": "kind", "mapping": {"a": {"properties": {"x": {"type": "string", "pattern": "("}}}}}}`, "x: invalid pattern"},
+	}
+
+	for _, tt := range tests {
+		spec, err := ParseSpecString(tt.specJSON)
+		if err != nil {
+			t.Fatalf("Failed to parse spec: %v", err)
+		}
+		if _, err := Compile(spec); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.specJSON, tt.wantErr, err)
+		}
+	}
+}
+
+func TestGenerateDiscriminator(t *testing.T) {
+	spec, err := ParseSpecString(paymentSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	gen := NewGenerator(spec, 1)
+	types := map[any]bool{}
+	violations := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		instance := gen.Valid()
+		if result := Validate(instance, spec); !result.Valid {
+			t.Fatalf("instance %#v does not validate: %v", instance, result.Errors)
+		}
+		types[instance.(map[string]any)["type"]] = true
+
+		if _, violation, ok := gen.Invalid(); ok {
+			violations[violation.String()] = true
+		}
+	}
+	if !types["card"] || !types["paypal"] {
+		t.Errorf("expected both branches, got %v", types)
+	}
+	for _, want := range []string{"type: discriminator", "number: required", "email: required"} {
+		if !violations[want] {
+			t.Errorf("expected violation %s, got %v", want, violations)
+		}
+	}
+}
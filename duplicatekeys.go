@@ -0,0 +1,90 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// WithDuplicateKeys reports object keys that appear more than once in raw
+// JSON validated by ValidateJSON and DecodeAndValidate, which encoding/json
+// silently resolves by keeping the last value. Each duplicated key is an
+// error with code duplicateKey at its path, as duplicates usually indicate a
+// client bug.
+func WithDuplicateKeys() Option {
+	return func(v *Validator) {
+		v.duplicateKeys = true
+	}
+}
+
+// duplicateKey is a key that appears more than once in an object
+type duplicateKey struct {
+	path  string // Path of the key's value, e.g. "items[0].id"
+	key   string
+	count int
+}
+
+// findDuplicateKeys scans the tokens of a JSON document for keys that
+// appear more than once in an object, in document order
+func findDuplicateKeys(data []byte) ([]duplicateKey, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var duplicates []duplicateKey
+	if err := scanDuplicateKeys(dec, "", &duplicates); err != nil {
+		return nil, err
+	}
+	return duplicates, nil
+}
+
+// scanDuplicateKeys reads the value the decoder is at, appending the
+// duplicate keys of it and the values inside it
+func scanDuplicateKeys(dec *json.Decoder, path string, duplicates *[]duplicateKey) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		counts := make(map[string]int)
+		var keys []string
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			if counts[key]++; counts[key] == 2 {
+				keys = append(keys, key)
+			}
+			if err := scanDuplicateKeys(dec, buildPath(path, key), duplicates); err != nil {
+				return err
+			}
+		}
+		for _, key := range keys {
+			*duplicates = append(*duplicates, duplicateKey{path: buildPath(path, key), key: key, count: counts[key]})
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := scanDuplicateKeys(dec, buildArrayPath(path, i), duplicates); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
+
+// addDuplicateKeys reports the duplicate keys of the JSON document the
+// result validated
+func (r *ValidationResult) addDuplicateKeys(data []byte) error {
+	duplicates, err := findDuplicateKeys(data)
+	if err != nil {
+		return err
+	}
+	for _, d := range duplicates {
+		r.addError(d.path, CodeDuplicateKey, fmt.Sprintf("duplicate key %q appears %d times; only the last value is used", d.key, d.count),
+			map[string]any{"key": d.key, "count": d.count})
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package mowgli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDuplicateKeys(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "object", "properties": {"id": {"type": "integer"}}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string // path: message
+	}{
+		{"no duplicates", `{"id": 1, "items": [{"id": 1}, {"id": 2}]}`, nil},
+		{"top level", `{"id": 1, "name": "a", "id": 2}`,
+			[]string{`id: duplicate key "id" appears 2 times; only the last value is used`}},
+		{"nested in arrays", `{"items": [{"sku": "a"}, {"sku": "b", "qty": 1, "sku": "c", "sku": "d"}]}`,
+			[]string{`items[1].sku: duplicate key "sku" appears 3 times; only the last value is used`}},
+		{"inside a duplicate", `{"meta": {"a": 1, "a": 2}, "meta": {"b": 1}}`,
+			[]string{
+				`meta.a: duplicate key "a" appears 2 times; only the last value is used`,
+				`meta: duplicate key "meta" appears 2 times; only the last value is used`,
+			}},
+		{"escaped keys", `{"a\u0062": 1, "ab": 2}`,
+			[]string{`ab: duplicate key "ab" appears 2 times; only the last value is used`}},
+	}
+
+	v := NewValidator(WithDuplicateKeys())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := v.ValidateJSON([]byte(tt.data), spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				if !errors.Is(e, ErrDuplicateKey) {
+					t.Errorf("unexpected error: %v", e)
+				}
+				got = append(got, e.Path+": "+e.Message)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.errors, "; ") {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+			if result.Valid != (len(tt.errors) == 0) {
+				t.Errorf("expected valid %v", len(tt.errors) == 0)
+			}
+		})
+	}
+
+	// Without the option, the last value wins silently
+	result, err := ValidateJSON([]byte(`{"id": 1, "id": 2}`), spec)
+	if err != nil || !result.Valid {
+		t.Errorf("expected the document to be valid without WithDuplicateKeys, got %v, %v", result, err)
+	}
+}
+
+func TestDuplicateKeysDecode(t *testing.T) {
+	type Order struct {
+		ID int `json:"id"`
+	}
+	v := NewValidator(WithDuplicateKeys())
+	result, _, err := DecodeAndValidate[Order](strings.NewReader(`{"id": 1, "id": 2}`), WithValidator(v))
+	if err != nil {
+		t.Fatalf("DecodeAndValidate failed: %v", err)
+	}
+	if result.Valid || result.Errors[0].Code != CodeDuplicateKey || result.Errors[0].Params["count"] != 2 {
+		t.Errorf("expected a duplicate key error, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,86 @@
+package mowgli
+
+import "errors"
+
+// Error kinds group related error codes. Every ValidationError with a known
+// code wraps its kind, so callers can branch with errors.Is instead of
+// comparing codes or messages, e.g. errors.Is(err, mowgli.ErrRange).
+var (
+	ErrRequired      = errors.New("required value missing")    // required, anyRequired, oneRequired
+	ErrType          = errors.New("wrong type")                // type
+	ErrRange         = errors.New("value out of range")        // min, max, minInt, maxInt
+	ErrLength        = errors.New("length out of range")       // minLength, maxLength, minBytes, maxBytes
+	ErrPattern       = errors.New("pattern mismatch")          // pattern
+	ErrFormat        = errors.New("invalid format")            // format, timeFormat, semverRange, uriSchemes, publicHost, contentEncoding, contentMediaType
+	ErrEnum          = errors.New("value not allowed")         // enum, discriminator
+	ErrUniqueItems   = errors.New("duplicate items")           // uniqueItems
+	ErrCondition     = errors.New("condition failed")          // condition
+	ErrCheck         = errors.New("check failed")              // check
+	ErrInvalidSpec   = errors.New("invalid spec")              // invalidSpec
+	ErrReadOnly      = errors.New("read-only value")           // readOnly
+	ErrWriteOnly     = errors.New("write-only value")          // writeOnly
+	ErrDeprecated    = errors.New("deprecated value")          // deprecated
+	ErrLimitExceeded = errors.New("validation limit exceeded") // limitExceeded; see also LimitError
+	ErrAssert        = errors.New("assertion failed")          // assert
+	ErrRefIntegrity  = errors.New("dangling reference")        // refIntegrity
+	ErrDuplicateKey  = errors.New("duplicate key")             // duplicateKey
+	ErrUnknownField  = errors.New("unknown field")             // unknownField
+)
+
+// errorKinds maps error codes to their kind
+var errorKinds = map[string]error{
+	CodeRequired:         ErrRequired,
+	CodeAnyRequired:      ErrRequired,
+	CodeOneRequired:      ErrRequired,
+	CodeType:             ErrType,
+	CodeMin:              ErrRange,
+	CodeMax:              ErrRange,
+	CodeMinInt:           ErrRange,
+	CodeMaxInt:           ErrRange,
+	CodeMinLength:        ErrLength,
+	CodeMaxLength:        ErrLength,
+	CodeMinBytes:         ErrLength,
+	CodeMaxBytes:         ErrLength,
+	CodePattern:          ErrPattern,
+	CodeFormat:           ErrFormat,
+	CodeTimeFormat:       ErrFormat,
+	CodeSemverRange:      ErrFormat,
+	CodeURISchemes:       ErrFormat,
+	CodePublicHost:       ErrFormat,
+	CodeContentEncoding:  ErrFormat,
+	CodeContentMediaType: ErrFormat,
+	CodeEnum:             ErrEnum,
+	CodeDiscriminator:    ErrEnum,
+	CodeUniqueItems:      ErrUniqueItems,
+	CodeCondition:        ErrCondition,
+	CodeCheck:            ErrCheck,
+	CodeInvalidSpec:      ErrInvalidSpec,
+	CodeReadOnly:         ErrReadOnly,
+	CodeWriteOnly:        ErrWriteOnly,
+	CodeDeprecated:       ErrDeprecated,
+	CodeLimitExceeded:    ErrLimitExceeded,
+	CodeAssert:           ErrAssert,
+	CodeRefIntegrity:     ErrRefIntegrity,
+	CodeDuplicateKey:     ErrDuplicateKey,
+	CodeUnknownField:     ErrUnknownField,
+}
+
+// Unwrap returns the kind of the error, such as ErrRange for CodeMax, or nil
+// for an unknown code
+func (e *ValidationError) Unwrap() error {
+	return errorKinds[e.Code]
+}
+
+// Err returns the result's errors joined into one error, or nil if the
+// result is valid. errors.Is reports whether any of them is of a kind, and
+// errors.As finds the first *ValidationError.
+func (r *ValidationResult) Err() error {
+	if len(r.Errors) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Errors))
+	for i, err := range r.Errors {
+		errs[i] = err
+	}
+	return errors.Join(errs...)
+}
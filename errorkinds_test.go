@@ -0,0 +1,95 @@
+package mowgli
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "min": 0},
+			"name": {"type": "string", "maxLength": 3},
+			"code": {"type": "string", "pattern": "^[A-Z]+$"},
+			"email": {"type": "string", "format": "email"},
+			"role": {"type": "string", "enum": ["admin"]},
+			"tags": {"type": "array", "uniqueItems": true},
+			"count": {"type": "integer"}
+		},
+		"required": ["id"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{
+		"age":   -1.0,
+		"name":  "Grace",
+		"code":  "abc",
+		"email": "nope",
+		"role":  "root",
+		"tags":  []any{"a", "a"},
+		"count": "1",
+	}, spec)
+
+	kinds := map[string]error{
+		"id":    ErrRequired,
+		"age":   ErrRange,
+		"name":  ErrLength,
+		"code":  ErrPattern,
+		"email": ErrFormat,
+		"role":  ErrEnum,
+		"tags":  ErrUniqueItems,
+		"count": ErrType,
+	}
+	if len(result.Errors) != len(kinds) {
+		t.Fatalf("expected %d errors, got %v", len(kinds), result.Errors)
+	}
+	for _, verr := range result.Errors {
+		if !errors.Is(verr, kinds[verr.Path]) {
+			t.Errorf("expected %s error to be %v", verr.Path, kinds[verr.Path])
+		}
+		if verr.Path != "count" && errors.Is(verr, ErrType) {
+			t.Errorf("expected %s error not to be ErrType", verr.Path)
+		}
+	}
+
+	joined := result.Err()
+	for path, kind := range kinds {
+		if !errors.Is(joined, kind) {
+			t.Errorf("expected result error to include %v (%s)", kind, path)
+		}
+	}
+	if errors.Is(joined, ErrCondition) {
+		t.Error("expected no condition error")
+	}
+	var verr *ValidationError
+	if !errors.As(joined, &verr) {
+		t.Error("expected errors.As to find a ValidationError")
+	}
+}
+
+func TestErrorKindsValid(t *testing.T) {
+	if err := Validate("x", &Spec{Type: "string"}).Err(); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if err := (&ValidationError{Code: "custom"}).Unwrap(); err != nil {
+		t.Errorf("expected no kind for an unknown code, got %v", err)
+	}
+}
+
+func TestErrorKindsWrapped(t *testing.T) {
+	patch, err := ParsePatch([]byte(`[{"op": "replace", "path": "/age", "value": -5}]`))
+	if err != nil {
+		t.Fatalf("Failed to parse patch: %v", err)
+	}
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{"age": {Type: "integer", Min: new(float64)}}}
+	result, err := ValidatePatch(map[string]any{"age": 1.0}, patch, spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Errors) != 1 || !errors.Is(result.Errors[0], ErrRange) {
+		t.Errorf("expected a patch error of kind ErrRange, got %v", result.Errors)
+	}
+}
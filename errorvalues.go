@@ -0,0 +1,95 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// RedactedValue is the Actual value of errors about writeOnly values, such
+// as passwords, and the values inside them
+const RedactedValue = "[redacted]"
+
+// WithErrorValues sets the Actual and Expected fields of errors, e.g. so a
+// UI can render "you sent 200, maximum is 150" without parsing messages.
+// Strings longer than maxSize runes are cut short, and arrays and objects
+// are left out if their JSON is longer than maxSize bytes (default 256).
+// Values of writeOnly specs are reported as RedactedValue.
+func WithErrorValues(maxSize int) Option {
+	return func(v *Validator) {
+		if maxSize <= 0 {
+			maxSize = 256
+		}
+		v.errorValueSize = maxSize
+	}
+}
+
+// currentValue is the value being validated, for the Actual field of its
+// errors
+type currentValue struct {
+	path     string
+	value    any
+	redacted bool // The value is, or is inside, a writeOnly value
+	set      bool
+}
+
+// enterValue makes value the value errors at path report, returning a
+// function restoring the previous one
+func (r *ValidationResult) enterValue(path string, value any, spec *Spec) func() {
+	previous := r.current
+	r.current = currentValue{
+		path:     path,
+		value:    value,
+		redacted: previous.redacted || (spec.WriteOnly != nil && *spec.WriteOnly),
+		set:      true,
+	}
+	return func() {
+		r.current = previous
+	}
+}
+
+// expectedParams are the params holding what a constraint expects, in
+// order of preference
+var expectedParams = []string{"limit", "expected", "allowed", "pattern", "format"}
+
+// setErrorValues sets err's Actual and Expected fields if the result
+// reports them
+func (r *ValidationResult) setErrorValues(err *ValidationError) {
+	if r.errorValueSize <= 0 {
+		return
+	}
+	for _, param := range expectedParams {
+		if expected, ok := err.Params[param]; ok {
+			err.Expected = capValue(expected, r.errorValueSize)
+			break
+		}
+	}
+	if !r.current.set || err.Path != r.current.path {
+		// e.g. a missing required property, reported at its own path
+		return
+	}
+	if r.current.redacted {
+		err.Actual = RedactedValue
+	} else {
+		err.Actual = capValue(r.current.value, r.errorValueSize)
+	}
+}
+
+// capValue returns value cut down to size: a string to maxSize runes,
+// marking the cut with an ellipsis, and nil for other values whose JSON is
+// longer than maxSize bytes
+func capValue(value any, maxSize int) any {
+	switch v := value.(type) {
+	case string:
+		if utf8.RuneCountInString(v) > maxSize {
+			return truncateRunes(v, maxSize)
+		}
+		return v
+	case nil, bool, float64, int, int64, json.Number:
+		return v
+	}
+	data, err := json.Marshal(value)
+	if err != nil || len(data) > maxSize {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,91 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrorValues(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"quantity": {"type": "integer", "max": 150},
+			"code": {"type": "string", "transform": ["trim"], "minLength": 3},
+			"status": {"type": "string", "enum": ["active", "disabled"]},
+			"count": {"type": "integer"},
+			"bio": {"type": "string", "pattern": "^[a-z]+$"},
+			"tags": {"type": "array", "maxLength": 1},
+			"password": {"type": "string", "writeOnly": true, "minLength": 12},
+			"credentials": {"type": "object", "writeOnly": true, "properties": {"pin": {"type": "string", "pattern": "^[0-9]{4}$"}}}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	long := strings.Repeat("X", 40)
+	data := map[string]any{
+		"quantity":    200.0,
+		"code":        " ab ",
+		"status":      "gone",
+		"count":       "many",
+		"bio":         long,
+		"tags":        []any{strings.Repeat("a", 40), "b"},
+		"password":    "hunter2",
+		"credentials": map[string]any{"pin": "12345"},
+	}
+
+	tests := []struct {
+		path     string
+		actual   any
+		expected any
+	}{
+		{"name", nil, nil},
+		{"quantity", 200.0, 150.0},
+		{"code", "ab", 3},
+		{"status", "gone", []string{"active", "disabled"}},
+		{"count", "many", "integer"},
+		{"bio", strings.Repeat("X", 31) + "…", "^[a-z]+$"},
+		{"tags", nil, 1},
+		{"password", RedactedValue, 12},
+		{"credentials.pin", RedactedValue, "^[0-9]{4}$"},
+	}
+
+	result := NewValidator(WithErrorValues(32)).Validate(data, spec)
+	byPath := make(map[string]*ValidationError)
+	for _, err := range result.Errors {
+		byPath[err.Path] = err
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err, ok := byPath[tt.path]
+			if !ok {
+				t.Fatalf("expected an error at %s, got %v", tt.path, result.Errors)
+			}
+			if !reflect.DeepEqual(err.Actual, tt.actual) || !reflect.DeepEqual(err.Expected, tt.expected) {
+				t.Errorf("expected actual %#v and expected %#v, got %#v and %#v", tt.actual, tt.expected, err.Actual, err.Expected)
+			}
+		})
+	}
+
+	// Without the option, errors carry no values
+	for _, err := range Validate(data, spec).Errors {
+		if err.Actual != nil || err.Expected != nil {
+			t.Errorf("expected no values without WithErrorValues, got %v", err)
+		}
+	}
+}
+
+func TestErrorValuesInTemplates(t *testing.T) {
+	formatter, err := TemplateFormatter(`you sent {{.Actual}}, maximum is {{.Expected}}`)
+	if err != nil {
+		t.Fatalf("TemplateFormatter failed: %v", err)
+	}
+	v := NewValidator(WithErrorValues(0), WithFormatter(formatter))
+	result := v.Validate(200.0, &Spec{Type: "integer", Max: float64Ref(150)})
+	if len(result.Errors) != 1 || result.Errors[0].Message != "you sent 200, maximum is 150" {
+		t.Errorf("unexpected errors %v", result.Errors)
+	}
+}
@@ -23,7 +23,7 @@ func ValidateConfig(data map[string]any) (*mowgli.ValidationResult, error) {
 					},
 					"version": {
 						"type": "string",
-						"pattern": "^\\d+\\.\\d+\\.\\d+$"
+						"format": "semver"
 					},
 					"debug": {
 						"type": "boolean"
@@ -0,0 +1,36 @@
+package mowgli
+
+// exprOutcome is the result of evaluating an expression on an object
+type exprOutcome struct {
+	result bool
+	err    error
+}
+
+// evalObjectExpr evaluates an expression on the object being validated,
+// whose environment is env, and records it in the trace as kind at path. An
+// expression is evaluated once per object, however many conditions,
+// requiredIf entries and asserts of the object use it; later uses get the
+// same outcome and are traced as Cached.
+func (r *ValidationResult) evalObjectExpr(path, kind, expression string, env map[string]any) (bool, error) {
+	// Conditions evaluated outside validation, e.g. to generate examples,
+	// have no cache
+	var cache map[string]exprOutcome
+	if len(r.exprCache) > 0 {
+		if r.exprCache[len(r.exprCache)-1] == nil {
+			r.exprCache[len(r.exprCache)-1] = make(map[string]exprOutcome)
+		}
+		cache = r.exprCache[len(r.exprCache)-1]
+	}
+	outcome, cached := cache[expression]
+	if !cached {
+		outcome.result, outcome.err = r.evalCondition(expression, env)
+		if cache != nil {
+			cache[expression] = outcome
+		}
+	}
+	if r.tracing {
+		r.trace = append(r.trace, TraceEvent{Path: path, Kind: kind, Detail: expression,
+			Result: traceResult(outcome.result, outcome.err), Cached: cached})
+	}
+	return outcome.result, outcome.err
+}
@@ -0,0 +1,77 @@
+package mowgli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExprCache(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"plan": {"type": "string"},
+			"seats": {"type": "integer"},
+			"billing": {"type": "object", "requiredIf": {"vat": "premium(plan)"}, "properties": {"plan": {"type": "string"}}}
+		},
+		"requiredIf": {"invoiceEmail": "premium(plan)"},
+		"conditions": [
+			{"if": "premium(plan)", "then": {"seats": {"min": 5}}},
+			{"if": "premium(plan)", "required": ["billing"]}
+		],
+		"asserts": [{"expr": "!premium(plan) OR seats <= 500"}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	calls := 0
+	v := NewValidator(WithTrace())
+	if err := v.RegisterExprFunc("premium", func(plan string) bool {
+		calls++
+		return plan == "premium"
+	}); err != nil {
+		t.Fatalf("RegisterExprFunc failed: %v", err)
+	}
+
+	result := v.Validate(map[string]any{
+		"plan": "premium", "seats": 10.0, "invoiceEmail": "a@b.c",
+		"billing": map[string]any{"plan": "basic"},
+	}, spec)
+	if !result.Valid {
+		t.Fatalf("expected valid, got %v", result.Errors)
+	}
+	// Once per distinct expression on the document, and once for billing
+	if calls != 3 {
+		t.Errorf("expected premium to be evaluated 3 times, got %d", calls)
+	}
+
+	var cached []string
+	for _, event := range result.Trace() {
+		if event.Cached {
+			cached = append(cached, event.String())
+		}
+	}
+	expected := []string{
+		"(root): condition premium(plan) -> true (cached)",
+		"(root): condition premium(plan) -> true (cached)",
+	}
+	if !slices.Equal(cached, expected) {
+		t.Errorf("unexpected cached events:\n%s", strings.Join(cached, "\n"))
+	}
+}
+
+func TestExprCacheErrors(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"conditions": [{"if": "count >", "required": ["a"]}, {"if": "count >", "required": ["b"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{}, spec)
+	if len(result.Errors) != 2 || result.Errors[0].Message != result.Errors[1].Message {
+		t.Errorf("expected the same error for both conditions, got %v", result.Errors)
+	}
+}
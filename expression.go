@@ -1,13 +1,78 @@
 package mowgli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/builtin"
+	"github.com/expr-lang/expr/file"
+	"github.com/expr-lang/expr/parser"
+	"github.com/expr-lang/expr/parser/lexer"
+	"github.com/expr-lang/expr/types"
+	"github.com/expr-lang/expr/vm"
 )
 
+const (
+	// rootIdentifier refers to the top-level document from within a condition
+	rootIdentifier = "$root"
+	// parentIdentifier refers to the enclosing object from within a condition
+	parentIdentifier = "$parent"
+
+	// containsFunc is the internal name of the contains() helper. "contains" is
+	// an infix operator in expr, so function-call syntax is rewritten to this name.
+	containsFunc = "_contains"
+	// matchesFunc is the internal name of the matches() helper, rewritten for
+	// the same reason as containsFunc
+	matchesFunc = "_matches"
+	// objectsFunc is the internal helper predicates over element fields
+	// iterate with, see nilSafePredicates
+	objectsFunc = "_objects"
+)
+
+// exprOptions are the compile options shared by all condition expressions.
+// They add array-aware helpers and make collection helpers tolerate missing fields.
+var exprOptions = []expr.Option{
+	expr.DisableBuiltin("len"),
+	expr.Function("len", exprLen),
+	expr.Function(containsFunc, exprContains),
+	expr.Function(matchesFunc, exprMatches),
+	expr.Function(objectsFunc, exprObjects),
+	expr.Patch(nilSafePredicates{}),
+}
+
+// predicateBuiltins are the expr builtins taking a collection and a predicate
+var predicateBuiltins = map[string]bool{
+	"all":    true,
+	"any":    true,
+	"none":   true,
+	"one":    true,
+	"filter": true,
+	"count":  true,
+}
+
+// exprFunctions are the names of the functions expressions call without a
+// custom function of the name being registered
+var exprFunctions = func() map[string]bool {
+	functions := map[string]bool{"len": true, containsFunc: true, matchesFunc: true, objectsFunc: true}
+	for _, name := range builtin.Names {
+		functions[name] = true
+	}
+	return functions
+}()
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // evalExpression evaluates an expression in the context of an object
 // Supports expressions like:
 //   - "fieldName == true"
@@ -17,30 +82,52 @@ import (
 //   - "field1 == value1 OR field2 == value2"
 //   - "(field1 == value1) AND (field2 == value2 OR field3 == value3)"
 //   - "fieldName != null"
+//   - "$root.validationLevel == \"strict\"" (top-level document)
+//   - "$parent.enabled == true" (enclosing object)
+//   - "len(items) > 0", "contains(tags, \"admin\")" (array helpers)
+//   - "any(items, .price > 0)", "all(items, .quantity >= 1)" (array predicates)
+//   - "matches(email, \"@internal\\\\.corp$\")" (regular expressions)
 func evalExpression(exprStr string, obj map[string]any) (bool, error) {
+	return evalExpressionLimited(exprStr, obj, ExprLimits{})
+}
+
+// evalExpressionLimited evaluates an expression like evalExpression, enforcing limits
+func evalExpressionLimited(exprStr string, obj map[string]any, limits ExprLimits) (bool, error) {
+	return evalExpressionDefs(exprStr, obj, limits, nil, nil, nil)
+}
+
+// evalExpressionDefs evaluates an expression like evalExpressionLimited, in
+// which the names of the conditions defined in defs stand for their
+// expressions. funcs are the custom functions env holds. The program is
+// taken from programs if not nil, see compileExpression.
+func evalExpressionDefs(exprStr string, env map[string]any, limits ExprLimits, defs conditionDefScope, funcs map[string]any, programs *programSet) (bool, error) {
 	exprStr = strings.TrimSpace(exprStr)
 	if exprStr == "" {
 		return false, fmt.Errorf("empty expression")
 	}
-
-	// Translate AND/OR to &&/|| for expr library compatibility
-	// Use word boundaries to avoid replacing inside other words
-	translatedExpr := translateExpression(exprStr)
-
-	// Replace "null" and "nil" with nil for expr compatibility
-	translatedExpr = strings.ReplaceAll(translatedExpr, " null ", " nil ")
-	translatedExpr = strings.ReplaceAll(translatedExpr, " null", " nil")
-	translatedExpr = strings.ReplaceAll(translatedExpr, "null ", "nil ")
-	if translatedExpr == "null" {
-		translatedExpr = "nil"
+	if limits.MaxLength > 0 && len(exprStr) > limits.MaxLength {
+		return false, fmt.Errorf("expression is %d bytes long, exceeding the limit of %d", len(exprStr), limits.MaxLength)
 	}
 
-	// Use Eval for dynamic map environments - this allows map keys to shadow built-in functions
-	// when they exist in the provided object
-	result, err := expr.Eval(translatedExpr, obj)
-	if err != nil {
+	// Compiling counts against the time limit too, as expanding defined
+	// conditions can be costly
+	result, err := withTimeout(limits.Timeout, func() (any, error) {
+		compiled := compileExpression(exprStr, limits, defs, funcs, programs)
+		if compiled.err != nil {
+			return nil, compiled.err
+		}
+		result, err := runProgram(compiled.program, env, limits)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate expression '%s': %w", exprStr, err)
+		}
+		return result, nil
+	})
+	if errors.Is(err, errTimeLimit) {
 		return false, fmt.Errorf("failed to evaluate expression '%s': %w", exprStr, err)
 	}
+	if err != nil {
+		return false, err
+	}
 
 	// Convert result to bool
 	if boolResult, ok := result.(bool); ok {
@@ -50,15 +137,528 @@ func evalExpression(exprStr string, obj map[string]any) (bool, error) {
 	return false, fmt.Errorf("expression '%s' did not evaluate to a boolean, got %T: %v", exprStr, result, result)
 }
 
-// translateExpression converts AND/OR to &&/|| while preserving word boundaries
+// exprProgram is an expression compiled for evaluation, with the conditions
+// it uses expanded
+type exprProgram struct {
+	program    *vm.Program
+	references []string // Fields the expression reads, see nodeReferences
+	err        error
+}
+
+// programCacheSize is the number of compiled expressions kept
+const programCacheSize = 4096
+
+// programCache holds compiled expressions by programKey, so that each
+// expression of a spec is expanded and compiled once rather than on every
+// evaluation. It is bounded, as services may compile specs from untrusted
+// sources, e.g. for each tenant.
+var programCache = newLRUCache[string, *exprProgram](programCacheSize)
+
+// programKey identifies what compiling an expression depends on
+func programKey(exprStr string, limits ExprLimits, defs conditionDefScope, funcs map[string]any) string {
+	var key strings.Builder
+	fmt.Fprintf(&key, "%d %d %q\x00", limits.MaxLength, limits.MaxNodes, limits.BannedFunctions)
+	for _, name := range slices.Sorted(maps.Keys(funcs)) {
+		fmt.Fprintf(&key, "%s %T\x00", name, funcs[name])
+	}
+	key.WriteString(defs.key())
+	key.WriteByte(0)
+	key.WriteString(exprStr)
+	return key.String()
+}
+
+// programSet holds the compiled expressions of a CompiledSpec by
+// programKey. They are kept for as long as the spec is, whatever
+// programCache evicts.
+type programSet struct {
+	programs sync.Map
+}
+
+// compileExpression compiles an expression, reusing earlier compilations:
+// those in programs if it is not nil, recent ones otherwise
+func compileExpression(exprStr string, limits ExprLimits, defs conditionDefScope, funcs map[string]any, programs *programSet) *exprProgram {
+	key := programKey(exprStr, limits, defs, funcs)
+	if programs != nil {
+		if compiled, ok := programs.programs.Load(key); ok {
+			return compiled.(*exprProgram)
+		}
+		compiled := buildProgram(exprStr, limits, defs, funcs)
+		programs.programs.Store(key, compiled)
+		return compiled
+	}
+	if compiled, ok := programCache.get(key); ok {
+		return compiled
+	}
+	compiled := buildProgram(exprStr, limits, defs, funcs)
+	programCache.add(key, compiled)
+	return compiled
+}
+
+// buildProgram compiles an expression for objects with any fields: a
+// program looks fields up when it runs, so one program serves every object
+func buildProgram(exprStr string, limits ExprLimits, defs conditionDefScope, funcs map[string]any) *exprProgram {
+	translatedExpr := translateToExpr(exprStr)
+
+	// Expressions that don't parse are reported by expr.Compile below
+	var references []string
+	env := make(types.Map, len(funcs))
+	if tree, err := parser.Parse(translatedExpr); err == nil {
+		if len(defs) > 0 {
+			if err := defs.expand(&tree.Node, limits); err != nil {
+				return &exprProgram{err: fmt.Errorf("failed to evaluate expression '%s': %w", exprStr, err)}
+			}
+		}
+		references = nodeReferences(tree.Node)
+		compileEnv(env, tree.Node)
+	}
+	for name, fn := range funcs {
+		env[name] = types.TypeOf(fn)
+	}
+
+	// Fields missing from the object evaluate to nil
+	options := []expr.Option{expr.Env(env), expr.AllowUndefinedVariables()}
+	// Defined conditions are expanded first, so that the other patches apply to them too
+	if len(defs) > 0 {
+		options = append(options, expr.Patch(defs.expander(limits)))
+	}
+	options = append(options, exprOptions...)
+	if limits.MaxNodes > 0 {
+		options = append(options, expr.MaxNodes(limits.MaxNodes))
+	}
+	banned := &bannedCallFinder{banned: limits.bannedSet()}
+	if len(banned.banned) > 0 {
+		options = append(options, expr.Patch(banned))
+	}
+	program, err := expr.Compile(translatedExpr, options...)
+	if err != nil {
+		return &exprProgram{err: fmt.Errorf("failed to evaluate expression '%s': %w", exprStr, err)}
+	}
+	if banned.found != "" {
+		return &exprProgram{err: fmt.Errorf("expression '%s' calls banned function %s", exprStr, banned.found)}
+	}
+	return &exprProgram{program: program, references: references}
+}
+
+// compileEnv adds the identifiers of an expression to env, the environment
+// it is compiled against, so that fields shadow built-in functions (e.g. a
+// field named "count") unless they are called. Their values are only known
+// when the program runs.
+func compileEnv(env types.Map, node ast.Node) {
+	collector := &referenceCollector{callees: make(map[string]bool)}
+	ast.Walk(&node, collector)
+	for _, ref := range collector.refs {
+		if strings.Contains(ref, ".") || (collector.callees[ref] && exprFunctions[ref]) {
+			continue
+		}
+		env[ref] = types.Any
+	}
+}
+
+// ExprLimits bounds the cost of evaluating condition expressions. They matter
+// when specs come from untrusted sources, e.g. tenants in a multi-tenant system.
+// Zero values mean no limit (or expr's defaults for MaxNodes and MemoryBudget).
+//
+// An evaluation that exceeds Timeout fails, but keeps running in the
+// background until it finishes, as expr programs can't be interrupted.
+// MaxNodes and MemoryBudget bound the work it does, except within custom
+// functions, which should return promptly.
+type ExprLimits struct {
+	MaxLength       int           // Maximum length in bytes of expressions and of the conditions they use
+	MaxNodes        uint          // Maximum number of nodes in the parsed expression, with the conditions it uses expanded
+	MemoryBudget    uint          // Maximum memory units the expr VM may allocate per evaluation
+	Timeout         time.Duration // Maximum time a single evaluation may take
+	BannedFunctions []string      // Functions and builtins conditions may not call, e.g. "now", "matches"
+}
+
+// bannedSet returns the banned functions keyed by the name used in compiled expressions
+func (l ExprLimits) bannedSet() map[string]bool {
+	if len(l.BannedFunctions) == 0 {
+		return nil
+	}
+	banned := make(map[string]bool, len(l.BannedFunctions))
+	for _, name := range l.BannedFunctions {
+		switch name {
+		case "contains":
+			name = containsFunc
+		case "matches":
+			name = matchesFunc
+		}
+		banned[name] = true
+	}
+	return banned
+}
+
+// bannedCallFinder records the first call to a banned function in an expression
+type bannedCallFinder struct {
+	banned map[string]bool
+	found  string
+}
+
+func (f *bannedCallFinder) Visit(node *ast.Node) {
+	if f.found != "" {
+		return
+	}
+	name := ""
+	switch n := (*node).(type) {
+	case *ast.BuiltinNode:
+		name = n.Name
+	case *ast.CallNode:
+		if ident, ok := n.Callee.(*ast.IdentifierNode); ok {
+			name = ident.Value
+		}
+	}
+	if f.banned[name] {
+		f.found = strings.TrimPrefix(name, "_")
+	}
+}
+
+// runProgram runs a compiled expression within the memory limit
+func runProgram(program *vm.Program, env map[string]any, limits ExprLimits) (any, error) {
+	machine := vm.VM{MemoryBudget: limits.MemoryBudget}
+	return machine.Run(program, env)
+}
+
+// errTimeLimit is returned by withTimeout when run takes too long
+var errTimeLimit = errors.New("evaluation exceeded time limit")
+
+// withTimeout calls run, giving up on it after timeout if that is positive
+func withTimeout(timeout time.Duration, run func() (any, error)) (any, error) {
+	if timeout <= 0 {
+		return run()
+	}
+
+	type outcome struct {
+		result any
+		err    error
+	}
+	// The expr VM can't be interrupted, so an evaluation that runs out of
+	// time is abandoned rather than stopped, and its goroutine runs on until
+	// the program finishes. What it can do is bounded before it starts:
+	// MaxNodes applies to the expression with its conditions expanded, and
+	// the VM enforces MemoryBudget, both with expr's defaults if unset. A
+	// custom function that never returns holds its goroutine forever.
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := run()
+		done <- outcome{result, err}
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-timer.C:
+		return nil, fmt.Errorf("%w of %s", errTimeLimit, timeout)
+	}
+}
+
+// translateToExpr applies all syntax translations needed to hand exprStr to expr
+func translateToExpr(exprStr string) string {
+	return translateExpression(strings.TrimSpace(exprStr))
+}
+
+// nodeReferences returns the sorted, de-duplicated field references in a
+// parsed expression
+func nodeReferences(node ast.Node) []string {
+	collector := &referenceCollector{callees: make(map[string]bool)}
+	ast.Walk(&node, collector)
+
+	seen := make(map[string]bool)
+	for _, ref := range collector.refs {
+		// Function names are not field references
+		if !collector.callees[ref] {
+			seen[ref] = true
+		}
+	}
+	refs := make([]string, 0, len(seen))
+	for ref := range seen {
+		// Keep only the most specific reference, e.g. "$root.a" rather than "$root"
+		covered := false
+		for other := range seen {
+			if strings.HasPrefix(other, ref+".") {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+	return refs
+}
+
+// referenceCollector gathers identifiers and dotted member paths from an expression AST
+type referenceCollector struct {
+	callees map[string]bool
+	refs    []string
+}
+
+func (c *referenceCollector) Visit(node *ast.Node) {
+	switch n := (*node).(type) {
+	case *ast.CallNode:
+		if ident, ok := n.Callee.(*ast.IdentifierNode); ok {
+			c.callees[ident.Value] = true
+		}
+	case *ast.IdentifierNode:
+		c.refs = append(c.refs, n.Value)
+	case *ast.MemberNode:
+		if path, ok := memberPath(n); ok {
+			c.refs = append(c.refs, path)
+		}
+	}
+}
+
+// memberPath renders a member access chain such as $root.a.b as a dotted path
+func memberPath(node ast.Node) (string, bool) {
+	switch n := node.(type) {
+	case *ast.IdentifierNode:
+		return n.Value, true
+	case *ast.MemberNode:
+		prop, ok := n.Property.(*ast.StringNode)
+		if !ok {
+			return "", false
+		}
+		base, ok := memberPath(n.Node)
+		if !ok {
+			return "", false
+		}
+		return base + "." + prop.Value, true
+	default:
+		return "", false
+	}
+}
+
+// exprKeywords are the words of the expression syntax that expr spells
+// differently
+var exprKeywords = map[string]string{
+	"AND":  "&&",
+	"OR":   "||",
+	"null": "nil",
+}
+
+// helperFuncs are the internal names of the helpers called like operators
+var helperFuncs = map[string]string{
+	"contains": containsFunc,
+	"matches":  matchesFunc,
+}
+
+// translateExpression converts AND/OR to &&/|| and null to nil, and calls
+// of contains() and matches() to calls of their helpers. It rewrites the
+// expression's tokens rather than its text, so string literals such as
+// "contains(x)" are kept as they are; expr's parser rejects contains( and
+// matches( as both are infix operators, so the parsed tree can't be patched
+// instead. Expressions that don't lex are returned unchanged for expr to
+// report.
 func translateExpression(expr string) string {
-	// Match AND/OR with word boundaries (whitespace, parentheses, operators, start/end of string)
-	// Use negative lookbehind and lookahead to ensure we don't replace inside identifiers
-	andPattern := regexp.MustCompile(`\bAND\b`)
-	orPattern := regexp.MustCompile(`\bOR\b`)
+	tokens, err := lexer.Lex(file.NewSource(expr))
+	if err != nil {
+		return expr
+	}
+
+	source := []rune(expr)
+	var translated strings.Builder
+	last := 0
+	for i, token := range tokens {
+		var replacement string
+		switch {
+		case token.Kind == lexer.Identifier && exprKeywords[token.Value] != "":
+			if i > 0 && tokens[i-1].Is(lexer.Operator, ".", "?.") {
+				continue // A field named like a keyword, e.g. "$root.AND"
+			}
+			replacement = exprKeywords[token.Value]
+		case token.Is(lexer.Operator, "contains", "matches") && i+1 < len(tokens) && tokens[i+1].Is(lexer.Bracket, "("):
+			replacement = helperFuncs[token.Value]
+		default:
+			continue
+		}
+		translated.WriteString(string(source[last:token.From]))
+		translated.WriteString(replacement)
+		last = token.To
+	}
+	translated.WriteString(string(source[last:]))
+	return translated.String()
+}
+
+// nilSafePredicates rewrites predicate builtins such as any(items, ...) so that a
+// missing or null collection is treated as empty. Predicates reading element
+// fields, like any(items, .price > 0), skip elements that aren't objects, such
+// as nulls, instead of failing on them.
+type nilSafePredicates struct{}
+
+func (nilSafePredicates) Visit(node *ast.Node) {
+	builtin, ok := (*node).(*ast.BuiltinNode)
+	if !ok || !predicateBuiltins[builtin.Name] || len(builtin.Arguments) == 0 {
+		return
+	}
+	if len(builtin.Arguments) > 1 && readsElementFields(builtin.Arguments[1]) {
+		builtin.Arguments[0] = &ast.CallNode{
+			Callee:    &ast.IdentifierNode{Value: objectsFunc},
+			Arguments: []ast.Node{builtin.Arguments[0]},
+		}
+		return
+	}
+	builtin.Arguments[0] = &ast.BinaryNode{
+		Operator: "??",
+		Left:     builtin.Arguments[0],
+		Right:    &ast.ArrayNode{},
+	}
+}
+
+// readsElementFields reports whether a predicate accesses fields of the
+// current element, e.g. ".price"
+func readsElementFields(predicate ast.Node) bool {
+	finder := &elementFieldFinder{}
+	ast.Walk(&predicate, finder)
+	return finder.found
+}
+
+// elementFieldFinder finds member accesses on the current element of a predicate
+type elementFieldFinder struct {
+	found bool
+}
+
+func (f *elementFieldFinder) Visit(node *ast.Node) {
+	if member, ok := (*node).(*ast.MemberNode); ok {
+		if pointer, ok := member.Node.(*ast.PointerNode); ok && pointer.Name == "" {
+			f.found = true
+		}
+	}
+}
+
+// exprObjects returns the elements of an array that are objects, treating null
+// as empty
+func exprObjects(params ...any) (any, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(params))
+	}
+	if params[0] == nil {
+		return []any{}, nil
+	}
+
+	val := reflect.ValueOf(params[0])
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return nil, fmt.Errorf("cannot iterate over %T", params[0])
+	}
+	objects := make([]any, 0, val.Len())
+	for i := range val.Len() {
+		elem := val.Index(i).Interface()
+		if elem != nil && reflect.ValueOf(elem).Kind() == reflect.Map {
+			objects = append(objects, elem)
+		}
+	}
+	return objects, nil
+}
+
+// exprLen returns the length of a string, array or object, treating null as empty
+func exprLen(params ...any) (any, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("len expects 1 argument, got %d", len(params))
+	}
+	if params[0] == nil {
+		return 0, nil
+	}
+
+	val := reflect.ValueOf(params[0])
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return val.Len(), nil
+	default:
+		return nil, fmt.Errorf("invalid argument for len (type %T)", params[0])
+	}
+}
 
-	expr = andPattern.ReplaceAllString(expr, "&&")
-	expr = orPattern.ReplaceAllString(expr, "||")
+// exprContains reports whether an array holds a value, an object has a key,
+// or a string contains a substring. A null collection contains nothing.
+func exprContains(params ...any) (any, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("contains expects 2 arguments, got %d", len(params))
+	}
+	collection, needle := params[0], params[1]
+	if collection == nil {
+		return false, nil
+	}
+
+	if str, ok := collection.(string); ok {
+		sub, ok := needle.(string)
+		if !ok {
+			return nil, fmt.Errorf("contains on a string expects a string, got %T", needle)
+		}
+		return strings.Contains(str, sub), nil
+	}
 
-	return expr
+	val := reflect.ValueOf(collection)
+	switch val.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if valuesEqual(val.Index(i).Interface(), needle) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case reflect.Map:
+		key := reflect.ValueOf(needle)
+		if !key.IsValid() || !key.Type().AssignableTo(val.Type().Key()) {
+			return false, nil
+		}
+		return val.MapIndex(key).IsValid(), nil
+	default:
+		return nil, fmt.Errorf("invalid argument for contains (type %T)", collection)
+	}
+}
+
+// exprMatches reports whether a string matches a regular expression.
+// A null value matches nothing.
+func exprMatches(params ...any) (any, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("matches expects 2 arguments, got %d", len(params))
+	}
+	pattern, ok := params[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("matches expects a string pattern, got %T", params[1])
+	}
+	re, err := compilePattern(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	if params[0] == nil {
+		return false, nil
+	}
+	str, ok := params[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("matches expects a string, got %T", params[0])
+	}
+	return re.MatchString(str), nil
+}
+
+// valuesEqual compares two values, treating all numeric types as comparable
+// (JSON numbers decode as float64 while expression literals are int)
+func valuesEqual(a, b any) bool {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf && numbersEqual(a, b, af)
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// toFloat converts any Go numeric value, or json.Number, to float64
+func toFloat(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	default:
+		return 0, false
+	}
 }
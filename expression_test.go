@@ -1,7 +1,9 @@
 package mowgli
 
 import (
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestEvalExpressionAND(t *testing.T) {
@@ -379,3 +381,441 @@ func TestEvalExpressionErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestEvalExpressionArrayHelpers(t *testing.T) {
+	items := []any{
+		map[string]any{"price": 10.0, "quantity": 1.0},
+		map[string]any{"price": 0.0, "quantity": 3.0},
+	}
+
+	tests := []struct {
+		name     string
+		expr     string
+		obj      map[string]any
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:     "len of array",
+			expr:     "len(items) == 2",
+			obj:      map[string]any{"items": items},
+			expected: true,
+		},
+		{
+			name:     "len of missing array is zero",
+			expr:     "len(items) == 0",
+			obj:      map[string]any{},
+			expected: true,
+		},
+		{
+			name:     "contains string in array",
+			expr:     `contains(tags, "admin")`,
+			obj:      map[string]any{"tags": []any{"user", "admin"}},
+			expected: true,
+		},
+		{
+			name:     "contains string not in array",
+			expr:     `contains(tags, "admin")`,
+			obj:      map[string]any{"tags": []any{"user"}},
+			expected: false,
+		},
+		{
+			name:     "contains number matches JSON float",
+			expr:     "contains(codes, 3)",
+			obj:      map[string]any{"codes": []any{1.0, 3.0}},
+			expected: true,
+		},
+		{
+			name:     "contains on missing array",
+			expr:     `contains(tags, "admin")`,
+			obj:      map[string]any{},
+			expected: false,
+		},
+		{
+			name:     "contains combined with AND",
+			expr:     `contains(tags, "admin") AND len(tags) > 1`,
+			obj:      map[string]any{"tags": []any{"user", "admin"}},
+			expected: true,
+		},
+		{
+			name:     "any matches",
+			expr:     "any(items, .price > 0)",
+			obj:      map[string]any{"items": items},
+			expected: true,
+		},
+		{
+			name:     "all does not match",
+			expr:     "all(items, .price > 0)",
+			obj:      map[string]any{"items": items},
+			expected: false,
+		},
+		{
+			name:     "all matches",
+			expr:     "all(items, .quantity >= 1)",
+			obj:      map[string]any{"items": items},
+			expected: true,
+		},
+		{
+			name:     "any on missing array",
+			expr:     "any(items, .price > 0)",
+			obj:      map[string]any{},
+			expected: false,
+		},
+		{
+			name:     "none matches",
+			expr:     `none(addresses, .country == "US")`,
+			obj:      map[string]any{"addresses": []any{map[string]any{"country": "CA"}}},
+			expected: true,
+		},
+		{
+			name:     "one matches",
+			expr:     "one(items, .price == 0)",
+			obj:      map[string]any{"items": items},
+			expected: true,
+		},
+		{
+			name:     "count of matching elements",
+			expr:     "count(items, .quantity > 1) == 1",
+			obj:      map[string]any{"items": items},
+			expected: true,
+		},
+		{
+			name:     "predicate skips null elements",
+			expr:     "all(items, .quantity > 0)",
+			obj:      map[string]any{"items": []any{nil, map[string]any{"quantity": 1.0}}},
+			expected: true,
+		},
+		{
+			name:     "predicate skips scalar elements",
+			expr:     `any(addresses, .country == "US")`,
+			obj:      map[string]any{"addresses": []any{"US", map[string]any{"country": "US"}}},
+			expected: true,
+		},
+		{
+			name:     "predicate over scalar elements",
+			expr:     `any(tags, # == "admin")`,
+			obj:      map[string]any{"tags": []any{"user", "admin"}},
+			expected: true,
+		},
+		{
+			name:     "nested predicates",
+			expr:     "any(orders, all(.lines, .quantity > 0))",
+			obj:      map[string]any{"orders": []any{map[string]any{"lines": []any{map[string]any{"quantity": 2.0}}}}},
+			expected: true,
+		},
+		{
+			name:     "nested predicate on missing array",
+			expr:     "any(orders, any(.lines, .quantity > 0))",
+			obj:      map[string]any{"orders": []any{map[string]any{}}},
+			expected: false,
+		},
+		{
+			name:    "predicate over non-array",
+			expr:    "any(items, .price > 0)",
+			obj:     map[string]any{"items": "none"},
+			wantErr: true,
+		},
+		{
+			name:     "field named like builtin is still a field",
+			expr:     "count > 0",
+			obj:      map[string]any{"count": 5},
+			expected: true,
+		},
+		{
+			name:    "len of number",
+			expr:    "len(count) > 0",
+			obj:     map[string]any{"count": 5},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evalExpression(tt.expr, tt.obj)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestTranslateExpressionLiterals(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: `contains(tags, "a") AND note == "contains(x)"`, want: `_contains(tags, "a") && note == "contains(x)"`},
+		{expr: `note == 'matches(a, b) OR null' OR matches(code, "^A")`, want: `note == 'matches(a, b) OR null' || _matches(code, "^A")`},
+		{expr: `tags contains "a"`, want: `tags contains "a"`},
+		{expr: `nullable == null`, want: `nullable == nil`},
+		{expr: `$root.AND == true`, want: `$root.AND == true`},
+		{expr: `"unterminated AND`, want: `"unterminated AND`},
+	}
+	for _, tt := range tests {
+		if got := translateToExpr(tt.expr); got != tt.want {
+			t.Errorf("translateToExpr(%s): expected %s, got %s", tt.expr, tt.want, got)
+		}
+	}
+
+	obj := map[string]any{"note": "contains(x) AND null", "tags": []any{"a"}}
+	result, err := evalExpression(`note == "contains(x) AND null" AND contains(tags, "a")`, obj)
+	if err != nil || !result {
+		t.Errorf("expected the literal to be kept, got %v, %v", result, err)
+	}
+}
+
+func TestEvalExpressionMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		obj      map[string]any
+		expected bool
+		wantErr  bool
+	}{
+		{
+			name:     "matches",
+			expr:     `matches(email, "@internal\\.corp$")`,
+			obj:      map[string]any{"email": "ops@internal.corp"},
+			expected: true,
+		},
+		{
+			name:     "does not match",
+			expr:     `matches(email, "@internal\\.corp$")`,
+			obj:      map[string]any{"email": "ops@internalXcorp"},
+			expected: false,
+		},
+		{
+			name:     "missing field does not match",
+			expr:     `matches(email, "@internal\\.corp$")`,
+			obj:      map[string]any{},
+			expected: false,
+		},
+		{
+			name:     "negated with OR",
+			expr:     `!matches(email, "^admin@") OR isAdmin`,
+			obj:      map[string]any{"email": "admin@example.com", "isAdmin": true},
+			expected: true,
+		},
+		{
+			name:    "invalid pattern",
+			expr:    `matches(email, "[")`,
+			obj:     map[string]any{"email": "a"},
+			wantErr: true,
+		},
+		{
+			name:    "non-string value",
+			expr:    `matches(count, "1")`,
+			obj:     map[string]any{"count": 1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evalExpression(tt.expr, tt.obj)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestEvalExpressionLimits(t *testing.T) {
+	slow := func() bool {
+		time.Sleep(200 * time.Millisecond)
+		return true
+	}
+
+	tests := []struct {
+		name    string
+		expr    string
+		obj     map[string]any
+		limits  ExprLimits
+		wantErr string
+	}{
+		{
+			name:   "within limits",
+			expr:   `matches(email, "@") AND count > 0`,
+			obj:    map[string]any{"email": "a@b", "count": 1},
+			limits: ExprLimits{MaxLength: 100, MaxNodes: 20, Timeout: time.Second, BannedFunctions: []string{"now"}},
+		},
+		{
+			name:    "too long",
+			expr:    "count > 0 AND count < 100",
+			obj:     map[string]any{"count": 1},
+			limits:  ExprLimits{MaxLength: 10},
+			wantErr: "exceeding the limit of 10",
+		},
+		{
+			name:    "too many nodes",
+			expr:    "a + a + a + a + a + a + a + a > 0",
+			obj:     map[string]any{"a": 1},
+			limits:  ExprLimits{MaxNodes: 5},
+			wantErr: "exceeds maximum allowed nodes",
+		},
+		{
+			name:    "memory budget",
+			expr:    "len(1..100000) > 0",
+			obj:     map[string]any{},
+			limits:  ExprLimits{MemoryBudget: 1000},
+			wantErr: "memory budget exceeded",
+		},
+		{
+			name:    "timeout",
+			expr:    "slow()",
+			obj:     map[string]any{"slow": slow},
+			limits:  ExprLimits{Timeout: 10 * time.Millisecond},
+			wantErr: "exceeded time limit",
+		},
+		{
+			name:    "banned builtin",
+			expr:    "now() != nil",
+			obj:     map[string]any{},
+			limits:  ExprLimits{BannedFunctions: []string{"now"}},
+			wantErr: "calls banned function now",
+		},
+		{
+			name:    "banned helper",
+			expr:    `matches(email, "a")`,
+			obj:     map[string]any{"email": "a"},
+			limits:  ExprLimits{BannedFunctions: []string{"matches"}},
+			wantErr: "calls banned function matches",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := evalExpressionLimited(tt.expr, tt.obj, tt.limits)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestQuantifiedConditions(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"currency": {"type": "string"},
+			"items": {"type": "array"},
+			"customsForm": {"type": "string"}
+		},
+		"requiredIf": {
+			"customsForm": "any(items, .origin != null AND .origin != $root.country)"
+		},
+		"conditions": [{"if": "any(items, .currency != $root.currency)", "required": ["exchangeRate"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		valid bool
+	}{
+		{"no items", map[string]any{"currency": "USD"}, true},
+		{"domestic items", map[string]any{"country": "US", "currency": "USD", "items": []any{
+			map[string]any{"origin": "US", "currency": "USD"}, nil,
+		}}, true},
+		{"imported item", map[string]any{"country": "US", "currency": "USD", "items": []any{
+			map[string]any{"origin": "DE", "currency": "USD"},
+		}}, false},
+		{"imported item with customs form", map[string]any{"country": "US", "currency": "USD", "customsForm": "CN22", "items": []any{
+			map[string]any{"origin": "DE", "currency": "USD"},
+		}}, true},
+		{"foreign currency", map[string]any{"currency": "USD", "items": []any{
+			map[string]any{"currency": "EUR"},
+		}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Validate(tt.data, spec); result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestConditionUnless(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"guestCheckout": {"type": "boolean"}, "accountId": {"type": "string"}, "total": {"type": "number"}},
+		"conditions": [
+			{"unless": "guestCheckout", "required": ["accountId"]},
+			{"if": "total > 100", "unless": "guestCheckout == true OR accountId == \"staff\"", "then": {"total": {"max": 500}}}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		data  map[string]any
+		valid bool
+	}{
+		{"guest without account", map[string]any{"guestCheckout": true, "total": 50.0}, true},
+		{"member without account", map[string]any{"guestCheckout": false, "total": 50.0}, false},
+		{"member with account", map[string]any{"guestCheckout": false, "accountId": "a1", "total": 50.0}, true},
+		{"member over limit", map[string]any{"guestCheckout": false, "accountId": "a1", "total": 600.0}, false},
+		{"staff over limit", map[string]any{"guestCheckout": false, "accountId": "staff", "total": 600.0}, true},
+		{"guest over limit", map[string]any{"guestCheckout": true, "total": 600.0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := Validate(tt.data, spec); result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestConditionExpression(t *testing.T) {
+	tests := []struct {
+		condition Condition
+		expected  string
+	}{
+		{Condition{If: "a > 1"}, "a > 1"},
+		{Condition{Unless: "guest"}, "!(guest)"},
+		{Condition{If: "a > 1", Unless: "b OR c"}, "(a > 1) AND !(b OR c)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.condition.Expression(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
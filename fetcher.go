@@ -0,0 +1,195 @@
+package mowgli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTPFetcherConfig configures an HTTPFetcher
+type HTTPFetcherConfig struct {
+	Client       *http.Client  // Client making the requests (default http.DefaultClient)
+	MaxRetries   int           // Retries of requests that fail with a network error, 429 or 5xx (default 0)
+	RetryBackoff time.Duration // Delay before the first retry, doubled for each further retry (default 100ms)
+	MaxBytes     int64         // Maximum size of a spec (default 10 MiB)
+	// TrustKeys, if not nil, makes the fetcher expect SignedSpecs signed
+	// with one of these keys and reject specs that don't verify; an empty
+	// set rejects every spec
+	TrustKeys TrustKeys
+}
+
+// HTTPFetcher fetches specs from a schema registry over HTTP. It caches
+// each spec for as long as the response's Cache-Control max-age allows and
+// then revalidates it with its ETag, so unchanged specs aren't downloaded
+// again. When a fetch fails after its retries, the last spec fetched for
+// the ref is returned instead, so an unavailable registry doesn't stop
+// validation. An HTTPFetcher is safe for concurrent use.
+type HTTPFetcher struct {
+	urlPattern string
+	config     HTTPFetcherConfig
+
+	mu    sync.Mutex
+	cache map[string]*fetchedSpec // by URL
+}
+
+// fetchedSpec is the last spec fetched from a URL
+type fetchedSpec struct {
+	spec    *Spec
+	etag    string
+	expires time.Time // Revalidated after this time
+}
+
+// NewHTTPFetcher creates a fetcher for specs at urlPattern, in which
+// "{ref}" is replaced by the ref, e.g.
+// "https://schemas.example.com/specs/{ref}.json"
+func NewHTTPFetcher(urlPattern string, config HTTPFetcherConfig) *HTTPFetcher {
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 100 * time.Millisecond
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 10 << 20
+	}
+	return &HTTPFetcher{urlPattern: urlPattern, config: config, cache: make(map[string]*fetchedSpec)}
+}
+
+// LoadSpec fetches the spec for ref, implementing SpecLoader so the fetcher
+// can back a Registry (see Registry.SetLoader)
+func (f *HTTPFetcher) LoadSpec(ctx context.Context, ref string) (*Spec, error) {
+	return f.Fetch(ctx, ref)
+}
+
+// Fetch returns the spec for ref, from the cache while it is fresh
+func (f *HTTPFetcher) Fetch(ctx context.Context, ref string) (*Spec, error) {
+	specURL := strings.ReplaceAll(f.urlPattern, "{ref}", url.PathEscape(ref))
+
+	f.mu.Lock()
+	cached := f.cache[specURL]
+	f.mu.Unlock()
+	if cached != nil && time.Now().Before(cached.expires) {
+		return cached.spec, nil
+	}
+
+	fetched, err := f.fetch(ctx, specURL, cached)
+	if err != nil {
+		if cached != nil {
+			return cached.spec, nil
+		}
+		return nil, fmt.Errorf("failed to fetch spec %s: %w", ref, err)
+	}
+
+	f.mu.Lock()
+	f.cache[specURL] = fetched
+	f.mu.Unlock()
+	return fetched.spec, nil
+}
+
+// Source returns a SpecSource fetching the given refs, named by ref, so a
+// SpecStore can keep them up to date
+func (f *HTTPFetcher) Source(refs ...string) SpecSource {
+	return SpecSourceFunc(func(ctx context.Context) (map[string]*Spec, error) {
+		specs := make(map[string]*Spec, len(refs))
+		for _, ref := range refs {
+			spec, err := f.Fetch(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+			specs[ref] = spec
+		}
+		return specs, nil
+	})
+}
+
+// fetch requests specURL, revalidating cached if it is set, and retries
+// temporary failures
+func (f *HTTPFetcher) fetch(ctx context.Context, specURL string, cached *fetchedSpec) (*fetchedSpec, error) {
+	backoff := f.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		fetched, err := f.request(ctx, specURL, cached)
+		if err == nil || !IsTemporary(err) || attempt >= f.config.MaxRetries {
+			return fetched, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// request makes a single request for specURL. Network errors, 429 and 5xx
+// responses are returned as Temporary errors.
+func (f *HTTPFetcher) request(ctx context.Context, specURL string, cached *fetchedSpec) (*fetchedSpec, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := f.config.Client.Do(req)
+	if err != nil {
+		return nil, Temporary(err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		return &fetchedSpec{spec: cached.spec, etag: cached.etag, expires: cacheExpiry(resp.Header)}, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, Temporary(fmt.Errorf("%s: %s", specURL, resp.Status))
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s: %s", specURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.config.MaxBytes+1))
+	if err != nil {
+		return nil, Temporary(fmt.Errorf("failed to read %s: %w", specURL, err))
+	}
+	if int64(len(body)) > f.config.MaxBytes {
+		return nil, fmt.Errorf("%s: spec exceeds %d bytes", specURL, f.config.MaxBytes)
+	}
+	var spec *Spec
+	if f.config.TrustKeys != nil {
+		if spec, err = VerifySpec(body, f.config.TrustKeys); err != nil {
+			return nil, fmt.Errorf("spec from %s: %w", specURL, err)
+		}
+	} else if spec, err = ParseSpec(body); err != nil {
+		return nil, fmt.Errorf("failed to parse spec from %s: %w", specURL, err)
+	}
+	if _, err := Compile(spec); err != nil {
+		return nil, fmt.Errorf("spec from %s: %w", specURL, err)
+	}
+	return &fetchedSpec{spec: spec, etag: resp.Header.Get("ETag"), expires: cacheExpiry(resp.Header)}, nil
+}
+
+// cacheExpiry returns until when a response may be used without
+// revalidation, by its Cache-Control max-age. Responses without one, or
+// with no-cache or no-store, are revalidated on every fetch.
+func cacheExpiry(header http.Header) time.Time {
+	now := time.Now()
+	maxAge := time.Duration(0)
+	for directive := range strings.SplitSeq(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-cache", "no-store":
+			return now
+		case "max-age":
+			if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+				maxAge = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return now.Add(maxAge)
+}
@@ -0,0 +1,167 @@
+package mowgli
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// schemaRegistry serves specs by path with ETags and counts requests
+type schemaRegistry struct {
+	mu           sync.Mutex
+	specs        map[string]string
+	cacheControl string
+	failures     int // Requests to fail with 503 before serving
+	requests     []string
+}
+
+func (s *schemaRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		s.requests = append(s.requests, "503")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	spec, ok := s.specs[r.URL.Path]
+	if !ok {
+		s.requests = append(s.requests, "404")
+		http.NotFound(w, r)
+		return
+	}
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256([]byte(spec)))
+	if s.cacheControl != "" {
+		w.Header().Set("Cache-Control", s.cacheControl)
+	}
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.requests = append(s.requests, "304")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.requests = append(s.requests, "200")
+	w.Write([]byte(spec))
+}
+
+func (s *schemaRegistry) take() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := strings.Join(s.requests, " ")
+	s.requests = nil
+	return requests
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	registry := &schemaRegistry{specs: map[string]string{"/specs/user@1.json": `{"type": "object", "required": ["name"]}`}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	f := NewHTTPFetcher(server.URL+"/specs/{ref}.json", HTTPFetcherConfig{MaxRetries: 2, RetryBackoff: time.Millisecond})
+	ctx := context.Background()
+
+	// Without max-age, every fetch revalidates
+	first, err := f.Fetch(ctx, "user@1")
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+	second, err := f.Fetch(ctx, "user@1")
+	if err != nil || second != first {
+		t.Errorf("expected the revalidated spec to be reused, got %v, %v", second, err)
+	}
+	if got := registry.take(); got != "200 304" {
+		t.Errorf("expected a download and a revalidation, got %q", got)
+	}
+
+	// A changed spec is downloaded again, and fresh responses aren't revalidated
+	registry.mu.Lock()
+	registry.specs["/specs/user@1.json"] = `{"type": "object"}`
+	registry.cacheControl = "public, max-age=60"
+	registry.mu.Unlock()
+	changed, _ := f.Fetch(ctx, "user@1")
+	if changed == first || len(changed.Required) != 0 {
+		t.Errorf("expected the changed spec, got %+v", changed)
+	}
+	f.Fetch(ctx, "user@1")
+	if got := registry.take(); got != "200" {
+		t.Errorf("expected a single download, got %q", got)
+	}
+
+	// Temporary failures are retried
+	registry.mu.Lock()
+	registry.specs["/specs/order@1.json"] = `{"type": "object"}`
+	registry.failures = 2
+	registry.mu.Unlock()
+	if _, err := f.Fetch(ctx, "order@1"); err != nil {
+		t.Errorf("expected the retries to succeed, got %v", err)
+	}
+	if got := registry.take(); got != "503 503 200" {
+		t.Errorf("expected two retries, got %q", got)
+	}
+
+	// Unknown refs fail without retries
+	if _, err := f.Fetch(ctx, "missing@1"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+	if got := registry.take(); got != "404" {
+		t.Errorf("expected no retries, got %q", got)
+	}
+}
+
+func TestHTTPFetcherFallback(t *testing.T) {
+	registry := &schemaRegistry{specs: map[string]string{"/user": `{"type": "string"}`}, cacheControl: "no-cache"}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	f := NewHTTPFetcher(server.URL+"/{ref}", HTTPFetcherConfig{})
+	good, err := f.Fetch(context.Background(), "user")
+	if err != nil {
+		t.Fatalf("Fetch returned error: %v", err)
+	}
+
+	// A failing registry or a broken spec falls back to the last good spec
+	registry.failures = 1
+	if spec, err := f.Fetch(context.Background(), "user"); err != nil || spec != good {
+		t.Errorf("expected the last good spec, got %v, %v", spec, err)
+	}
+	registry.specs["/user"] = `{"type": "string", "pattern": "["}`
+	if spec, err := f.Fetch(context.Background(), "user"); err != nil || spec != good {
+		t.Errorf("expected the last good spec, got %v, %v", spec, err)
+	}
+	server.Close()
+	if spec, err := f.Fetch(context.Background(), "user"); err != nil || spec != good {
+		t.Errorf("expected the last good spec, got %v, %v", spec, err)
+	}
+}
+
+func TestRegistryLoader(t *testing.T) {
+	registry := &schemaRegistry{specs: map[string]string{"/address@1": `{"type": "object", "required": ["city"]}`}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	reg := NewRegistry()
+	reg.SetLoader(NewHTTPFetcher(server.URL+"/{ref}", HTTPFetcherConfig{}))
+	if err := reg.Register("order@1", &Spec{
+		Type:       "object",
+		Properties: map[string]*Spec{"shipping": {Ref: "address@1"}},
+	}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+
+	result, err := reg.Validate(map[string]any{"shipping": map[string]any{}}, "order@1")
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Path != "shipping.city" {
+		t.Errorf("expected shipping.city to be required, got %v", result.Errors)
+	}
+	if _, err := reg.Lookup("unknown@1"); err == nil {
+		t.Error("expected an error for a ref the loader can't fetch")
+	}
+}
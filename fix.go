@@ -0,0 +1,273 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Kinds of fixes
+const (
+	FixDefault     = "default"     // A missing property was set to its spec's default
+	FixTrim        = "trim"        // Whitespace was trimmed from a string
+	FixCoerce      = "coerce"      // A value was converted to its spec's type, e.g. "42" to 42
+	FixClamp       = "clamp"       // A number was clamped into its spec's min and max
+	FixDropUnknown = "dropUnknown" // A property the spec doesn't declare was removed (see DropUnknown)
+)
+
+// AppliedFix is a correction Fix made to a document
+type AppliedFix struct {
+	Path string
+	Kind string // One of the Fix* constants
+	From any    // The value before the fix; nil for defaults
+	To   any    // The value after the fix; nil for dropped properties
+}
+
+// FixResult is the result of fixing a document
+type FixResult struct {
+	Valid    bool
+	Errors   []*ValidationError // Errors Fix couldn't correct
+	Fixes    []AppliedFix       // In document order
+	Document any                // The corrected document
+}
+
+// FixOption configures Fix
+type FixOption func(*fixConfig)
+
+type fixConfig struct {
+	dropUnknown bool
+}
+
+// DropUnknown removes the properties of objects that the spec doesn't
+// declare, unless it has additionalProperties
+func DropUnknown() FixOption {
+	return func(c *fixConfig) {
+		c.dropUnknown = true
+	}
+}
+
+// Fix corrects what it safely can in a copy of data and validates the
+// result. See Validator.Fix.
+func Fix(data any, spec *Spec, opts ...FixOption) *FixResult {
+	return defaultValidator.Fix(data, spec, opts...)
+}
+
+// Fix corrects a copy of data against spec and validates the result,
+// returning the corrected document, the fixes applied and the errors left.
+// Missing properties are set to their spec's default, and with DropUnknown
+// undeclared properties are removed. An invalid value is trimmed, converted
+// to its spec's type and clamped into its range, as far as needed to make
+// it valid; values these can't make valid are left as they are. data isn't
+// modified.
+func (v *Validator) Fix(data any, spec *Spec, opts ...FixOption) *FixResult {
+	var config fixConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	document := deepCopy(data)
+	f := &fixer{r: v.newResult(document), config: config}
+	if spec != nil {
+		document = f.fix("", document, spec)
+	}
+
+	result := v.Validate(document, spec)
+	return &FixResult{Valid: result.Valid, Errors: result.Errors, Fixes: f.fixes, Document: document}
+}
+
+// fixer walks a document fixing it, using a result to resolve the specs
+// that apply to each value
+type fixer struct {
+	r      *ValidationResult
+	config fixConfig
+	fixes  []AppliedFix
+}
+
+// fix returns value fixed against spec, fixing objects and arrays in place
+func (f *fixer) fix(path string, value any, spec *Spec) any {
+	spec = f.resolve(spec)
+	if spec == nil {
+		return value
+	}
+	spec = f.r.conditional(path, value, spec)
+
+	switch v := value.(type) {
+	case map[string]any:
+		if spec.Type == "" || spec.Type == "object" {
+			f.fixObject(path, v, spec)
+			return v
+		}
+	case []any:
+		if spec.Type == "" || spec.Type == "array" {
+			for i, item := range v {
+				v[i] = f.fix(buildArrayPath(path, i), item, spec.Items)
+			}
+			return v
+		}
+	}
+	return f.fixValue(path, value, spec)
+}
+
+// resolve returns the spec spec refers to, or nil if it can't be resolved
+func (f *fixer) resolve(spec *Spec) *Spec {
+	if spec == nil || spec.Ref == "" {
+		return spec
+	}
+	resolved, err := f.r.resolveRef(spec)
+	if err != nil {
+		return nil
+	}
+	return resolved
+}
+
+// fixObject fills in defaults, drops unknown properties and fixes the
+// properties of obj
+func (f *fixer) fixObject(path string, obj map[string]any, spec *Spec) {
+	f.r.objects = append(f.r.objects, obj)
+	f.r.exprCache = append(f.r.exprCache, nil)
+	defer func() {
+		f.r.objects = f.r.objects[:len(f.r.objects)-1]
+		f.r.exprCache = f.r.exprCache[:len(f.r.exprCache)-1]
+	}()
+
+	discriminator := ""
+	spec = f.r.reshaped(path, obj, spec)
+	if spec.Discriminator != nil {
+		discriminator = spec.Discriminator.PropertyName
+		spec = f.r.discriminated(path, obj, spec)
+	}
+	declared := func(effective map[string]*Spec, key string) *Spec {
+		if override, ok := effective[key]; ok {
+			return override
+		}
+		return spec.Properties[key]
+	}
+
+	effective, _ := f.r.buildEffectiveSpecs(path, obj, spec)
+	filled := false
+	for _, key := range sortedKeys(spec.Properties) {
+		if _, exists := obj[key]; exists {
+			continue
+		}
+		if propSpec := f.resolve(declared(effective, key)); propSpec != nil && propSpec.Default != nil && !f.r.excluded(propSpec) {
+			obj[key] = deepCopy(propSpec.Default)
+			f.fixes = append(f.fixes, AppliedFix{Path: buildPath(path, key), Kind: FixDefault, To: obj[key]})
+			filled = true
+		}
+	}
+	if filled {
+		// Conditions may depend on the defaults
+		f.r.exprCache[len(f.r.exprCache)-1] = nil
+		effective, _ = f.r.buildEffectiveSpecs(path, obj, spec)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		propSpec := declared(effective, key)
+		if propSpec == nil {
+			propSpec = spec.AdditionalProperties
+		}
+		if propSpec != nil {
+			f.r.pushSegment(key)
+			obj[key] = f.fix(buildPath(path, key), obj[key], propSpec)
+			f.r.popSegment()
+			continue
+		}
+		if f.config.dropUnknown && key != discriminator && (spec.Properties != nil || len(effective) > 0) {
+			f.fixes = append(f.fixes, AppliedFix{Path: buildPath(path, key), Kind: FixDropUnknown, From: obj[key]})
+			delete(obj, key)
+		}
+	}
+}
+
+// fixSteps are the corrections tried on invalid values, in order, each
+// building on the previous ones
+var fixSteps = []struct {
+	kind  string
+	apply func(value any, spec *Spec) (any, bool)
+}{
+	{FixTrim, trimValue},
+	{FixCoerce, coerceValue},
+	{FixClamp, clampValue},
+}
+
+// fixValue returns value if it is valid against spec, or else the value
+// the fewest fixSteps make valid. If none do, value is returned unchanged.
+func (f *fixer) fixValue(path string, value any, spec *Spec) any {
+	if f.r.matches(path, value, spec) {
+		return value
+	}
+	current := value
+	var applied []AppliedFix
+	for _, step := range fixSteps {
+		next, ok := step.apply(current, spec)
+		if !ok {
+			continue
+		}
+		applied = append(applied, AppliedFix{Path: path, Kind: step.kind, From: current, To: next})
+		current = next
+		if f.r.matches(path, current, spec) {
+			f.fixes = append(f.fixes, applied...)
+			return current
+		}
+	}
+	return value
+}
+
+// trimValue trims the whitespace around a string
+func trimValue(value any, spec *Spec) (any, bool) {
+	str, ok := value.(string)
+	if !ok || strings.TrimSpace(str) == str {
+		return value, false
+	}
+	return strings.TrimSpace(str), true
+}
+
+// coerceValue converts a string to the number or boolean its spec expects,
+// or a number or boolean to a string
+func coerceValue(value any, spec *Spec) (any, bool) {
+	switch spec.Type {
+	case "number", "integer":
+		if str, ok := value.(string); ok {
+			if num, err := strconv.ParseFloat(str, 64); err == nil {
+				return num, true
+			}
+		}
+	case "boolean":
+		if str, ok := value.(string); ok {
+			switch strings.ToLower(str) {
+			case "true":
+				return true, true
+			case "false":
+				return false, true
+			}
+		}
+	case "string":
+		if b, ok := value.(bool); ok {
+			return strconv.FormatBool(b), true
+		}
+		if n, ok := value.(json.Number); ok {
+			return string(n), true
+		}
+		if num, _, ok := numberValue(value); ok {
+			return strconv.FormatFloat(num, 'f', -1, 64), true
+		}
+	}
+	return value, false
+}
+
+// clampValue clamps a number into its spec's min and max
+func clampValue(value any, spec *Spec) (any, bool) {
+	num, _, ok := numberValue(value)
+	if !ok {
+		return value, false
+	}
+	switch {
+	case spec.Min != nil && num < *spec.Min:
+		return *spec.Min, true
+	case spec.Max != nil && num > *spec.Max:
+		return *spec.Max, true
+	}
+	return value, false
+}
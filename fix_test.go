@@ -0,0 +1,124 @@
+package mowgli
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestFix(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["name", "port"],
+		"properties": {
+			"name": {"type": "string", "pattern": "^[a-z]+$"},
+			"port": {"type": "integer", "min": 1, "max": 65535},
+			"debug": {"type": "boolean", "default": false},
+			"replicas": {"type": "integer", "min": 1, "default": 2},
+			"label": {"type": "string", "maxLength": 3},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		data     map[string]any
+		opts     []FixOption
+		expected map[string]any
+		fixes    []AppliedFix
+		errors   []string
+	}{
+		{
+			name:     "valid document only gets defaults",
+			data:     map[string]any{"name": "api", "port": 80.0},
+			expected: map[string]any{"name": "api", "port": 80.0, "debug": false, "replicas": 2.0},
+			fixes: []AppliedFix{
+				{Path: "debug", Kind: FixDefault, To: false},
+				{Path: "replicas", Kind: FixDefault, To: 2.0},
+			},
+		},
+		{
+			name:     "trim, coerce and clamp",
+			data:     map[string]any{"name": " api ", "port": " 70000 ", "debug": "TRUE", "replicas": 0.0, "label": 42.0},
+			expected: map[string]any{"name": "api", "port": 65535.0, "debug": true, "replicas": 1.0, "label": "42"},
+			fixes: []AppliedFix{
+				{Path: "debug", Kind: FixCoerce, From: "TRUE", To: true},
+				{Path: "label", Kind: FixCoerce, From: 42.0, To: "42"},
+				{Path: "name", Kind: FixTrim, From: " api ", To: "api"},
+				{Path: "port", Kind: FixTrim, From: " 70000 ", To: "70000"},
+				{Path: "port", Kind: FixCoerce, From: "70000", To: 70000.0},
+				{Path: "port", Kind: FixClamp, From: 70000.0, To: 65535.0},
+				{Path: "replicas", Kind: FixClamp, From: 0.0, To: 1.0},
+			},
+		},
+		{
+			name:     "unfixable values are left as they are",
+			data:     map[string]any{"name": "API", "port": "eighty", "debug": true, "replicas": 1.0, "tags": []any{"a", "c"}},
+			expected: map[string]any{"name": "API", "port": "eighty", "debug": true, "replicas": 1.0, "tags": []any{"a", "c"}},
+			errors:   []string{"name", "port", "tags[1]"},
+		},
+		{
+			name:     "unknown properties are kept by default",
+			data:     map[string]any{"name": "api", "port": 80.0, "debug": true, "replicas": 1.0, "extra": 1.0},
+			expected: map[string]any{"name": "api", "port": 80.0, "debug": true, "replicas": 1.0, "extra": 1.0},
+		},
+		{
+			name:     "unknown properties dropped",
+			data:     map[string]any{"name": "api", "port": 80.0, "debug": true, "replicas": 1.0, "extra": 1.0, "labels": map[string]any{"team": "x"}},
+			opts:     []FixOption{DropUnknown()},
+			expected: map[string]any{"name": "api", "port": 80.0, "debug": true, "replicas": 1.0, "labels": map[string]any{"team": "x"}},
+			fixes:    []AppliedFix{{Path: "extra", Kind: FixDropUnknown, From: 1.0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := deepCopy(tt.data)
+			result := Fix(tt.data, spec, tt.opts...)
+			if !reflect.DeepEqual(tt.data, original) {
+				t.Errorf("Fix modified its input: %v", tt.data)
+			}
+			if !reflect.DeepEqual(result.Document, tt.expected) {
+				t.Errorf("expected document %v, got %v", tt.expected, result.Document)
+			}
+			if !reflect.DeepEqual(result.Fixes, tt.fixes) {
+				t.Errorf("expected fixes %v, got %v", tt.fixes, result.Fixes)
+			}
+			var paths []string
+			for _, err := range result.Errors {
+				paths = append(paths, err.Path)
+			}
+			slices.Sort(paths)
+			if !reflect.DeepEqual(paths, tt.errors) {
+				t.Errorf("expected errors at %v, got %v", tt.errors, result.Errors)
+			}
+			if result.Valid != (len(tt.errors) == 0) {
+				t.Errorf("expected valid %v", len(tt.errors) == 0)
+			}
+		})
+	}
+}
+
+func TestFixConditionalDefaults(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"tier": {"type": "string", "default": "premium"},
+			"seats": {"type": "integer"}
+		},
+		"conditions": [{"if": "tier == 'premium'", "then": {"seats": {"type": "integer", "min": 5}}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Fix(map[string]any{"seats": 2.0}, spec)
+	expected := map[string]any{"tier": "premium", "seats": 5.0}
+	if !result.Valid || !reflect.DeepEqual(result.Document, expected) {
+		t.Errorf("expected %v, got %v (%v)", expected, result.Document, result.Errors)
+	}
+}
@@ -0,0 +1,148 @@
+package mowgli
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatFunc reports whether a string is in a given format
+type FormatFunc func(value string) bool
+
+// formats holds the named formats available to the "format" keyword
+var formats = struct {
+	sync.RWMutex
+	funcs map[string]FormatFunc
+}{funcs: map[string]FormatFunc{
+	"email":      isEmail,
+	"uuid":       uuidPattern.MatchString,
+	"date":       isLayout(time.DateOnly),
+	"date-time":  isLayout(time.RFC3339),
+	"time":       isLayout(time.TimeOnly),
+	"ipv4":       isIPv4,
+	"ipv6":       isIPv6,
+	"hostname":   isHostname,
+	"uri":        isURI,
+	"semver":     isSemver,
+	"creditcard": isCreditCard,
+	"iban":       isIBAN,
+}}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+)
+
+// RegisterFormat makes fn available to specs as "format": name, replacing
+// any existing format with the same name
+func RegisterFormat(name string, fn FormatFunc) {
+	formats.Lock()
+	defer formats.Unlock()
+	formats.funcs[name] = fn
+}
+
+// lookupFormat returns the named format
+func lookupFormat(name string) (FormatFunc, bool) {
+	formats.RLock()
+	defer formats.RUnlock()
+	fn, ok := formats.funcs[name]
+	return fn, ok
+}
+
+// formatScope holds the "formats" sections of the specs enclosing the one
+// being walked, innermost last
+type formatScope []map[string]string
+
+// push enters a spec's formats, returning a func that leaves them
+func (s *formatScope) push(formats map[string]string) func() {
+	*s = append(*s, formats)
+	return func() { *s = (*s)[:len(*s)-1] }
+}
+
+// pattern returns the regular expression of a format defined in the scope
+func (s formatScope) pattern(name string) (string, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if pattern, ok := s[i][name]; ok {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// lookup returns the named format, preferring formats defined in the scope
+// over registered ones
+func (s formatScope) lookup(name string) (FormatFunc, error) {
+	if pattern, ok := s.pattern(name); ok {
+		re, err := compilePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid format %s: %w", name, err)
+		}
+		return re.MatchString, nil
+	}
+	if fn, ok := lookupFormat(name); ok {
+		return fn, nil
+	}
+	return nil, fmt.Errorf("unknown format: %s", name)
+}
+
+// checkFormats reports format definitions whose patterns don't compile
+func checkFormats(formats map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(formats)) {
+		if _, err := compilePattern(formats[name]); err != nil {
+			return fmt.Errorf("invalid format %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (r *ValidationResult) validateFormat(path, str, format string) {
+	fn, err := r.formats.lookup(format)
+	if err != nil {
+		r.addError(path, CodeInvalidSpec, err.Error(), nil)
+		return
+	}
+	if !fn(str) {
+		r.addError(path, CodeFormat, fmt.Sprintf("string is not a valid %s", format),
+			map[string]any{"format": format})
+	}
+}
+
+// isEmail accepts a bare address such as "user@example.com" (no display name)
+func isEmail(value string) bool {
+	addr, err := mail.ParseAddress(value)
+	return err == nil && addr.Address == value && addr.Name == ""
+}
+
+func isLayout(layout string) FormatFunc {
+	return func(value string) bool {
+		_, err := time.Parse(layout, value)
+		return err == nil
+	}
+}
+
+func isIPv4(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && ip.To4() != nil && !strings.Contains(value, ":")
+}
+
+func isIPv6(value string) bool {
+	ip := net.ParseIP(value)
+	return ip != nil && strings.Contains(value, ":")
+}
+
+func isHostname(value string) bool {
+	return len(value) <= 253 && hostnamePattern.MatchString(value)
+}
+
+// isURI accepts absolute URIs with a scheme, e.g. "https://example.com/path"
+func isURI(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+}
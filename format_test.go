@@ -0,0 +1,174 @@
+package mowgli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateFormat(t *testing.T) {
+	tests := []struct {
+		format    string
+		value     string
+		shouldErr bool
+	}{
+		{format: "email", value: "user@example.com"},
+		{format: "email", value: "not-an-email", shouldErr: true},
+		{format: "email", value: "Jane <jane@example.com>", shouldErr: true},
+		{format: "uuid", value: "123e4567-e89b-12d3-a456-426614174000"},
+		{format: "uuid", value: "123e4567-e89b-12d3-a456", shouldErr: true},
+		{format: "date", value: "2024-02-29"},
+		{format: "date", value: "2023-02-29", shouldErr: true},
+		{format: "date-time", value: "2024-01-02T15:04:05Z"},
+		{format: "date-time", value: "2024-01-02 15:04:05", shouldErr: true},
+		{format: "time", value: "15:04:05"},
+		{format: "ipv4", value: "192.168.0.1"},
+		{format: "ipv4", value: "::1", shouldErr: true},
+		{format: "ipv6", value: "2001:db8::1"},
+		{format: "ipv6", value: "192.168.0.1", shouldErr: true},
+		{format: "hostname", value: "api.example.com"},
+		{format: "hostname", value: "-bad-.example.com", shouldErr: true},
+		{format: "uri", value: "https://example.com/path?q=1"},
+		{format: "uri", value: "/relative/path", shouldErr: true},
+		{format: "creditcard", value: "4111111111111111"},
+		{format: "creditcard", value: "4111 1111 1111 1111"},
+		{format: "creditcard", value: "5500-0000-0000-0004"},
+		{format: "creditcard", value: "378282246310005"},
+		{format: "creditcard", value: "4111111111111112", shouldErr: true},
+		{format: "creditcard", value: "4111  1111 1111 1111", shouldErr: true},
+		{format: "creditcard", value: "0000", shouldErr: true},
+		{format: "creditcard", value: "4111-1111-1111-111a", shouldErr: true},
+		{format: "iban", value: "GB82WEST12345698765432"},
+		{format: "iban", value: "GB82 WEST 1234 5698 7654 32"},
+		{format: "iban", value: "de89370400440532013000"},
+		{format: "iban", value: "NO9386011117947"},
+		{format: "iban", value: "GB82WEST12345698765433", shouldErr: true},
+		{format: "iban", value: "GB82WEST1234569876543", shouldErr: true},
+		{format: "iban", value: "XX82WEST12345698765432", shouldErr: true},
+		{format: "iban", value: "GB82-WEST-1234-5698-7654-32", shouldErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.value, func(t *testing.T) {
+			spec := &Spec{Type: "string", Format: &tt.format}
+			result := Validate(tt.value, spec)
+			if result.Valid == tt.shouldErr {
+				if tt.shouldErr {
+					t.Errorf("Expected validation to fail, but it passed")
+				} else {
+					t.Errorf("Expected validation to pass, but it failed: %v", result.Errors)
+				}
+			}
+			if tt.shouldErr && result.Errors[0].Code != CodeFormat {
+				t.Errorf("expected code %s, got %s", CodeFormat, result.Errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("test-upper", func(value string) bool {
+		return value == strings.ToUpper(value)
+	})
+
+	spec, err := ParseSpecString(`{"type": "string", "format": "test-upper"}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if result := Validate("ABC", spec); !result.Valid {
+		t.Errorf("expected validation to pass, got %v", result.Errors)
+	}
+	if result := Validate("abc", spec); result.Valid {
+		t.Error("expected validation to fail")
+	}
+}
+
+func TestValidateUnknownFormat(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "string", "format": "no-such-format"}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate("x", spec)
+	if result.Valid || result.Errors[0].Code != CodeInvalidSpec {
+		t.Errorf("expected invalid spec error, got %v", result.Errors)
+	}
+	if _, err := Compile(spec); err == nil {
+		t.Error("expected Compile to reject unknown format")
+	}
+}
+
+func TestSpecFormats(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"formats": {"sku": "^[A-Z0-9-]+$", "email": "@example\\.com$"},
+		"properties": {
+			"sku": {"type": "string", "format": "sku"},
+			"contact": {"type": "string", "format": "email"},
+			"variant": {
+				"type": "object",
+				"formats": {"sku": "^V-[0-9]+$"},
+				"properties": {"sku": {"type": "string", "format": "sku"}}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]any
+		wantPaths []string
+	}{
+		{name: "valid", data: map[string]any{"sku": "AB-12", "contact": "ops@example.com", "variant": map[string]any{"sku": "V-1"}}},
+		{name: "local format", data: map[string]any{"sku": "ab_12"}, wantPaths: []string{"sku"}},
+		{name: "local format shadows registered one", data: map[string]any{"contact": "ops@other.org"}, wantPaths: []string{"contact"}},
+		{name: "inner definition wins", data: map[string]any{"variant": map[string]any{"sku": "AB-12"}}, wantPaths: []string{"variant.sku"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var paths []string
+			for _, err := range result.Errors {
+				if err.Code != CodeFormat {
+					t.Errorf("unexpected error: %v", err)
+				}
+				paths = append(paths, err.Path)
+			}
+			if strings.Join(paths, ",") != strings.Join(tt.wantPaths, ",") {
+				t.Errorf("expected errors at %v, got %v", tt.wantPaths, result.Errors)
+			}
+		})
+	}
+
+	if _, err := Compile(spec); err != nil {
+		t.Errorf("Compile failed: %v", err)
+	}
+	if result := Validate(GenerateExample(spec), spec); !result.Valid {
+		t.Errorf("expected generated example to be valid, got %v", result.Errors)
+	}
+}
+
+func TestCompileSpecFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{name: "invalid pattern", spec: `{"type": "string", "formats": {"sku": "[A-Z"}}`, wantErr: "invalid format sku"},
+		{name: "format out of scope", spec: `{"type": "object", "properties": {"a": {"type": "string", "formats": {"sku": "^A$"}}, "b": {"type": "string", "format": "sku"}}}`, wantErr: "b: unknown format: sku"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			if _, err := Compile(spec); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package mowgli
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Formatter renders the messages of validation errors, e.g. to follow an API
+// style guide. Format is called once per error and warning with its path,
+// code and params set and Message holding the default message, or the
+// spec's custom message for the code if it has one; the error's message
+// becomes the returned string. Params hold the values the message refers
+// to, e.g. "expected" and "actual" for type errors and "limit" and "actual"
+// for ranges and lengths.
+type Formatter interface {
+	Format(err *ValidationError) string
+}
+
+// FormatterFunc adapts a function to a Formatter
+type FormatterFunc func(err *ValidationError) string
+
+// Format returns f(err)
+func (f FormatterFunc) Format(err *ValidationError) string {
+	return f(err)
+}
+
+// WithFormatter renders error messages with f
+func WithFormatter(f Formatter) Option {
+	return func(v *Validator) {
+		v.formatter = f
+	}
+}
+
+// TemplateFormatter renders messages with a text/template executed with the
+// *ValidationError, e.g. `{{.Code}}: {{.Message}}` or
+// `{{field .Path}} must be {{.Params.expected}}`. Besides the standard
+// functions, field returns the last segment of a path, e.g. "city" for
+// "user.address.city". If the template fails for an error, the error keeps
+// its message.
+func TemplateFormatter(text string) (Formatter, error) {
+	tmpl, err := template.New("message").Option("missingkey=zero").Funcs(template.FuncMap{
+		"field": fieldName,
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message template: %w", err)
+	}
+	return FormatterFunc(func(err *ValidationError) string {
+		var b strings.Builder
+		if tmpl.Execute(&b, err) != nil {
+			return err.Message
+		}
+		return b.String()
+	}), nil
+}
+
+// fieldName returns the last segment of a path
+func fieldName(path string) string {
+	path = path[strings.LastIndex(path, ".")+1:]
+	if i := strings.Index(path, "["); i > 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// format renders err's message with the result's formatter and returns err
+func (r *ValidationResult) format(err *ValidationError) *ValidationError {
+	if r.formatter != nil {
+		err.Message = r.formatter.Format(err)
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package mowgli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithFormatter(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "messages": {"minLength": "too short"}},
+			"age": {"type": "integer"},
+			"legacy": {"type": "string", "deprecated": true}
+		},
+		"required": ["email"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	v := NewValidator(WithFormatter(FormatterFunc(func(err *ValidationError) string {
+		return "[" + err.Code + "] " + err.Path + ": " + err.Message
+	})))
+	result := v.Validate(map[string]any{"name": "A", "age": "old", "legacy": "x"}, spec)
+
+	got := result.MessagesByPath()
+	want := map[string][]string{
+		"email": {"[required] email: required field is missing"},
+		"name":  {"[minLength] name: too short"},
+		"age":   {"[type] age: expected integer, got string"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Message[:13] != "[deprecated] " {
+		t.Errorf("expected a formatted warning, got %v", result.Warnings)
+	}
+}
+
+func TestWithFormatterAsync(t *testing.T) {
+	v := NewValidator(WithFormatter(FormatterFunc(func(err *ValidationError) string {
+		return "E:" + err.Code
+	})))
+	if err := v.RegisterAsyncCheck("taken", AsyncCheck{
+		Check: func(ctx context.Context, value any) error { return errors.New("taken") },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := v.ValidateAsync(context.Background(), "ada", &Spec{Type: "string", Checks: []string{"taken"}}, nil)
+	if len(result.Errors) != 1 || result.Errors[0].Message != "E:check" {
+		t.Errorf("expected a formatted check error, got %v", result.Errors)
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	three := 3.0
+	tests := []struct {
+		name     string
+		template string
+		value    any
+		spec     *Spec
+		want     string
+	}{
+		{
+			name:     "fields",
+			template: `{{.Code}}|{{.Path}}|{{.Message}}`,
+			value:    map[string]any{"n": 5.0},
+			spec:     &Spec{Type: "object", Properties: map[string]*Spec{"n": {Type: "integer", Max: &three}}},
+			want:     "max|n|integer 5 is greater than maximum 3",
+		},
+		{
+			name:     "params",
+			template: `{{field .Path}} must be {{.Params.expected}}, not {{.Params.actual}}`,
+			value:    map[string]any{"user": map[string]any{"tags": []any{1.0}}},
+			spec: &Spec{Type: "object", Properties: map[string]*Spec{"user": {Type: "object", Properties: map[string]*Spec{
+				"tags": {Type: "array", Items: &Spec{Type: "string"}},
+			}}}},
+			want: "tags must be string, not float64",
+		},
+		{
+			name:     "missing param",
+			template: `{{.Message}}{{with .Params.nothing}} ({{.}}){{end}}`,
+			value:    nil,
+			spec:     &Spec{Type: "string"},
+			want:     "expected type string, got null",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := TemplateFormatter(tt.template)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			result := NewValidator(WithFormatter(f)).Validate(tt.value, tt.spec)
+			if len(result.Errors) != 1 || result.Errors[0].Message != tt.want {
+				t.Errorf("expected %q, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+
+	if _, err := TemplateFormatter("{{.Code"); err == nil {
+		t.Error("expected an error for an invalid template")
+	}
+}
+
+func TestTemplateFormatterExecutionError(t *testing.T) {
+	f, err := TemplateFormatter(`{{index .Params "limit" 3}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := f.Format(&ValidationError{Code: CodeMax, Message: "too big", Params: map[string]any{"limit": 3.0}})
+	if got != "too big" {
+		t.Errorf("expected the default message, got %q", got)
+	}
+}
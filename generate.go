@@ -0,0 +1,373 @@
+package mowgli
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"regexp/syntax"
+	"strings"
+)
+
+// GenerateExample produces a plausible document that satisfies spec, for use
+// in docs, mocks and test fixtures. The output is deterministic: the spec's
+// first example or enum value is used where present, numbers sit at the
+// lower bound of their range and strings honor format, pattern and length.
+// All declared properties are included, plus any a condition makes required.
+func GenerateExample(spec *Spec) any {
+	return (&generator{}).generate(spec)
+}
+
+// generator builds instances of a spec. With a nil rnd it makes the first
+// (simplest) choice everywhere, which GenerateExample relies on.
+type generator struct {
+	rnd     *rand.Rand
+	formats formatScope
+}
+
+// intn returns a number in [0, n), always 0 without a random source
+func (g *generator) intn(n int) int {
+	if g.rnd == nil || n <= 1 {
+		return 0
+	}
+	return g.rnd.Intn(n)
+}
+
+func (g *generator) generate(spec *Spec) any {
+	if spec == nil {
+		return nil
+	}
+	if spec.Formats != nil {
+		defer g.formats.push(spec.Formats)()
+	}
+	if len(spec.Examples) > 0 {
+		return spec.Examples[g.intn(len(spec.Examples))]
+	}
+	if len(spec.Enum) > 0 {
+		return spec.Enum[g.intn(len(spec.Enum))]
+	}
+
+	switch spec.Type {
+	case "string":
+		return g.generateString(spec)
+	case "number":
+		return g.generateNumber(spec, false)
+	case "integer":
+		return g.generateNumber(spec, true)
+	case "boolean":
+		return g.intn(2) == 0
+	case "array":
+		return g.generateArray(spec)
+	case "object":
+		return g.generateObject(spec)
+	default:
+		return nil
+	}
+}
+
+// formatExamples are sample values for the built-in formats
+var formatExamples = map[string]string{
+	"email":      "user@example.com",
+	"uuid":       "123e4567-e89b-12d3-a456-426614174000",
+	"date":       "2024-01-15",
+	"date-time":  "2024-01-15T09:30:00Z",
+	"time":       "09:30:00",
+	"ipv4":       "192.0.2.1",
+	"ipv6":       "2001:db8::1",
+	"hostname":   "example.com",
+	"uri":        "https://example.com",
+	"semver":     "1.0.0",
+	"creditcard": "4111111111111111",
+	"iban":       "DE89370400440532013000",
+}
+
+func (g *generator) generateString(spec *Spec) string {
+	str := "example"
+	if spec.TimeFormat != "" {
+		return timeExample.Format(spec.TimeFormat)
+	}
+	if inside, ok := semverExample(spec.SemverRange, true); ok {
+		return inside
+	}
+	if len(spec.URISchemes) > 0 || (spec.PublicHost != nil && *spec.PublicHost) {
+		return uriExample(spec, "example.com")
+	}
+	if spec.Format != nil {
+		if pattern, ok := g.formats.pattern(*spec.Format); ok {
+			if generated, ok := g.generatePattern(pattern); ok {
+				return generated
+			}
+		} else if example, ok := formatExamples[*spec.Format]; ok {
+			return example
+		}
+	}
+	if spec.Pattern != nil {
+		if generated, ok := g.generatePattern(*spec.Pattern); ok {
+			return generated
+		}
+	}
+
+	// Generated strings are ASCII, so their length in bytes and characters match
+	minLength, maxLength := spec.MinLength, spec.MaxLength
+	if spec.MinBytes != nil && (minLength == nil || *spec.MinBytes > *minLength) {
+		minLength = spec.MinBytes
+	}
+	if spec.MaxBytes != nil && (maxLength == nil || *spec.MaxBytes < *maxLength) {
+		maxLength = spec.MaxBytes
+	}
+
+	if g.rnd != nil {
+		low, high := 1, 12
+		if minLength != nil {
+			low = *minLength
+			high = max(high, low+4)
+		}
+		if maxLength != nil {
+			high = min(high, *maxLength)
+			low = min(low, high)
+		}
+		letters := make([]byte, low+g.intn(high-low+1))
+		for i := range letters {
+			letters[i] = byte('a' + g.intn(26))
+		}
+		return string(letters)
+	}
+
+	if minLength != nil && len(str) < *minLength {
+		str += strings.Repeat("x", *minLength-len(str))
+	}
+	if maxLength != nil && len(str) > *maxLength {
+		str = str[:*maxLength]
+	}
+	return str
+}
+
+func (g *generator) generateNumber(spec *Spec, integer bool) any {
+	low, high := math.Inf(-1), math.Inf(1)
+	if spec.Min != nil {
+		low = *spec.Min
+	}
+	if spec.Max != nil {
+		high = *spec.Max
+	}
+	// Exact integer bounds, rounded inwards where float64 can't hold them
+	if limit, err := intBound(spec.MinInt); err == nil {
+		bound, accuracy := new(big.Float).SetInt(limit).Float64()
+		if accuracy == big.Below {
+			bound = math.Nextafter(bound, math.Inf(1))
+		}
+		low = math.Max(low, bound)
+	}
+	if limit, err := intBound(spec.MaxInt); err == nil {
+		bound, accuracy := new(big.Float).SetInt(limit).Float64()
+		if accuracy == big.Above {
+			bound = math.Nextafter(bound, math.Inf(-1))
+		}
+		high = math.Min(high, bound)
+	}
+	if integer {
+		low, high = math.Ceil(low), math.Floor(high)
+	}
+
+	// Start from zero, or the bound nearest to it
+	value := math.Max(low, math.Min(0, high))
+	if g.rnd != nil && !math.IsInf(low, 0) && !math.IsInf(high, 0) && high > low {
+		value = low + g.rnd.Float64()*(high-low)
+	} else if g.rnd != nil {
+		value += float64(g.intn(100))
+		if value > high {
+			value = high
+		}
+	}
+	if integer {
+		value = math.Floor(value)
+		if value < low {
+			value = low
+		}
+	}
+	return value
+}
+
+func (g *generator) generateArray(spec *Spec) []any {
+	count := 1
+	if spec.MinLength != nil && *spec.MinLength > count {
+		count = *spec.MinLength
+	}
+	if g.rnd != nil {
+		low := 0
+		if spec.MinLength != nil {
+			low = *spec.MinLength
+		}
+		count = low + g.intn(4)
+	}
+	if spec.MaxLength != nil && *spec.MaxLength < count {
+		count = *spec.MaxLength
+	}
+	if spec.Items == nil {
+		count = 0
+	}
+
+	arr := make([]any, 0, count)
+	for len(arr) < count {
+		item := g.generate(spec.Items)
+		if spec.UniqueItems != nil && *spec.UniqueItems {
+			// Vary duplicates where the item type allows it
+			item = g.distinct(item, arr, spec.Items)
+		}
+		arr = append(arr, item)
+	}
+	return arr
+}
+
+// distinct returns a variation of item not equal to any value in existing
+func (g *generator) distinct(item any, existing []any, spec *Spec) any {
+	for attempt := 0; attempt < len(existing)+1; attempt++ {
+		duplicate := false
+		for _, other := range existing {
+			if valuesEqual(item, other) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			return item
+		}
+		switch v := item.(type) {
+		case float64:
+			item = v + 1
+		case string:
+			item = v + "x"
+		default:
+			if len(spec.Enum) > attempt+1 {
+				item = spec.Enum[attempt+1]
+			}
+		}
+	}
+	return item
+}
+
+func (g *generator) generateObject(spec *Spec) map[string]any {
+	if spec.Discriminator != nil {
+		if obj, ok := g.generateDiscriminated(spec); ok {
+			return obj
+		}
+	}
+
+	required := make(map[string]bool, len(spec.Required))
+	for _, name := range spec.Required {
+		required[name] = true
+	}
+
+	obj := make(map[string]any, len(spec.Properties))
+	for _, name := range sortedKeys(spec.Properties) {
+		// Random instances leave out optional properties half of the time
+		if !required[name] && g.intn(2) == 1 {
+			continue
+		}
+		obj[name] = g.generate(spec.Properties[name])
+	}
+	if len(spec.Properties) == 0 && spec.AdditionalProperties != nil {
+		key := "key"
+		if spec.PropertyNames != nil {
+			if name, ok := g.generate(spec.PropertyNames).(string); ok {
+				key = name
+			}
+		}
+		obj[key] = g.generate(spec.AdditionalProperties)
+	}
+
+	g.fillPropertyGroups(obj, spec)
+
+	// Regenerate properties whose constraints conditions change, and add
+	// properties the conditions require
+	if len(spec.Conditions) > 0 || len(spec.RequiredIf) > 0 {
+		r := defaultValidator.newResult(obj)
+		effective, conditionalRequired := r.buildEffectiveSpecs("", obj, spec)
+		for _, name := range sortedKeys(effective) {
+			if _, exists := obj[name]; exists {
+				obj[name] = g.generate(effective[name])
+			}
+		}
+		for _, name := range conditionalRequired {
+			if _, exists := obj[name]; !exists {
+				propSpec := spec.Properties[name]
+				if override, ok := effective[name]; ok {
+					propSpec = override
+				}
+				obj[name] = g.generate(propSpec)
+			}
+		}
+	}
+	return obj
+}
+
+// generatePattern produces a string matching a regular expression
+func (g *generator) generatePattern(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if !g.writeRegexp(&b, re.Simplify()) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+func (g *generator) writeRegexp(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return true
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+		return true
+	case syntax.OpCharClass:
+		if len(re.Rune) < 2 {
+			return false
+		}
+		pair := g.intn(len(re.Rune)/2) * 2
+		low, high := re.Rune[pair], re.Rune[pair+1]
+		// Prefer readable characters from wide classes such as [^,]
+		if low <= 'a' && high >= 'z' {
+			low, high = 'a', 'z'
+		}
+		b.WriteRune(low + rune(g.intn(int(high-low)+1)))
+		return true
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune('a' + rune(g.intn(26)))
+		return true
+	case syntax.OpCapture:
+		return g.writeRegexp(b, re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !g.writeRegexp(b, sub) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpAlternate:
+		return g.writeRegexp(b, re.Sub[g.intn(len(re.Sub))])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		low, high := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			low, high = 0, -1
+		case syntax.OpPlus:
+			low, high = 1, -1
+		case syntax.OpQuest:
+			low, high = 0, 1
+		}
+		if high < 0 {
+			high = low + 3
+		}
+		count := low + g.intn(high-low+1)
+		for i := 0; i < count; i++ {
+			if !g.writeRegexp(b, re.Sub[0]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateExample(t *testing.T) {
+	tests := []struct {
+		name     string
+		specJSON string
+		want     any // nil to only check the example validates
+	}{
+		{name: "string", specJSON: `{"type": "string"}`, want: "example"},
+		{name: "string min length", specJSON: `{"type": "string", "minLength": 10}`, want: "examplexxx"},
+		{name: "string max length", specJSON: `{"type": "string", "maxLength": 3}`, want: "exa"},
+		{name: "format", specJSON: `{"type": "string", "format": "email"}`, want: "user@example.com"},
+		{name: "pattern", specJSON: `{"type": "string", "pattern": "^[A-Z]{3}-[0-9]+$"}`, want: "AAA-0"},
+		{name: "pattern with alternation", specJSON: `{"type": "string", "pattern": "^(red|green)\\d{2}$"}`},
+		{name: "enum", specJSON: `{"type": "string", "enum": ["b", "a"]}`, want: "b"},
+		{name: "examples", specJSON: `{"type": "integer", "examples": [42]}`, want: 42.0},
+		{name: "number in range", specJSON: `{"type": "number", "min": 5, "max": 10}`, want: 5.0},
+		{name: "negative range", specJSON: `{"type": "integer", "max": -3}`, want: -3.0},
+		{name: "integer bound rounding", specJSON: `{"type": "integer", "min": 1.5}`, want: 2.0},
+		{name: "boolean", specJSON: `{"type": "boolean"}`, want: true},
+		{name: "array", specJSON: `{"type": "array", "items": {"type": "integer"}, "minLength": 2}`, want: []any{0.0, 0.0}},
+		{name: "unique array", specJSON: `{"type": "array", "items": {"type": "integer"}, "minLength": 3, "uniqueItems": true}`, want: []any{0.0, 1.0, 2.0}},
+		{name: "map", specJSON: `{"type": "object", "additionalProperties": {"type": "boolean"}}`, want: map[string]any{"key": true}},
+		{
+			name: "nested object",
+			specJSON: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "minLength": 1},
+					"age": {"type": "integer", "min": 18, "max": 99},
+					"address": {"type": "object", "properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}, "required": ["zip"]}
+				},
+				"required": ["name", "address"]
+			}`,
+			want: map[string]any{"name": "example", "age": 18.0, "address": map[string]any{"zip": "00000"}},
+		},
+		{
+			name: "conditions",
+			specJSON: `{
+				"type": "object",
+				"properties": {
+					"type": {"type": "string", "enum": ["card", "cash"]},
+					"cardNumber": {"type": "string"}
+				},
+				"conditions": [
+					{"if": "type == \"card\"", "then": {"cardNumber": {"pattern": "^4[0-9]{15}$"}}, "required": ["cardNumber"]}
+				]
+			}`,
+		},
+		{
+			name: "requiredIf",
+			specJSON: `{
+				"type": "object",
+				"properties": {"age": {"type": "integer", "max": 10}, "guardian": {"type": "string"}},
+				"required": ["age"],
+				"requiredIf": {"guardian": "age < 18"}
+			}`,
+			want: map[string]any{"age": 0.0, "guardian": "example"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.specJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+
+			example := GenerateExample(spec)
+			if tt.want != nil && !reflect.DeepEqual(example, tt.want) {
+				t.Errorf("expected %#v, got %#v", tt.want, example)
+			}
+			if result := Validate(example, spec); !result.Valid {
+				t.Errorf("example %#v does not validate: %v", example, result.Errors)
+			}
+		})
+	}
+}
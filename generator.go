@@ -0,0 +1,306 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Generator produces random instances of a spec for property-based testing:
+// valid instances, and invalid ones that break one known constraint, so
+// handlers can be fuzzed to check that validation and business logic agree.
+// A Generator is not safe for concurrent use.
+type Generator struct {
+	spec    *Spec
+	gen     generator
+	formats formatScope
+}
+
+// Violation identifies the constraint an invalid instance was built to break.
+// Validating the instance reports an error with this Path and Code, possibly
+// among others.
+type Violation struct {
+	Path string
+	Code string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Code
+	}
+	return v.Path + ": " + v.Code
+}
+
+// NewGenerator creates a Generator for spec. The same seed produces the same
+// sequence of instances.
+func NewGenerator(spec *Spec, seed int64) *Generator {
+	return &Generator{spec: spec, gen: generator{rnd: rand.New(rand.NewSource(seed))}}
+}
+
+// Valid returns a random instance that satisfies the spec. Patterns are
+// honored only as far as simple generation allows, so in rare cases (such as
+// patterns with lookarounds) the instance may not validate.
+func (g *Generator) Valid() any {
+	return g.gen.generate(g.spec)
+}
+
+// Invalid returns a random instance that violates one constraint of the spec,
+// and which constraint that is. ok is false if no violation could be
+// produced, e.g. for a spec that accepts anything.
+func (g *Generator) Invalid() (value any, violation Violation, ok bool) {
+	// Every attempt mutates an identical fresh instance, so failed attempts
+	// don't accumulate
+	seed := g.gen.rnd.Int63()
+	build := func() (*any, []mutation) {
+		doc := (&generator{rnd: rand.New(rand.NewSource(seed))}).generate(g.spec)
+		root := &doc
+		var candidates []mutation
+		g.collect(&candidates, "", doc, g.spec, func(v any) { *root = v })
+		return root, candidates
+	}
+
+	_, candidates := build()
+	for _, i := range g.gen.rnd.Perm(len(candidates)) {
+		root, fresh := build()
+		m := fresh[i]
+		m.apply()
+		if reportsViolation(Validate(*root, g.spec), m.violation) {
+			return *root, m.violation, true
+		}
+	}
+	return nil, Violation{}, false
+}
+
+func reportsViolation(result *ValidationResult, violation Violation) bool {
+	for _, err := range result.Errors {
+		if err.Path == violation.Path && err.Code == violation.Code {
+			return true
+		}
+	}
+	return false
+}
+
+// mutation is a way to make an instance invalid
+type mutation struct {
+	violation Violation
+	apply     func()
+}
+
+// collect lists the mutations that break a constraint of spec at path.
+// set replaces the value in its parent.
+func (g *Generator) collect(out *[]mutation, path string, value any, spec *Spec, set func(any)) {
+	if spec == nil {
+		return
+	}
+	if spec.Formats != nil {
+		defer g.formats.push(spec.Formats)()
+	}
+	add := func(code string, apply func()) {
+		*out = append(*out, mutation{violation: Violation{Path: path, Code: code}, apply: apply})
+	}
+
+	if wrong, ok := wrongType(spec.Type); ok {
+		add(CodeType, func() { set(wrong) })
+	}
+	if len(spec.Enum) > 0 {
+		if outside, ok := outsideEnum(spec); ok {
+			add(CodeEnum, func() { set(outside) })
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		g.collectString(add, spec, set)
+	case float64:
+		if spec.Min != nil && !math.IsInf(*spec.Min, 0) {
+			add(CodeMin, func() { set(math.Floor(*spec.Min) - 1) })
+		}
+		if spec.Max != nil && !math.IsInf(*spec.Max, 0) {
+			add(CodeMax, func() { set(math.Ceil(*spec.Max) + 1) })
+		}
+		if limit, err := intBound(spec.MinInt); err == nil {
+			add(CodeMinInt, func() { set(json.Number(limit.Sub(limit, big.NewInt(1)).String())) })
+		}
+		if limit, err := intBound(spec.MaxInt); err == nil {
+			add(CodeMaxInt, func() { set(json.Number(limit.Add(limit, big.NewInt(1)).String())) })
+		}
+	case []any:
+		g.collectArray(out, add, path, v, spec, set)
+	case map[string]any:
+		g.collectObject(out, path, v, spec)
+	}
+}
+
+func (g *Generator) collectString(add func(string, func()), spec *Spec, set func(any)) {
+	if spec.AllowEmpty != nil && *spec.AllowEmpty {
+		return
+	}
+	if spec.MinLength != nil && *spec.MinLength > 0 {
+		short := strings.Repeat("a", *spec.MinLength-1)
+		add(CodeMinLength, func() { set(short) })
+	}
+	if spec.MaxLength != nil {
+		long := strings.Repeat("a", *spec.MaxLength+1)
+		add(CodeMaxLength, func() { set(long) })
+	}
+	if spec.MinBytes != nil && *spec.MinBytes > 0 {
+		small := strings.Repeat("a", *spec.MinBytes-1)
+		add(CodeMinBytes, func() { set(small) })
+	}
+	if spec.MaxBytes != nil {
+		// Two-byte characters exceed the byte limit with fewer characters
+		large := strings.Repeat("é", *spec.MaxBytes/2+1)
+		add(CodeMaxBytes, func() { set(large) })
+	}
+	if spec.Pattern != nil {
+		if re, err := compilePattern(*spec.Pattern); err == nil {
+			for _, candidate := range []string{"!", "", " ", "0", "a", "A", "~~~~~~~~"} {
+				if !re.MatchString(candidate) {
+					candidate := candidate
+					add(CodePattern, func() { set(candidate) })
+					break
+				}
+			}
+		}
+	}
+	if spec.Format != nil {
+		if fn, err := g.formats.lookup(*spec.Format); err == nil {
+			bad := "not a valid " + *spec.Format
+			if !fn(bad) {
+				add(CodeFormat, func() { set(bad) })
+			}
+		}
+	}
+	if len(spec.URISchemes) > 0 {
+		add(CodeURISchemes, func() { set("x-unlisted://example.com/") })
+	}
+	if spec.PublicHost != nil && *spec.PublicHost {
+		add(CodePublicHost, func() { set(uriExample(spec, "127.0.0.1")) })
+	}
+	if outside, ok := semverExample(spec.SemverRange, false); ok {
+		add(CodeSemverRange, func() { set(outside) })
+	}
+	if _, err := time.Parse(spec.TimeFormat, "not a time"); spec.TimeFormat != "" && err != nil {
+		add(CodeTimeFormat, func() { set("not a time") })
+	}
+}
+
+func (g *Generator) collectArray(out *[]mutation, add func(string, func()), path string, arr []any, spec *Spec, set func(any)) {
+	if spec.MinLength != nil && *spec.MinLength > 0 && len(arr) >= *spec.MinLength {
+		add(CodeMinLength, func() { set(arr[:*spec.MinLength-1]) })
+	}
+	if spec.MaxLength != nil && spec.Items != nil {
+		add(CodeMaxLength, func() {
+			longer := append([]any(nil), arr...)
+			for len(longer) <= *spec.MaxLength {
+				longer = append(longer, g.gen.generate(spec.Items))
+			}
+			set(longer)
+		})
+	}
+	if spec.UniqueItems != nil && *spec.UniqueItems && len(arr) > 0 {
+		add(CodeUniqueItems, func() { set(append(append([]any(nil), arr...), arr[0])) })
+	}
+
+	for i, item := range arr {
+		i := i
+		g.collect(out, buildArrayPath(path, i), item, spec.Items, func(v any) { arr[i] = v })
+	}
+}
+
+func (g *Generator) collectObject(out *[]mutation, path string, obj map[string]any, spec *Spec) {
+	r := defaultValidator.newResult(obj)
+	if d := spec.Discriminator; d != nil {
+		*out = append(*out, mutation{
+			violation: Violation{Path: buildPath(path, d.PropertyName), Code: CodeDiscriminator},
+			apply:     func() { obj[d.PropertyName] = "unknown-" + d.PropertyName },
+		})
+		spec = r.discriminated(path, obj, spec)
+	}
+	effective, conditionalRequired := r.buildEffectiveSpecs(path, obj, spec)
+
+	for _, name := range append(append([]string(nil), spec.Required...), conditionalRequired...) {
+		if _, exists := obj[name]; exists {
+			name := name
+			*out = append(*out, mutation{
+				violation: Violation{Path: buildPath(path, name), Code: CodeRequired},
+				apply:     func() { delete(obj, name) },
+			})
+		}
+	}
+	if present := presentProperties(obj, spec.AnyRequired); len(present) > 0 {
+		*out = append(*out, mutation{
+			violation: Violation{Path: path, Code: CodeAnyRequired},
+			apply: func() {
+				for _, name := range present {
+					delete(obj, name)
+				}
+			},
+		})
+	}
+	if present := presentProperties(obj, spec.OneRequired); len(present) == 1 {
+		for _, name := range spec.OneRequired {
+			if name != present[0] {
+				*out = append(*out, mutation{
+					violation: Violation{Path: path, Code: CodeOneRequired},
+					apply:     func() { obj[name] = g.gen.generate(spec.Properties[name]) },
+				})
+				break
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(spec.Properties) {
+		value, exists := obj[name]
+		if !exists {
+			continue
+		}
+		propSpec := spec.Properties[name]
+		if override, ok := effective[name]; ok {
+			propSpec = override
+		}
+		name := name
+		g.collect(out, buildPath(path, name), value, propSpec, func(v any) { obj[name] = v })
+	}
+}
+
+// wrongType returns a value that is not of the given type
+func wrongType(specType string) (any, bool) {
+	switch specType {
+	case "string":
+		return 12345.0, true
+	case "number", "integer", "boolean", "array", "object", "null":
+		return "not a " + specType, true
+	default:
+		return nil, false
+	}
+}
+
+// outsideEnum returns a value of the spec's type that is not in its enum
+func outsideEnum(spec *Spec) (any, bool) {
+	var candidate any
+	switch spec.Type {
+	case "number", "integer":
+		max := 0.0
+		for _, v := range spec.Enum {
+			if f, ok := toFloat(v); ok && f > max {
+				max = f
+			}
+		}
+		candidate = math.Floor(max) + 1
+	case "string", "":
+		candidate = fmt.Sprintf("not-in-enum-%d", len(spec.Enum))
+	default:
+		return nil, false
+	}
+	for _, v := range spec.Enum {
+		if valuesEqual(v, candidate) {
+			return nil, false
+		}
+	}
+	return candidate, true
+}
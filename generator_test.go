@@ -0,0 +1,101 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+const generatorTestSpec = `{
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "pattern": "^[a-z]{3}-[0-9]{4}$"},
+		"email": {"type": "string", "format": "email"},
+		"name": {"type": "string", "minLength": 2, "maxLength": 20},
+		"age": {"type": "integer", "min": 0, "max": 130},
+		"score": {"type": "number", "min": 0, "max": 1},
+		"role": {"type": "string", "enum": ["admin", "user", "guest"]},
+		"active": {"type": "boolean"},
+		"tags": {"type": "array", "items": {"type": "string", "minLength": 1}, "maxLength": 5, "uniqueItems": true},
+		"address": {
+			"type": "object",
+			"properties": {"city": {"type": "string", "minLength": 1}, "zip": {"type": "string", "pattern": "^[0-9]{5}$"}},
+			"required": ["city", "zip"]
+		},
+		"cardNumber": {"type": "string"},
+		"nickname": {"type": "string", "minBytes": 2, "maxBytes": 8},
+		"since": {"type": "string", "timeFormat": "02/01/2006"},
+		"sequence": {"type": "integer", "minInt": 9007199254740993, "maxInt": "18446744073709551615"}
+	},
+	"required": ["id", "email", "name", "role", "address"],
+	"conditions": [{"if": "role == \"admin\"", "required": ["age"]}],
+	"requiredIf": {"nickname": "active == true"}
+}`
+
+func TestGeneratorValid(t *testing.T) {
+	spec, err := ParseSpecString(generatorTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	gen := NewGenerator(spec, 1)
+	for i := 0; i < 200; i++ {
+		instance := gen.Valid()
+		if result := Validate(instance, spec); !result.Valid {
+			t.Fatalf("instance %#v does not validate: %v", instance, result.Errors)
+		}
+	}
+}
+
+func TestGeneratorInvalid(t *testing.T) {
+	spec, err := ParseSpecString(generatorTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	gen := NewGenerator(spec, 2)
+	codes := make(map[string]bool)
+	for i := 0; i < 300; i++ {
+		instance, violation, ok := gen.Invalid()
+		if !ok {
+			t.Fatal("expected an invalid instance")
+		}
+		codes[violation.Code] = true
+
+		result := Validate(instance, spec)
+		if !reportsViolation(result, violation) {
+			t.Fatalf("instance %#v does not report %s: %v", instance, violation, result.Errors)
+		}
+	}
+
+	for _, code := range []string{CodeType, CodeRequired, CodeMin, CodeMax, CodeMinLength, CodeMaxLength, CodePattern, CodeFormat, CodeEnum, CodeUniqueItems, CodeMinBytes, CodeMaxBytes} {
+		if !codes[code] {
+			t.Errorf("expected some instance to violate %s", code)
+		}
+	}
+}
+
+func TestGeneratorDeterministic(t *testing.T) {
+	spec, err := ParseSpecString(generatorTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	a, b := NewGenerator(spec, 42), NewGenerator(spec, 42)
+	for i := 0; i < 10; i++ {
+		if va, vb := a.Valid(), b.Valid(); !reflect.DeepEqual(va, vb) {
+			t.Fatalf("same seed produced %v and %v", va, vb)
+		}
+		ia, va, _ := a.Invalid()
+		ib, vb, _ := b.Invalid()
+		if va != vb || !reflect.DeepEqual(ia, ib) {
+			t.Fatalf("same seed produced different invalid instances: %v, %v", va, vb)
+		}
+	}
+}
+
+func TestGeneratorNothingToViolate(t *testing.T) {
+	gen := NewGenerator(&Spec{}, 1)
+	if _, _, ok := gen.Invalid(); ok {
+		t.Error("expected no violation for an empty spec")
+	}
+}
@@ -2,12 +2,15 @@ package mowgli
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
 )
 
 // ValidateStruct validates data against a struct type and returns a typed result
 // T is the struct type to validate against
 // data is the raw data (map[string]any or compatible)
 // specJSON is an optional JSON spec that will be merged on top of the struct-generated spec
+// For JSON request bodies, DecodeAndValidate avoids converting data back into T through JSON.
 func ValidateStruct[T any](data any, specJSON ...string) (*ValidationResult, T, error) {
 	var zero T
 
@@ -33,7 +36,8 @@ func ValidateStruct[T any](data any, specJSON ...string) (*ValidationResult, T,
 	}
 
 	// Convert data to the struct type
-	typedResult, err := convertToType(data, zero)
+	document, _ := result.typedDocument(reflect.TypeOf(&zero).Elem())
+	typedResult, err := convertToType(document, zero)
 	if err != nil {
 		return nil, zero, err
 	}
@@ -83,7 +87,8 @@ func ValidateAndConvert[T any](data any, spec *Spec) (*ValidationResult, T, erro
 	}
 
 	// Convert data to the struct type
-	typedResult, err := convertToType(data, zero)
+	document, _ := result.typedDocument(reflect.TypeOf(&zero).Elem())
+	typedResult, err := convertToType(document, zero)
 	if err != nil {
 		return nil, zero, err
 	}
@@ -91,6 +96,46 @@ func ValidateAndConvert[T any](data any, spec *Spec) (*ValidationResult, T, erro
 	return result, typedResult, nil
 }
 
+// ValidateJSONAs validates a JSON document against a spec and decodes it into T
+// T can be any JSON-compatible type, e.g. []Order, map[string]Config or string,
+// not just structs. The typed value is decoded straight from jsonData, so it is
+// not subject to the map[string]any round trip used by ValidateAndConvert,
+// unless the spec's transforms or time layouts changed a value.
+//
+// Of the DecodeAndValidate options, WithValidator, WithMaxBytes,
+// DecodeUseNumber, DisallowUnknownFields and RejectIntegerOverflow apply.
+func ValidateJSONAs[T any](jsonData []byte, spec *Spec, opts ...DecodeOption) (*ValidationResult, T, error) {
+	var zero T
+
+	config := decodeConfig{validator: defaultValidator}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.maxBytes > 0 && int64(len(jsonData)) > config.maxBytes {
+		return nil, zero, fmt.Errorf("body exceeds %d bytes", config.maxBytes)
+	}
+
+	result, err := config.validator.validateJSON(specName(spec), jsonData, spec, config.useNumber || config.validator.useNumber,
+		config.strict(reflect.TypeOf(&zero).Elem()))
+	if err != nil {
+		return nil, zero, err
+	}
+	if !result.Valid {
+		return result, zero, nil
+	}
+
+	if jsonData, err = result.typedJSON(jsonData, reflect.TypeOf(&zero).Elem()); err != nil {
+		return nil, zero, fmt.Errorf("failed to encode transformed document: %w", err)
+	}
+
+	var typed T
+	if err := json.Unmarshal(jsonData, &typed); err != nil {
+		return nil, zero, fmt.Errorf("failed to decode JSON into %T: %w", zero, err)
+	}
+
+	return result, typed, nil
+}
+
 // GetSpecFromStruct generates a Spec from a struct type
 // This is a convenience function that can be used to get the spec for a struct type
 func GetSpecFromStruct[T any](structValue T) (*Spec, error) {
@@ -201,3 +201,99 @@ func TestValidateStructArray(t *testing.T) {
 		t.Errorf("expected first tag developer, got %s", user.Tags[0])
 	}
 }
+
+func TestValidateJSONAs(t *testing.T) {
+	type Order struct {
+		ID    string  `json:"id"`
+		Total float64 `json:"total"`
+	}
+
+	ordersSpec, err := ParseSpecString(`{
+		"type": "array",
+		"minLength": 1,
+		"items": {
+			"type": "object",
+			"properties": {
+				"id": {"type": "string", "minLength": 1},
+				"total": {"type": "number", "min": 0}
+			},
+			"required": ["id"]
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	t.Run("array of structs", func(t *testing.T) {
+		result, orders, err := ValidateJSONAs[[]Order]([]byte(`[{"id": "a", "total": 1.5}, {"id": "b", "total": 0}]`), ordersSpec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Valid {
+			t.Fatalf("expected validation to pass, but it failed: %v", result.Errors)
+		}
+		if len(orders) != 2 || orders[0].ID != "a" || orders[0].Total != 1.5 {
+			t.Errorf("unexpected orders: %+v", orders)
+		}
+	})
+
+	t.Run("invalid array returns zero value", func(t *testing.T) {
+		result, orders, err := ValidateJSONAs[[]Order]([]byte(`[{"total": -1}]`), ordersSpec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Valid {
+			t.Fatal("expected validation to fail, but it passed")
+		}
+		if orders != nil {
+			t.Errorf("expected nil orders, got %+v", orders)
+		}
+	})
+
+	t.Run("map of values", func(t *testing.T) {
+		spec, err := ParseSpecString(`{"type": "object", "properties": {"port": {"type": "integer", "max": 65535}}}`)
+		if err != nil {
+			t.Fatalf("Failed to parse spec: %v", err)
+		}
+		result, config, err := ValidateJSONAs[map[string]int]([]byte(`{"port": 8080}`), spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Valid {
+			t.Fatalf("expected validation to pass, but it failed: %v", result.Errors)
+		}
+		if config["port"] != 8080 {
+			t.Errorf("expected port 8080, got %d", config["port"])
+		}
+	})
+
+	t.Run("scalar string", func(t *testing.T) {
+		spec, err := ParseSpecString(`{"type": "string", "enum": ["red", "green"]}`)
+		if err != nil {
+			t.Fatalf("Failed to parse spec: %v", err)
+		}
+		result, color, err := ValidateJSONAs[string]([]byte(`"green"`), spec)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Valid || color != "green" {
+			t.Errorf("expected valid green, got %v %q", result.Errors, color)
+		}
+	})
+
+	t.Run("type mismatch with T", func(t *testing.T) {
+		spec, err := ParseSpecString(`{"type": "number"}`)
+		if err != nil {
+			t.Fatalf("Failed to parse spec: %v", err)
+		}
+		if _, _, err := ValidateJSONAs[int]([]byte(`1.5`), spec); err == nil {
+			t.Error("expected decode error but got none")
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		if _, _, err := ValidateJSONAs[[]Order]([]byte(`[`), ordersSpec); err == nil {
+			t.Error("expected error but got none")
+		}
+	})
+}
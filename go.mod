@@ -3,3 +3,12 @@ module github.com/matjam/mowgli
 go 1.25.1
 
 require github.com/expr-lang/expr v1.17.6
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/rivo/uniseg v0.4.7
+)
+
+require github.com/x448/float16 v0.8.4 // indirect
@@ -0,0 +1,75 @@
+package mowgli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExpectedError is an error a test case expects validation to report
+type ExpectedError struct {
+	Path string `json:"path"`
+	Code string `json:"code"`
+}
+
+func (e ExpectedError) String() string {
+	return fmt.Sprintf("%s (%s)", displayPath(e.Path), e.Code)
+}
+
+// SnapshotErrors returns the path and code of each error in result, sorted
+// so that the snapshot doesn't depend on the order errors were found in
+func SnapshotErrors(result *ValidationResult) []ExpectedError {
+	snapshot := make([]ExpectedError, 0, len(result.Errors))
+	for _, err := range result.Errors {
+		snapshot = append(snapshot, ExpectedError{Path: err.Path, Code: err.Code})
+	}
+	sortExpectedErrors(snapshot)
+	return snapshot
+}
+
+// CompareErrors checks that result reports exactly the expected errors, in
+// any order. The returned error lists the missing and unexpected errors.
+func CompareErrors(expected []ExpectedError, result *ValidationResult) error {
+	remaining := make(map[ExpectedError]int, len(expected))
+	for _, e := range expected {
+		remaining[e]++
+	}
+
+	var unexpected []ExpectedError
+	for _, e := range SnapshotErrors(result) {
+		if remaining[e] > 0 {
+			remaining[e]--
+		} else {
+			unexpected = append(unexpected, e)
+		}
+	}
+	var missing []ExpectedError
+	for _, e := range expected {
+		if remaining[e] > 0 {
+			remaining[e]--
+			missing = append(missing, e)
+		}
+	}
+	sortExpectedErrors(missing)
+
+	if len(missing) == 0 && len(unexpected) == 0 {
+		return nil
+	}
+	var parts []string
+	if len(missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing errors %v", missing))
+	}
+	if len(unexpected) > 0 {
+		parts = append(parts, fmt.Sprintf("unexpected errors %v", unexpected))
+	}
+	return fmt.Errorf("%s", strings.Join(parts, ", "))
+}
+
+func sortExpectedErrors(errors []ExpectedError) {
+	sort.Slice(errors, func(i, j int) bool {
+		if errors[i].Path != errors[j].Path {
+			return errors[i].Path < errors[j].Path
+		}
+		return errors[i].Code < errors[j].Code
+	})
+}
@@ -0,0 +1,65 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompareErrors(t *testing.T) {
+	spec, _ := ParseSpecString(`{"type": "object", "properties": {
+		"a": {"type": "string", "minLength": 3, "pattern": "^x"},
+		"b": {"type": "integer"}
+	}, "required": ["c"]}`)
+	result := Validate(map[string]any{"a": "y", "b": "1"}, spec)
+
+	want := []ExpectedError{{Path: "a", Code: CodeMinLength}, {Path: "a", Code: CodePattern}, {Path: "b", Code: CodeType}, {Path: "c", Code: CodeRequired}}
+	if got := SnapshotErrors(result); !reflect.DeepEqual(got, want) {
+		t.Errorf("SnapshotErrors: expected %v, got %v", want, got)
+	}
+
+	tests := []struct {
+		name     string
+		expected []ExpectedError
+		wantErr  []string
+	}{
+		{
+			name:     "exact in any order",
+			expected: []ExpectedError{want[3], want[2], want[1], want[0]},
+		},
+		{
+			name:     "missing and unexpected",
+			expected: []ExpectedError{want[0], want[1], want[2], {Path: "d", Code: CodeRequired}},
+			wantErr:  []string{"missing errors [d (required)]", "unexpected errors [c (required)]"},
+		},
+		{
+			name:     "expected twice",
+			expected: append(append([]ExpectedError{}, want...), want[0]),
+			wantErr:  []string{"missing errors [a (minLength)]"},
+		},
+		{
+			name:    "none expected",
+			wantErr: []string{"unexpected errors [a (minLength) a (pattern) b (type) c (required)]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CompareErrors(tt.expected, result)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected error containing %q, got %v", want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package mowgli
+
+// UniqueErrors returns the result's errors in the order they were reported,
+// leaving out errors with the same path and message as an earlier one, such
+// as those of overlapping conditions or of several codes sharing a custom
+// message
+func (r *ValidationResult) UniqueErrors() []*ValidationError {
+	type key struct{ path, message string }
+	seen := make(map[key]bool, len(r.Errors))
+	unique := make([]*ValidationError, 0, len(r.Errors))
+	for _, err := range r.Errors {
+		k := key{err.Path, err.Message}
+		if !seen[k] {
+			seen[k] = true
+			unique = append(unique, err)
+		}
+	}
+	return unique
+}
+
+// ByPath groups the result's unique errors (see UniqueErrors) by path, in the
+// order they were reported, e.g. to show one list of messages per form
+// input. Errors about the document as a whole are under "".
+func (r *ValidationResult) ByPath() map[string][]*ValidationError {
+	grouped := make(map[string][]*ValidationError)
+	for _, err := range r.UniqueErrors() {
+		grouped[err.Path] = append(grouped[err.Path], err)
+	}
+	return grouped
+}
+
+// MessagesByPath is ByPath with only the messages of the errors
+func (r *ValidationResult) MessagesByPath() map[string][]string {
+	messages := make(map[string][]string)
+	for _, err := range r.UniqueErrors() {
+		messages[err.Path] = append(messages[err.Path], err.Message)
+	}
+	return messages
+}
@@ -0,0 +1,63 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestByPath(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"username": {
+				"type": "string",
+				"minLength": 3,
+				"pattern": "^[a-z]+$",
+				"messages": {"minLength": "Use 3-20 lowercase letters", "pattern": "Use 3-20 lowercase letters"}
+			},
+			"age": {"type": "integer", "min": 18, "enum": [21, 30]}
+		},
+		"required": ["email"],
+		"oneRequired": ["phone", "email"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"username": "A", "age": 7.0}, spec)
+	if len(result.Errors) != 6 {
+		t.Fatalf("expected 6 errors, got %v", result.Errors)
+	}
+
+	grouped := result.ByPath()
+	if len(grouped) != 4 {
+		t.Errorf("expected 4 paths, got %v", grouped)
+	}
+	if errs := grouped["username"]; len(errs) != 1 || errs[0].Code != CodeMinLength {
+		t.Errorf("expected the repeated username message once, got %v", errs)
+	}
+	if errs := grouped["age"]; len(errs) != 2 || errs[0].Code != CodeMin || errs[1].Code != CodeEnum {
+		t.Errorf("expected age errors in order, got %v", errs)
+	}
+	if errs := grouped[""]; len(errs) != 1 || errs[0].Code != CodeOneRequired {
+		t.Errorf("expected the object's error under \"\", got %v", errs)
+	}
+
+	messages := result.MessagesByPath()
+	if want := []string{"Use 3-20 lowercase letters"}; !reflect.DeepEqual(messages["username"], want) {
+		t.Errorf("expected %v, got %v", want, messages["username"])
+	}
+	if len(result.UniqueErrors()) != 5 {
+		t.Errorf("expected 5 unique errors, got %v", result.UniqueErrors())
+	}
+}
+
+func TestByPathValid(t *testing.T) {
+	result := Validate("x", &Spec{Type: "string"})
+	if grouped := result.ByPath(); len(grouped) != 0 {
+		t.Errorf("expected no groups, got %v", grouped)
+	}
+	if unique := result.UniqueErrors(); unique == nil || len(unique) != 0 {
+		t.Errorf("expected an empty list, got %v", unique)
+	}
+}
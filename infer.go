@@ -0,0 +1,205 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// maxEnumCandidates is the largest number of distinct string values that
+// InferSpec will propose as an enum
+const maxEnumCandidates = 5
+
+// InferSpec produces a draft spec from example documents. It records the
+// observed types, marks object properties present in every example as
+// required, proposes enums for strings that only take a few repeated values,
+// and sets min/max to the observed numeric range. The result is a starting
+// point to be reviewed by hand, not a finished contract.
+//
+// Examples may be decoded JSON values, Go values that marshal to JSON, or
+// raw JSON as []byte or json.RawMessage. Examples that cannot be converted
+// to JSON are ignored.
+func InferSpec(examples ...any) *Spec {
+	root := newInferNode()
+	for _, example := range examples {
+		value, ok := toJSONValue(example)
+		if !ok {
+			continue
+		}
+		root.observe(value)
+	}
+	return root.spec()
+}
+
+// toJSONValue converts an example into the generic tree produced by encoding/json
+func toJSONValue(v any) (any, bool) {
+	var data []byte
+	switch val := v.(type) {
+	case json.RawMessage:
+		data = val
+	case []byte:
+		data = val
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, false
+		}
+	}
+
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// inferNode accumulates observations for one position in the document tree
+type inferNode struct {
+	typeOrder  []string
+	typeCounts map[string]int
+
+	// Objects
+	objects    int
+	props      map[string]*inferNode
+	propCounts map[string]int
+
+	// Arrays
+	items *inferNode
+
+	// Numbers
+	hasRange bool
+	min, max float64
+
+	// Strings
+	strings      int
+	stringValues map[string]bool
+}
+
+func newInferNode() *inferNode {
+	return &inferNode{
+		typeCounts:   make(map[string]int),
+		props:        make(map[string]*inferNode),
+		propCounts:   make(map[string]int),
+		stringValues: make(map[string]bool),
+	}
+}
+
+func (n *inferNode) addType(t string) {
+	if n.typeCounts[t] == 0 {
+		n.typeOrder = append(n.typeOrder, t)
+	}
+	n.typeCounts[t]++
+}
+
+func (n *inferNode) observe(value any) {
+	switch v := value.(type) {
+	case nil:
+		n.addType("null")
+	case bool:
+		n.addType("boolean")
+	case float64:
+		if v == math.Trunc(v) {
+			n.addType("integer")
+		} else {
+			n.addType("number")
+		}
+		if !n.hasRange || v < n.min {
+			n.min = v
+		}
+		if !n.hasRange || v > n.max {
+			n.max = v
+		}
+		n.hasRange = true
+	case string:
+		n.addType("string")
+		n.strings++
+		// Stop tracking once there are too many values for an enum
+		if len(n.stringValues) <= maxEnumCandidates {
+			n.stringValues[v] = true
+		}
+	case []any:
+		n.addType("array")
+		if n.items == nil {
+			n.items = newInferNode()
+		}
+		for _, item := range v {
+			n.items.observe(item)
+		}
+	case map[string]any:
+		n.addType("object")
+		n.objects++
+		for key, propValue := range v {
+			prop, ok := n.props[key]
+			if !ok {
+				prop = newInferNode()
+				n.props[key] = prop
+			}
+			n.propCounts[key]++
+			prop.observe(propValue)
+		}
+	}
+}
+
+// inferredType picks the spec type for the node. Integers widen to number
+// when both were seen; otherwise the most frequent non-null type wins.
+func (n *inferNode) inferredType() string {
+	if n.typeCounts["integer"] > 0 && n.typeCounts["number"] > 0 {
+		return "number"
+	}
+
+	best := ""
+	for _, t := range n.typeOrder {
+		if t == "null" {
+			continue
+		}
+		if best == "" || n.typeCounts[t] > n.typeCounts[best] {
+			best = t
+		}
+	}
+	if best == "" && n.typeCounts["null"] > 0 {
+		return "null"
+	}
+	return best
+}
+
+func (n *inferNode) spec() *Spec {
+	spec := &Spec{Type: n.inferredType()}
+
+	switch spec.Type {
+	case "integer", "number":
+		if n.hasRange {
+			min, max := n.min, n.max
+			spec.Min = &min
+			spec.Max = &max
+		}
+	case "string":
+		// Propose an enum when a handful of values repeat across examples
+		distinct := len(n.stringValues)
+		if distinct > 0 && distinct <= maxEnumCandidates && n.strings >= 2*distinct {
+			values := make([]string, 0, distinct)
+			for value := range n.stringValues {
+				values = append(values, value)
+			}
+			sort.Strings(values)
+			spec.Enum = make([]any, len(values))
+			for i, value := range values {
+				spec.Enum[i] = value
+			}
+		}
+	case "array":
+		if n.items != nil && len(n.items.typeOrder) > 0 {
+			spec.Items = n.items.spec()
+		}
+	case "object":
+		spec.Properties = make(map[string]*Spec, len(n.props))
+		for key, prop := range n.props {
+			spec.Properties[key] = prop.spec()
+			if n.propCounts[key] == n.objects {
+				spec.Required = append(spec.Required, key)
+			}
+		}
+		sort.Strings(spec.Required)
+	}
+
+	return spec
+}
@@ -0,0 +1,94 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInferSpec(t *testing.T) {
+	examples := []any{
+		json.RawMessage(`{"id": 1, "name": "a", "status": "active", "price": 10, "tags": ["x"]}`),
+		json.RawMessage(`{"id": 2, "name": "b", "status": "inactive", "price": 2.5}`),
+		json.RawMessage(`{"id": 3, "name": "c", "status": "active", "price": 7, "note": null}`),
+		json.RawMessage(`{"id": 4, "name": "d", "status": "active", "price": 1}`),
+	}
+
+	spec := InferSpec(examples...)
+
+	if spec.Type != "object" {
+		t.Fatalf("expected type object, got %s", spec.Type)
+	}
+
+	wantRequired := []string{"id", "name", "price", "status"}
+	if !reflect.DeepEqual(spec.Required, wantRequired) {
+		t.Errorf("expected required %v, got %v", wantRequired, spec.Required)
+	}
+
+	tests := []struct {
+		name string
+		prop string
+		typ  string
+	}{
+		{name: "integer", prop: "id", typ: "integer"},
+		{name: "string", prop: "name", typ: "string"},
+		{name: "mixed integer and float widens", prop: "price", typ: "number"},
+		{name: "array", prop: "tags", typ: "array"},
+		{name: "only null", prop: "note", typ: "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prop := spec.Properties[tt.prop]
+			if prop == nil {
+				t.Fatalf("expected property %s", tt.prop)
+			}
+			if prop.Type != tt.typ {
+				t.Errorf("expected type %s, got %s", tt.typ, prop.Type)
+			}
+		})
+	}
+
+	id := spec.Properties["id"]
+	if id.Min == nil || *id.Min != 1 || id.Max == nil || *id.Max != 4 {
+		t.Errorf("expected id range 1..4, got %v..%v", id.Min, id.Max)
+	}
+
+	if enum := spec.Properties["status"].Enum; !reflect.DeepEqual(enum, []any{"active", "inactive"}) {
+		t.Errorf("expected status enum [active inactive], got %v", enum)
+	}
+	if enum := spec.Properties["name"].Enum; enum != nil {
+		t.Errorf("expected no enum for unique names, got %v", enum)
+	}
+
+	if items := spec.Properties["tags"].Items; items == nil || items.Type != "string" {
+		t.Errorf("expected string items, got %+v", items)
+	}
+
+	// Every example must validate against the inferred spec
+	for i, example := range examples {
+		var data any
+		if err := json.Unmarshal(example.(json.RawMessage), &data); err != nil {
+			t.Fatalf("failed to decode example %d: %v", i, err)
+		}
+		if result := Validate(data, spec); !result.Valid {
+			t.Errorf("example %d does not validate against inferred spec: %v", i, result.Errors)
+		}
+	}
+}
+
+func TestInferSpecFromGoValues(t *testing.T) {
+	type item struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+
+	spec := InferSpec([]item{{SKU: "A", Qty: 1}}, []item{{SKU: "B", Qty: 5}, {SKU: "C", Qty: 3}})
+
+	if spec.Type != "array" || spec.Items == nil || spec.Items.Type != "object" {
+		t.Fatalf("expected array of objects, got %+v", spec)
+	}
+	qty := spec.Items.Properties["qty"]
+	if qty == nil || qty.Type != "integer" || *qty.Min != 1 || *qty.Max != 5 {
+		t.Errorf("unexpected qty spec: %+v", qty)
+	}
+}
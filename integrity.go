@@ -0,0 +1,125 @@
+package mowgli
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+)
+
+// digestPrefix marks the algorithm of spec digests
+const digestPrefix = "sha256:"
+
+// SignedSpec is a spec together with its digest and, optionally, a
+// signature of the digest, so a spec can be checked for tampering between
+// the party that wrote it and the service that validates with it. It is
+// stored and sent as JSON, in place of the bare spec.
+type SignedSpec struct {
+	Spec      *Spec  `json:"spec"`
+	Digest    string `json:"digest"`              // SpecDigest of Spec, e.g. "sha256:9f86d0..."
+	KeyID     string `json:"keyId,omitempty"`     // Name of the key that signed the digest
+	Signature string `json:"signature,omitempty"` // Base64 Ed25519 signature of the digest
+}
+
+// TrustKeys are the public keys specs may be signed with, by key ID
+type TrustKeys map[string]ed25519.PublicKey
+
+// SpecDigest returns the SHA-256 digest of spec's canonical JSON encoding,
+// e.g. "sha256:9f86d0...". Specs that are equal keyword for keyword have the
+// same digest however their JSON was formatted, so digests can be logged to
+// record which spec validated a payload.
+func SpecDigest(spec *Spec) (string, error) {
+	if spec == nil {
+		return "", fmt.Errorf("spec is nil")
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode spec: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(sum[:]), nil
+}
+
+// SignSpec signs spec's digest with key, recording keyID so verifiers know
+// which trusted key to check it with
+func SignSpec(spec *Spec, keyID string, key ed25519.PrivateKey) (*SignedSpec, error) {
+	digest, err := SpecDigest(spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key")
+	}
+	return &SignedSpec{
+		Spec:      spec,
+		Digest:    digest,
+		KeyID:     keyID,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(digest))),
+	}, nil
+}
+
+// Verify checks that the spec matches its digest and returns the spec. With
+// nil keys only the digest is checked; any other keys, even an empty set,
+// require the digest to be signed with one of them, so a missing key
+// configuration can't turn signature checks off.
+func (s *SignedSpec) Verify(keys TrustKeys) (*Spec, error) {
+	digest, err := SpecDigest(s.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if s.Digest != digest {
+		return nil, fmt.Errorf("spec digest mismatch: expected %s, got %s", s.Digest, digest)
+	}
+	if keys == nil {
+		return s.Spec, nil
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no trusted keys to verify the spec signature with")
+	}
+
+	if s.Signature == "" {
+		return nil, fmt.Errorf("spec is not signed")
+	}
+	key, ok := keys[s.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("spec is signed with untrusted key %q", s.KeyID)
+	}
+	signature, err := base64.StdEncoding.DecodeString(s.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec signature: %w", err)
+	}
+	if !ed25519.Verify(key, []byte(digest), signature) {
+		return nil, fmt.Errorf("spec signature does not verify with key %q", s.KeyID)
+	}
+	return s.Spec, nil
+}
+
+// VerifySpec parses a SignedSpec and verifies it with keys (see
+// SignedSpec.Verify)
+func VerifySpec(data []byte, keys TrustKeys) (*Spec, error) {
+	var signed SignedSpec
+	if err := json.Unmarshal(data, &signed); err != nil {
+		return nil, fmt.Errorf("invalid signed spec: %w", err)
+	}
+	if signed.Spec == nil {
+		return nil, fmt.Errorf("invalid signed spec: missing spec")
+	}
+	return signed.Verify(keys)
+}
+
+// LoadSignedSpecFS loads a SignedSpec file from fsys and verifies it with
+// keys before returning its spec
+func LoadSignedSpecFS(fsys fs.FS, name string, keys TrustKeys) (*Spec, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file %s: %w", name, err)
+	}
+	spec, err := VerifySpec(data, keys)
+	if err != nil {
+		return nil, fmt.Errorf("spec file %s: %w", name, err)
+	}
+	return spec, nil
+}
@@ -0,0 +1,129 @@
+package mowgli
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// testKeys returns two deterministic key pairs
+func testKeys(t *testing.T) (ed25519.PrivateKey, ed25519.PrivateKey) {
+	t.Helper()
+	return ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+		ed25519.NewKeyFromSeed([]byte(strings.Repeat("x", ed25519.SeedSize)))
+}
+
+func TestSpecDigest(t *testing.T) {
+	a, _ := ParseSpecString(`{"type": "object", "properties": {"b": {"type": "string"}, "a": {"min": 1.0}}}`)
+	b, _ := ParseSpecString(`{"properties":{"a":{"min":1},"b":{"type":"string"}},"type":"object"}`)
+	c, _ := ParseSpecString(`{"type": "object", "properties": {"b": {"type": "string"}, "a": {"min": 2}}}`)
+
+	digestA, err := SpecDigest(a)
+	if err != nil {
+		t.Fatalf("SpecDigest returned error: %v", err)
+	}
+	if !strings.HasPrefix(digestA, "sha256:") || len(digestA) != len("sha256:")+64 {
+		t.Errorf("expected a sha256 digest, got %q", digestA)
+	}
+	if digestB, _ := SpecDigest(b); digestB != digestA {
+		t.Errorf("expected formatting not to change the digest, got %s and %s", digestA, digestB)
+	}
+	if digestC, _ := SpecDigest(c); digestC == digestA {
+		t.Error("expected a changed spec to change the digest")
+	}
+	if _, err := SpecDigest(nil); err == nil {
+		t.Error("expected an error for a nil spec")
+	}
+}
+
+func TestVerifySpec(t *testing.T) {
+	trusted, untrusted := testKeys(t)
+	keys := TrustKeys{"release": trusted.Public().(ed25519.PublicKey)}
+	spec := &Spec{Type: "object", Required: []string{"tenant"}}
+
+	sign := func(key ed25519.PrivateKey, keyID string, tamper func(*SignedSpec)) []byte {
+		signed, err := SignSpec(spec.Clone(), keyID, key)
+		if err != nil {
+			t.Fatalf("SignSpec returned error: %v", err)
+		}
+		if tamper != nil {
+			tamper(signed)
+		}
+		data, _ := json.Marshal(signed)
+		return data
+	}
+	digestOnly := func(s *SignedSpec) { s.KeyID, s.Signature = "", "" }
+
+	tests := []struct {
+		name    string
+		data    []byte
+		keys    TrustKeys
+		wantErr string
+	}{
+		{name: "signed", data: sign(trusted, "release", nil), keys: keys},
+		{name: "digest only without keys", data: sign(trusted, "", digestOnly)},
+		{name: "tampered spec", data: sign(trusted, "release", func(s *SignedSpec) { s.Spec.Required = nil }), keys: keys, wantErr: "spec digest mismatch"},
+		{name: "tampered spec without keys", data: sign(trusted, "release", func(s *SignedSpec) { s.Spec.Type = "array" }), wantErr: "spec digest mismatch"},
+		{name: "unsigned", data: sign(trusted, "", digestOnly), keys: keys, wantErr: "spec is not signed"},
+		{name: "unsigned with empty keys", data: sign(trusted, "", digestOnly), keys: TrustKeys{}, wantErr: "no trusted keys"},
+		{name: "signed with empty keys", data: sign(trusted, "release", nil), keys: TrustKeys{}, wantErr: "no trusted keys"},
+		{name: "unknown key", data: sign(untrusted, "dev", nil), keys: keys, wantErr: `untrusted key "dev"`},
+		{name: "wrong key", data: sign(untrusted, "release", nil), keys: keys, wantErr: `does not verify with key "release"`},
+		{name: "bad signature", data: sign(trusted, "release", func(s *SignedSpec) { s.Signature = "!" }), keys: keys, wantErr: "invalid spec signature"},
+		{name: "missing spec", data: []byte(`{"digest": "sha256:00"}`), wantErr: "missing spec"},
+		{name: "not JSON", data: []byte(`{`), wantErr: "invalid signed spec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifySpec(tt.data, tt.keys)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifySpec returned error: %v", err)
+			}
+			if !SpecEqual(got, spec) {
+				t.Errorf("expected %+v, got %+v", spec, got)
+			}
+		})
+	}
+}
+
+func TestSignedSpecLoaders(t *testing.T) {
+	key, other := testKeys(t)
+	keys := TrustKeys{"release": key.Public().(ed25519.PublicKey)}
+	signed, err := SignSpec(&Spec{Type: "string"}, "release", key)
+	if err != nil {
+		t.Fatalf("SignSpec returned error: %v", err)
+	}
+	good, _ := json.Marshal(signed)
+	forged, _ := SignSpec(&Spec{Type: "string"}, "release", other)
+	bad, _ := json.Marshal(forged)
+
+	fsys := fstest.MapFS{"good.json": {Data: good}, "bad.json": {Data: bad}}
+	if spec, err := LoadSignedSpecFS(fsys, "good.json", keys); err != nil || spec.Type != "string" {
+		t.Errorf("expected the signed spec, got %v, %v", spec, err)
+	}
+	if _, err := LoadSignedSpecFS(fsys, "bad.json", keys); err == nil || !strings.Contains(err.Error(), "spec file bad.json: spec signature") {
+		t.Errorf("expected a signature error, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(fsys[strings.TrimPrefix(r.URL.Path, "/")].Data)
+	}))
+	defer server.Close()
+	fetcher := NewHTTPFetcher(server.URL+"/{ref}.json", HTTPFetcherConfig{TrustKeys: keys})
+	if spec, err := fetcher.Fetch(t.Context(), "good"); err != nil || spec.Type != "string" {
+		t.Errorf("expected the signed spec, got %v, %v", spec, err)
+	}
+	if _, err := fetcher.Fetch(t.Context(), "bad"); err == nil || !strings.Contains(err.Error(), "spec signature") {
+		t.Errorf("expected a signature error, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package mowgli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// currentIdentifier refers to the value a JSONPath selected, written "@" in
+// filters and the expressions of asserts with a selector
+const currentIdentifier = "$current"
+
+// Kinds of JSONPath steps
+const (
+	jsonPathChild    = iota // A property by name, e.g. .total or ['total']
+	jsonPathWildcard        // Every property or item, .* or [*]
+	jsonPathIndex           // An item by index, e.g. [0] or [-1]
+	jsonPathFilter          // The properties or items a filter accepts, e.g. [?(@.status == 'paid')]
+)
+
+// jsonPathStep is a step of a JSONPath
+type jsonPathStep struct {
+	kind      int
+	name      string
+	index     int
+	filter    string // Expression, with "@" standing for the candidate value
+	recursive bool   // Applies to the value and all its descendants (..)
+}
+
+// jsonPathMatch is a value a JSONPath selected
+type jsonPathMatch struct {
+	path  string
+	value any
+}
+
+// parseJSONPath parses a JSONPath such as "$.orders[?(@.status == 'paid')].total".
+// It supports names, wildcards, indexes, recursive descent and filters.
+func parseJSONPath(jsonPath string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(jsonPath, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", jsonPath)
+	}
+	var steps []jsonPathStep
+	rest := jsonPath[1:]
+	for rest != "" {
+		var step jsonPathStep
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			step.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			fallthrough
+		case strings.HasPrefix(rest, "."):
+			rest = strings.TrimPrefix(rest, ".")
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid JSONPath %q: empty name", jsonPath)
+			case "*":
+				step.kind = jsonPathWildcard
+			default:
+				step.kind, step.name = jsonPathChild, name
+			}
+			steps = append(steps, step)
+			continue
+		case !strings.HasPrefix(rest, "["):
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", jsonPath, rest)
+		}
+
+		end := closingBracket(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("invalid JSONPath %q: unclosed [", jsonPath)
+		}
+		selector := strings.TrimSpace(rest[1:end])
+		rest = rest[end+1:]
+		switch {
+		case selector == "*":
+			step.kind = jsonPathWildcard
+		case strings.HasPrefix(selector, "?"):
+			filter := strings.TrimSpace(selector[1:])
+			if strings.HasPrefix(filter, "(") && strings.HasSuffix(filter, ")") {
+				filter = filter[1 : len(filter)-1]
+			}
+			if strings.TrimSpace(filter) == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty filter", jsonPath)
+			}
+			step.kind, step.filter = jsonPathFilter, filter
+		case len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0]:
+			step.kind, step.name = jsonPathChild, selector[1:len(selector)-1]
+		default:
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: unsupported selector [%s]", jsonPath, selector)
+			}
+			step.kind, step.index = jsonPathIndex, index
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the "]" closing the "[" s starts
+// with, skipping brackets in quotes and nested in filters, or -1
+func closingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// selectJSONPath returns the values steps select from value, located at
+// path, in document order. Filters are evaluated with accept.
+func selectJSONPath(path string, value any, steps []jsonPathStep, accept func(filter string, value any) (bool, error)) ([]jsonPathMatch, error) {
+	nodes := []jsonPathMatch{{path: path, value: value}}
+	for _, step := range steps {
+		var next []jsonPathMatch
+		for _, node := range nodes {
+			candidates := []jsonPathMatch{node}
+			if step.recursive {
+				candidates = descendants(node, nil)
+			}
+			for _, candidate := range candidates {
+				selected, err := step.apply(candidate, accept)
+				if err != nil {
+					return nil, err
+				}
+				next = append(next, selected...)
+			}
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// apply returns the values the step selects from node
+func (s jsonPathStep) apply(node jsonPathMatch, accept func(filter string, value any) (bool, error)) ([]jsonPathMatch, error) {
+	switch s.kind {
+	case jsonPathChild:
+		if obj, ok := node.value.(map[string]any); ok {
+			if value, exists := obj[s.name]; exists {
+				return []jsonPathMatch{{path: buildPath(node.path, s.name), value: value}}, nil
+			}
+		}
+		return nil, nil
+	case jsonPathIndex:
+		arr, ok := node.value.([]any)
+		index := s.index
+		if index < 0 {
+			index += len(arr)
+		}
+		if !ok || index < 0 || index >= len(arr) {
+			return nil, nil
+		}
+		return []jsonPathMatch{{path: buildArrayPath(node.path, index), value: arr[index]}}, nil
+	}
+
+	var selected []jsonPathMatch
+	for _, child := range children(node) {
+		if s.kind == jsonPathFilter {
+			ok, err := accept(s.filter, child.value)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		selected = append(selected, child)
+	}
+	return selected, nil
+}
+
+// children returns the properties, sorted by name, or items of node
+func children(node jsonPathMatch) []jsonPathMatch {
+	var nodes []jsonPathMatch
+	switch v := node.value.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			nodes = append(nodes, jsonPathMatch{path: buildPath(node.path, key), value: v[key]})
+		}
+	case []any:
+		for i, item := range v {
+			nodes = append(nodes, jsonPathMatch{path: buildArrayPath(node.path, i), value: item})
+		}
+	}
+	return nodes
+}
+
+// descendants appends node and the values inside it to nodes, in document
+// order
+func descendants(node jsonPathMatch, nodes []jsonPathMatch) []jsonPathMatch {
+	nodes = append(nodes, node)
+	for _, child := range children(node) {
+		nodes = descendants(child, nodes)
+	}
+	return nodes
+}
+
+// translateCurrent replaces "@" outside string literals in an expression
+// with currentIdentifier
+func translateCurrent(expression string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && i+1 < len(expression) {
+				b.WriteByte(c)
+				i++
+				c = expression[i]
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '@':
+			b.WriteString(currentIdentifier)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package mowgli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectJSONPath(t *testing.T) {
+	document := map[string]any{
+		"store": map[string]any{
+			"books": []any{
+				map[string]any{"title": "A", "price": 8.0},
+				map[string]any{"title": "B", "price": 12.0},
+				map[string]any{"title": "C", "price": 30.0, "isbn": "123"},
+			},
+			"bicycle": map[string]any{"price": 20.0},
+		},
+	}
+
+	tests := []struct {
+		jsonPath string
+		paths    []string
+	}{
+		{"$", []string{""}},
+		{"$.store.bicycle.price", []string{"store.bicycle.price"}},
+		{"$['store']['bicycle']", []string{"store.bicycle"}},
+		{"$.store.books[0].title", []string{"store.books[0].title"}},
+		{"$.store.books[-1].title", []string{"store.books[2].title"}},
+		{"$.store.books[5]", nil},
+		{"$.store.books[*].title", []string{"store.books[0].title", "store.books[1].title", "store.books[2].title"}},
+		{"$.store.*", []string{"store.bicycle", "store.books"}},
+		{"$..price", []string{"store.bicycle.price", "store.books[0].price", "store.books[1].price", "store.books[2].price"}},
+		{"$..[1].title", []string{"store.books[1].title"}},
+		{"$.store.books[?(@.price > 10)].title", []string{"store.books[1].title", "store.books[2].title"}},
+		{"$.store.books[?@.isbn != null]", []string{"store.books[2]"}},
+		{"$.store.books[?(@.title == 'B')]", []string{"store.books[1]"}},
+		{"$.missing[*]", nil},
+	}
+
+	r := &ValidationResult{}
+	accept := func(filter string, value any) (bool, error) {
+		return r.evalCondition(translateCurrent(filter), map[string]any{currentIdentifier: value})
+	}
+	for _, tt := range tests {
+		t.Run(tt.jsonPath, func(t *testing.T) {
+			steps, err := parseJSONPath(tt.jsonPath)
+			if err != nil {
+				t.Fatalf("parseJSONPath failed: %v", err)
+			}
+			selected, err := selectJSONPath("", document, steps, accept)
+			if err != nil {
+				t.Fatalf("selectJSONPath failed: %v", err)
+			}
+			var paths []string
+			for _, match := range selected {
+				paths = append(paths, match.path)
+			}
+			if strings.Join(paths, " ") != strings.Join(tt.paths, " ") {
+				t.Errorf("expected %v, got %v", tt.paths, paths)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	for _, jsonPath := range []string{"", "store", "$.", "$..", "$[", "$[x]", "$[?()]", "$store"} {
+		t.Run(jsonPath, func(t *testing.T) {
+			if _, err := parseJSONPath(jsonPath); err == nil {
+				t.Errorf("expected an error for %q", jsonPath)
+			}
+		})
+	}
+}
+
+func TestTranslateCurrent(t *testing.T) {
+	tests := []struct {
+		in, out string
+	}{
+		{"@ > 0", "$current > 0"},
+		{"@.status == 'paid'", "$current.status == 'paid'"},
+		{`@.email == "a@b.c"`, `$current.email == "a@b.c"`},
+		{`@.note == 'it\'s @'`, `$current.note == 'it\'s @'`},
+	}
+	for _, tt := range tests {
+		if got := translateCurrent(tt.in); got != tt.out {
+			t.Errorf("translateCurrent(%q) = %q, want %q", tt.in, got, tt.out)
+		}
+	}
+}
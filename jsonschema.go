@@ -0,0 +1,444 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FromJSONSchema converts a JSON Schema (draft 7 to 2020-12) to a Spec, for
+// the keywords mowgli supports: type (one type, optionally with "null"),
+// properties, required, items, additionalProperties, propertyNames,
+// minimum, maximum, minLength, maxLength, minItems, maxItems, pattern,
+// format, enum, const, uniqueItems, readOnly, writeOnly, deprecated, if,
+// then, else and the content keywords. Annotations such as title and
+// description are dropped.
+//
+// A schema without "type" gets the type its keywords apply to, e.g. string
+// for minLength. Unlike JSON Schema, the Spec then rejects values of other
+// types. Schemas using other keywords, boolean schemas and
+// "additionalProperties": false return an error listing what is
+// unsupported.
+func FromJSONSchema(data []byte) (*Spec, error) {
+	var schema any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema: %w", err)
+	}
+
+	var c schemaConverter
+	spec := c.convert("", schema)
+	if len(c.unsupported) > 0 {
+		return nil, fmt.Errorf("unsupported JSON Schema: %s", strings.Join(c.unsupported, "; "))
+	}
+	return spec, nil
+}
+
+// schemaConverter converts a JSON Schema, collecting what can't be converted
+type schemaConverter struct {
+	unsupported []string
+}
+
+func (c *schemaConverter) fail(pointer, problem string) {
+	if pointer == "" {
+		pointer = "/"
+	}
+	c.unsupported = append(c.unsupported, fmt.Sprintf("%s (at %s)", problem, pointer))
+}
+
+// schemaKeywords are the supported keywords that apply to any type
+var schemaKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true,
+	"examples": true, "type": true, "enum": true, "const": true, "readOnly": true, "writeOnly": true, "deprecated": true,
+	"if": true, "then": true, "else": true,
+}
+
+// schemaKeywordTypes are the types each type-specific keyword applies to
+var schemaKeywordTypes = map[string]string{
+	"properties": "object", "required": "object", "additionalProperties": "object", "propertyNames": "object",
+	"items": "array", "minItems": "array", "maxItems": "array", "uniqueItems": "array",
+	"minLength": "string", "maxLength": "string", "pattern": "string", "format": "string",
+	"contentEncoding": "string", "contentMediaType": "string", "contentSchema": "string",
+	"minimum": "number", "maximum": "number",
+}
+
+func (c *schemaConverter) convert(pointer string, schema any) *Spec {
+	obj, ok := schema.(map[string]any)
+	if !ok {
+		c.fail(pointer, "boolean schema")
+		return nil
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	supported := true
+	for _, key := range keys {
+		if _, ok := schemaKeywordTypes[key]; !ok && !schemaKeywords[key] {
+			c.fail(pointer, "keyword "+key)
+			supported = false
+		}
+	}
+	spec := &Spec{}
+	if !supported || !c.convertType(pointer, obj, spec) {
+		return spec
+	}
+
+	for _, key := range keys {
+		value := obj[key]
+		at := pointer + "/" + key
+		// Type-specific keywords don't apply to values of other types
+		if applies, ok := schemaKeywordTypes[key]; ok && applies != spec.Type && !(applies == "number" && spec.Type == "integer") {
+			continue
+		}
+
+		switch key {
+		case "$schema", "$id", "$comment", "title", "description", "type":
+		case "default":
+			spec.Default = value
+		case "examples":
+			spec.Examples = c.list(at, value)
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				c.fail(at, "properties that aren't an object")
+				continue
+			}
+			spec.Properties = make(map[string]*Spec, len(props))
+			for name, prop := range props {
+				spec.Properties[name] = c.convert(at+"/"+escapePointer(name), prop)
+			}
+		case "required":
+			for _, name := range c.list(at, value) {
+				if s, ok := name.(string); ok {
+					spec.Required = append(spec.Required, s)
+				}
+			}
+		case "items":
+			spec.Items = c.convert(at, value)
+		case "additionalProperties":
+			if allowed, ok := value.(bool); ok {
+				if !allowed {
+					c.fail(at, "additionalProperties: false")
+				}
+				continue
+			}
+			spec.AdditionalProperties = c.convert(at, value)
+		case "propertyNames":
+			spec.PropertyNames = c.convert(at, value)
+			if spec.PropertyNames != nil && spec.PropertyNames.Type == "" {
+				spec.PropertyNames.Type = "string"
+			}
+		case "if":
+			spec.If = c.convertSubschema(at, value, spec)
+		case "then", "else":
+			// JSON Schema ignores then and else without if
+			if _, ok := obj["if"]; !ok {
+				continue
+			}
+			if key == "then" {
+				spec.Then = c.convertSubschema(at, value, spec)
+			} else {
+				spec.Else = c.convertSubschema(at, value, spec)
+			}
+		case "contentSchema":
+			spec.ContentSchema = c.convert(at, value)
+		case "minimum", "maximum":
+			num, ok := value.(float64)
+			if !ok {
+				c.fail(at, key+" that isn't a number")
+				continue
+			}
+			if key == "minimum" {
+				spec.Min = &num
+			} else {
+				spec.Max = &num
+			}
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, ok := c.count(at, key, value)
+			if !ok {
+				continue
+			}
+			if strings.HasPrefix(key, "min") {
+				spec.MinLength = &n
+			} else {
+				spec.MaxLength = &n
+			}
+			// JSON Schema counts code points, whatever the Validator's default
+			if spec.Type == "string" {
+				spec.LengthUnit = LengthRunes
+			}
+		case "pattern", "format", "contentEncoding", "contentMediaType":
+			s, ok := value.(string)
+			if !ok {
+				c.fail(at, key+" that isn't a string")
+				continue
+			}
+			switch key {
+			case "pattern":
+				spec.Pattern = &s
+			case "format":
+				if _, ok := lookupFormat(s); !ok {
+					c.fail(at, "format "+s)
+					continue
+				}
+				spec.Format = &s
+			case "contentEncoding":
+				spec.ContentEncoding = s
+			default:
+				spec.ContentMediaType = s
+			}
+		case "enum":
+			spec.Enum = c.list(at, value)
+		case "const":
+			spec.Enum = []any{value}
+		case "uniqueItems", "readOnly", "writeOnly", "deprecated":
+			b, ok := value.(bool)
+			if !ok {
+				c.fail(at, key+" that isn't a boolean")
+				continue
+			}
+			switch key {
+			case "uniqueItems":
+				spec.UniqueItems = &b
+			case "readOnly":
+				spec.ReadOnly = &b
+			case "writeOnly":
+				spec.WriteOnly = &b
+			default:
+				spec.Deprecated = &b
+			}
+		}
+	}
+
+	// null passes type validation only if the spec is nullable
+	if slices.Contains(spec.Enum, nil) && spec.Type != "null" {
+		nullable := true
+		spec.Nullable = &nullable
+	}
+	return spec
+}
+
+// convertSubschema converts a subschema applying to the same value as spec,
+// such as if, leaving out the type spec declares. A boolean subschema
+// matches every value (true) or none (false).
+func (c *schemaConverter) convertSubschema(pointer string, schema any, spec *Spec) *Spec {
+	if matchesAll, ok := schema.(bool); ok {
+		if matchesAll {
+			return &Spec{}
+		}
+		c.fail(pointer, "false subschema")
+		return nil
+	}
+	if obj, ok := schema.(map[string]any); ok && len(obj) == 0 {
+		return &Spec{}
+	}
+	sub := c.convert(pointer, schema)
+	if sub != nil && (sub.Type == spec.Type || sub.Type == "number" && spec.Type == "integer") {
+		sub.Type = ""
+	}
+	return sub
+}
+
+// convertType sets the spec's type and nullability from "type", or infers
+// the type from the schema's keywords. It reports whether it succeeded.
+func (c *schemaConverter) convertType(pointer string, obj map[string]any, spec *Spec) bool {
+	switch t := obj["type"].(type) {
+	case string:
+		spec.Type = t
+	case []any:
+		var types []string
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				types = append(types, s)
+			}
+		}
+		switch {
+		case len(types) == 0 && len(t) > 0:
+			spec.Type = "null"
+		case len(types) == 1 && len(t) == 2:
+			nullable := true
+			spec.Type, spec.Nullable = types[0], &nullable
+		default:
+			c.fail(pointer+"/type", "several types")
+			return false
+		}
+	case nil:
+		return c.inferType(pointer, obj, spec)
+	default:
+		c.fail(pointer+"/type", "type that isn't a string or array")
+		return false
+	}
+
+	if !knownTypes[spec.Type] {
+		c.fail(pointer+"/type", "type "+spec.Type)
+		return false
+	}
+	return true
+}
+
+// inferType picks the type for a schema without "type" from the keywords
+// and enum values it has
+func (c *schemaConverter) inferType(pointer string, obj map[string]any, spec *Spec) bool {
+	types := map[string]bool{}
+	for key := range obj {
+		if t, ok := schemaKeywordTypes[key]; ok {
+			types[t] = true
+		}
+	}
+	values := c.list(pointer+"/enum", obj["enum"])
+	if constValue, ok := obj["const"]; ok {
+		values = append(values, constValue)
+	}
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			types["string"] = true
+		case bool:
+			types["boolean"] = true
+		case float64:
+			if v == math.Trunc(v) && len(types) == 0 {
+				types["integer"] = true
+			} else {
+				types["number"] = true
+			}
+		case []any:
+			types["array"] = true
+		case map[string]any:
+			types["object"] = true
+		}
+	}
+	if types["integer"] && types["number"] {
+		delete(types, "integer")
+	}
+
+	switch len(types) {
+	case 0:
+		if len(values) > 0 {
+			spec.Type = "null"
+			return true
+		}
+		c.fail(pointer, "schema without type")
+		return false
+	case 1:
+		for t := range types {
+			spec.Type = t
+		}
+		return true
+	}
+	c.fail(pointer, "keywords or values of several types without type")
+	return false
+}
+
+// list returns a JSON array value, or nil if value is absent
+func (c *schemaConverter) list(pointer string, value any) []any {
+	if value == nil {
+		return nil
+	}
+	arr, ok := value.([]any)
+	if !ok {
+		c.fail(pointer, "value that isn't an array")
+	}
+	return arr
+}
+
+// count returns a non-negative integer keyword value
+func (c *schemaConverter) count(pointer, key string, value any) (int, bool) {
+	num, ok := value.(float64)
+	if !ok || num < 0 || num != math.Trunc(num) {
+		c.fail(pointer, key+" that isn't a non-negative integer")
+		return 0, false
+	}
+	return int(num), true
+}
+
+// escapePointer escapes a property name for a JSON pointer
+func escapePointer(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
+
+// Outcomes of a JSON Schema test suite test
+const (
+	JSONSchemaPass = "pass" // mowgli agrees with the expected result
+	JSONSchemaFail = "fail" // mowgli disagrees with the expected result
+	JSONSchemaSkip = "skip" // the schema uses something FromJSONSchema can't convert
+)
+
+// JSONSchemaTestResult is the outcome of one test of the JSON Schema test suite
+type JSONSchemaTestResult struct {
+	File    string // Path of the suite file, e.g. "minLength.json"
+	Group   string // Description of the group of tests sharing a schema
+	Test    string // Description of the test
+	Outcome string // JSONSchemaPass, JSONSchemaFail or JSONSchemaSkip
+	Reason  string // Why the schema was skipped, or the errors reported for a failed test
+}
+
+// jsonSchemaTestGroup is a schema and its tests in the test suite's format
+type jsonSchemaTestGroup struct {
+	Description string          `json:"description"`
+	Schema      json.RawMessage `json:"schema"`
+	Tests       []struct {
+		Description string `json:"description"`
+		Data        any    `json:"data"`
+		Valid       bool   `json:"valid"`
+	} `json:"tests"`
+}
+
+// RunJSONSchemaSuite runs the test files of the json-schema-org test suite
+// (https://github.com/json-schema-org/JSON-Schema-Test-Suite) found under
+// dir, e.g. "tests/draft2020-12" in a checkout, converting each schema with
+// FromJSONSchema. The results show which JSON Schema behaviors mowgli
+// matches; tests of schemas that can't be converted are skipped.
+func RunJSONSchemaSuite(fsys fs.FS, dir string) ([]JSONSchemaTestResult, error) {
+	var results []JSONSchemaTestResult
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != ".json" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		var groups []jsonSchemaTestGroup
+		if err := json.Unmarshal(data, &groups); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+
+		file := name
+		if dir != "." {
+			file = strings.TrimPrefix(name, dir+"/")
+		}
+		for _, group := range groups {
+			spec, convertErr := FromJSONSchema(group.Schema)
+			for _, test := range group.Tests {
+				result := JSONSchemaTestResult{File: file, Group: group.Description, Test: test.Description}
+				switch {
+				case convertErr != nil:
+					result.Outcome, result.Reason = JSONSchemaSkip, convertErr.Error()
+				default:
+					validation := Validate(test.Data, spec)
+					result.Outcome = JSONSchemaPass
+					if validation.Valid != test.Valid {
+						result.Outcome = JSONSchemaFail
+						result.Reason = fmt.Sprintf("expected valid=%v, got %v", test.Valid, validation.Errors)
+					}
+				}
+				results = append(results, result)
+			}
+		}
+		return nil
+	})
+	return results, err
+}
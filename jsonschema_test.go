@@ -0,0 +1,189 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFromJSONSchema(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		want    string // mowgli spec
+		wantErr string
+	}{
+		{
+			name:   "object",
+			schema: `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "User", "type": "object", "properties": {"name": {"type": "string", "minLength": 1}}, "required": ["name"]}`,
+			want:   `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 1, "lengthUnit": "runes"}}}`,
+		},
+		{
+			name:   "default",
+			schema: `{"type": "integer", "default": 8080, "description": "Port"}`,
+			want:   `{"type": "integer", "default": 8080}`,
+		},
+		{
+			name:   "nullable",
+			schema: `{"type": ["null", "integer"], "maximum": 10}`,
+			want:   `{"type": "integer", "nullable": true, "max": 10}`,
+		},
+		{
+			name:   "array",
+			schema: `{"type": "array", "items": {"type": "number"}, "minItems": 1, "uniqueItems": true}`,
+			want:   `{"type": "array", "items": {"type": "number"}, "minLength": 1, "uniqueItems": true}`,
+		},
+		{
+			name:   "keywords of other types are ignored",
+			schema: `{"type": "string", "minimum": 1, "maxLength": 5}`,
+			want:   `{"type": "string", "maxLength": 5, "lengthUnit": "runes"}`,
+		},
+		{
+			name:   "type inferred from keywords",
+			schema: `{"pattern": "^a"}`,
+			want:   `{"type": "string", "pattern": "^a"}`,
+		},
+		{
+			name:   "type inferred from const",
+			schema: `{"const": 3}`,
+			want:   `{"type": "integer", "enum": [3]}`,
+		},
+		{
+			name:   "enum with null",
+			schema: `{"enum": ["a", null]}`,
+			want:   `{"type": "string", "enum": ["a", null], "nullable": true}`,
+		},
+		{
+			name:   "if then else",
+			schema: `{"type": "object", "properties": {"country": {"type": "string"}}, "if": {"properties": {"country": {"const": "US"}}}, "then": {"required": ["zip"]}, "else": {"required": ["postcode"]}}`,
+			want:   `{"type": "object", "properties": {"country": {"type": "string"}}, "if": {"type": "", "properties": {"country": {"type": "string", "enum": ["US"]}}}, "then": {"type": "", "required": ["zip"]}, "else": {"type": "", "required": ["postcode"]}}`,
+		},
+		{
+			name:   "then without if is ignored",
+			schema: `{"type": "integer", "then": {"minimum": 1}}`,
+			want:   `{"type": "integer"}`,
+		},
+		{
+			name:   "if with boolean schema",
+			schema: `{"type": "integer", "if": true, "then": {"minimum": 1}}`,
+			want:   `{"type": "integer", "if": {"type": ""}, "then": {"type": "", "min": 1}}`,
+		},
+		{
+			name:    "unsupported keywords",
+			schema:  `{"type": "object", "properties": {"a": {"oneOf": [], "not": {}}}}`,
+			wantErr: "keyword not (at /properties/a); keyword oneOf (at /properties/a)",
+		},
+		{
+			name:    "additionalProperties false",
+			schema:  `{"type": "object", "additionalProperties": false}`,
+			wantErr: "additionalProperties: false (at /additionalProperties)",
+		},
+		{
+			name:    "boolean schema",
+			schema:  `{"type": "array", "items": true}`,
+			wantErr: "boolean schema (at /items)",
+		},
+		{
+			name:    "several types",
+			schema:  `{"type": ["string", "number"]}`,
+			wantErr: "several types (at /type)",
+		},
+		{
+			name:    "no type",
+			schema:  `{}`,
+			wantErr: "schema without type (at /)",
+		},
+		{
+			name:    "unknown format",
+			schema:  `{"type": "string", "format": "unheard-of"}`,
+			wantErr: "format unheard-of (at /format)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := FromJSONSchema([]byte(tt.schema))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromJSONSchema failed: %v", err)
+			}
+			want, err := ParseSpecString(tt.want)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			got, _ := json.Marshal(spec)
+			expected, _ := json.Marshal(want)
+			if string(got) != string(expected) {
+				t.Errorf("expected %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaSuite(t *testing.T) {
+	// Groups whose tests don't all pass; every other test must
+	differences := map[string]string{
+		"minLength without type ignores non-strings":                       JSONSchemaFail,
+		"additionalProperties being false does not allow other properties": JSONSchemaSkip,
+		"type as array of several types":                                   JSONSchemaSkip,
+		"exclusiveMinimum validation":                                      JSONSchemaSkip,
+		"prefixItems validation":                                           JSONSchemaSkip,
+		"allOf is not supported":                                           JSONSchemaSkip,
+	}
+
+	results, err := RunJSONSchemaSuite(os.DirFS("testdata"), "jsonschema")
+	if err != nil {
+		t.Fatalf("RunJSONSchemaSuite failed: %v", err)
+	}
+	if len(results) == 0 {
+		t.Fatal("expected results")
+	}
+
+	failed := map[string]bool{}
+	for _, result := range results {
+		want, ok := differences[result.Group]
+		if !ok {
+			want = JSONSchemaPass
+		}
+		if result.Outcome == JSONSchemaFail {
+			failed[result.Group] = true
+		}
+		if result.Outcome != want && !(want == JSONSchemaFail && result.Outcome == JSONSchemaPass) {
+			t.Errorf("%s: %s: %s: expected %s, got %s %s", result.File, result.Group, result.Test, want, result.Outcome, result.Reason)
+		}
+	}
+	for group, outcome := range differences {
+		if outcome == JSONSchemaFail && !failed[group] {
+			t.Errorf("%s: expected a failing test", group)
+		}
+	}
+}
+
+// TestOfficialJSONSchemaSuite reports the results of a checkout of the
+// json-schema-org test suite, e.g.
+// JSON_SCHEMA_TEST_SUITE=../JSON-Schema-Test-Suite/tests/draft2020-12
+func TestOfficialJSONSchemaSuite(t *testing.T) {
+	dir := os.Getenv("JSON_SCHEMA_TEST_SUITE")
+	if dir == "" {
+		t.Skip("JSON_SCHEMA_TEST_SUITE is not set")
+	}
+
+	results, err := RunJSONSchemaSuite(os.DirFS(dir), ".")
+	if err != nil {
+		t.Fatalf("RunJSONSchemaSuite failed: %v", err)
+	}
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Outcome]++
+		if result.Outcome == JSONSchemaFail {
+			t.Logf("%s: %s: %s: %s", result.File, result.Group, result.Test, result.Reason)
+		}
+	}
+	t.Logf("%d passed, %d failed, %d skipped", counts[JSONSchemaPass], counts[JSONSchemaFail], counts[JSONSchemaSkip])
+}
@@ -0,0 +1,173 @@
+// Package kafkaval validates Kafka message payloads against mowgli specs,
+// checking JSON values before they are produced and after they are
+// consumed.
+//
+// Topics maps each topic to a spec in a mowgli.Registry. A Serde for a topic
+// marshals and validates values for producing and validates and unmarshals
+// consumed payloads. Its Encode and Decode methods have the shape of
+// franz-go's sr.Serde, for kgo.Record values, and Encoder returns a
+// sarama.Encoder for sarama.ProducerMessage values. The package doesn't
+// import either client.
+package kafkaval
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/matjam/mowgli"
+)
+
+// ErrUnknownTopic is returned for messages of a topic without a spec, unless
+// unknown topics are allowed
+var ErrUnknownTopic = errors.New("no spec for topic")
+
+// MessageError is returned for a payload that doesn't satisfy its topic's
+// spec
+type MessageError struct {
+	Topic  string
+	Ref    string // Registry ref of the topic's spec
+	Errors []*mowgli.ValidationError
+}
+
+func (e *MessageError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("invalid message for topic %s: %s", e.Topic, strings.Join(messages, "; "))
+}
+
+// Option configures Topics
+type Option func(*Topics)
+
+// AllowUnknownTopics passes messages of topics without a spec through
+// unvalidated instead of rejecting them with ErrUnknownTopic
+func AllowUnknownTopics() Option {
+	return func(t *Topics) {
+		t.allowUnknown = true
+	}
+}
+
+// Topics maps topics to the specs of their messages. It is safe for
+// concurrent use.
+type Topics struct {
+	registry     *mowgli.Registry
+	allowUnknown bool
+
+	mu   sync.RWMutex
+	refs map[string]string // topic -> registry ref
+}
+
+// NewTopics returns Topics that look up specs in reg
+func NewTopics(reg *mowgli.Registry, opts ...Option) *Topics {
+	t := &Topics{registry: reg, refs: make(map[string]string)}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Set validates messages of topic against the spec registered as ref, either
+// "name@version" or a bare name for the latest version at validation time
+func (t *Topics) Set(topic, ref string) error {
+	if _, err := t.registry.Lookup(ref); err != nil {
+		return fmt.Errorf("topic %s: %w", topic, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refs[topic] = ref
+	return nil
+}
+
+// ref returns the registry ref of topic's spec
+func (t *Topics) ref(topic string) (string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	ref, ok := t.refs[topic]
+	return ref, ok
+}
+
+// Validate validates a JSON payload of topic. It returns a *MessageError if
+// the payload is invalid, and ErrUnknownTopic if the topic has no spec.
+func (t *Topics) Validate(topic string, payload []byte) error {
+	ref, ok := t.ref(topic)
+	if !ok {
+		if t.allowUnknown {
+			return nil
+		}
+		return fmt.Errorf("%w %s", ErrUnknownTopic, topic)
+	}
+
+	result, err := t.registry.ValidateJSON(payload, ref)
+	if err != nil {
+		return fmt.Errorf("topic %s: %w", topic, err)
+	}
+	if !result.Valid {
+		return &MessageError{Topic: topic, Ref: ref, Errors: result.Errors}
+	}
+	return nil
+}
+
+// Serde returns the serializer and deserializer of topic's messages
+func (t *Topics) Serde(topic string) *Serde {
+	return &Serde{topics: t, topic: topic}
+}
+
+// Serde marshals and unmarshals the JSON messages of one topic, validating
+// them on the way
+type Serde struct {
+	topics *Topics
+	topic  string
+}
+
+// Encode marshals v to JSON and validates it, e.g. for kgo.Record.Value
+func (s *Serde) Encode(v any) ([]byte, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("topic %s: %w", s.topic, err)
+	}
+	if err := s.topics.Validate(s.topic, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// Decode validates a consumed payload and unmarshals it into v. v is left
+// unchanged if the payload is invalid.
+func (s *Serde) Decode(payload []byte, v any) error {
+	if err := s.topics.Validate(s.topic, payload); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("topic %s: %w", s.topic, err)
+	}
+	return nil
+}
+
+// Encoder marshals and validates v up front and returns it as a
+// sarama.Encoder, e.g. for sarama.ProducerMessage.Value
+func (s *Serde) Encoder(v any) (*Encoder, error) {
+	payload, err := s.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{payload: payload}, nil
+}
+
+// Encoder is a validated payload. It implements sarama.Encoder.
+type Encoder struct {
+	payload []byte
+}
+
+// Encode returns the payload
+func (e *Encoder) Encode() ([]byte, error) {
+	return e.payload, nil
+}
+
+// Length returns the length of the payload
+func (e *Encoder) Length() int {
+	return len(e.payload)
+}
@@ -0,0 +1,131 @@
+package kafkaval
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matjam/mowgli"
+)
+
+type order struct {
+	ID       string  `json:"id"`
+	Quantity int     `json:"quantity"`
+	Price    float64 `json:"price,omitempty"`
+}
+
+func newTopics(t *testing.T, opts ...Option) *Topics {
+	t.Helper()
+	spec, err := mowgli.ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "minLength": 1},
+			"quantity": {"type": "integer", "min": 1},
+			"price": {"type": "number"}
+		},
+		"required": ["id", "quantity"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	reg := mowgli.NewRegistry()
+	if err := reg.Register("order@1", spec); err != nil {
+		t.Fatal(err)
+	}
+	topics := NewTopics(reg, opts...)
+	if err := topics.Set("orders", "order"); err != nil {
+		t.Fatal(err)
+	}
+	return topics
+}
+
+func TestValidate(t *testing.T) {
+	topics := newTopics(t)
+
+	tests := []struct {
+		name    string
+		topic   string
+		payload string
+		code    string // code of the first error, "" if valid
+		unknown bool
+	}{
+		{name: "valid", topic: "orders", payload: `{"id": "o1", "quantity": 2}`},
+		{name: "invalid", topic: "orders", payload: `{"id": "o1", "quantity": 0}`, code: "min"},
+		{name: "missing field", topic: "orders", payload: `{"quantity": 1}`, code: "required"},
+		{name: "unknown topic", topic: "payments", payload: `{}`, unknown: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := topics.Validate(tt.topic, []byte(tt.payload))
+			if tt.unknown {
+				if !errors.Is(err, ErrUnknownTopic) {
+					t.Errorf("expected ErrUnknownTopic, got %v", err)
+				}
+				return
+			}
+
+			var msgErr *MessageError
+			switch {
+			case tt.code == "" && err != nil:
+				t.Errorf("unexpected error %v", err)
+			case tt.code != "" && !errors.As(err, &msgErr):
+				t.Errorf("expected a MessageError, got %v", err)
+			case tt.code != "" && (msgErr.Topic != tt.topic || msgErr.Ref != "order" || msgErr.Errors[0].Code != tt.code):
+				t.Errorf("unexpected error %+v", msgErr)
+			}
+		})
+	}
+
+	if err := topics.Validate("orders", []byte(`{`)); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
+
+func TestAllowUnknownTopics(t *testing.T) {
+	topics := newTopics(t, AllowUnknownTopics())
+	if err := topics.Validate("payments", []byte(`"anything"`)); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := topics.Set("payments", "payment"); err == nil {
+		t.Error("expected an error for an unregistered spec")
+	}
+}
+
+func TestSerde(t *testing.T) {
+	serde := newTopics(t).Serde("orders")
+
+	payload, err := serde.Encode(order{ID: "o1", Quantity: 3})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	var got order
+	if err := serde.Decode(payload, &got); err != nil || got.ID != "o1" || got.Quantity != 3 {
+		t.Errorf("expected the order back, got %+v, %v", got, err)
+	}
+
+	if _, err := serde.Encode(order{ID: "o1"}); err == nil {
+		t.Error("expected produce of an invalid order to fail")
+	}
+
+	got = order{}
+	if err := serde.Decode([]byte(`{"id": "", "quantity": 1}`), &got); err == nil || got.Quantity != 0 {
+		t.Errorf("expected an invalid payload to be rejected without decoding, got %+v, %v", got, err)
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	serde := newTopics(t).Serde("orders")
+
+	enc, err := serde.Encoder(order{ID: "o1", Quantity: 1, Price: 9.5})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	payload, err := enc.Encode()
+	if err != nil || string(payload) != `{"id":"o1","quantity":1,"price":9.5}` || enc.Length() != len(payload) {
+		t.Errorf("unexpected payload %s, %v", payload, err)
+	}
+
+	if _, err := serde.Encoder(order{Quantity: 1}); err == nil {
+		t.Error("expected an error for an invalid order")
+	}
+}
@@ -0,0 +1,44 @@
+package mowgli
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// Units for the minLength and maxLength of strings, set per spec with
+// "lengthUnit" or per Validator with WithLengthUnit
+const (
+	LengthBytes     = "bytes"     // UTF-8 bytes, e.g. for database columns sized in bytes
+	LengthRunes     = "runes"     // Unicode code points (the default)
+	LengthGraphemes = "graphemes" // User-perceived characters, e.g. "👍🏽" is one
+)
+
+// WithLengthUnit sets the unit string lengths are measured in for specs that
+// don't set "lengthUnit". The default is LengthRunes.
+func WithLengthUnit(unit string) Option {
+	return func(v *Validator) {
+		v.lengthUnit = unit
+	}
+}
+
+// stringLength measures str in unit, "" meaning runes
+func stringLength(str, unit string) (int, error) {
+	switch unit {
+	case LengthRunes, "":
+		return utf8.RuneCountInString(str), nil
+	case LengthBytes:
+		return len(str), nil
+	case LengthGraphemes:
+		return uniseg.GraphemeClusterCount(str), nil
+	default:
+		return 0, fmt.Errorf("unknown length unit: %s", unit)
+	}
+}
+
+// checkLengthUnit reports an unknown length unit
+func checkLengthUnit(unit string) error {
+	_, err := stringLength("", unit)
+	return err
+}
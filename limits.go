@@ -0,0 +1,68 @@
+package mowgli
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDepthExceeded is returned when a document nests deeper than
+// Limits.MaxDepth
+var ErrDepthExceeded = errors.New("maximum nesting depth exceeded")
+
+// ErrNodeLimitExceeded is returned when a document has more values than
+// Limits.MaxNodes
+var ErrNodeLimitExceeded = errors.New("maximum node count exceeded")
+
+// Limits bounds the work done validating a document, for documents from
+// untrusted sources. Zero values mean no limit.
+type Limits struct {
+	MaxDepth int // Maximum nesting depth of validated values; the root is depth 1
+	MaxNodes int // Maximum number of values validated
+}
+
+// WithLimits stops validation of documents that exceed limits, so deeply
+// nested or very large input can't exhaust the stack or CPU. A document
+// that contains itself, such as a map stored in itself, exceeds any
+// MaxDepth.
+func WithLimits(limits Limits) Option {
+	return func(v *Validator) {
+		v.limits = limits
+	}
+}
+
+// LimitError returns why validation stopped early, wrapping
+// ErrDepthExceeded or ErrNodeLimitExceeded, or nil if it ran to completion.
+// The result is invalid when it is non-nil.
+func (r *ValidationResult) LimitError() error {
+	return r.limitErr
+}
+
+// enter accounts for validating a value at path, returning false if a
+// limit is exceeded, in which case validation stops
+func (r *ValidationResult) enter(path string) bool {
+	if r.limitErr != nil {
+		return false
+	}
+
+	var err error
+	switch {
+	case r.limits.MaxDepth > 0 && r.depth >= r.limits.MaxDepth:
+		err = fmt.Errorf("%w: %s is deeper than %d levels", ErrDepthExceeded, displayPath(path), r.limits.MaxDepth)
+	case r.limits.MaxNodes > 0 && r.nodes >= r.limits.MaxNodes:
+		err = fmt.Errorf("%w: document has more than %d values", ErrNodeLimitExceeded, r.limits.MaxNodes)
+	}
+	if err != nil {
+		r.limitErr = err
+		r.addError(path, CodeLimitExceeded, err.Error(),
+			map[string]any{"maxDepth": r.limits.MaxDepth, "maxNodes": r.limits.MaxNodes})
+		return false
+	}
+
+	r.depth++
+	r.nodes++
+	return true
+}
+
+func (r *ValidationResult) leave() {
+	r.depth--
+}
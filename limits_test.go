@@ -0,0 +1,85 @@
+package mowgli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// nestedJSON returns depth levels of {"child": ...} around an empty object
+func nestedJSON(depth int) string {
+	return strings.Repeat(`{"child": `, depth) + `{}` + strings.Repeat(`}`, depth)
+}
+
+func TestValidateLimits(t *testing.T) {
+	// A recursive spec, so every level of nesting is validated
+	reg := NewRegistry()
+	if err := reg.Register("node@1", &Spec{Type: "object", AdditionalProperties: &Spec{Ref: "node"}}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	spec := &Spec{Ref: "node"}
+
+	tests := []struct {
+		name    string
+		limits  Limits
+		json    string
+		wantErr error
+	}{
+		{name: "no limits", json: nestedJSON(50)},
+		{name: "within depth", limits: Limits{MaxDepth: 10}, json: nestedJSON(9)},
+		{name: "too deep", limits: Limits{MaxDepth: 10}, json: nestedJSON(10), wantErr: ErrDepthExceeded},
+		{name: "within node count", limits: Limits{MaxNodes: 4}, json: `{"a": {}, "b": {}, "c": {}}`},
+		{name: "too many nodes", limits: Limits{MaxNodes: 4}, json: `{"a": {}, "b": {}, "c": {}, "d": {}}`, wantErr: ErrNodeLimitExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewValidator(WithRegistry(reg), WithLimits(tt.limits))
+			result, err := v.ValidateJSON([]byte(tt.json), spec)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && !result.Valid {
+				t.Errorf("expected valid, got %v", result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateLimitsResult(t *testing.T) {
+	data := map[string]any{"child": map[string]any{"child": map[string]any{}}}
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{
+		"child": {Type: "object", Properties: map[string]*Spec{"child": {Type: "object"}}},
+	}}
+
+	result := NewValidator(WithLimits(Limits{MaxDepth: 2})).Validate(data, spec)
+	if result.Valid || !errors.Is(result.LimitError(), ErrDepthExceeded) {
+		t.Fatalf("expected depth error, got %v", result.LimitError())
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Path != "child.child" || result.Errors[0].Code != CodeLimitExceeded {
+		t.Errorf("unexpected errors: %v", result.Errors)
+	}
+}
+
+func TestValidateLimitsCycles(t *testing.T) {
+	// A document containing itself is stopped by the depth limit
+	cyclic := map[string]any{}
+	cyclic["self"] = cyclic
+	spec := &Spec{Type: "object", AdditionalProperties: &Spec{Type: "object", AdditionalProperties: &Spec{Type: "object"}}}
+	spec.AdditionalProperties.AdditionalProperties = spec
+
+	result := NewValidator(WithLimits(Limits{MaxDepth: 100})).Validate(cyclic, spec)
+	if !errors.Is(result.LimitError(), ErrDepthExceeded) {
+		t.Errorf("expected depth error for a cyclic document, got %v", result.LimitError())
+	}
+
+	// ValidateValue rejects cyclic Go values before validating
+	type node struct {
+		Next *node `json:"next"`
+	}
+	n := &node{}
+	n.Next = n
+	if _, err := ValidateValue(n, &Spec{Type: "object"}); err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package mowgli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LocalizedError is a validation error rendered for a specific locale
+type LocalizedError struct {
+	Path    string `json:"path"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// messageCatalog holds message templates keyed by locale and then by error code
+var messageCatalog = struct {
+	sync.RWMutex
+	locales map[string]map[string]string
+}{locales: make(map[string]map[string]string)}
+
+// RegisterMessages adds message templates for a locale to the catalog.
+// Templates are keyed by error code and may reference error params with
+// {name} placeholders, e.g. "mindestens {limit} Zeichen" for CodeMinLength.
+// Registering the same locale again adds to (and overrides) its templates.
+func RegisterMessages(locale string, messages map[string]string) {
+	messageCatalog.Lock()
+	defer messageCatalog.Unlock()
+
+	locale = normalizeLocale(locale)
+	existing, ok := messageCatalog.locales[locale]
+	if !ok {
+		existing = make(map[string]string, len(messages))
+		messageCatalog.locales[locale] = existing
+	}
+	for code, template := range messages {
+		existing[code] = template
+	}
+}
+
+// Localize renders the result's errors in each of the given locales.
+// The catalog is read once for all locales, so the snapshot is consistent even
+// if messages are registered concurrently. A locale such as "pt-BR" falls back
+// to "pt" and then to the error's default message. Custom messages from a
+// spec's "messages" take precedence over the catalog: specs hold one message
+// per code, so errors with one keep it in every locale.
+func (r *ValidationResult) Localize(locales ...string) map[string][]LocalizedError {
+	return localize(r.Errors, locales)
+}
+
+// LocalizeWarnings renders the result's warnings, such as deprecated fields
+// being present, in each of the given locales like Localize
+func (r *ValidationResult) LocalizeWarnings(locales ...string) map[string][]LocalizedError {
+	return localize(r.Warnings, locales)
+}
+
+// localize renders errs in each of locales, see Localize
+func localize(errs []*ValidationError, locales []string) map[string][]LocalizedError {
+	localized := make(map[string][]LocalizedError, len(locales))
+
+	messageCatalog.RLock()
+	defer messageCatalog.RUnlock()
+
+	for _, locale := range locales {
+		chain := localeChain(normalizeLocale(locale))
+		rendered := make([]LocalizedError, len(errs))
+		for i, err := range errs {
+			rendered[i] = LocalizedError{
+				Path:    err.Path,
+				Code:    err.Code,
+				Message: lookupMessage(chain, err),
+			}
+		}
+		localized[locale] = rendered
+	}
+
+	return localized
+}
+
+// lookupMessage finds the most specific template for err along the locale
+// chain, unless err has a custom message. The caller must hold the catalog
+// read lock.
+func lookupMessage(chain []string, err *ValidationError) string {
+	if err.custom {
+		return err.Message
+	}
+	for _, locale := range chain {
+		if template, ok := messageCatalog.locales[locale][err.Code]; ok {
+			return formatMessage(template, err.Params)
+		}
+	}
+	return err.Message
+}
+
+// formatMessage substitutes {name} placeholders in template with params
+func formatMessage(template string, params map[string]any) string {
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// normalizeLocale lower-cases a locale tag and uses "-" as the separator
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
+
+// localeChain returns the locale followed by its less specific parents,
+// e.g. "zh-hant-tw" -> ["zh-hant-tw", "zh-hant", "zh"]
+func localeChain(locale string) []string {
+	chain := []string{locale}
+	for {
+		idx := strings.LastIndex(locale, "-")
+		if idx == -1 {
+			return chain
+		}
+		locale = locale[:idx]
+		chain = append(chain, locale)
+	}
+}
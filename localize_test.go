@@ -0,0 +1,164 @@
+package mowgli
+
+import (
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	RegisterMessages("de", map[string]string{
+		CodeRequired:  "Pflichtfeld fehlt",
+		CodeMinLength: "mindestens {limit} Zeichen erforderlich (aktuell {actual})",
+	})
+	RegisterMessages("pt", map[string]string{
+		CodeRequired: "campo obrigatório ausente",
+	})
+	RegisterMessages("pt_BR", map[string]string{
+		CodeMinLength: "mínimo de {limit} caracteres",
+	})
+
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 3},
+			"age": {"type": "integer", "max": 150}
+		},
+		"required": ["email"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"name": "ab", "age": 200}, spec)
+	if result.Valid {
+		t.Fatal("Expected validation to fail, but it passed")
+	}
+
+	localized := result.Localize("de", "pt-BR", "en")
+	if len(localized) != 3 {
+		t.Fatalf("expected 3 locales, got %d", len(localized))
+	}
+
+	tests := []struct {
+		locale string
+		path   string
+		want   string
+	}{
+		{locale: "de", path: "email", want: "Pflichtfeld fehlt"},
+		{locale: "de", path: "name", want: "mindestens 3 Zeichen erforderlich (aktuell 2)"},
+		{locale: "de", path: "age", want: "integer 200 is greater than maximum 150"},
+		{locale: "pt-BR", path: "email", want: "campo obrigatório ausente"},
+		{locale: "pt-BR", path: "name", want: "mínimo de 3 caracteres"},
+		{locale: "en", path: "name", want: "string length 2 is less than minimum 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.path, func(t *testing.T) {
+			for _, err := range localized[tt.locale] {
+				if err.Path != tt.path {
+					continue
+				}
+				if err.Message != tt.want {
+					t.Errorf("expected %q, got %q", tt.want, err.Message)
+				}
+				return
+			}
+			t.Errorf("no error for path %s in locale %s", tt.path, tt.locale)
+		})
+	}
+}
+
+func TestValidationErrorCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		specJSON string
+		value    any
+		code     string
+	}{
+		{name: "type", specJSON: `{"type": "string"}`, value: 1, code: CodeType},
+		{name: "minLength", specJSON: `{"type": "string", "minLength": 2}`, value: "a", code: CodeMinLength},
+		{name: "maxLength", specJSON: `{"type": "array", "maxLength": 1}`, value: []any{1, 2}, code: CodeMaxLength},
+		{name: "pattern", specJSON: `{"type": "string", "pattern": "^a$"}`, value: "b", code: CodePattern},
+		{name: "min", specJSON: `{"type": "number", "min": 1}`, value: 0.5, code: CodeMin},
+		{name: "max", specJSON: `{"type": "integer", "max": 1}`, value: 2, code: CodeMax},
+		{name: "enum", specJSON: `{"type": "string", "enum": ["a"]}`, value: "b", code: CodeEnum},
+		{name: "required", specJSON: `{"type": "object", "required": ["a"]}`, value: map[string]any{}, code: CodeRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.specJSON)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+
+			result := Validate(tt.value, spec)
+			if len(result.Errors) != 1 {
+				t.Fatalf("expected 1 error, got %v", result.Errors)
+			}
+			if result.Errors[0].Code != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, result.Errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestLocalizeWarningsAndSpecMessages(t *testing.T) {
+	RegisterMessages("fr", map[string]string{
+		CodeRequired:   "champ obligatoire manquant",
+		CodeMinLength:  "au moins {limit} caractères",
+		CodeDeprecated: "champ obsolète",
+	})
+
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"password": {"type": "string", "minLength": 8, "messages": {"minLength": "Choose a longer password", "required": "Choose a password"}},
+			"nickname": {"type": "string", "minLength": 3},
+			"fax": {"type": "string", "deprecated": true},
+			"pager": {"type": "string", "deprecated": true, "messages": {"deprecated": "Pagers are gone"}}
+		},
+		"required": ["password", "email"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"nickname": "ab", "fax": "1", "pager": "2"}, spec)
+	wantErrors := map[string]string{
+		"password": "Choose a password",
+		"email":    "champ obligatoire manquant",
+		"nickname": "au moins 3 caractères",
+	}
+	errs := result.Localize("fr")["fr"]
+	if len(errs) != len(wantErrors) {
+		t.Fatalf("expected %d errors, got %v", len(wantErrors), errs)
+	}
+	for _, err := range errs {
+		if want := wantErrors[err.Path]; err.Message != want {
+			t.Errorf("%s: expected %q, got %q", err.Path, want, err.Message)
+		}
+	}
+
+	result = Validate(map[string]any{"password": "short", "fax": "1", "pager": "2"}, spec)
+	for _, err := range result.Localize("fr")["fr"] {
+		if err.Path == "password" && err.Message != "Choose a longer password" {
+			t.Errorf("expected the spec's message to be kept, got %q", err.Message)
+		}
+	}
+
+	wantWarnings := map[string]string{"fax": "champ obsolète", "pager": "Pagers are gone"}
+	warnings := result.LocalizeWarnings("fr", "en")
+	if len(warnings["fr"]) != len(wantWarnings) {
+		t.Fatalf("expected %d warnings, got %v", len(wantWarnings), warnings["fr"])
+	}
+	for _, warning := range warnings["fr"] {
+		if want := wantWarnings[warning.Path]; warning.Message != want {
+			t.Errorf("%s: expected %q, got %q", warning.Path, want, warning.Message)
+		}
+	}
+	for _, warning := range warnings["en"] {
+		if warning.Path == "fax" && warning.Message != "field is deprecated" {
+			t.Errorf("expected the default message in English, got %q", warning.Message)
+		}
+	}
+}
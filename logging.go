@@ -0,0 +1,73 @@
+package mowgli
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// LogLevels are the levels a Validator created WithLogger logs at. Nil
+// levels use the defaults.
+type LogLevels struct {
+	InvalidSpec slog.Leveler // Problems with the spec found during validation, e.g. an invalid pattern or unresolvable $ref (default error)
+	Condition   slog.Leveler // Condition and requiredIf expressions that failed to evaluate (default warn)
+	Slow        slog.Leveler // Validations slower than the threshold set WithSlowThreshold (default warn)
+}
+
+// WithLogger logs problems that validation results only report as errors:
+// invalid specs, condition expressions that fail to evaluate, with the path
+// of the object they were evaluated for, and validations slower than the
+// threshold set WithSlowThreshold
+func WithLogger(logger *slog.Logger) Option {
+	return func(v *Validator) {
+		v.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels a Validator created WithLogger logs at
+func WithLogLevels(levels LogLevels) Option {
+	return func(v *Validator) {
+		v.logLevels = levels
+	}
+}
+
+// WithSlowThreshold logs validations that take longer than threshold, if
+// the Validator was created WithLogger
+func WithSlowThreshold(threshold time.Duration) Option {
+	return func(v *Validator) {
+		v.slowThreshold = threshold
+	}
+}
+
+// log logs msg at level, or at def if level is nil, if the result has a
+// logger
+func (r *ValidationResult) log(level slog.Leveler, def slog.Level, msg string, args ...any) {
+	if r.logger == nil {
+		return
+	}
+	if level != nil {
+		def = level.Level()
+	}
+	r.logger.Log(context.Background(), def, msg, args...)
+}
+
+// logInvalidSpec logs a problem with the spec of the value at path
+func (r *ValidationResult) logInvalidSpec(path, message string) {
+	r.log(r.logLevels.InvalidSpec, slog.LevelError, "invalid spec", "path", path, "error", message)
+}
+
+// logCondition logs an expression that failed to evaluate for the object at
+// path
+func (r *ValidationResult) logCondition(path, expr string, err error) {
+	r.log(r.logLevels.Condition, slog.LevelWarn, "condition evaluation failed",
+		"path", path, "condition", expr, "error", err)
+}
+
+// logSlow logs the validation against the spec named name if it took longer
+// than the slow threshold
+func (r *ValidationResult) logSlow(name string, duration time.Duration) {
+	if r.slowThreshold > 0 && duration > r.slowThreshold {
+		r.log(r.logLevels.Slow, slog.LevelWarn, "slow validation",
+			"spec", name, "duration", duration, "errors", len(r.Errors))
+	}
+}
@@ -0,0 +1,107 @@
+package mowgli
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestLogger returns a logger writing text records without times to buf
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "duration" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestWithLogger(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"address": {
+				"type": "object",
+				"properties": {"zip": {"type": "string", "pattern": "["}},
+				"requiredIf": {"zip": "country =="}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		levels LogLevels
+		want   []string
+	}{
+		{
+			name: "default levels",
+			want: []string{
+				`level=WARN msg="condition evaluation failed" path=address.zip condition="country =="`,
+				`level=ERROR msg="invalid spec" path=address.zip error="invalid pattern:`,
+			},
+		},
+		{
+			name:   "custom levels",
+			levels: LogLevels{InvalidSpec: slog.LevelWarn, Condition: slog.LevelDebug},
+			want: []string{
+				`level=DEBUG msg="condition evaluation failed" path=address.zip`,
+				`level=WARN msg="invalid spec" path=address.zip`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			v := NewValidator(WithLogger(newTestLogger(&buf)), WithLogLevels(tt.levels))
+			v.Validate(map[string]any{"address": map[string]any{"zip": "12345"}}, spec)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d records, got %q", len(tt.want), lines)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("expected record %d to start with %q, got %q", i, want, lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithSlowThreshold(t *testing.T) {
+	spec := &Spec{Ref: "slow@1"}
+	reg := NewRegistry()
+	if err := reg.Register("slow@1", &Spec{Type: "string"}); err != nil {
+		t.Fatalf("Failed to register spec: %v", err)
+	}
+
+	var buf bytes.Buffer
+	v := NewValidator(WithRegistry(reg), WithLogger(newTestLogger(&buf)), WithSlowThreshold(time.Nanosecond))
+	v.Validate(1, spec)
+	if want := `level=WARN msg="slow validation" spec=slow@1 errors=1`; strings.TrimSpace(buf.String()) != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	buf.Reset()
+	v = NewValidator(WithRegistry(reg), WithLogger(newTestLogger(&buf)), WithSlowThreshold(time.Hour))
+	v.Validate(1, spec)
+	if buf.Len() != 0 {
+		t.Errorf("expected no records, got %q", buf.String())
+	}
+}
+
+func TestWithoutLogger(t *testing.T) {
+	v := NewValidator(WithSlowThreshold(time.Nanosecond))
+	if result := v.Validate("x", &Spec{Type: "string", Pattern: stringPtr("[")}); result.Valid {
+		t.Error("expected the invalid pattern to be reported")
+	}
+}
@@ -0,0 +1,69 @@
+package mowgli
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruCache is a cache of at most max entries, evicting the least recently
+// used entry when it is full. An lruCache is safe for concurrent use.
+type lruCache[K comparable, V any] struct {
+	max int
+
+	mu      sync.Mutex
+	entries map[K]*list.Element
+	recency *list.List // Of *lruEntry[K, V], most recently used first
+}
+
+// lruEntry is a cached value
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// newLRUCache creates a cache holding at most max entries
+func newLRUCache[K comparable, V any](max int) *lruCache[K, V] {
+	return &lruCache[K, V]{
+		max:     max,
+		entries: make(map[K]*list.Element),
+		recency: list.New(),
+	}
+}
+
+// get returns the cached value for key, marking it recently used
+func (c *lruCache[K, V]) get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.recency.MoveToFront(elem)
+	return elem.Value.(*lruEntry[K, V]).value, true
+}
+
+// add caches value for key, evicting the least recently used value if the
+// cache is full
+func (c *lruCache[K, V]) add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry[K, V]).value = value
+		c.recency.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.recency.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.recency.Len() > c.max {
+		oldest := c.recency.Back()
+		c.recency.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+}
+
+// len returns the number of cached values
+func (c *lruCache[K, V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,58 @@
+package mowgli
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := newLRUCache[string, int](2)
+	cache.add("a", 1)
+	cache.add("b", 2)
+	if v, ok := cache.get("a"); !ok || v != 1 {
+		t.Fatalf("expected a=1, got %v %v", v, ok)
+	}
+
+	// "b" is now the least recently used
+	cache.add("c", 3)
+	if _, ok := cache.get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := cache.get(key); !ok || v != want {
+			t.Errorf("expected %s=%d, got %v %v", key, want, v, ok)
+		}
+	}
+
+	cache.add("a", 10)
+	if v, _ := cache.get("a"); v != 10 || cache.len() != 2 {
+		t.Errorf("expected a to be replaced in place, got %v with %d entries", v, cache.len())
+	}
+}
+
+func TestPatternCacheBounded(t *testing.T) {
+	spec := &Spec{
+		Type:       "object",
+		Conditions: []Condition{{If: "matches(name, pattern)", Then: map[string]*Spec{"name": {MinLength: intPtr(1)}}}},
+	}
+	for i := range patternCacheSize + 100 {
+		doc := map[string]any{"name": "a", "pattern": fmt.Sprintf("^a%d?$", i)}
+		if result := Validate(doc, spec); !result.Valid {
+			t.Fatalf("unexpected errors: %v", result.Errors)
+		}
+	}
+	if n := patternCache.len(); n > patternCacheSize {
+		t.Errorf("expected at most %d cached patterns, got %d", patternCacheSize, n)
+	}
+}
+
+func TestProgramCacheBounded(t *testing.T) {
+	for i := range programCacheSize + 100 {
+		if _, err := evalExpression(fmt.Sprintf("a > %d", i), map[string]any{"a": 1}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := programCache.len(); n > programCacheSize {
+		t.Errorf("expected at most %d cached programs, got %d", programCacheSize, n)
+	}
+}
@@ -0,0 +1,32 @@
+package mowgli
+
+// MapSpec returns the spec of a dictionary whose keys satisfy keySpec and
+// whose values satisfy valueSpec, either of which may be nil to accept any.
+// It is {"type": "object", "propertyNames": keySpec, "additionalProperties":
+// valueSpec}, so it can be nested in other specs. A keySpec without a type
+// is taken to be a string spec.
+func MapSpec(keySpec, valueSpec *Spec) *Spec {
+	if keySpec != nil && keySpec.Type == "" {
+		copied := *keySpec
+		copied.Type = "string"
+		keySpec = &copied
+	}
+	return &Spec{Type: "object", PropertyNames: keySpec, AdditionalProperties: valueSpec}
+}
+
+// ValidateMapValues validates a dictionary such as id -> object without
+// enumerating its keys. See Validator.ValidateMapValues.
+func ValidateMapValues(data map[string]any, keySpec, valueSpec *Spec) *ValidationResult {
+	return defaultValidator.ValidateMapValues(data, keySpec, valueSpec)
+}
+
+// ValidateMapValues validates every key of data against keySpec and every
+// value against valueSpec, as Validate does with MapSpec(keySpec,
+// valueSpec). Errors for a value have the key as their path; errors for a
+// key have the key as their path too, with the key spec's error codes.
+func (v *Validator) ValidateMapValues(data map[string]any, keySpec, valueSpec *Spec) *ValidationResult {
+	if data == nil {
+		data = map[string]any{}
+	}
+	return v.Validate(data, MapSpec(keySpec, valueSpec))
+}
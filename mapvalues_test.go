@@ -0,0 +1,58 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateMapValues(t *testing.T) {
+	keySpec := &Spec{Pattern: stringPtr("^u[0-9]+$")}
+	valueSpec, err := ParseSpecString(`{"type": "object", "properties": {"name": {"type": "string", "minLength": 1}}, "required": ["name"]}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data map[string]any
+		want []string // "path: code"
+	}{
+		{name: "valid", data: map[string]any{"u1": map[string]any{"name": "Ada"}, "u2": map[string]any{"name": "Grace"}}},
+		{name: "empty", data: map[string]any{}},
+		{name: "nil", data: nil},
+		{name: "invalid value", data: map[string]any{"u1": map[string]any{"name": ""}}, want: []string{"u1.name: minLength"}},
+		{name: "missing field", data: map[string]any{"u1": map[string]any{}}, want: []string{"u1.name: required"}},
+		{name: "invalid key", data: map[string]any{"admin": map[string]any{"name": "root"}}, want: []string{"admin: pattern"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateMapValues(tt.data, keySpec, valueSpec)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+": "+err.Code)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestMapSpec(t *testing.T) {
+	keySpec := &Spec{MaxLength: intPtr(3)}
+	spec := MapSpec(keySpec, &Spec{Type: "integer"})
+	if spec.Type != "object" || spec.PropertyNames.Type != "string" || spec.AdditionalProperties.Type != "integer" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+	if keySpec.Type != "" {
+		t.Error("expected key spec to be left unchanged")
+	}
+
+	// Nested in a document, with any key
+	doc := &Spec{Type: "object", Properties: map[string]*Spec{"scores": MapSpec(nil, &Spec{Type: "integer"})}}
+	result := Validate(map[string]any{"scores": map[string]any{"ada": 3, "grace": "x"}}, doc)
+	if len(result.Errors) != 1 || result.Errors[0].Path != "scores.grace" {
+		t.Errorf("expected an error at scores.grace, got %v", result.Errors)
+	}
+}
@@ -0,0 +1,250 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEmptyIntersection is returned when MergeIntersect leaves no allowed
+// values in a list such as enum, where an empty list would allow any value
+var ErrEmptyIntersection = errors.New("specs allow no values in common")
+
+// MergeStrategy decides how a keyword set in both specs is merged
+type MergeStrategy int
+
+const (
+	// MergeReplace uses the override's value (the MergeSpecs behavior)
+	MergeReplace MergeStrategy = iota
+	// MergeUnion accepts what either spec accepts: lists are combined and
+	// bounds take the looser limit. Required lists are combined too.
+	MergeUnion
+	// MergeIntersect accepts only what both specs accept: lists keep the
+	// values in both and bounds take the tighter limit. Merging enum or
+	// uriSchemes lists without values in common fails with
+	// ErrEmptyIntersection.
+	MergeIntersect
+)
+
+// MergeOptions configures MergeSpecsWith. Strategies apply to the keywords
+// that can be combined: required, enum, checks, examples, min, max,
+// minLength, maxLength, minBytes, maxBytes, messages and severity. Other keywords, such as type
+// and pattern, are always replaced.
+type MergeOptions struct {
+	Strategy MergeStrategy            // Strategy for keywords not listed in Keywords
+	Keywords map[string]MergeStrategy // Strategy per keyword, by JSON name, e.g. {"required": MergeUnion}
+}
+
+func (o MergeOptions) strategy(keyword string) MergeStrategy {
+	if s, ok := o.Keywords[keyword]; ok {
+		return s
+	}
+	return o.Strategy
+}
+
+// MergeSpecsWith merges two specs like MergeSpecs, but merges keywords set
+// in both specs using the strategies in opts. Properties are merged by name
+// with the same options. Neither spec is modified.
+func MergeSpecsWith(base, override *Spec, opts MergeOptions) (*Spec, error) {
+	return mergeSpecsWith("", base, override, opts)
+}
+
+// mergeSpecsWith merges the specs at path like MergeSpecsWith
+func mergeSpecsWith(path string, base, override *Spec, opts MergeOptions) (*Spec, error) {
+	if base == nil {
+		return override, nil
+	}
+	if override == nil {
+		return base, nil
+	}
+
+	merged := MergeSpecs(base, override)
+
+	if override.Properties != nil {
+		merged.Properties = make(map[string]*Spec, len(base.Properties)+len(override.Properties))
+		for k, v := range base.Properties {
+			merged.Properties[k] = v
+		}
+		for _, k := range sortedKeys(override.Properties) {
+			property, err := mergeSpecsWith(buildPath(path, k), merged.Properties[k], override.Properties[k], opts)
+			if err != nil {
+				return nil, err
+			}
+			merged.Properties[k] = property
+		}
+	}
+
+	if base.Required != nil && override.Required != nil {
+		merged.Required = mergeLists(base.Required, override.Required, opts.strategy(CodeRequired),
+			func(a, b string) bool { return a == b })
+	}
+	if base.AnyRequired != nil && override.AnyRequired != nil {
+		merged.AnyRequired = mergeLists(base.AnyRequired, override.AnyRequired, opts.strategy(CodeAnyRequired),
+			func(a, b string) bool { return a == b })
+	}
+	if base.OneRequired != nil && override.OneRequired != nil {
+		merged.OneRequired = mergeLists(base.OneRequired, override.OneRequired, opts.strategy(CodeOneRequired),
+			func(a, b string) bool { return a == b })
+	}
+	if base.Enum != nil && override.Enum != nil {
+		merged.Enum = mergeLists(base.Enum, override.Enum, opts.strategy(CodeEnum), valuesEqual)
+		if len(merged.Enum) == 0 {
+			return nil, fmt.Errorf("%s: %w: enum", displayPath(path), ErrEmptyIntersection)
+		}
+	}
+	if base.Checks != nil && override.Checks != nil {
+		merged.Checks = mergeLists(base.Checks, override.Checks, opts.strategy("checks"),
+			func(a, b string) bool { return a == b })
+	}
+	if base.URISchemes != nil && override.URISchemes != nil {
+		merged.URISchemes = mergeLists(base.URISchemes, override.URISchemes, opts.strategy(CodeURISchemes), strings.EqualFold)
+		if len(merged.URISchemes) == 0 {
+			return nil, fmt.Errorf("%s: %w: uriSchemes", displayPath(path), ErrEmptyIntersection)
+		}
+	}
+	if base.Asserts != nil && override.Asserts != nil {
+		merged.Asserts = mergeLists(base.Asserts, override.Asserts, opts.strategy(CodeAssert),
+			func(a, b Assert) bool { return a.Expr == b.Expr })
+	}
+	if base.Examples != nil && override.Examples != nil {
+		merged.Examples = mergeLists(base.Examples, override.Examples, opts.strategy("examples"), valuesEqual)
+	}
+
+	merged.Min = mergeBound(base.Min, override.Min, opts.strategy(CodeMin), true)
+	merged.Max = mergeBound(base.Max, override.Max, opts.strategy(CodeMax), false)
+	merged.MinLength = mergeBound(base.MinLength, override.MinLength, opts.strategy(CodeMinLength), true)
+	merged.MaxLength = mergeBound(base.MaxLength, override.MaxLength, opts.strategy(CodeMaxLength), false)
+	merged.MinBytes = mergeBound(base.MinBytes, override.MinBytes, opts.strategy(CodeMinBytes), true)
+	merged.MaxBytes = mergeBound(base.MaxBytes, override.MaxBytes, opts.strategy(CodeMaxBytes), false)
+	merged.MinInt = mergeIntBound(base.MinInt, override.MinInt, opts.strategy(CodeMinInt), true)
+	merged.MaxInt = mergeIntBound(base.MaxInt, override.MaxInt, opts.strategy(CodeMaxInt), false)
+
+	if base.Messages != nil && override.Messages != nil {
+		merged.Messages = mergeMaps(base.Messages, override.Messages, opts.strategy("messages"))
+	}
+	if base.RequiredIf != nil && override.RequiredIf != nil {
+		merged.RequiredIf = mergeMaps(base.RequiredIf, override.RequiredIf, opts.strategy("requiredIf"))
+	}
+	if base.Formats != nil && override.Formats != nil {
+		merged.Formats = mergeMaps(base.Formats, override.Formats, opts.strategy("formats"))
+	}
+	if base.ConditionDefs != nil && override.ConditionDefs != nil {
+		merged.ConditionDefs = mergeMaps(base.ConditionDefs, override.ConditionDefs, opts.strategy("conditionDefs"))
+	}
+	if base.Severity != nil && override.Severity != nil {
+		merged.Severity = mergeMaps(base.Severity, override.Severity, opts.strategy("severity"))
+	}
+
+	return merged, nil
+}
+
+// MergePlan is the outcome of a merge, for reviewing before it is used
+type MergePlan struct {
+	Spec    *Spec        // The merged spec
+	Changes []SpecChange // How the merged spec differs from base
+}
+
+// PlanMerge performs a dry run of MergeSpecsWith, reporting the merged spec
+// along with how it differs from base and which differences are breaking
+func PlanMerge(base, override *Spec, opts MergeOptions) (*MergePlan, error) {
+	merged, err := MergeSpecsWith(base, override, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &MergePlan{
+		Spec:    merged,
+		Changes: DiffSpecs(base, merged).Changes,
+	}, nil
+}
+
+// mergeLists merges two lists, preserving the order of base then override
+func mergeLists[T any](base, override []T, strategy MergeStrategy, equal func(a, b T) bool) []T {
+	contains := func(list []T, value T) bool {
+		for _, v := range list {
+			if equal(v, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch strategy {
+	case MergeUnion:
+		merged := append([]T{}, base...)
+		for _, v := range override {
+			if !contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	case MergeIntersect:
+		merged := []T{}
+		for _, v := range base {
+			if contains(override, v) && !contains(merged, v) {
+				merged = append(merged, v)
+			}
+		}
+		return merged
+	default:
+		return override
+	}
+}
+
+// mergeBound merges a lower (lower is true) or upper bound
+func mergeBound[T int | float64](base, override *T, strategy MergeStrategy, lower bool) *T {
+	if base == nil || override == nil || strategy == MergeReplace {
+		if override != nil {
+			return override
+		}
+		return base
+	}
+
+	// For a lower bound the smaller limit is the looser one
+	looser := *base < *override == lower
+	if (strategy == MergeUnion) == looser {
+		return base
+	}
+	return override
+}
+
+// mergeIntBound merges a lower (lower is true) or upper minInt or maxInt
+// bound like mergeBound
+func mergeIntBound(base, override json.Number, strategy MergeStrategy, lower bool) json.Number {
+	if base == "" || override == "" || strategy == MergeReplace {
+		if override != "" {
+			return override
+		}
+		return base
+	}
+
+	looser := compareIntBounds(base, override) < 0 == lower
+	if (strategy == MergeUnion) == looser {
+		return base
+	}
+	return override
+}
+
+// mergeMaps merges two maps keyed by error code, with override's values
+// winning for keys in both
+func mergeMaps[T any](base, override map[string]T, strategy MergeStrategy) map[string]T {
+	merged := make(map[string]T)
+	switch strategy {
+	case MergeUnion:
+		for k, v := range base {
+			merged[k] = v
+		}
+		for k, v := range override {
+			merged[k] = v
+		}
+	case MergeIntersect:
+		for k, v := range override {
+			if _, ok := base[k]; ok {
+				merged[k] = v
+			}
+		}
+	default:
+		return override
+	}
+	return merged
+}
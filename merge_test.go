@@ -0,0 +1,135 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMergeSpecsWith(t *testing.T) {
+	base := `{"type": "object", "required": ["a", "b"], "properties": {
+		"a": {"type": "string", "minLength": 2, "maxLength": 10, "enum": ["x", "y"], "messages": {"enum": "pick x or y", "minLength": "short"}},
+		"n": {"type": "number", "min": 0, "max": 100}
+	}}`
+	override := `{"required": ["b", "c"], "properties": {
+		"a": {"minLength": 4, "maxLength": 20, "enum": ["y", "z"], "messages": {"enum": "pick one"}},
+		"n": {"min": 5}
+	}}`
+
+	tests := []struct {
+		name string
+		opts MergeOptions
+		want string
+	}{
+		{
+			name: "replace",
+			opts: MergeOptions{},
+			want: `{"type": "object", "required": ["b", "c"], "properties": {
+				"a": {"type": "string", "minLength": 4, "maxLength": 20, "enum": ["y", "z"], "messages": {"enum": "pick one"}},
+				"n": {"type": "number", "min": 5, "max": 100}
+			}}`,
+		},
+		{
+			name: "union",
+			opts: MergeOptions{Strategy: MergeUnion},
+			want: `{"type": "object", "required": ["a", "b", "c"], "properties": {
+				"a": {"type": "string", "minLength": 2, "maxLength": 20, "enum": ["x", "y", "z"], "messages": {"enum": "pick one", "minLength": "short"}},
+				"n": {"type": "number", "min": 0, "max": 100}
+			}}`,
+		},
+		{
+			name: "intersect",
+			opts: MergeOptions{Strategy: MergeIntersect},
+			want: `{"type": "object", "required": ["b"], "properties": {
+				"a": {"type": "string", "minLength": 4, "maxLength": 10, "enum": ["y"], "messages": {"enum": "pick one"}},
+				"n": {"type": "number", "min": 5, "max": 100}
+			}}`,
+		},
+		{
+			name: "per keyword",
+			opts: MergeOptions{Strategy: MergeIntersect, Keywords: map[string]MergeStrategy{CodeRequired: MergeUnion, "messages": MergeReplace}},
+			want: `{"type": "object", "required": ["a", "b", "c"], "properties": {
+				"a": {"type": "string", "minLength": 4, "maxLength": 10, "enum": ["y"], "messages": {"enum": "pick one"}},
+				"n": {"type": "number", "min": 5, "max": 100}
+			}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseSpec, _ := ParseSpecString(base)
+			overrideSpec, _ := ParseSpecString(override)
+			wantSpec, err := ParseSpecString(tt.want)
+			if err != nil {
+				t.Fatalf("Failed to parse expected spec: %v", err)
+			}
+
+			merged, err := MergeSpecsWith(baseSpec, overrideSpec, tt.opts)
+			if err != nil {
+				t.Fatalf("MergeSpecsWith failed: %v", err)
+			}
+			got, _ := json.Marshal(merged)
+			want, _ := json.Marshal(wantSpec)
+			if string(got) != string(want) {
+				t.Errorf("expected %s, got %s", want, got)
+			}
+
+			unchanged, _ := ParseSpecString(base)
+			if before, after := mustMarshal(t, unchanged), mustMarshal(t, baseSpec); before != after {
+				t.Errorf("base spec was modified: %s", after)
+			}
+		})
+	}
+}
+
+func TestMergeIntersectDisjointEnums(t *testing.T) {
+	base, _ := ParseSpecString(`{"type": "object", "properties": {"a": {"type": "string", "enum": ["x", "y"]}}}`)
+	override, _ := ParseSpecString(`{"properties": {"a": {"enum": ["z"]}}}`)
+
+	merged, err := MergeSpecsWith(base, override, MergeOptions{Strategy: MergeIntersect})
+	if !errors.Is(err, ErrEmptyIntersection) || !strings.HasPrefix(err.Error(), "a: ") {
+		t.Fatalf("expected ErrEmptyIntersection at a, got %v (merged %s)", err, mustMarshal(t, merged))
+	}
+
+	if _, err := MergeSpecsWith(base, override, MergeOptions{Strategy: MergeIntersect,
+		Keywords: map[string]MergeStrategy{CodeEnum: MergeUnion}}); err != nil {
+		t.Errorf("unexpected error merging enums by union: %v", err)
+	}
+}
+
+func TestPlanMerge(t *testing.T) {
+	base, _ := ParseSpecString(`{"type": "object", "required": ["a"], "properties": {"n": {"type": "integer", "min": 0}}}`)
+	override, _ := ParseSpecString(`{"required": ["b"], "properties": {"n": {"min": 10}}}`)
+
+	plan, err := PlanMerge(base, override, MergeOptions{Keywords: map[string]MergeStrategy{CodeRequired: MergeUnion}})
+	if err != nil {
+		t.Fatalf("PlanMerge failed: %v", err)
+	}
+	if got := mustMarshal(t, plan.Spec.Required); got != `["a","b"]` {
+		t.Errorf("expected merged required [a b], got %s", got)
+	}
+
+	want := []SpecChange{
+		{Path: "b", Constraint: CodeRequired, Change: ChangeAdded, Breaking: true},
+		{Path: "n", Constraint: CodeMin, Change: ChangeTightened, Breaking: true},
+	}
+	if len(plan.Changes) != len(want) {
+		t.Fatalf("expected %d changes, got %v", len(want), plan.Changes)
+	}
+	for i, w := range want {
+		got := plan.Changes[i]
+		if got.Path != w.Path || got.Constraint != w.Constraint || got.Change != w.Change || got.Breaking != w.Breaking {
+			t.Errorf("change %d: expected %s, got %s", i, w, got)
+		}
+	}
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,118 @@
+package mowgli
+
+import "strings"
+
+// Causes of errors in a merge-patched document
+const (
+	MergePatchRemoved  = "removed"  // The patch removed the field with null, e.g. a required field
+	MergePatchChanged  = "changed"  // The patch set the field, or changed what it depends on
+	MergePatchExisting = "existing" // The original document already had the error
+)
+
+// MergePatchError is a validation error in a merge-patched document
+type MergePatchError struct {
+	*ValidationError
+	Cause string // One of the MergePatch* constants
+}
+
+// MergePatchResult is the result of validating a merge-patched document
+type MergePatchResult struct {
+	Valid    bool
+	Errors   []*MergePatchError
+	Document any // The merged document
+}
+
+// ApplyMergePatch merges a JSON Merge Patch (RFC 7386) onto a copy of
+// document and returns the result: object members in the patch are merged
+// recursively, null removes a member and any other value replaces it.
+func ApplyMergePatch(document, patch any) any {
+	return mergePatch(deepCopy(document), patch)
+}
+
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return deepCopy(patch)
+	}
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any, len(patchObj))
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+		} else {
+			targetObj[key] = mergePatch(targetObj[key], value)
+		}
+	}
+	return targetObj
+}
+
+// ValidateMergePatch merges patch onto original and validates the result.
+// See Validator.ValidateMergePatch.
+func ValidateMergePatch(original, patch any, spec *Spec) *MergePatchResult {
+	return defaultValidator.ValidateMergePatch(original, patch, spec)
+}
+
+// ValidateMergePatch merges a JSON Merge Patch (RFC 7386) onto a copy of
+// original and validates the result. Each error carries its cause, telling
+// apart fields the patch removed with null, fields it set to invalid values
+// and errors the original document already had.
+func (v *Validator) ValidateMergePatch(original, patch any, spec *Spec) *MergePatchResult {
+	merged := ApplyMergePatch(original, patch)
+	final := v.Validate(merged, spec)
+	result := &MergePatchResult{Valid: final.Valid, Errors: []*MergePatchError{}, Document: merged}
+	if final.Valid {
+		return result
+	}
+
+	var removed, changed []string
+	collectMergePatchPaths("", original, patch, &removed, &changed)
+	existing := errorKeys(v.Validate(original, spec))
+
+	for _, err := range final.Errors {
+		cause := MergePatchChanged
+		switch {
+		case underAnyPath(err.Path, removed):
+			cause = MergePatchRemoved
+		case underAnyPath(err.Path, changed):
+		case existing[errorKey{err.Path, err.Code}]:
+			cause = MergePatchExisting
+		}
+		result.Errors = append(result.Errors, &MergePatchError{ValidationError: err, Cause: cause})
+	}
+	return result
+}
+
+// collectMergePatchPaths lists the paths patch removes and sets in target
+func collectMergePatchPaths(path string, target, patch any, removed, changed *[]string) {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		*changed = append(*changed, path)
+		return
+	}
+	targetObj, _ := target.(map[string]any)
+	if targetObj == nil && path != "" {
+		// The patch replaces a non-object with an object
+		*changed = append(*changed, path)
+		return
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			*removed = append(*removed, buildPath(path, key))
+		} else {
+			collectMergePatchPaths(buildPath(path, key), targetObj[key], value, removed, changed)
+		}
+	}
+}
+
+// underAnyPath reports whether path is one of paths or inside one of them
+func underAnyPath(path string, paths []string) bool {
+	for _, p := range paths {
+		if path == p || p == "" ||
+			strings.HasPrefix(path, p+".") || strings.HasPrefix(path, p+"[") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	// Examples from RFC 7386, appendix A
+	tests := []struct {
+		original, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		var original, patch, want any
+		json.Unmarshal([]byte(tt.original), &original)
+		json.Unmarshal([]byte(tt.patch), &patch)
+		json.Unmarshal([]byte(tt.want), &want)
+		before, _ := json.Marshal(original)
+
+		got := ApplyMergePatch(original, patch)
+		if !jsonEqual(got, want) {
+			gotJSON, _ := json.Marshal(got)
+			t.Errorf("%s + %s: expected %s, got %s", tt.original, tt.patch, tt.want, gotJSON)
+		}
+		if after, _ := json.Marshal(original); string(after) != string(before) {
+			t.Errorf("%s + %s: original was modified", tt.original, tt.patch)
+		}
+	}
+}
+
+func TestValidateMergePatch(t *testing.T) {
+	spec, _ := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"age": {"type": "integer", "min": 0},
+			"country": {"type": "string"},
+			"zip": {"type": "string"},
+			"address": {"type": "object", "properties": {"city": {"type": "string"}, "street": {"type": "string"}}, "required": ["city"]}
+		},
+		"required": ["name"],
+		"conditions": [{"if": "country == \"US\"", "then": {"zip": {"pattern": "^[0-9]{5}$"}}}]
+	}`)
+	var original, patch any
+	json.Unmarshal([]byte(`{"name": "Ann", "age": -1, "zip": "ABC", "address": {"city": "Oslo", "street": "Main"}}`), &original)
+	json.Unmarshal([]byte(`{"name": null, "country": "US", "address": {"city": null, "street": 5}}`), &patch)
+
+	result := ValidateMergePatch(original, patch, spec)
+	if result.Valid {
+		t.Fatal("expected merged document to be invalid")
+	}
+
+	want := map[string]string{
+		"name/required":         MergePatchRemoved,
+		"address.city/required": MergePatchRemoved,
+		"address.street/type":   MergePatchChanged,
+		"zip/pattern":           MergePatchChanged, // through the condition on country
+		"age/min":               MergePatchExisting,
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(result.Errors), result.Errors)
+	}
+	for _, e := range result.Errors {
+		key := e.Path + "/" + e.Code
+		if cause, ok := want[key]; !ok || cause != e.Cause {
+			t.Errorf("%s: expected cause %q, got %q", key, want[key], e.Cause)
+		}
+	}
+
+	json.Unmarshal([]byte(`{"age": 30, "zip": null}`), &patch)
+	if fixed := ValidateMergePatch(original, patch, spec); !fixed.Valid || len(fixed.Errors) != 0 {
+		t.Errorf("expected the fixed document to be valid, got %v", fixed.Errors)
+	}
+}
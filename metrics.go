@@ -0,0 +1,60 @@
+package mowgli
+
+import "time"
+
+// Observer is notified of the outcome of each validation, e.g. to count
+// failures and record latencies with Prometheus. specName is the ref the
+// spec was looked up by for ValidateRef and the Registry's Validate
+// methods, the spec's $ref if it is only a reference, and "" otherwise.
+// Observers are called synchronously from the validating goroutine and must
+// be safe for concurrent use.
+type Observer interface {
+	OnValidate(specName string, valid bool, numErrors int, duration time.Duration)
+}
+
+// ErrorObserver is an Observer that is also notified of each error, before
+// OnValidate, e.g. to count failures per error code
+type ErrorObserver interface {
+	Observer
+	OnError(specName string, err *ValidationError)
+}
+
+// ObserverFunc adapts a function to an Observer
+type ObserverFunc func(specName string, valid bool, numErrors int, duration time.Duration)
+
+// OnValidate calls f
+func (f ObserverFunc) OnValidate(specName string, valid bool, numErrors int, duration time.Duration) {
+	f(specName, valid, numErrors, duration)
+}
+
+// WithObserver reports the outcome of every validation to o
+func WithObserver(o Observer) Option {
+	return func(v *Validator) {
+		v.observer = o
+	}
+}
+
+// observe reports the result to the observer, if any, as the outcome of
+// validating against the spec named name that started at start, and logs
+// the validation if it was slow
+func (r *ValidationResult) observe(name string, start time.Time) {
+	duration := time.Since(start)
+	r.logSlow(name, duration)
+	if r.observer == nil {
+		return
+	}
+	if o, ok := r.observer.(ErrorObserver); ok {
+		for _, err := range r.Errors {
+			o.OnError(name, err)
+		}
+	}
+	r.observer.OnValidate(name, r.Valid, len(r.Errors), duration)
+}
+
+// specName returns the name reported to observers for spec
+func specName(spec *Spec) string {
+	if spec == nil {
+		return ""
+	}
+	return spec.Ref
+}
@@ -0,0 +1,100 @@
+package mowgli
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingObserver records outcomes and error codes
+type recordingObserver struct {
+	mu       sync.Mutex
+	outcomes []string
+	codes    []string
+}
+
+func (o *recordingObserver) OnValidate(specName string, valid bool, numErrors int, duration time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if duration < 0 {
+		panic("negative duration")
+	}
+	o.outcomes = append(o.outcomes, fmt.Sprintf("%s %t %d", specName, valid, numErrors))
+}
+
+func (o *recordingObserver) OnError(specName string, err *ValidationError) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.codes = append(o.codes, specName+" "+err.Code)
+}
+
+func TestObserver(t *testing.T) {
+	spec := &Spec{
+		Type:       "object",
+		Properties: map[string]*Spec{"name": {Type: "string", MinLength: intPtr(2)}},
+		Required:   []string{"name"},
+	}
+	obs := &recordingObserver{}
+	reg := NewRegistry(WithObserver(obs))
+	if err := reg.Register("user@1", spec); err != nil {
+		t.Fatalf("Failed to register spec: %v", err)
+	}
+	v := NewValidator(WithObserver(obs), WithRegistry(reg))
+
+	v.Validate(map[string]any{"name": "Ada"}, spec)
+	v.Validate(map[string]any{"name": "A"}, &Spec{Ref: "user@1"})
+	if _, err := reg.Validate(map[string]any{}, "user"); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if _, err := reg.ValidateJSON([]byte(`{"name": "Ada"}`), "user@1"); err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	v.ValidateQuery(url.Values{"name": {"A"}}, spec)
+	v.ValidateAsync(context.Background(), map[string]any{}, spec, nil)
+
+	wantOutcomes := []string{
+		" true 0",
+		"user@1 false 1",
+		"user false 1",
+		"user@1 true 0",
+		" false 1",
+		" false 1",
+	}
+	wantCodes := []string{"user@1 minLength", "user required", " minLength", " required"}
+	if len(obs.outcomes) != len(wantOutcomes) {
+		t.Fatalf("expected outcomes %q, got %q", wantOutcomes, obs.outcomes)
+	}
+	for i := range wantOutcomes {
+		if obs.outcomes[i] != wantOutcomes[i] {
+			t.Errorf("outcome %d: expected %q, got %q", i, wantOutcomes[i], obs.outcomes[i])
+		}
+	}
+	if len(obs.codes) != len(wantCodes) {
+		t.Fatalf("expected codes %q, got %q", wantCodes, obs.codes)
+	}
+	for i := range wantCodes {
+		if obs.codes[i] != wantCodes[i] {
+			t.Errorf("code %d: expected %q, got %q", i, wantCodes[i], obs.codes[i])
+		}
+	}
+}
+
+func TestObserverFunc(t *testing.T) {
+	calls := 0
+	v := NewValidator(WithObserver(ObserverFunc(func(specName string, valid bool, numErrors int, duration time.Duration) {
+		calls++
+	})))
+	v.Validate("x", &Spec{Type: "string"})
+	if _, err := v.ValidateJSON([]byte(`1`), &Spec{Type: "string"}); err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if _, err := v.ValidateJSON([]byte(`{`), &Spec{Type: "string"}); err == nil {
+		t.Fatal("expected invalid JSON to fail")
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 observed validations, got %d", calls)
+	}
+}
@@ -0,0 +1,27 @@
+// Package mowgliecho binds validated JSON request bodies in echo handlers
+package mowgliecho
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/matjam/mowgli"
+	"github.com/matjam/mowgli/mowglihttp"
+)
+
+// Bind decodes and validates the request's JSON body into T using
+// mowgli.DecodeAndValidate. If the body is unreadable or invalid, Bind
+// returns an *echo.HTTPError with status 400 whose message is a
+// mowglihttp.ErrorResponse, which echo's error handler writes as JSON.
+//
+//	user, err := mowgliecho.Bind[User](c)
+//	if err != nil {
+//		return err
+//	}
+func Bind[T any](c echo.Context, opts ...mowgli.DecodeOption) (T, error) {
+	result, value, err := mowgli.DecodeAndValidate[T](c.Request().Body, opts...)
+	if err != nil || !result.Valid {
+		return value, echo.NewHTTPError(http.StatusBadRequest, mowglihttp.NewErrorResponse(result, err))
+	}
+	return value, nil
+}
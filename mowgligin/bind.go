@@ -0,0 +1,28 @@
+// Package mowgligin binds validated JSON request bodies in gin handlers
+package mowgligin
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/matjam/mowgli"
+	"github.com/matjam/mowgli/mowglihttp"
+)
+
+// Bind decodes and validates the request's JSON body into T using
+// mowgli.DecodeAndValidate. If the body is unreadable or invalid, Bind aborts
+// the request with a 400 Bad Request and a mowglihttp.ErrorResponse, and
+// returns false.
+//
+//	user, ok := mowgligin.Bind[User](c)
+//	if !ok {
+//		return
+//	}
+func Bind[T any](c *gin.Context, opts ...mowgli.DecodeOption) (T, bool) {
+	result, value, err := mowgli.DecodeAndValidate[T](c.Request.Body, opts...)
+	if err != nil || !result.Valid {
+		c.AbortWithStatusJSON(http.StatusBadRequest, mowglihttp.NewErrorResponse(result, err))
+		return value, false
+	}
+	return value, true
+}
@@ -0,0 +1,48 @@
+package mowgligin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type signup struct {
+	Email string `json:"email" mowgli:"required,format=email"`
+}
+
+func TestBind(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{name: "valid", body: `{"email": "a@example.com"}`, wantCode: http.StatusOK, wantBody: "a@example.com"},
+		{name: "invalid", body: `{"email": "nope"}`, wantCode: http.StatusBadRequest, wantBody: `"error":"validation failed"`},
+		{name: "malformed", body: `{`, wantCode: http.StatusBadRequest, wantBody: "invalid JSON"},
+	}
+
+	router := gin.New()
+	router.POST("/", func(c *gin.Context) {
+		user, ok := Bind[signup](c)
+		if !ok {
+			return
+		}
+		c.String(http.StatusOK, user.Email)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantCode || !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("expected %d containing %q, got %d %s", tt.wantCode, tt.wantBody, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
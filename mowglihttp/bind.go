@@ -0,0 +1,50 @@
+// Package mowglihttp binds validated JSON request bodies in net/http
+// handlers, including routers built on net/http such as chi.
+package mowglihttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/matjam/mowgli"
+)
+
+// ErrorResponse is the JSON body written when a request body is rejected
+type ErrorResponse struct {
+	Error  string                    `json:"error"`            // Summary of why the body was rejected
+	Errors []*mowgli.ValidationError `json:"errors,omitempty"` // Validation errors, if the body was valid JSON
+}
+
+// NewErrorResponse builds the response for a body that failed to decode
+// (err is non-nil) or to validate (result is invalid)
+func NewErrorResponse(result *mowgli.ValidationResult, err error) ErrorResponse {
+	if err != nil {
+		return ErrorResponse{Error: err.Error()}
+	}
+	return ErrorResponse{Error: "validation failed", Errors: result.Errors}
+}
+
+// Bind decodes and validates the request's JSON body into T using
+// mowgli.DecodeAndValidate. If the body is unreadable or invalid, Bind writes
+// a 400 Bad Request with an ErrorResponse and returns false; the handler
+// should then return without writing anything else.
+//
+//	user, ok := mowglihttp.Bind[User](w, r)
+//	if !ok {
+//		return
+//	}
+func Bind[T any](w http.ResponseWriter, r *http.Request, opts ...mowgli.DecodeOption) (T, bool) {
+	result, value, err := mowgli.DecodeAndValidate[T](r.Body, opts...)
+	if err != nil || !result.Valid {
+		WriteError(w, http.StatusBadRequest, NewErrorResponse(result, err))
+		return value, false
+	}
+	return value, true
+}
+
+// WriteError writes response as JSON with the given status code
+func WriteError(w http.ResponseWriter, status int, response ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
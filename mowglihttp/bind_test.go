@@ -0,0 +1,68 @@
+package mowglihttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type signup struct {
+	Email string `json:"email" mowgli:"required,format=email"`
+	Age   int    `json:"age" mowgli:"min=18"`
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOK     bool
+		wantError  string
+		wantErrors int
+	}{
+		{name: "valid", body: `{"email": "a@example.com", "age": 30}`, wantOK: true},
+		{name: "invalid", body: `{"email": "nope", "age": 12}`, wantError: "validation failed", wantErrors: 2},
+		{name: "malformed", body: `{"email":`, wantError: "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got signup
+			var ok bool
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				if got, ok = Bind[signup](w, r); !ok {
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			if ok != tt.wantOK {
+				t.Fatalf("expected ok=%v, got %v", tt.wantOK, ok)
+			}
+			if tt.wantOK {
+				if rec.Code != http.StatusNoContent || got.Email != "a@example.com" {
+					t.Errorf("expected decoded value and handler response, got %d %+v", rec.Code, got)
+				}
+				return
+			}
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", rec.Code)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected JSON content type, got %q", ct)
+			}
+			var response ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if !strings.Contains(response.Error, tt.wantError) || len(response.Errors) != tt.wantErrors {
+				t.Errorf("unexpected response: %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Package mowgliotel traces validation with OpenTelemetry, so that the time
+// spent validating shows up in the traces of the requests that do it
+package mowgliotel
+
+import (
+	"context"
+
+	"github.com/matjam/mowgli"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer spans are created with
+const instrumentationName = "github.com/matjam/mowgli/mowgliotel"
+
+// Attributes set on spans
+const (
+	AttrSpecName    = attribute.Key("mowgli.spec.name")    // The ref of the spec, if it has one
+	AttrValid       = attribute.Key("mowgli.valid")        // Whether the document is valid
+	AttrErrorCount  = attribute.Key("mowgli.error.count")  // Number of validation errors
+	AttrPayloadSize = attribute.Key("mowgli.payload.size") // Size of the JSON document in bytes, for ValidateJSON
+)
+
+// Validator wraps a mowgli.Validator, creating a span for each validation
+// and compilation as a child of the span in the context passed to it
+type Validator struct {
+	validator *mowgli.Validator
+	tracer    trace.Tracer
+}
+
+// Option configures a Validator
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider creates spans with provider instead of the global
+// TracerProvider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// New wraps v, or a default Validator if v is nil
+func New(v *mowgli.Validator, opts ...Option) *Validator {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if v == nil {
+		v = mowgli.NewValidator()
+	}
+	return &Validator{validator: v, tracer: c.provider.Tracer(instrumentationName)}
+}
+
+// Validate validates data against spec in a "mowgli.Validate" span
+func (v *Validator) Validate(ctx context.Context, data any, spec *mowgli.Spec) *mowgli.ValidationResult {
+	_, span := v.start(ctx, "mowgli.Validate", specRef(spec))
+	defer span.End()
+
+	result := v.validator.Validate(data, spec)
+	endValidation(span, result, nil)
+	return result
+}
+
+// ValidateJSON validates a JSON document against spec in a
+// "mowgli.ValidateJSON" span, which records the document's size
+func (v *Validator) ValidateJSON(ctx context.Context, jsonData []byte, spec *mowgli.Spec) (*mowgli.ValidationResult, error) {
+	_, span := v.start(ctx, "mowgli.ValidateJSON", specRef(spec))
+	defer span.End()
+	span.SetAttributes(AttrPayloadSize.Int(len(jsonData)))
+
+	result, err := v.validator.ValidateJSON(jsonData, spec)
+	endValidation(span, result, err)
+	return result, err
+}
+
+// ValidateRef validates data against the spec registered as ref in the
+// Validator's registry in a "mowgli.ValidateRef" span
+func (v *Validator) ValidateRef(ctx context.Context, data any, ref string) (*mowgli.ValidationResult, error) {
+	_, span := v.start(ctx, "mowgli.ValidateRef", ref)
+	defer span.End()
+
+	result, err := v.validator.ValidateRef(data, ref)
+	endValidation(span, result, err)
+	return result, err
+}
+
+// Compile compiles spec in a "mowgli.Compile" span
+func (v *Validator) Compile(ctx context.Context, spec *mowgli.Spec) (*mowgli.CompiledSpec, error) {
+	_, span := v.start(ctx, "mowgli.Compile", specRef(spec))
+	defer span.End()
+
+	compiled, err := mowgli.Compile(spec)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return compiled, err
+}
+
+// start starts a span named name for the spec with ref name specName
+func (v *Validator) start(ctx context.Context, name, specName string) (context.Context, trace.Span) {
+	ctx, span := v.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindInternal))
+	if specName != "" {
+		span.SetAttributes(AttrSpecName.String(specName))
+	}
+	return ctx, span
+}
+
+// endValidation records the outcome of a validation on span. Invalid
+// documents are an expected outcome and leave the span's status unset;
+// errors such as malformed JSON set it to Error.
+func endValidation(span trace.Span, result *mowgli.ValidationResult, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(AttrValid.Bool(result.Valid), AttrErrorCount.Int(len(result.Errors)))
+}
+
+// specRef returns the ref of spec, if any
+func specRef(spec *mowgli.Spec) string {
+	if spec == nil {
+		return ""
+	}
+	return spec.Ref
+}
@@ -0,0 +1,82 @@
+package mowgliotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matjam/mowgli"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestValidator(t *testing.T) {
+	reg := mowgli.NewRegistry()
+	user := &mowgli.Spec{Type: "object", Required: []string{"email"}}
+	if err := reg.Register("user@1", user); err != nil {
+		t.Fatalf("Failed to register spec: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	v := New(mowgli.NewValidator(mowgli.WithRegistry(reg)), WithTracerProvider(provider))
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	v.Validate(ctx, map[string]any{}, user)
+	if _, err := v.ValidateJSON(ctx, []byte(`{"email": "a@example.com"}`), &mowgli.Spec{Ref: "user@1"}); err != nil {
+		t.Fatalf("ValidateJSON returned error: %v", err)
+	}
+	if _, err := v.ValidateJSON(ctx, []byte(`{`), user); err == nil {
+		t.Fatal("expected malformed JSON to fail")
+	}
+	if _, err := v.ValidateRef(ctx, map[string]any{}, "user"); err != nil {
+		t.Fatalf("ValidateRef returned error: %v", err)
+	}
+	pattern := "["
+	if _, err := v.Compile(ctx, &mowgli.Spec{Type: "string", Pattern: &pattern}); err == nil {
+		t.Fatal("expected an invalid pattern to fail to compile")
+	}
+	parent.End()
+
+	tests := []struct {
+		name   string
+		attrs  map[attribute.Key]any
+		failed bool
+	}{
+		{name: "mowgli.Validate", attrs: map[attribute.Key]any{AttrValid: false, AttrErrorCount: int64(1)}},
+		{name: "mowgli.ValidateJSON", attrs: map[attribute.Key]any{AttrSpecName: "user@1", AttrPayloadSize: int64(26), AttrValid: true, AttrErrorCount: int64(0)}},
+		{name: "mowgli.ValidateJSON", attrs: map[attribute.Key]any{AttrPayloadSize: int64(1)}, failed: true},
+		{name: "mowgli.ValidateRef", attrs: map[attribute.Key]any{AttrSpecName: "user", AttrValid: false, AttrErrorCount: int64(1)}},
+		{name: "mowgli.Compile", attrs: map[attribute.Key]any{}, failed: true},
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != len(tests)+1 {
+		t.Fatalf("expected %d spans, got %d", len(tests)+1, len(spans))
+	}
+	for i, tt := range tests {
+		span := spans[i]
+		if span.Name() != tt.name {
+			t.Errorf("span %d: expected name %q, got %q", i, tt.name, span.Name())
+		}
+		if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+			t.Errorf("span %d: expected the request span as parent", i)
+		}
+		attrs := map[attribute.Key]any{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value.AsInterface()
+		}
+		if len(attrs) != len(tt.attrs) {
+			t.Errorf("span %d: expected attributes %v, got %v", i, tt.attrs, attrs)
+		}
+		for key, want := range tt.attrs {
+			if attrs[key] != want {
+				t.Errorf("span %d: expected %s = %v, got %v", i, key, want, attrs[key])
+			}
+		}
+		if failed := span.Status().Code == codes.Error; failed != tt.failed {
+			t.Errorf("span %d: expected failed %v, got status %v", i, tt.failed, span.Status())
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package mowglitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/matjam/mowgli"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	if flag.Lookup("update") == nil {
+		flag.Bool("update", false, "rewrite the expectedErrors of spec test files with the errors reported")
+	}
+}
+
+// updating reports whether the test binary was run with -update
+func updating() bool {
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// WritableFS is a file system that RunSpecTests can write updated golden
+// expectations to
+type WritableFS interface {
+	fs.FS
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// DirFS returns a WritableFS for the directory dir. Pass it to RunSpecTests
+// instead of os.DirFS to allow -update to rewrite the fixtures.
+func DirFS(dir string) WritableFS {
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "writefile", Path: name, Err: fs.ErrInvalid}
+	}
+	return os.WriteFile(filepath.Join(d.dir, filepath.FromSlash(name)), data, perm)
+}
+
+// updateExpectedErrors rewrites the expectedErrors of the test cases in the
+// spec test file name, leaving the rest of the file as it was
+func updateExpectedErrors(fsys fs.FS, name string, snapshots [][]mowgli.ExpectedError) error {
+	writable, ok := fsys.(WritableFS)
+	if !ok {
+		return fmt.Errorf("-update needs a writable file system, such as mowglitest.DirFS")
+	}
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	switch path.Ext(name) {
+	case ".yaml", ".yml":
+		data, err = updateYAML(data, snapshots)
+	default:
+		data, err = updateJSON(data, snapshots)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", name, err)
+	}
+	return writable.WriteFile(name, data, 0o644)
+}
+
+// updateJSON rewrites a JSON spec test, keeping the spec and data as written
+func updateJSON(data []byte, snapshots [][]mowgli.ExpectedError) ([]byte, error) {
+	var file struct {
+		Description string          `json:"description,omitempty"`
+		Spec        json.RawMessage `json:"spec"`
+		TestCases   []struct {
+			Name           string                 `json:"name"`
+			Data           json.RawMessage        `json:"data"`
+			ExpectedValid  bool                   `json:"expectedValid"`
+			ExpectedErrors []mowgli.ExpectedError `json:"expectedErrors,omitempty"`
+		} `json:"testCases"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	for i := range file.TestCases {
+		file.TestCases[i].ExpectedErrors = snapshots[i]
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// updateYAML rewrites a YAML spec test, preserving its layout and comments
+func updateYAML(data []byte, snapshots [][]mowgli.ExpectedError) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return nil, fmt.Errorf("empty document")
+	}
+
+	cases := mappingValue(root.Content[0], "testCases")
+	if cases == nil || cases.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("testCases is not a list")
+	}
+	for i, tc := range cases.Content {
+		var errorsNode *yaml.Node
+		if len(snapshots[i]) > 0 {
+			errorsNode = &yaml.Node{}
+			if err := errorsNode.Encode(snapshots[i]); err != nil {
+				return nil, err
+			}
+		}
+		setMappingValue(tc, "expectedErrors", errorsNode)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value of key in a YAML mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets key in a YAML mapping node, removing it if value is nil
+func setMappingValue(node *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != key {
+			continue
+		}
+		if value == nil {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+		} else {
+			node.Content[i+1] = value
+		}
+		return
+	}
+	if value != nil {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+}
@@ -0,0 +1,95 @@
+package mowglitest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/matjam/mowgli"
+)
+
+func TestUpdateExpectedErrors(t *testing.T) {
+	snapshots := [][]mowgli.ExpectedError{
+		{{Path: "", Code: mowgli.CodeMinLength}},
+		nil,
+	}
+
+	yamlFile := `# Keep this comment
+spec: {type: string, minLength: 2}
+testCases:
+  - name: short
+    data: "a"
+    expectedValid: false
+  - name: ok # and this one
+    data: "ab"
+    expectedValid: true
+    expectedErrors:
+      - {path: "", code: stale}
+`
+	jsonFile := `{"spec": {"type": "string", "minLength": 2, "pattern": "<"}, "testCases": [
+		{"name": "short", "data": "a", "expectedValid": false},
+		{"name": "ok", "data": "ab", "expectedValid": true, "expectedErrors": [{"path": "", "code": "stale"}]}
+	]}`
+
+	dir := t.TempDir()
+	for name, content := range map[string]string{"a.yaml": yamlFile, "b.json": jsonFile} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := DirFS(dir)
+
+	tests := []struct {
+		file    string
+		want    []string
+		notWant []string
+	}{
+		{
+			file:    "a.yaml",
+			want:    []string{"# Keep this comment", "# and this one", "expectedErrors:\n      - path: \"\"\n        code: minLength\n  - name: ok"},
+			notWant: []string{"stale"},
+		},
+		{
+			file:    "b.json",
+			want:    []string{`"pattern": "<"`, `"expectedErrors": [`, `"code": "minLength"`},
+			notWant: []string{"stale"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			if err := updateExpectedErrors(fsys, tt.file, snapshots); err != nil {
+				t.Fatalf("updateExpectedErrors failed: %v", err)
+			}
+			data, _ := os.ReadFile(filepath.Join(dir, tt.file))
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("expected updated file to contain %q, got:\n%s", want, data)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(string(data), notWant) {
+					t.Errorf("expected updated file not to contain %q, got:\n%s", notWant, data)
+				}
+			}
+
+			specTest, err := mowgli.LoadSpecTestFS(fsys, tt.file)
+			if err != nil {
+				t.Fatalf("updated file doesn't load: %v", err)
+			}
+			if got := specTest.TestCases[0].ExpectedErrors; len(got) != 1 || got[0].Code != mowgli.CodeMinLength {
+				t.Errorf("expected minLength error to be recorded, got %v", got)
+			}
+			if got := specTest.TestCases[1].ExpectedErrors; got != nil {
+				t.Errorf("expected no errors to be recorded, got %v", got)
+			}
+		})
+	}
+
+	err := updateExpectedErrors(fstest.MapFS{"a.json": {Data: []byte(jsonFile)}}, "a.json", snapshots)
+	if err == nil || !strings.Contains(err.Error(), "writable") {
+		t.Errorf("expected error for a read-only file system, got %v", err)
+	}
+}
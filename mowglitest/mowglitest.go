@@ -0,0 +1,90 @@
+// Package mowglitest runs shared mowgli conformance fixtures as Go tests and
+// records test cases for them from integration tests
+package mowglitest
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/matjam/mowgli"
+)
+
+// RunSpecTests runs every spec test file (see mowgli.SpecTest) in dir and
+// its subdirectories as subtests of t: one subtest per file, named by its
+// path relative to dir without the extension, and one per test case within
+// it. Files ending in .json, .yaml and .yml are spec test files.
+//
+// Test cases with expectedErrors must report exactly those errors. Running
+// the tests with -update records the errors currently reported as each
+// case's expectedErrors instead; fsys must then be writable, e.g. DirFS.
+// The -update flag is registered by this package, so packages importing it
+// must not define their own.
+//
+//	//go:embed conformance
+//	var fixtures embed.FS
+//
+//	func TestConformance(t *testing.T) {
+//		mowglitest.RunSpecTests(t, fixtures, "conformance")
+//	}
+func RunSpecTests(t *testing.T, fsys fs.FS, dir string) {
+	t.Helper()
+
+	var files []string
+	err := fs.WalkDir(fsys, dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		switch path.Ext(name) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				files = append(files, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to discover spec tests in %s: %v", dir, err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("no spec tests found in %s", dir)
+	}
+
+	for _, name := range files {
+		testName := strings.TrimPrefix(strings.TrimSuffix(name, path.Ext(name)), dir+"/")
+		t.Run(testName, func(t *testing.T) {
+			specTest, err := mowgli.LoadSpecTestFS(fsys, name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			snapshots := make([][]mowgli.ExpectedError, len(specTest.TestCases))
+			for i, tc := range specTest.TestCases {
+				t.Run(tc.Name, func(t *testing.T) {
+					result := mowgli.Validate(tc.Data, specTest.Spec)
+					snapshots[i] = mowgli.SnapshotErrors(result)
+
+					if result.Valid != tc.ExpectedValid {
+						if tc.ExpectedValid {
+							t.Errorf("expected validation to pass, but it failed: %v", result.Errors)
+						} else {
+							t.Errorf("expected validation to fail, but it passed")
+						}
+					}
+					if tc.ExpectedErrors != nil && !updating() {
+						if err := mowgli.CompareErrors(tc.ExpectedErrors, result); err != nil {
+							t.Errorf("%v (run with -update to accept the new errors)", err)
+						}
+					}
+				})
+			}
+
+			if updating() {
+				if err := updateExpectedErrors(fsys, name, snapshots); err != nil {
+					t.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package mowglitest
+
+import (
+	"testing"
+)
+
+func TestRunSpecTests(t *testing.T) {
+	RunSpecTests(t, DirFS("../testdata"), "spectests")
+}
@@ -0,0 +1,179 @@
+package mowglitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/matjam/mowgli"
+)
+
+// MaxResponseBody is the number of bytes of each response body a Recorder
+// keeps
+const MaxResponseBody = 64 << 10
+
+// Exchange is a request and response captured by a Recorder
+type Exchange struct {
+	Method       string
+	Path         string
+	Status       int
+	RequestBody  []byte
+	ResponseBody []byte // The first MaxResponseBody bytes of the response body
+}
+
+// Recorder wraps an http.Handler in integration tests and records the
+// request and response bodies passing through it, so the requests can be
+// written out as a testdata case file. This bootstraps shared validation
+// suites from existing tests: requests the handler accepted become valid
+// cases and requests it rejected as invalid become invalid ones.
+type Recorder struct {
+	// Classify decides from a response status whether the request's payload
+	// was valid; ok is false for statuses that don't say. Nil means
+	// ClassifyStatus.
+	Classify func(status int) (valid, ok bool)
+
+	handler  http.Handler
+	specName string
+
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// NewRecorder wraps handler. specName is the spec file the recorded cases
+// are meant to be validated against, e.g. "user_registration.json".
+func NewRecorder(handler http.Handler, specName string) *Recorder {
+	return &Recorder{handler: handler, specName: specName}
+}
+
+// ServeHTTP passes the request to the wrapped handler and records the exchange
+func (rec *Recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var requestBody []byte
+	if r.Body != nil {
+		var err error
+		requestBody, err = io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	capture := &capturingWriter{ResponseWriter: w, status: http.StatusOK}
+	rec.handler.ServeHTTP(capture, r)
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.exchanges = append(rec.exchanges, Exchange{
+		Method:       r.Method,
+		Path:         r.URL.Path,
+		Status:       capture.status,
+		RequestBody:  requestBody,
+		ResponseBody: capture.body.Bytes(),
+	})
+}
+
+// Exchanges returns the exchanges recorded so far
+func (rec *Recorder) Exchanges() []Exchange {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]Exchange(nil), rec.exchanges...)
+}
+
+// ClassifyStatus is the default Recorder.Classify: 2xx responses mean the
+// payload was valid, and 400 Bad Request and 422 Unprocessable Entity that
+// it was invalid. Other statuses, such as 401, 404 or 429, say nothing about
+// the payload.
+func ClassifyStatus(status int) (valid, ok bool) {
+	switch {
+	case status >= 200 && status < 300:
+		return true, true
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return false, true
+	}
+	return false, false
+}
+
+// TestCases converts the recorded requests into test cases. Only requests
+// with a JSON object body and a response Classify tells the validity from
+// are included.
+func (rec *Recorder) TestCases() []mowgli.TestCase {
+	classify := rec.Classify
+	if classify == nil {
+		classify = ClassifyStatus
+	}
+
+	cases := []mowgli.TestCase{}
+	for i, exchange := range rec.Exchanges() {
+		valid, ok := classify(exchange.Status)
+		if !ok {
+			continue
+		}
+
+		var data map[string]any
+		if err := json.Unmarshal(exchange.RequestBody, &data); err != nil || data == nil {
+			continue
+		}
+
+		cases = append(cases, mowgli.TestCase{
+			Name:          caseName(exchange, i+1),
+			Data:          data,
+			ExpectedValid: valid,
+		})
+	}
+	return cases
+}
+
+// WriteTestCaseFile writes the recorded test cases to path in the testdata
+// case file format
+func (rec *Recorder) WriteTestCaseFile(path string) error {
+	data, err := json.MarshalIndent(mowgli.TestCaseFile{
+		Spec:      rec.specName,
+		TestCases: rec.TestCases(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode test cases: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write test case file %s: %w", path, err)
+	}
+	return nil
+}
+
+var nonNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// caseName derives a test case name such as "post_users_3_status_201"
+func caseName(exchange Exchange, n int) string {
+	base := nonNameChars.ReplaceAllString(strings.ToLower(exchange.Method+" "+exchange.Path), "_")
+	return fmt.Sprintf("%s_%d_status_%d", strings.Trim(base, "_"), n, exchange.Status)
+}
+
+// capturingWriter records the status and, up to MaxResponseBody bytes, the
+// body written by a handler
+type capturingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *capturingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *capturingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	if room := MaxResponseBody - w.body.Len(); room > 0 {
+		w.body.Write(b[:min(len(b), room)])
+	}
+	return w.ResponseWriter.Write(b)
+}
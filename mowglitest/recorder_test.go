@@ -0,0 +1,168 @@
+package mowglitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matjam/mowgli"
+)
+
+func TestRecorder(t *testing.T) {
+	spec, err := mowgli.LoadSpecFS(os.DirFS("../testdata"), "specs/user_registration.json")
+	if err != nil {
+		t.Fatalf("failed to load spec: %v", err)
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"ok": true}`))
+			return
+		}
+		if r.Header.Get("Authorization") == "" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var data any
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, "bad JSON", http.StatusBadRequest)
+			return
+		}
+		if result := mowgli.Validate(data, spec); !result.Valid {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(result.Errors)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	rec := NewRecorder(handler, "user_registration.json")
+	server := httptest.NewServer(rec)
+	defer server.Close()
+
+	requests := []struct {
+		method       string
+		body         string
+		unauthorized bool
+	}{
+		{method: http.MethodPost, body: `{"username": "johndoe", "email": "john@example.com", "password": "securepass123", "age": 25}`},
+		{method: http.MethodPost, body: `{"username": "johndoe"}`},
+		{method: http.MethodPost, body: `not json`},
+		{method: http.MethodGet},
+		{method: http.MethodPost, body: `{"username": "johndoe", "email": "john@example.com", "password": "securepass123", "age": 25}`, unauthorized: true},
+	}
+	for _, req := range requests {
+		httpReq, err := http.NewRequest(req.method, server.URL+"/api/users", strings.NewReader(req.body))
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+		if !req.unauthorized {
+			httpReq.Header.Set("Authorization", "Bearer token")
+		}
+		resp, err := http.DefaultClient.Do(httpReq)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		resp.Body.Close()
+	}
+
+	exchanges := rec.Exchanges()
+	if len(exchanges) != 5 {
+		t.Fatalf("expected 5 exchanges, got %d", len(exchanges))
+	}
+	if exchanges[1].Status != http.StatusUnprocessableEntity || exchanges[1].RequestBody == nil {
+		t.Errorf("expected recorded request with a 422 response, got %d %q", exchanges[1].Status, exchanges[1].RequestBody)
+	}
+	if !strings.Contains(string(exchanges[1].ResponseBody), "email") {
+		t.Errorf("expected the response body to be recorded, got %q", exchanges[1].ResponseBody)
+	}
+	if string(exchanges[3].ResponseBody) != `{"ok": true}` {
+		t.Errorf("expected the GET response body, got %q", exchanges[3].ResponseBody)
+	}
+
+	path := filepath.Join(t.TempDir(), "recorded.json")
+	if err := rec.WriteTestCaseFile(path); err != nil {
+		t.Fatalf("failed to write test case file: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read test case file: %v", err)
+	}
+	var file mowgli.TestCaseFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		t.Fatalf("failed to parse test case file: %v", err)
+	}
+
+	if file.Spec != "user_registration.json" {
+		t.Errorf("expected spec reference, got %q", file.Spec)
+	}
+	// The malformed body, the GET without a body and the unauthorized
+	// request are not usable cases
+	if len(file.TestCases) != 2 {
+		t.Fatalf("expected 2 test cases, got %d", len(file.TestCases))
+	}
+	if file.TestCases[0].Name != "post_api_users_1_status_201" {
+		t.Errorf("unexpected case name %q", file.TestCases[0].Name)
+	}
+	for _, tc := range file.TestCases {
+		if result := mowgli.Validate(tc.Data, spec); result.Valid != tc.ExpectedValid {
+			t.Errorf("case %s: expected valid=%v, got %v", tc.Name, tc.ExpectedValid, result.Valid)
+		}
+	}
+}
+
+func TestRecorderClassify(t *testing.T) {
+	statuses := []int{http.StatusCreated, http.StatusBadRequest, http.StatusForbidden, http.StatusConflict, http.StatusTooManyRequests}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Status int }
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(body.Status)
+	})
+	record := func(rec *Recorder) {
+		for _, status := range statuses {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(fmt.Sprintf(`{"status": %d}`, status)))
+			rec.ServeHTTP(httptest.NewRecorder(), req)
+		}
+	}
+
+	rec := NewRecorder(handler, "")
+	record(rec)
+	cases := rec.TestCases()
+	if len(cases) != 2 || !cases[0].ExpectedValid || cases[1].ExpectedValid {
+		t.Errorf("expected a valid 201 and an invalid 400 case, got %+v", cases)
+	}
+
+	rec = NewRecorder(handler, "")
+	rec.Classify = func(status int) (valid, ok bool) {
+		return false, status == http.StatusConflict
+	}
+	record(rec)
+	if cases := rec.TestCases(); len(cases) != 1 || cases[0].Name != "post_4_status_409" {
+		t.Errorf("expected the 409 case only, got %+v", cases)
+	}
+}
+
+func TestRecorderResponseBodyLimit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte("x"), MaxResponseBody/2+1)
+		w.Write(chunk)
+		w.Write(chunk)
+	})
+	rec := NewRecorder(handler, "")
+	response := httptest.NewRecorder()
+	rec.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if response.Body.Len() != MaxResponseBody+2 {
+		t.Errorf("expected the whole body to reach the client, got %d bytes", response.Body.Len())
+	}
+	if got := len(rec.Exchanges()[0].ResponseBody); got != MaxResponseBody {
+		t.Errorf("expected %d recorded bytes, got %d", MaxResponseBody, got)
+	}
+}
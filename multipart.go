@@ -0,0 +1,113 @@
+package mowgli
+
+import (
+	"fmt"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ValidateMultipartForm converts the fields and files of a multipart form to
+// the types an object spec declares and validates them. See
+// Validator.ValidateMultipartForm.
+func ValidateMultipartForm(form *multipart.Form, spec *Spec) (map[string]any, *ValidationResult) {
+	return defaultValidator.ValidateMultipartForm(form, spec)
+}
+
+// ValidateMultipartForm converts a multipart form, as parsed by
+// http.Request.ParseMultipartForm, and validates it. Fields are converted as
+// ValidateQuery converts query parameters. Each uploaded file becomes an
+// object {"filename": ..., "size": ..., "contentType": ...}, with the
+// content type's parameters removed, so file properties are object specs;
+// FileSpec builds one. Several files under one key become an array for array
+// properties. It returns the converted form and the validation result.
+func (v *Validator) ValidateMultipartForm(form *multipart.Form, spec *Spec) (map[string]any, *ValidationResult) {
+	if form == nil {
+		form = &multipart.Form{}
+	}
+	return v.validateParams(form.Value, form.File, spec)
+}
+
+// FileSpec returns the spec of an uploaded file of at most maxSize bytes,
+// or any size if maxSize is 0, whose content type is one of contentTypes, or
+// any if none are given. A content type may end in "/*" to allow all its
+// subtypes, e.g. "image/*". Make the property required to require the file.
+func FileSpec(maxSize int64, contentTypes ...string) *Spec {
+	size := &Spec{Type: "integer"}
+	if maxSize > 0 {
+		limit := float64(maxSize)
+		size.Max = &limit
+		size.Messages = map[string]string{CodeMax: fmt.Sprintf("file must be at most %d bytes", maxSize)}
+	}
+
+	contentType := &Spec{Type: "string"}
+	if len(contentTypes) > 0 {
+		message := "content type must be one of " + strings.Join(contentTypes, ", ")
+		if slices.ContainsFunc(contentTypes, func(t string) bool { return strings.HasSuffix(t, "/*") }) {
+			alternatives := make([]string, len(contentTypes))
+			for i, t := range contentTypes {
+				if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+					alternatives[i] = regexp.QuoteMeta(strings.ToLower(prefix)) + "/[^/]+"
+				} else {
+					alternatives[i] = regexp.QuoteMeta(strings.ToLower(t))
+				}
+			}
+			pattern := "^(?:" + strings.Join(alternatives, "|") + ")$"
+			contentType.Pattern = &pattern
+			contentType.Messages = map[string]string{CodePattern: message}
+		} else {
+			for _, t := range contentTypes {
+				contentType.Enum = append(contentType.Enum, strings.ToLower(t))
+			}
+			contentType.Messages = map[string]string{CodeEnum: message}
+		}
+	}
+
+	return &Spec{
+		Type: "object",
+		Properties: map[string]*Spec{
+			"filename":    {Type: "string"},
+			"size":        size,
+			"contentType": contentType,
+		},
+	}
+}
+
+// convertFiles adds uploaded files to params as file objects. Several files
+// for a property that isn't an array are reported as errors.
+func (r *ValidationResult) convertFiles(files map[string][]*multipart.FileHeader, spec *Spec, params map[string]any) {
+	for _, key := range slices.Sorted(maps.Keys(files)) {
+		headers := files[key]
+		propSpec := paramSpec(spec, key)
+
+		switch {
+		case propSpec != nil && propSpec.Type == "array", len(headers) > 1 && propSpec == nil:
+			items := make([]any, len(headers))
+			for i, header := range headers {
+				items[i] = fileObject(header)
+			}
+			params[key] = items
+		case len(headers) > 1:
+			r.addError(key, CodeType, fmt.Sprintf("expected a single file, got %d", len(headers)),
+				map[string]any{"expected": propSpec.Type, "actual": "array"})
+		case len(headers) == 1:
+			params[key] = fileObject(headers[0])
+		}
+	}
+}
+
+// fileObject describes an uploaded file
+func fileObject(header *multipart.FileHeader) map[string]any {
+	contentType := header.Header.Get("Content-Type")
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+	return map[string]any{
+		"filename":    header.Filename,
+		"size":        float64(header.Size),
+		"contentType": strings.ToLower(contentType),
+	}
+}
@@ -0,0 +1,163 @@
+package mowgli
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/textproto"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// testFile is a file part of a test form
+type testFile struct {
+	field, filename, contentType string
+	size                         int
+}
+
+// newMultipartForm encodes fields and files as multipart/form-data and
+// parses them back
+func newMultipartForm(t *testing.T, fields map[string][]string, files []testFile) *multipart.Form {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name, values := range fields {
+		for _, value := range values {
+			if err := w.WriteField(name, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, f := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		header.Set("Content-Type", f.contentType)
+		part, err := w.CreatePart(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write(bytes.Repeat([]byte("x"), f.size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	form, err := multipart.NewReader(&buf, w.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("Failed to read form: %v", err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form
+}
+
+func TestValidateMultipartForm(t *testing.T) {
+	spec := &Spec{
+		Type: "object",
+		Properties: map[string]*Spec{
+			"title":       {Type: "string", MinLength: intPtr(1)},
+			"public":      {Type: "boolean"},
+			"avatar":      FileSpec(100, "image/png", "image/jpeg"),
+			"attachments": {Type: "array", Items: FileSpec(0, "image/*", "application/pdf"), MaxLength: intPtr(2)},
+		},
+		Required: []string{"title", "avatar"},
+	}
+
+	tests := []struct {
+		name   string
+		fields map[string][]string
+		files  []testFile
+		want   []string // "path: code"
+	}{
+		{
+			name:   "valid",
+			fields: map[string][]string{"title": {"Holiday"}, "public": {"true"}},
+			files: []testFile{
+				{"avatar", "me.png", "image/png", 50},
+				{"attachments", "a.gif", "image/gif", 10},
+				{"attachments", "b.pdf", "application/pdf; version=1.7", 10},
+			},
+		},
+		{name: "missing file", fields: map[string][]string{"title": {"Holiday"}}, want: []string{"avatar: required"}},
+		{
+			name:   "too large",
+			fields: map[string][]string{"title": {"Holiday"}},
+			files:  []testFile{{"avatar", "me.png", "image/png", 101}},
+			want:   []string{"avatar.size: max"},
+		},
+		{
+			name:   "wrong content type",
+			fields: map[string][]string{"title": {"Holiday"}},
+			files:  []testFile{{"avatar", "me.gif", "image/gif", 10}},
+			want:   []string{"avatar.contentType: enum"},
+		},
+		{
+			name:   "wrong content type in array",
+			fields: map[string][]string{"title": {"Holiday"}},
+			files:  []testFile{{"avatar", "me.png", "image/png", 10}, {"attachments", "a.txt", "text/plain", 10}},
+			want:   []string{"attachments[0].contentType: pattern"},
+		},
+		{
+			name:   "several files for one",
+			fields: map[string][]string{"title": {"Holiday"}},
+			files:  []testFile{{"avatar", "a.png", "image/png", 10}, {"avatar", "b.png", "image/png", 10}},
+			want:   []string{"avatar: required", "avatar: type"},
+		},
+		{
+			name:   "field coercion",
+			fields: map[string][]string{"title": {""}, "public": {"maybe"}},
+			files:  []testFile{{"avatar", "me.png", "image/png", 10}},
+			want:   []string{"public: type", "title: minLength"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, result := ValidateMultipartForm(newMultipartForm(t, tt.fields, tt.files), spec)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+": "+err.Code)
+			}
+			// Properties are validated in no particular order
+			slices.Sort(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+			if result.Valid && params["avatar"] == nil {
+				t.Errorf("expected the avatar in %v", params)
+			}
+		})
+	}
+}
+
+func TestValidateMultipartFormFileObject(t *testing.T) {
+	form := newMultipartForm(t, nil, []testFile{{"doc", "report.pdf", "Application/PDF; charset=binary", 3}})
+	params, result := ValidateMultipartForm(form, &Spec{Type: "object"})
+	want := map[string]any{"doc": map[string]any{"filename": "report.pdf", "size": 3.0, "contentType": "application/pdf"}}
+	if !result.Valid || !reflect.DeepEqual(params, want) {
+		t.Errorf("expected %v, got %v, %v", want, params, result.Errors)
+	}
+
+	_, result = ValidateMultipartForm(nil, &Spec{Type: "object", Required: []string{"doc"}})
+	if result.Valid {
+		t.Error("expected a nil form to be missing its required file")
+	}
+}
+
+func TestFileSpecMessages(t *testing.T) {
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{"avatar": FileSpec(10, "image/*")}}
+	form := newMultipartForm(t, nil, []testFile{{"avatar", "me.txt", "text/plain", 11}})
+	_, result := ValidateMultipartForm(form, spec)
+	messages := map[string]string{}
+	for _, err := range result.Errors {
+		messages[err.Path] = err.Message
+	}
+	want := map[string]string{
+		"avatar.size":        "file must be at most 10 bytes",
+		"avatar.contentType": "content type must be one of image/*",
+	}
+	if !reflect.DeepEqual(messages, want) {
+		t.Errorf("expected %v, got %v", want, messages)
+	}
+}
@@ -0,0 +1,222 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+)
+
+// WithUseNumber decodes JSON numbers as json.Number rather than float64, as
+// json.Decoder.UseNumber does, so integers beyond 2^53 such as 64-bit IDs
+// keep their exact value through validation and into the result's Document.
+// Conditions see such numbers as int64 or float64.
+func WithUseNumber() Option {
+	return func(v *Validator) {
+		v.useNumber = true
+	}
+}
+
+// maxExactInteger is 2^53: a float64 of this magnitude or more may be a
+// rounded integer
+const maxExactInteger = 1 << 53
+
+// numberPrecision is the mantissa size, in bits, used to compare numbers
+// that float64 can't represent exactly
+const numberPrecision = 256
+
+// decodeJSON unmarshals a single JSON document, keeping numbers as
+// json.Number if useNumber is set
+func decodeJSON(data []byte, useNumber bool) (any, error) {
+	var value any
+	if !useNumber {
+		err := json.Unmarshal(data, &value)
+		return value, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after top-level value")
+	}
+	return value, nil
+}
+
+// numberValue returns a numeric value as float64, which may round integers
+// beyond 2^53, and whether it is an integer
+func numberValue(value any) (num float64, isInt bool, ok bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, v == math.Trunc(v) && !math.IsInf(v, 0), true
+	case float32:
+		return float64(v), float64(v) == math.Trunc(float64(v)) && !math.IsInf(float64(v), 0), true
+	case int:
+		return float64(v), true, true
+	case int64:
+		return float64(v), true, true
+	case uint64:
+		return float64(v), true, true
+	case json.Number:
+		exact, ok := bigNumber(v)
+		if !ok {
+			return 0, false, false
+		}
+		num, _ := exact.Float64()
+		return num, exact.IsInt(), true
+	}
+	return 0, false, false
+}
+
+// bigNumber converts a numeric value to a big.Float without losing precision
+func bigNumber(value any) (*big.Float, bool) {
+	if n, ok := value.(json.Number); ok {
+		exact, _, err := big.ParseFloat(string(n), 10, numberPrecision, big.ToNearestEven)
+		return exact, err == nil
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return new(big.Float).SetInt64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return new(big.Float).SetUint64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if math.IsNaN(v.Float()) {
+			return nil, false
+		}
+		return big.NewFloat(v.Float()), true
+	}
+	return nil, false
+}
+
+// compareNumber compares a numeric value, whose float64 approximation is
+// num, with bound. It is exact for integers and json.Number values that
+// float64 can't represent.
+func compareNumber(value any, num, bound float64) int {
+	_, isNumber := value.(json.Number)
+	if isNumber || (math.Abs(num) >= maxExactInteger && !isFloat(value)) {
+		if exact, ok := bigNumber(value); ok {
+			return exact.Cmp(big.NewFloat(bound))
+		}
+	}
+	switch {
+	case num < bound:
+		return -1
+	case num > bound:
+		return 1
+	}
+	return 0
+}
+
+// intBound parses a minInt or maxInt bound, which must be an integer literal
+func intBound(bound json.Number) (*big.Int, error) {
+	limit, ok := new(big.Int).SetString(string(bound), 10)
+	if !ok {
+		return nil, fmt.Errorf("not an integer: %s", bound)
+	}
+	return limit, nil
+}
+
+// compareInt compares a numeric value with an integer bound without
+// converting either to float64
+func compareInt(value any, bound *big.Int) (int, bool) {
+	exact, ok := bigNumber(value)
+	if !ok {
+		return 0, false
+	}
+	return exact.Cmp(new(big.Float).SetInt(bound)), true
+}
+
+// compareIntBounds compares two minInt or maxInt bounds, treating invalid
+// bounds as equal
+func compareIntBounds(a, b json.Number) int {
+	limitA, errA := intBound(a)
+	limitB, errB := intBound(b)
+	if errA != nil || errB != nil {
+		return 0
+	}
+	return limitA.Cmp(limitB)
+}
+
+// checkIntBounds reports minInt and maxInt bounds that aren't integers
+func checkIntBounds(spec *Spec) error {
+	if _, err := intBound(spec.MinInt); spec.MinInt != "" && err != nil {
+		return fmt.Errorf("invalid %s: %w", CodeMinInt, err)
+	}
+	if _, err := intBound(spec.MaxInt); spec.MaxInt != "" && err != nil {
+		return fmt.Errorf("invalid %s: %w", CodeMaxInt, err)
+	}
+	return nil
+}
+
+func isFloat(value any) bool {
+	switch value.(type) {
+	case float64, float32:
+		return true
+	}
+	return false
+}
+
+// numbersEqual compares numeric values whose float64 approximations are
+// equal, exactly if they are integers beyond 2^53
+func numbersEqual(a, b any, num float64) bool {
+	if math.Abs(num) < maxExactInteger || (isFloat(a) && isFloat(b)) {
+		return true
+	}
+	exactA, okA := bigNumber(a)
+	exactB, okB := bigNumber(b)
+	return okA && okB && exactA.Cmp(exactB) == 0
+}
+
+// formatNumber renders a number for error messages, keeping the literal
+// text of json.Number values
+func formatNumber(value any, num float64) string {
+	switch v := value.(type) {
+	case json.Number:
+		return string(v)
+	case int64, uint64:
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprintf("%g", num)
+}
+
+// numberParam is the value reported as an error's "actual" param
+func numberParam(value any, num float64) any {
+	if n, ok := value.(json.Number); ok {
+		return n
+	}
+	return num
+}
+
+// exprValue converts json.Number values in v, at any depth, to int64 or
+// float64 so expressions can compare them
+func exprValue(v any) any {
+	switch val := v.(type) {
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		f, _ := val.Float64()
+		return f
+	case map[string]any:
+		converted := make(map[string]any, len(val))
+		for k, item := range val {
+			converted[k] = exprValue(item)
+		}
+		return converted
+	case []any:
+		converted := make([]any, len(val))
+		for i, item := range val {
+			converted[i] = exprValue(item)
+		}
+		return converted
+	}
+	return v
+}
@@ -0,0 +1,196 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateUseNumber(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "max": 9007199254740992},
+			"price": {"type": "number", "min": 0},
+			"count": {"type": "integer"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		json       string
+		useNumber  bool
+		wantErrors []string
+	}{
+		// As float64, 2^53+1 rounds to the maximum and passes
+		{name: "float64 rounds large integers", json: `{"id": 9007199254740993}`},
+		{name: "exact with UseNumber", json: `{"id": 9007199254740993}`, useNumber: true,
+			wantErrors: []string{"id: integer 9007199254740993 is greater than maximum 9.007199254740992e+15"}},
+		{name: "at maximum", json: `{"id": 9007199254740992}`, useNumber: true},
+		{name: "negative number", json: `{"price": -0.5}`, useNumber: true, wantErrors: []string{"price: number -0.5 is less than minimum 0"}},
+		{name: "fraction is not an integer", json: `{"count": 1.5}`, useNumber: true, wantErrors: []string{"count: expected integer, got float: 1.5"}},
+		{name: "integral float is an integer", json: `{"count": 2.0}`, useNumber: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.useNumber {
+				opts = append(opts, WithUseNumber())
+			}
+			result, err := NewValidator(opts...).ValidateJSON([]byte(tt.json), spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var errors []string
+			for _, e := range result.Errors {
+				errors = append(errors, e.Error())
+			}
+			if !reflect.DeepEqual(errors, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, errors)
+			}
+		})
+	}
+}
+
+func TestValidateJSONNumberValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		spec  string
+		data  any
+		valid bool
+	}{
+		{name: "enum", spec: `{"type": "integer", "enum": [1, 2]}`, data: json.Number("2"), valid: true},
+		{name: "enum mismatch", spec: `{"type": "integer", "enum": [1, 2]}`, data: json.Number("3")},
+		{name: "unique large integers", spec: `{"type": "array", "uniqueItems": true}`, data: []any{json.Number("9007199254740993"), json.Number("9007199254740992")}, valid: true},
+		{name: "duplicate large integers", spec: `{"type": "array", "uniqueItems": true}`, data: []any{json.Number("9007199254740993"), json.Number("9007199254740993")}},
+		{name: "int64 beyond float precision", spec: `{"type": "integer", "max": 9007199254740992}`, data: int64(9007199254740993)},
+		{name: "not a number", spec: `{"type": "number"}`, data: json.Number("abc")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			if result := Validate(tt.data, spec); result.Valid != tt.valid {
+				t.Errorf("expected valid=%v, got %v", tt.valid, result.Errors)
+			}
+		})
+	}
+}
+
+func TestUseNumberConditions(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"items": {"type": "array"}, "note": {"type": "string"}},
+		"conditions": [{"if": "any(items, .qty > 10)", "required": ["note"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	v := NewValidator(WithUseNumber())
+	result, err := v.ValidateJSON([]byte(`{"items": [{"qty": 20}]}`), spec)
+	if err != nil {
+		t.Fatalf("ValidateJSON failed: %v", err)
+	}
+	if result.Valid || result.Errors[0].Code != CodeRequired {
+		t.Errorf("expected condition to see the number, got %v", result.Errors)
+	}
+
+	if _, err := v.ValidateJSON([]byte(`{} {}`), spec); err == nil || !strings.Contains(err.Error(), "after top-level value") {
+		t.Errorf("expected trailing data error, got %v", err)
+	}
+}
+
+func TestDecodeUseNumber(t *testing.T) {
+	type order struct {
+		ID   uint64 `json:"id"`
+		Code string `json:"code" mowgli:"transform=upper"`
+	}
+
+	// The transformed document is re-encoded, which must not round the ID
+	body := `{"id": 18446744073709551615, "code": "ab"}`
+	result, value, err := DecodeAndValidate[order](strings.NewReader(body), DecodeUseNumber())
+	if err != nil {
+		t.Fatalf("DecodeAndValidate failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected valid, got %v", result.Errors)
+	}
+	if value.ID != 18446744073709551615 || value.Code != "AB" {
+		t.Errorf("unexpected value: %+v", value)
+	}
+}
+
+func TestValidateIntBounds(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "minInt": 1, "maxInt": 18446744073709551615},
+			"offset": {"type": "integer", "minInt": "-9223372036854775808", "maxInt": "9223372036854775807"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       map[string]any
+		wantErrors []string
+	}{
+		{name: "uint64 maximum", data: map[string]any{"id": uint64(18446744073709551615)}},
+		{name: "int64 extremes", data: map[string]any{"offset": int64(-9223372036854775808)}},
+		{name: "below minimum", data: map[string]any{"id": 0}, wantErrors: []string{"id: integer 0 is less than minimum 1"}},
+		{name: "beyond uint64", data: map[string]any{"id": json.Number("18446744073709551616")},
+			wantErrors: []string{"id: integer 18446744073709551616 is greater than maximum 18446744073709551615"}},
+		{name: "beyond int64", data: map[string]any{"offset": json.Number("9223372036854775808")},
+			wantErrors: []string{"offset: integer 9223372036854775808 is greater than maximum 9223372036854775807"}},
+		{name: "float at the rounded maximum", data: map[string]any{"offset": float64(9223372036854775807)},
+			wantErrors: []string{"offset: integer 9.223372036854776e+18 is greater than maximum 9223372036854775807"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var errors []string
+			for _, e := range result.Errors {
+				errors = append(errors, e.Error())
+			}
+			if !reflect.DeepEqual(errors, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, errors)
+			}
+		})
+	}
+
+	if _, err := Compile(&Spec{Type: "integer", MaxInt: "1.5"}); err == nil || !strings.Contains(err.Error(), "invalid maxInt") {
+		t.Errorf("expected invalid maxInt error, got %v", err)
+	}
+}
+
+func TestMergeIntBounds(t *testing.T) {
+	base := &Spec{Type: "integer", MinInt: "10", MaxInt: "18446744073709551615"}
+	override := &Spec{Type: "integer", MinInt: "20", MaxInt: "18446744073709551614"}
+
+	union, err := MergeSpecsWith(base, override, MergeOptions{Strategy: MergeUnion})
+	if err != nil {
+		t.Fatalf("MergeSpecsWith failed: %v", err)
+	}
+	if union.MinInt != "10" || union.MaxInt != "18446744073709551615" {
+		t.Errorf("union: got minInt %s, maxInt %s", union.MinInt, union.MaxInt)
+	}
+	intersect, err := MergeSpecsWith(base, override, MergeOptions{Strategy: MergeIntersect})
+	if err != nil {
+		t.Fatalf("MergeSpecsWith failed: %v", err)
+	}
+	if intersect.MinInt != "20" || intersect.MaxInt != "18446744073709551614" {
+		t.Errorf("intersect: got minInt %s, maxInt %s", intersect.MinInt, intersect.MaxInt)
+	}
+}
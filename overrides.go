@@ -0,0 +1,171 @@
+package mowgli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pathSelector matches document paths relative to the value of the spec
+// declaring it, e.g. "items[*].price" or "**.email"
+type pathSelector []string
+
+// parsePathSelector parses a selector into segments: property names, "*"
+// for any property, "[*]" for any item, "[2]" for an item and "**" for any
+// number of properties and items, including none
+func parsePathSelector(selector string) (pathSelector, error) {
+	segments := pathSegments(selector)
+	for _, segment := range segments {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("invalid selector %q: empty segment", selector)
+		case strings.HasPrefix(segment, "["):
+			index := strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")
+			if !strings.HasSuffix(segment, "]") || (index != "*" && !isIndex(index)) {
+				return nil, fmt.Errorf("invalid selector %q: malformed index %s", selector, segment)
+			}
+		case segment != "*" && segment != "**" && strings.Contains(segment, "*"):
+			return nil, fmt.Errorf("invalid selector %q: wildcard in %s must be a whole segment", selector, segment)
+		}
+	}
+	return segments, nil
+}
+
+// isIndex reports whether s is an array index
+func isIndex(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil && !strings.HasPrefix(s, "-") && !strings.HasPrefix(s, "+")
+}
+
+// pathSegments splits a path into its property names and bracketed
+// indexes, e.g. "items[0].price" into "items", "[0]" and "price"
+func pathSegments(path string) []string {
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		key, rest, indexed := strings.Cut(part, "[")
+		if key != "" || !indexed {
+			segments = append(segments, key)
+		}
+		for indexed {
+			var index string
+			index, rest, indexed = strings.Cut(rest, "[")
+			segments = append(segments, "["+index)
+		}
+	}
+	return segments
+}
+
+// match reports whether the selector matches the path segments
+func (s pathSelector) match(segments []string) bool {
+	if len(s) == 0 {
+		return len(segments) == 0
+	}
+	if s[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if s[1:].match(segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	segment := segments[0]
+	switch s[0] {
+	case "*":
+		if strings.HasPrefix(segment, "[") {
+			return false
+		}
+	case "[*]":
+		if !strings.HasPrefix(segment, "[") {
+			return false
+		}
+	default:
+		if s[0] != segment {
+			return false
+		}
+	}
+	return s[1:].match(segments[1:])
+}
+
+// override is a spec applied to the values its selector matches
+type override struct {
+	selector pathSelector
+	spec     *Spec
+}
+
+// overrideSection is the "overrides" section of a spec, anchored at the
+// path of the value the spec validates
+type overrideSection struct {
+	base      string
+	overrides []override
+}
+
+// overrideScope holds the "overrides" sections of the specs enclosing the
+// one being walked, innermost last
+type overrideScope []overrideSection
+
+// push enters a spec's overrides, returning a func that leaves them. The
+// overrides are applied in the order of their selectors.
+func (s *overrideScope) push(base string, overrides map[string]*Spec) (func(), error) {
+	section := overrideSection{base: base}
+	for _, selector := range slices.Sorted(maps.Keys(overrides)) {
+		parsed, err := parsePathSelector(selector)
+		if err != nil {
+			return func() {}, err
+		}
+		section.overrides = append(section.overrides, override{selector: parsed, spec: overrides[selector]})
+	}
+	*s = append(*s, section)
+	return func() { *s = (*s)[:len(*s)-1] }, nil
+}
+
+// apply returns spec with the overrides matching path merged in, outermost
+// first so that inner overrides take precedence
+func (s overrideScope) apply(r *ValidationResult, path string, spec *Spec) *Spec {
+	for _, section := range s {
+		relative, ok := relativePath(section.base, path)
+		if !ok {
+			continue
+		}
+		segments := pathSegments(relative)
+		for _, o := range section.overrides {
+			if o.selector.match(segments) {
+				spec = r.mergeSpecs(spec, o.spec)
+			}
+		}
+	}
+	return spec
+}
+
+// relativePath returns path relative to base, e.g. "[0].price" for
+// "items[0].price" in "items", if path is inside base
+func relativePath(base, path string) (string, bool) {
+	if base == "" {
+		return path, path != ""
+	}
+	if len(path) <= len(base) || !strings.HasPrefix(path, base) {
+		return "", false
+	}
+	switch path[len(base)] {
+	case '.':
+		return path[len(base)+1:], true
+	case '[':
+		return path[len(base):], true
+	}
+	return "", false
+}
+
+// checkOverrides reports selectors of an "overrides" section that don't
+// parse
+func checkOverrides(overrides map[string]*Spec) error {
+	for _, selector := range slices.Sorted(maps.Keys(overrides)) {
+		if _, err := parsePathSelector(selector); err != nil {
+			return err
+		}
+	}
+	return nil
+}
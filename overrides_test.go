@@ -0,0 +1,143 @@
+package mowgli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestOverrides(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string"},
+			"items": {"type": "array", "items": {"type": "object", "properties": {
+				"price": {"type": "number"},
+				"tags": {"type": "array", "items": {"type": "string"}}
+			}}},
+			"owner": {"type": "object", "properties": {
+				"email": {"type": "string"},
+				"contact": {"type": "object", "properties": {"email": {"type": "string"}}},
+				"settings": {"type": "object", "additionalProperties": {"type": "integer"}, "overrides": {
+					"*": {"max": 10},
+					"volume": {"max": 11}
+				}}
+			}}
+		},
+		"overrides": {
+			"items[*].price": {"min": 0},
+			"items[0].tags[*]": {"minLength": 2},
+			"**.email": {"format": "email"},
+			"owner": {"required": ["email"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string
+	}{
+		{
+			name:   "valid",
+			data:   `{"email": "a@example.com", "items": [{"price": 5, "tags": ["ab"]}], "owner": {"email": "b@example.com"}}`,
+			errors: nil,
+		},
+		{
+			name:   "array items",
+			data:   `{"items": [{"price": 1}, {"price": -1}], "owner": {"email": "b@example.com"}}`,
+			errors: []string{"items[1].price min"},
+		},
+		{
+			name:   "specific index",
+			data:   `{"items": [{"tags": ["a"]}, {"tags": ["b"]}], "owner": {"email": "b@example.com"}}`,
+			errors: []string{"items[0].tags[0] minLength"},
+		},
+		{
+			name:   "any depth",
+			data:   `{"email": "nope", "owner": {"email": "b", "contact": {"email": "c"}}}`,
+			errors: []string{"email format", "owner.contact.email format", "owner.email format"},
+		},
+		{
+			name:   "object keywords",
+			data:   `{"owner": {}}`,
+			errors: []string{"owner.email required"},
+		},
+		{
+			name:   "nested sections take precedence",
+			data:   `{"owner": {"email": "b@example.com", "settings": {"brightness": 11, "volume": 11}}}`,
+			errors: []string{"owner.settings.brightness max"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateJSON([]byte(tt.data), spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Path+" "+e.Code)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.errors) {
+				t.Errorf("expected errors %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestPathSelectors(t *testing.T) {
+	tests := []struct {
+		selector string
+		path     string
+		match    bool
+	}{
+		{"price", "price", true},
+		{"price", "cost", false},
+		{"*", "price", true},
+		{"*", "[0]", false},
+		{"[*]", "[3]", true},
+		{"[*]", "price", false},
+		{"[1]", "[1]", true},
+		{"[1]", "[10]", false},
+		{"items[*].price", "items[2].price", true},
+		{"items[*].price", "items[2].tax.price", false},
+		{"**.email", "email", true},
+		{"**.email", "a[0].b.email", true},
+		{"**.email", "email.primary", false},
+		{"a.**", "a.b[1].c", true},
+		{"a.**.c", "a.c", true},
+		{"matrix[*][*]", "matrix[0][1]", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector+" "+tt.path, func(t *testing.T) {
+			selector, err := parsePathSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("parsePathSelector failed: %v", err)
+			}
+			if got := selector.match(pathSegments(tt.path)); got != tt.match {
+				t.Errorf("expected match %v, got %v", tt.match, got)
+			}
+		})
+	}
+}
+
+func TestInvalidOverrides(t *testing.T) {
+	for _, selector := range []string{"", "items[", "items[x]", "it*ms", "a..b"} {
+		t.Run(selector, func(t *testing.T) {
+			spec := &Spec{Type: "object", Overrides: map[string]*Spec{selector: {MinLength: intPtr(1)}}}
+			result := Validate(map[string]any{}, spec)
+			if len(result.Errors) != 1 || result.Errors[0].Code != CodeInvalidSpec || !strings.Contains(result.Errors[0].Message, "invalid selector") {
+				t.Errorf("expected an invalid spec error, got %v", result.Errors)
+			}
+			if _, err := Compile(spec); err == nil {
+				t.Error("expected Compile to reject the selector")
+			}
+		})
+	}
+}
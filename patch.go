@@ -0,0 +1,357 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is a JSON Patch (RFC 6902) operation
+type PatchOperation struct {
+	Op    string `json:"op"` // add, remove, replace, move, copy or test
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`  // Source location for move and copy
+	Value any    `json:"value,omitempty"` // Value for add, replace and test
+}
+
+// JSONPatch is a JSON Patch document, a list of operations applied in order
+type JSONPatch []PatchOperation
+
+// ParsePatch parses a JSON Patch document
+func ParsePatch(data []byte) (JSONPatch, error) {
+	var patch JSONPatch
+	if err := json.Unmarshal(data, &patch); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	return patch, nil
+}
+
+// Apply applies the patch to a copy of document and returns the result. The
+// document itself is not modified.
+func (p JSONPatch) Apply(document any) (any, error) {
+	doc := deepCopy(document)
+	for i, op := range p {
+		var err error
+		if doc, err = op.apply(doc); err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return doc, nil
+}
+
+// PatchError is a validation error in a patched document
+type PatchError struct {
+	*ValidationError
+	Operation int // Index of the patch operation that introduced the error, or -1 if the original document had it
+}
+
+// PatchResult is the result of validating a patched document
+type PatchResult struct {
+	Valid    bool
+	Errors   []*PatchError
+	Document any // The patched document
+}
+
+// ValidatePatch applies patch to original and validates the result. See
+// Validator.ValidatePatch.
+func ValidatePatch(original any, patch JSONPatch, spec *Spec) (*PatchResult, error) {
+	return defaultValidator.ValidatePatch(original, patch, spec)
+}
+
+// ValidatePatch applies patch to a copy of original and validates the
+// result, attributing each error to the operation that introduced it so an
+// update API can say exactly which part of a patch is at fault. Errors the
+// original document already had are attributed to no operation. An error is
+// returned if the patch can't be applied, including when a test operation
+// fails.
+func (v *Validator) ValidatePatch(original any, patch JSONPatch, spec *Spec) (*PatchResult, error) {
+	// Keep the document after every operation to find where errors appear
+	states := make([]any, len(patch)+1)
+	states[0] = deepCopy(original)
+	for i, op := range patch {
+		next, err := op.apply(deepCopy(states[i]))
+		if err != nil {
+			return nil, fmt.Errorf("patch operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+		states[i+1] = next
+	}
+
+	final := v.Validate(states[len(patch)], spec)
+	result := &PatchResult{Valid: final.Valid, Errors: []*PatchError{}, Document: states[len(patch)]}
+	if final.Valid {
+		return result, nil
+	}
+
+	// An error is introduced by the last operation before which it was absent
+	present := make([]map[errorKey]bool, len(states))
+	present[len(patch)] = errorKeys(final)
+	for _, err := range final.Errors {
+		operation := -1
+		for i := len(patch) - 1; i >= 0; i-- {
+			if present[i] == nil {
+				present[i] = errorKeys(v.Validate(states[i], spec))
+			}
+			if !present[i][errorKey{err.Path, err.Code}] {
+				operation = i
+				break
+			}
+		}
+		result.Errors = append(result.Errors, &PatchError{ValidationError: err, Operation: operation})
+	}
+	return result, nil
+}
+
+// errorKey identifies an error across validations of different documents
+type errorKey struct {
+	path, code string
+}
+
+func errorKeys(result *ValidationResult) map[errorKey]bool {
+	keys := make(map[errorKey]bool, len(result.Errors))
+	for _, err := range result.Errors {
+		keys[errorKey{err.Path, err.Code}] = true
+	}
+	return keys
+}
+
+func (op PatchOperation) apply(doc any) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return pointerAdd(doc, path, deepCopy(op.Value), false)
+	case "replace":
+		return pointerAdd(doc, path, deepCopy(op.Value), true)
+	case "remove":
+		doc, _, err := pointerRemove(doc, path)
+		return doc, err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if op.Op == "move" {
+			if isPointerPrefix(from, path) && len(from) < len(path) {
+				return nil, fmt.Errorf("cannot move %s into itself", op.From)
+			}
+			if doc, value, err = pointerRemove(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = pointerGet(doc, from); err != nil {
+				return nil, err
+			}
+			value = deepCopy(value)
+		}
+		return pointerAdd(doc, path, value, false)
+	case "test":
+		value, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, op.Value) {
+			return nil, fmt.Errorf("test failed: value is %v, expected %v", value, op.Value)
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into unescaped segments
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+	segments := strings.Split(pointer[1:], "/")
+	for i, segment := range segments {
+		segments[i] = pointerUnescaper.Replace(segment)
+	}
+	return segments, nil
+}
+
+var pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func isPointerPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// arrayIndex parses an array index segment; "-" (the end) is allowed when
+// appending
+func arrayIndex(segment string, length int, appending bool) (int, error) {
+	if appending && segment == "-" {
+		return length, nil
+	}
+	index, err := strconv.Atoi(segment)
+	if err != nil || index < 0 || (segment != "0" && strings.HasPrefix(segment, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", segment)
+	}
+	limit := length - 1
+	if appending {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+	return index, nil
+}
+
+func pointerGet(doc any, path []string) (any, error) {
+	for _, segment := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", segment)
+			}
+			doc = value
+		case []any:
+			index, err := arrayIndex(segment, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("cannot traverse %T with %q", doc, segment)
+		}
+	}
+	return doc, nil
+}
+
+// pointerAdd adds value at path, or replaces the existing value if replace
+// is set, and returns the updated document
+func pointerAdd(doc any, path []string, value any, replace bool) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		if _, exists := node[last]; replace && !exists {
+			return nil, fmt.Errorf("member %q not found", last)
+		}
+		node[last] = value
+		return doc, nil
+	case []any:
+		index, err := arrayIndex(last, len(node), !replace)
+		if err != nil {
+			return nil, err
+		}
+		if replace {
+			node[index] = value
+			return doc, nil
+		}
+		node = append(node[:index], append([]any{value}, node[index:]...)...)
+		return pointerAdd(doc, path[:len(path)-1], node, true)
+	default:
+		return nil, fmt.Errorf("cannot add to %T", parent)
+	}
+}
+
+// pointerRemove removes the value at path, returning the updated document
+// and the removed value
+func pointerRemove(doc any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the whole document")
+	}
+
+	parent, err := pointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+
+	switch node := parent.(type) {
+	case map[string]any:
+		value, exists := node[last]
+		if !exists {
+			return nil, nil, fmt.Errorf("member %q not found", last)
+		}
+		delete(node, last)
+		return doc, value, nil
+	case []any:
+		index, err := arrayIndex(last, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		value := node[index]
+		node = append(node[:index:index], node[index+1:]...)
+		doc, err = pointerAdd(doc, path[:len(path)-1], node, true)
+		return doc, value, err
+	default:
+		return nil, nil, fmt.Errorf("cannot remove from %T", parent)
+	}
+}
+
+// jsonEqual compares JSON values deeply, treating numbers of any type as equal
+// if their values are
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if bv, ok := b[k]; !ok || !jsonEqual(v, bv) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return valuesEqual(a, b)
+	}
+}
+
+// deepCopy copies the maps and slices of a JSON value
+func deepCopy(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		copied := make(map[string]any, len(v))
+		for k, item := range v {
+			copied[k] = deepCopy(item)
+		}
+		return copied
+	case []any:
+		copied := make([]any, len(v))
+		for i, item := range v {
+			copied[i] = deepCopy(item)
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,125 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONPatchApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		docJSON  string
+		patch    string
+		wantJSON string
+		wantErr  string
+	}{
+		{name: "add member", docJSON: `{"a": 1}`, patch: `[{"op": "add", "path": "/b", "value": 2}]`, wantJSON: `{"a": 1, "b": 2}`},
+		{name: "add replaces member", docJSON: `{"a": 1}`, patch: `[{"op": "add", "path": "/a", "value": 2}]`, wantJSON: `{"a": 2}`},
+		{name: "insert into array", docJSON: `{"a": [1, 3]}`, patch: `[{"op": "add", "path": "/a/1", "value": 2}]`, wantJSON: `{"a": [1, 2, 3]}`},
+		{name: "append to array", docJSON: `{"a": [1]}`, patch: `[{"op": "add", "path": "/a/-", "value": 2}]`, wantJSON: `{"a": [1, 2]}`},
+		{name: "remove array item", docJSON: `[1, 2, 3]`, patch: `[{"op": "remove", "path": "/1"}]`, wantJSON: `[1, 3]`},
+		{name: "replace root", docJSON: `{"a": 1}`, patch: `[{"op": "replace", "path": "", "value": [1]}]`, wantJSON: `[1]`},
+		{name: "move", docJSON: `{"a": {"b": 1}, "c": {}}`, patch: `[{"op": "move", "from": "/a/b", "path": "/c/d"}]`, wantJSON: `{"a": {}, "c": {"d": 1}}`},
+		{name: "copy", docJSON: `{"a": [1]}`, patch: `[{"op": "copy", "from": "/a", "path": "/b"}, {"op": "add", "path": "/b/-", "value": 2}]`, wantJSON: `{"a": [1], "b": [1, 2]}`},
+		{name: "escaped pointer", docJSON: `{"a/b": 1, "m~n": 2}`, patch: `[{"op": "remove", "path": "/a~1b"}, {"op": "replace", "path": "/m~0n", "value": 3}]`, wantJSON: `{"m~n": 3}`},
+		{name: "test passes", docJSON: `{"a": [1, {"b": "c"}]}`, patch: `[{"op": "test", "path": "/a", "value": [1, {"b": "c"}]}]`, wantJSON: `{"a": [1, {"b": "c"}]}`},
+		{name: "test fails", docJSON: `{"a": 1}`, patch: `[{"op": "test", "path": "/a", "value": 2}]`, wantErr: "patch operation 0 (test /a): test failed"},
+		{name: "replace missing member", docJSON: `{}`, patch: `[{"op": "replace", "path": "/a", "value": 1}]`, wantErr: `member "a" not found`},
+		{name: "add to missing parent", docJSON: `{}`, patch: `[{"op": "add", "path": "/a/b", "value": 1}]`, wantErr: `member "a" not found`},
+		{name: "index out of range", docJSON: `[1]`, patch: `[{"op": "add", "path": "/2", "value": 1}]`, wantErr: "out of range"},
+		{name: "leading zero index", docJSON: `[1, 2]`, patch: `[{"op": "remove", "path": "/01"}]`, wantErr: "invalid array index"},
+		{name: "move into itself", docJSON: `{"a": {}}`, patch: `[{"op": "move", "from": "/a", "path": "/a/b"}]`, wantErr: "into itself"},
+		{name: "unknown op", docJSON: `{}`, patch: `[{"op": "frob", "path": "/a"}]`, wantErr: "unknown operation"},
+		{name: "invalid pointer", docJSON: `{}`, patch: `[{"op": "add", "path": "a", "value": 1}]`, wantErr: "must start with /"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc any
+			json.Unmarshal([]byte(tt.docJSON), &doc)
+			before, _ := json.Marshal(doc)
+			patch, err := ParsePatch([]byte(tt.patch))
+			if err != nil {
+				t.Fatalf("ParsePatch failed: %v", err)
+			}
+
+			got, err := patch.Apply(doc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+
+			var want any
+			json.Unmarshal([]byte(tt.wantJSON), &want)
+			if !jsonEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				t.Errorf("expected %s, got %s", tt.wantJSON, gotJSON)
+			}
+			if after, _ := json.Marshal(doc); string(after) != string(before) {
+				t.Errorf("original document was modified: %s", after)
+			}
+		})
+	}
+}
+
+func TestValidatePatch(t *testing.T) {
+	spec, _ := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"age": {"type": "integer", "min": 0},
+			"country": {"type": "string"},
+			"zip": {"type": "string"},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxLength": 2}
+		},
+		"required": ["name"],
+		"conditions": [{"if": "country == \"US\"", "then": {"zip": {"pattern": "^[0-9]{5}$"}}}]
+	}`)
+	original := map[string]any{"name": "Ann", "age": float64(-1), "zip": "ABC", "tags": []any{"a"}}
+
+	patch, _ := ParsePatch([]byte(`[
+		{"op": "remove", "path": "/name"},
+		{"op": "add", "path": "/tags/-", "value": "b"},
+		{"op": "add", "path": "/country", "value": "US"},
+		{"op": "add", "path": "/tags/-", "value": 3}
+	]`))
+
+	result, err := ValidatePatch(original, patch, spec)
+	if err != nil {
+		t.Fatalf("ValidatePatch failed: %v", err)
+	}
+	if result.Valid {
+		t.Fatal("expected patched document to be invalid")
+	}
+
+	want := map[string]int{
+		"name/required":  0,  // removed by the first operation
+		"age/min":        -1, // already invalid
+		"zip/pattern":    2,  // the condition applies once country is set
+		"tags/maxLength": 3,
+		"tags[2]/type":   3,
+	}
+	if len(result.Errors) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(result.Errors), result.Errors)
+	}
+	for _, e := range result.Errors {
+		key := e.Path + "/" + e.Code
+		if op, ok := want[key]; !ok || op != e.Operation {
+			t.Errorf("%s: expected operation %d, got %d", key, want[key], e.Operation)
+		}
+	}
+
+	if _, err := ValidatePatch(original, JSONPatch{{Op: "test", Path: "/name", Value: "Bob"}}, spec); err == nil {
+		t.Error("expected failing test operation to return an error")
+	}
+	valid, err := ValidatePatch(original, JSONPatch{{Op: "replace", Path: "/age", Value: 30}}, spec)
+	if err != nil || !valid.Valid || len(valid.Errors) != 0 {
+		t.Errorf("expected a patch fixing the document to validate, got %+v %v", valid, err)
+	}
+}
@@ -0,0 +1,217 @@
+package mowgli
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape sequences used by WritePretty
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+)
+
+// PrettyConfig configures WritePretty
+type PrettyConfig struct {
+	File    string  // Name of the document shown with each error, e.g. "config.yaml"
+	Locate  Locator // Locates errors in the source (default JSONLocator of the source)
+	Color   bool    // Highlight with ANSI colors, e.g. when writing to a terminal
+	Context int     // Lines of source shown before each offending line (default 0)
+}
+
+// WritePretty writes the result's errors and warnings for a person to read:
+// each with its code and message, the offending line of source with a
+// caret under the bad value and the violated constraint, e.g.
+//
+//	error[max]: port: integer 70000 is greater than maximum 65535
+//	 --> config.json:2:3
+//	  |
+//	2 |   "port": 70000,
+//	  |           ^^^^^ max 65535
+//
+// Errors are written in the order of the source, then warnings. Errors
+// that can't be located, e.g. without source, come last, without a
+// snippet.
+func WritePretty(w io.Writer, source []byte, result *ValidationResult, config PrettyConfig) error {
+	if config.Locate == nil && source != nil {
+		config.Locate = JSONLocator(source)
+	}
+	p := &prettyPrinter{
+		config: config,
+		lines:  strings.Split(strings.TrimSuffix(string(source), "\n"), "\n"),
+		root:   result.root,
+	}
+	p.writeAll("error", ansiRed, result.Errors)
+	p.writeAll("warning", ansiYellow, result.Warnings)
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+// prettyError is an error with its location in the source
+type prettyError struct {
+	err          *ValidationError
+	line, column int // 0 if the error isn't located
+}
+
+// prettyPrinter renders errors for WritePretty
+type prettyPrinter struct {
+	config PrettyConfig
+	lines  []string
+	root   any // Validated document, to tell which error paths exist
+	buf    bytes.Buffer
+}
+
+// paint wraps text in the ANSI style if colors are enabled
+func (p *prettyPrinter) paint(style, text string) string {
+	if !p.config.Color || text == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
+
+// writeAll writes errs at the given level in the order of the source, with
+// errors that can't be located last
+func (p *prettyPrinter) writeAll(level, color string, errs []*ValidationError) {
+	located := make([]prettyError, len(errs))
+	for i, err := range errs {
+		located[i].err = err
+		if p.config.Locate == nil {
+			continue
+		}
+		if line, column, ok := p.config.Locate(err.Path); ok && line >= 1 && line <= len(p.lines) {
+			located[i].line, located[i].column = line, column
+		}
+	}
+	slices.SortStableFunc(located, func(a, b prettyError) int {
+		if (a.line == 0) != (b.line == 0) {
+			return cmp.Compare(b.line, a.line)
+		}
+		return cmp.Or(cmp.Compare(a.line, b.line), cmp.Compare(a.column, b.column))
+	})
+	for _, e := range located {
+		p.write(level, color, e)
+	}
+}
+
+// write writes one error at the given level
+func (p *prettyPrinter) write(level, color string, e prettyError) {
+	w, err, line, column := &p.buf, e.err, e.line, e.column
+	fmt.Fprintf(w, "%s%s\n", p.paint(ansiBold+color, level+"["+err.Code+"]"), p.paint(ansiBold, ": "+err.Error()))
+
+	if line == 0 {
+		if p.config.File != "" {
+			fmt.Fprintf(w, "  %s %s\n\n", p.paint(ansiBlue, "-->"), p.config.File)
+		} else {
+			fmt.Fprintln(w)
+		}
+		return
+	}
+
+	file := p.config.File
+	if file == "" {
+		file = "<input>"
+	}
+	first := max(line-p.config.Context, 1)
+	width := len(strconv.Itoa(line))
+	gutter := strings.Repeat(" ", width)
+	fmt.Fprintf(w, "%s%s %s:%d:%d\n", gutter, p.paint(ansiBlue, "-->"), file, line, column)
+	fmt.Fprintf(w, "%s %s\n", gutter, p.paint(ansiBlue, "|"))
+	for n := first; n <= line; n++ {
+		fmt.Fprintf(w, "%s %s\n", p.paint(ansiBlue, fmt.Sprintf("%*d |", width, n)), p.lines[n-1])
+	}
+
+	text := []rune(p.lines[line-1])
+	start, end := tokenSpan(text, column-1, p.exists(err.Path))
+	label := constraint(err)
+	fmt.Fprintf(w, "%s %s %s%s\n\n", gutter, p.paint(ansiBlue, "|"),
+		strings.Repeat(" ", start), strings.TrimRight(p.paint(color, strings.Repeat("^", end-start)+" "+label), " "))
+}
+
+// exists reports whether path is in the validated document, as opposed to
+// e.g. the path of a missing required property, which is located at an
+// ancestor
+func (p *prettyPrinter) exists(path string) bool {
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return false
+	}
+	_, err = pointerGet(p.root, segments)
+	return err == nil
+}
+
+// tokenSpan returns the runes of line to underline for the value starting
+// at start. A property located at its key is underlined at its value if
+// the value follows on the same line and value is set.
+func tokenSpan(line []rune, start int, value bool) (int, int) {
+	start = min(max(start, 0), len(line))
+	end := scanToken(line, start, ":,]} \t")
+	if value && end < len(line) && line[end] == ':' {
+		next := end + 1
+		for next < len(line) && (line[next] == ' ' || line[next] == '\t') {
+			next++
+		}
+		if next < len(line) {
+			start = next
+			end = scanToken(line, start, ",]}")
+		}
+	}
+	return start, max(end, start+1)
+}
+
+// scanToken returns the end of the token at start: a quoted string, an
+// opening bracket or a run of runes up to one of stops, without trailing
+// whitespace
+func scanToken(line []rune, start int, stops string) int {
+	if start >= len(line) {
+		return start
+	}
+	switch quote := line[start]; quote {
+	case '"', '\'':
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\\' && quote == '"' {
+				i++
+			} else if line[i] == quote {
+				return i + 1
+			}
+		}
+		return len(line)
+	case '{', '[':
+		return start + 1
+	}
+	end := start
+	for end < len(line) && !strings.ContainsRune(stops, line[end]) {
+		end++
+	}
+	for end > start+1 && (line[end-1] == ' ' || line[end-1] == '\t') {
+		end--
+	}
+	return end
+}
+
+// constraint describes the constraint an error violated, e.g. "max 65535"
+// or "type integer"
+func constraint(err *ValidationError) string {
+	for _, param := range []string{"limit", "expected", "pattern", "format", "allowed", "condition", "assert"} {
+		if value, ok := err.Params[param]; ok {
+			return err.Code + " " + truncateRunes(fmt.Sprint(value), 60)
+		}
+	}
+	return err.Code
+}
+
+// truncateRunes shortens s to n runes, marking the cut with an ellipsis
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
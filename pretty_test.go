@@ -0,0 +1,116 @@
+package mowgli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWritePretty(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"port": {"type": "integer", "max": 65535},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+			"legacy": {"type": "string", "deprecated": true}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	jsonSource := []byte("{\n  \"port\": 70000,\n  \"tags\": [\"a\", \"zzz\"],\n  \"legacy\": \"x\"\n}\n")
+	yamlSource := []byte("name: api\nport: 70000\n")
+	yamlDocument := []byte(`{"name": "api", "port": 70000}`)
+
+	tests := []struct {
+		name     string
+		document []byte
+		source   []byte
+		config   PrettyConfig
+		expected string
+	}{
+		{
+			name:     "json",
+			document: jsonSource,
+			source:   jsonSource,
+			config:   PrettyConfig{File: "config.json"},
+			expected: `error[required]: name: required field is missing
+ --> config.json:1:1
+  |
+1 | {
+  | ^ required
+
+error[max]: port: integer 70000 is greater than maximum 65535
+ --> config.json:2:3
+  |
+2 |   "port": 70000,
+  |           ^^^^^ max 65535
+
+error[enum]: tags[1]: value not in enum: zzz (allowed: [a b])
+ --> config.json:3:17
+  |
+3 |   "tags": ["a", "zzz"],
+  |                 ^^^^^ enum [a b]
+
+warning[deprecated]: legacy: field is deprecated
+ --> config.json:4:3
+  |
+4 |   "legacy": "x"
+  |             ^^^ deprecated
+
+`,
+		},
+		{
+			name:     "yaml with context",
+			document: yamlDocument,
+			source:   yamlSource,
+			config:   PrettyConfig{File: "config.yaml", Locate: YAMLLocator(yamlSource), Context: 1},
+			expected: `error[max]: port: integer 70000 is greater than maximum 65535
+ --> config.yaml:2:1
+  |
+1 | name: api
+2 | port: 70000
+  |       ^^^^^ max 65535
+
+`,
+		},
+		{
+			name:     "without source",
+			document: yamlDocument,
+			config:   PrettyConfig{File: "config.yaml"},
+			expected: `error[max]: port: integer 70000 is greater than maximum 65535
+  --> config.yaml
+
+`,
+		},
+		{
+			name:     "color",
+			document: yamlDocument,
+			source:   yamlSource,
+			config:   PrettyConfig{Locate: YAMLLocator(yamlSource), Color: true},
+			expected: "\x1b[1m\x1b[31merror[max]\x1b[0m\x1b[1m: port: integer 70000 is greater than maximum 65535\x1b[0m\n" +
+				" \x1b[34m-->\x1b[0m <input>:2:1\n" +
+				"  \x1b[34m|\x1b[0m\n" +
+				"\x1b[34m2 |\x1b[0m port: 70000\n" +
+				"  \x1b[34m|\x1b[0m       \x1b[31m^^^^^ max 65535\x1b[0m\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateJSON(tt.document, spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := WritePretty(&buf, tt.source, result, tt.config); err != nil {
+				t.Fatalf("WritePretty failed: %v", err)
+			}
+			if buf.String() != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, buf.String())
+			}
+		})
+	}
+}
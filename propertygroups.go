@@ -0,0 +1,60 @@
+package mowgli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// validatePropertyGroups checks that at least one of the spec's anyRequired
+// properties, and exactly one of its oneRequired properties, is present. As
+// with required, a property set to null counts as present.
+func (r *ValidationResult) validatePropertyGroups(path string, obj map[string]any, spec *Spec) {
+	if len(spec.AnyRequired) > 0 && len(presentProperties(obj, spec.AnyRequired)) == 0 {
+		r.addError(path, CodeAnyRequired, fmt.Sprintf("at least one of %s is required", strings.Join(spec.AnyRequired, ", ")),
+			map[string]any{"properties": spec.AnyRequired})
+	}
+
+	if len(spec.OneRequired) > 0 {
+		switch present := presentProperties(obj, spec.OneRequired); len(present) {
+		case 0:
+			r.addError(path, CodeOneRequired, fmt.Sprintf("exactly one of %s is required", strings.Join(spec.OneRequired, ", ")),
+				map[string]any{"properties": spec.OneRequired, "present": present})
+		case 1:
+		default:
+			r.addError(path, CodeOneRequired, fmt.Sprintf("only one of %s may be set, got %s", strings.Join(spec.OneRequired, ", "), strings.Join(present, ", ")),
+				map[string]any{"properties": spec.OneRequired, "present": present})
+		}
+	}
+}
+
+// presentProperties returns the names that are properties of obj, in order
+func presentProperties(obj map[string]any, names []string) []string {
+	present := []string{}
+	for _, name := range names {
+		if _, ok := obj[name]; ok && !slices.Contains(present, name) {
+			present = append(present, name)
+		}
+	}
+	return present
+}
+
+// fillPropertyGroups makes a generated object satisfy the spec's
+// oneRequired and anyRequired properties
+func (g *generator) fillPropertyGroups(obj map[string]any, spec *Spec) {
+	if len(spec.OneRequired) > 0 {
+		present := presentProperties(obj, spec.OneRequired)
+		if len(present) == 0 {
+			name := spec.OneRequired[0]
+			obj[name] = g.generate(spec.Properties[name])
+		}
+		for _, name := range present[min(len(present), 1):] {
+			delete(obj, name)
+		}
+	}
+
+	if len(spec.AnyRequired) > 0 && len(presentProperties(obj, spec.AnyRequired)) == 0 {
+		name := spec.AnyRequired[0]
+		obj[name] = g.generate(spec.Properties[name])
+	}
+}
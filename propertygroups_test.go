@@ -0,0 +1,88 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidatePropertyGroups(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string"},
+			"phone": {"type": "string", "nullable": true},
+			"card": {"type": "object"},
+			"iban": {"type": "string"}
+		},
+		"anyRequired": ["email", "phone"],
+		"oneRequired": ["card", "iban"],
+		"messages": {"anyRequired": "Give us a way to contact you"}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value map[string]any
+		want  []string // error codes
+	}{
+		{name: "email and card", value: map[string]any{"email": "a@example.com", "card": map[string]any{}}},
+		{name: "both contacts", value: map[string]any{"email": "a@example.com", "phone": "555", "iban": "x"}},
+		{name: "null counts as present", value: map[string]any{"phone": nil, "iban": "x"}},
+		{name: "no contact", value: map[string]any{"iban": "x"}, want: []string{CodeAnyRequired}},
+		{name: "no payment", value: map[string]any{"phone": "555"}, want: []string{CodeOneRequired}},
+		{name: "two payments", value: map[string]any{"phone": "555", "card": map[string]any{}, "iban": "x"}, want: []string{CodeOneRequired}},
+		{name: "empty", value: map[string]any{}, want: []string{CodeAnyRequired, CodeOneRequired}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.value, spec)
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+				if err.Path != "" {
+					t.Errorf("expected errors on the object, got %v", err)
+				}
+				if err.Code == CodeAnyRequired && err.Message != "Give us a way to contact you" {
+					t.Errorf("expected custom message, got %q", err.Message)
+				}
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+
+	result := Validate(map[string]any{"card": map[string]any{}, "iban": "x", "email": "a@example.com"}, spec)
+	if want := "only one of card, iban may be set, got card, iban"; len(result.Errors) != 1 || result.Errors[0].Message != want {
+		t.Errorf("expected %q, got %v", want, result.Errors)
+	}
+}
+
+func TestGeneratePropertyGroups(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"email": {"type": "string"}, "phone": {"type": "string"}, "card": {"type": "string"}, "iban": {"type": "string"}},
+		"anyRequired": ["email", "phone"],
+		"oneRequired": ["card", "iban"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	gen := NewGenerator(spec, 1)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		if result := Validate(gen.Valid(), spec); !result.Valid {
+			t.Fatalf("expected a valid instance, got %v", result.Errors)
+		}
+		if _, violation, ok := gen.Invalid(); ok {
+			seen[violation.Code] = true
+		}
+	}
+	if !seen[CodeAnyRequired] || !seen[CodeOneRequired] {
+		t.Errorf("expected both groups to be violated, got %v", seen)
+	}
+}
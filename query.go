@@ -0,0 +1,144 @@
+package mowgli
+
+import (
+	"fmt"
+	"maps"
+	"mime/multipart"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ValidateQuery converts query parameters to the types an object spec
+// declares and validates them. See Validator.ValidateQuery.
+func ValidateQuery(values url.Values, spec *Spec) (map[string]any, *ValidationResult) {
+	return defaultValidator.ValidateQuery(values, spec)
+}
+
+// ValidateQuery converts query parameters, which are all strings and may be
+// repeated, to the types of spec's properties and validates the result. It
+// returns the converted parameters, with transforms applied, and the
+// validation result.
+//
+// Repeated keys become arrays for array properties, with each value
+// converted to the item type; for other properties they are an error with
+// code "type". Numbers, integers and booleans are parsed, and a key without
+// a value, as in "?verbose", is true for a boolean. An empty value of
+// another non-string type is treated as missing. Values that can't be
+// converted are left as strings, so validation reports a type error.
+// Parameters the spec doesn't declare are converted with its
+// additionalProperties spec, if any.
+func (v *Validator) ValidateQuery(values url.Values, spec *Spec) (map[string]any, *ValidationResult) {
+	return v.validateParams(values, nil, spec)
+}
+
+// validateParams converts form values and uploaded files to the types of
+// spec's properties and validates them, returning the converted parameters
+// with transforms applied
+func (v *Validator) validateParams(values map[string][]string, files map[string][]*multipart.FileHeader, spec *Spec) (map[string]any, *ValidationResult) {
+	start, name := time.Now(), specName(spec)
+	r := v.newResult(nil)
+	if spec != nil && spec.Ref != "" {
+		resolved, err := r.resolveRef(spec)
+		if err != nil {
+			r.addError("", CodeInvalidSpec, err.Error(), map[string]any{"ref": spec.Ref})
+			r.observe(name, start)
+			return map[string]any{}, r
+		}
+		spec = resolved
+	}
+
+	params := make(map[string]any, len(values)+len(files))
+	r.convertValues(values, spec, params)
+	r.convertFiles(files, spec, params)
+
+	r.root = params
+	r.run(spec)
+	r.observe(name, start)
+	typed, _ := r.Document.(map[string]any)
+	if typed == nil {
+		typed = params
+	}
+	return typed, r
+}
+
+// convertValues converts string values, such as query parameters and form
+// fields, to the types of spec's properties and adds them to params.
+// Repeated keys for properties that aren't arrays are reported as errors.
+func (r *ValidationResult) convertValues(values map[string][]string, spec *Spec, params map[string]any) {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		list := values[key]
+		propSpec := paramSpec(spec, key)
+
+		switch {
+		case propSpec != nil && propSpec.Type == "array":
+			items := make([]any, len(list))
+			for i, value := range list {
+				items[i] = convertQueryValue(value, propSpec.Items)
+			}
+			params[key] = items
+		case len(list) > 1 && propSpec != nil:
+			r.addError(key, CodeType, fmt.Sprintf("expected a single value, got %d", len(list)),
+				map[string]any{"expected": propSpec.Type, "actual": "array"})
+		case len(list) > 1:
+			params[key] = convertQueryValues(list)
+		case len(list) == 1:
+			if value := convertQueryValue(list[0], propSpec); value != nil {
+				params[key] = value
+			}
+		}
+	}
+}
+
+// paramSpec returns the spec of the parameter key: its property spec, or the
+// additionalProperties spec if spec doesn't declare it
+func paramSpec(spec *Spec, key string) *Spec {
+	if spec == nil {
+		return nil
+	}
+	if propSpec := spec.Properties[key]; propSpec != nil {
+		return propSpec
+	}
+	return spec.AdditionalProperties
+}
+
+// convertQueryValue converts a query value to spec's type, returning nil for
+// an empty value that should be treated as missing
+func convertQueryValue(value string, spec *Spec) any {
+	if spec == nil {
+		return value
+	}
+
+	switch spec.Type {
+	case "number", "integer":
+		if value == "" {
+			return nil
+		}
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if value == "" {
+			return true
+		}
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case "array", "object", "null":
+		if value == "" {
+			return nil
+		}
+	}
+	return value
+}
+
+// convertQueryValues keeps the values of an undeclared repeated key as an
+// array of strings
+func convertQueryValues(list []string) []any {
+	items := make([]any, len(list))
+	for i, value := range list {
+		items[i] = value
+	}
+	return items
+}
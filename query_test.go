@@ -0,0 +1,79 @@
+package mowgli
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestValidateQuery(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"q": {"type": "string", "minLength": 1},
+			"limit": {"type": "integer", "min": 1, "max": 100},
+			"verbose": {"type": "boolean"},
+			"tag": {"type": "array", "items": {"type": "string"}, "maxLength": 2},
+			"id": {"type": "array", "items": {"type": "integer"}}
+		},
+		"required": ["q"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		params map[string]any
+		want   []string // "path: code"
+	}{
+		{
+			name:   "converted",
+			query:  "q=go&limit=10&verbose=false&tag=a&tag=b&id=1&id=2",
+			params: map[string]any{"q": "go", "limit": 10.0, "verbose": false, "tag": []any{"a", "b"}, "id": []any{1.0, 2.0}},
+		},
+		{name: "bare boolean", query: "q=go&verbose", params: map[string]any{"q": "go", "verbose": true}},
+		{name: "single value array", query: "q=go&tag=a", params: map[string]any{"q": "go", "tag": []any{"a"}}},
+		{name: "empty number is missing", query: "q=go&limit=", params: map[string]any{"q": "go"}},
+		{name: "undeclared", query: "q=go&x=1&y=a&y=b", params: map[string]any{"q": "go", "x": "1", "y": []any{"a", "b"}}},
+		{name: "missing required", query: "limit=5", params: map[string]any{"limit": 5.0}, want: []string{"q: required"}},
+		{name: "not a number", query: "q=go&limit=ten", params: map[string]any{"q": "go", "limit": "ten"}, want: []string{"limit: type"}},
+		{name: "not an integer", query: "q=go&limit=1.5", params: map[string]any{"q": "go", "limit": 1.5}, want: []string{"limit: type"}},
+		{name: "out of range", query: "q=go&limit=500", params: map[string]any{"q": "go", "limit": 500.0}, want: []string{"limit: max"}},
+		{name: "bad item", query: "q=go&id=1&id=x", params: map[string]any{"q": "go", "id": []any{1.0, "x"}}, want: []string{"id[1]: type"}},
+		{name: "too many items", query: "q=go&tag=a&tag=b&tag=c", params: map[string]any{"q": "go", "tag": []any{"a", "b", "c"}}, want: []string{"tag: maxLength"}},
+		{name: "repeated single value", query: "q=a&q=b", params: map[string]any{}, want: []string{"q: type", "q: required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Failed to parse query: %v", err)
+			}
+			params, result := ValidateQuery(values, spec)
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("expected params %v, got %v", tt.params, params)
+			}
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+": "+err.Code)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+			if result.Valid != (len(tt.want) == 0) {
+				t.Errorf("expected Valid %v", len(tt.want) == 0)
+			}
+		})
+	}
+}
+
+func TestValidateQueryTransforms(t *testing.T) {
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{"q": {Type: "string", Transform: []string{"trim"}}}}
+	params, result := ValidateQuery(url.Values{"q": {"  go "}}, spec)
+	if !result.Valid || params["q"] != "go" {
+		t.Errorf("expected transformed params, got %v, %v", params, result.Errors)
+	}
+}
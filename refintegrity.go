@@ -0,0 +1,128 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RefIntegrity requires the values at one path of an object to be ids or
+// keys found at another, e.g. the product IDs of order lines to be IDs of
+// the listed products
+type RefIntegrity struct {
+	From    string `json:"from"`              // Selector of the references, e.g. "items[*].productId"
+	To      string `json:"to"`                // Selector of the ids they must match, e.g. "products[*].id"; keys for objects
+	Message string `json:"message,omitempty"` // Error message for a dangling reference (default names it and To)
+}
+
+// validateRefIntegrity reports the references in obj that no id matches.
+// Paths are selectors as in overrides, relative to obj. A selected object
+// provides its keys as ids, e.g. a map of products by id; null references
+// and arrays or objects selected by From are ignored.
+func (r *ValidationResult) validateRefIntegrity(path string, obj map[string]any, spec *Spec) {
+	for _, ref := range spec.RefIntegrity {
+		from, err := parsePathSelector(ref.From)
+		if err != nil {
+			r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"from": ref.From})
+			continue
+		}
+		to, err := parsePathSelector(ref.To)
+		if err != nil {
+			r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"to": ref.To})
+			continue
+		}
+
+		ids := make(map[string]bool)
+		for _, match := range selectPaths(obj, to) {
+			if members, ok := match.value.(map[string]any); ok {
+				for key := range members {
+					ids[refKey(key)] = true
+				}
+			} else if key, ok := refKeyOf(match.value); ok {
+				ids[key] = true
+			}
+		}
+
+		for _, match := range selectPaths(obj, from) {
+			key, ok := refKeyOf(match.value)
+			if !ok || ids[key] {
+				continue
+			}
+			message := ref.Message
+			if message == "" {
+				message = fmt.Sprintf("dangling reference %s: not found in %s", refDisplay(match.value), ref.To)
+			}
+			r.addError(buildPath(path, match.path), CodeRefIntegrity, message,
+				map[string]any{"reference": match.value, "from": ref.From, "to": ref.To})
+		}
+	}
+}
+
+// selectPaths returns the values inside obj whose paths, relative to obj,
+// the selector matches, in document order
+func selectPaths(obj map[string]any, selector pathSelector) []jsonPathMatch {
+	var selected []jsonPathMatch
+	for _, match := range descendants(jsonPathMatch{value: obj}, nil)[1:] {
+		if selector.match(pathSegments(match.path)) {
+			selected = append(selected, match)
+		}
+	}
+	return selected
+}
+
+// refKeyOf returns the key identifying a scalar reference or id, so that
+// e.g. 7 and 7.0 match but 7 and "7" don't
+func refKeyOf(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return refKey(v), true
+	case bool:
+		return "b" + strconv.FormatBool(v), true
+	}
+	num, isInt, ok := numberValue(value)
+	if !ok {
+		return "", false
+	}
+	if n, isNumber := value.(json.Number); isNumber && (!isInt || math.Abs(num) >= 1<<53) {
+		// Keep the digits float64 can't represent
+		return "n" + string(n), true
+	}
+	return "n" + strconv.FormatFloat(num, 'g', -1, 64), true
+}
+
+// refKey returns the key of a string reference or id
+func refKey(s string) string {
+	return "s" + s
+}
+
+// refDisplay renders a reference for error messages, quoting strings
+func refDisplay(value any) string {
+	if s, ok := value.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// refIntegrityRules renders refIntegrity entries, e.g.
+// "items[*].productId -> products[*].id"
+func refIntegrityRules(refs []RefIntegrity) []string {
+	rules := make([]string, len(refs))
+	for i, ref := range refs {
+		rules[i] = ref.From + " -> " + ref.To
+	}
+	return rules
+}
+
+// checkRefIntegrity reports refIntegrity entries whose selectors don't parse
+func checkRefIntegrity(refs []RefIntegrity) error {
+	for _, ref := range refs {
+		if _, err := parsePathSelector(ref.From); err != nil {
+			return fmt.Errorf("refIntegrity from: %w", err)
+		}
+		if _, err := parsePathSelector(ref.To); err != nil {
+			return fmt.Errorf("refIntegrity to: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRefIntegrity(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"refIntegrity": [
+			{"from": "items[*].productId", "to": "products[*].id"},
+			{"from": "**.warehouse", "to": "warehouses"},
+			{"from": "primary", "to": "items[*].line", "message": "primary must be one of the lines"}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	if _, err := Compile(spec); err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string // path: message
+	}{
+		{
+			name: "valid",
+			data: `{"products": [{"id": "p1"}, {"id": "p2"}], "warehouses": {"north": {}},
+				"items": [{"line": 1, "productId": "p2", "warehouse": "north"}], "primary": 1.0}`,
+		},
+		{
+			name:   "dangling reference",
+			data:   `{"products": [{"id": "p1"}], "items": [{"productId": "p1"}, {"productId": "p9"}]}`,
+			errors: []string{`items[1].productId: dangling reference "p9": not found in products[*].id`},
+		},
+		{
+			name:   "nothing to refer to",
+			data:   `{"items": [{"productId": "p1"}]}`,
+			errors: []string{`items[0].productId: dangling reference "p1": not found in products[*].id`},
+		},
+		{
+			name:   "keys of an object",
+			data:   `{"warehouses": {"north": {}}, "transfer": {"warehouse": "south"}}`,
+			errors: []string{`transfer.warehouse: dangling reference "south": not found in warehouses`},
+		},
+		{
+			name:   "types must match",
+			data:   `{"items": [{"line": "1"}], "primary": 1}`,
+			errors: []string{"primary: primary must be one of the lines"},
+		},
+		{
+			name: "null references are ignored",
+			data: `{"items": [{"productId": null}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateJSON([]byte(tt.data), spec)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				if !errors.Is(e, ErrRefIntegrity) {
+					t.Errorf("unexpected error: %v", e)
+				}
+				got = append(got, e.Path+": "+e.Message)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.errors) {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestRefIntegrityParams(t *testing.T) {
+	spec := &Spec{Type: "object", RefIntegrity: []RefIntegrity{{From: "ids[*]", To: "known[*]"}}}
+	// Large json.Number IDs keep their digits
+	result := Validate(map[string]any{
+		"ids":   []any{json.Number("7"), json.Number("9007199254740993")},
+		"known": []any{7.0, json.Number("9007199254740992")},
+	}, spec)
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected one error, got %v", result.Errors)
+	}
+	err := result.Errors[0]
+	if err.Path != "ids[1]" || err.Params["from"] != "ids[*]" || err.Params["to"] != "known[*]" || err.Params["reference"] != json.Number("9007199254740993") {
+		t.Errorf("unexpected error %v with params %v", err, err.Params)
+	}
+
+	if _, err := Compile(&Spec{Type: "object", RefIntegrity: []RefIntegrity{{From: "ids[", To: "known"}}}); err == nil {
+		t.Error("expected Compile to reject the selector")
+	}
+}
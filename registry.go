@@ -0,0 +1,209 @@
+package mowgli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry holds named, versioned specs. Specs are registered as
+// "name@version" and looked up by the same identifier, or by name alone for
+// the latest version. Specs validated through a registry can refer to each
+// other with "$ref". A Registry is safe for concurrent use.
+type Registry struct {
+	mu        sync.RWMutex
+	specs     map[string]map[string]*Spec // name -> version -> spec
+	loader    SpecLoader                  // Loads refs that aren't registered; nil if none
+	validator *Validator
+}
+
+// SpecLoader loads specs by ref from outside a Registry, such as a remote
+// schema registry (see HTTPFetcher)
+type SpecLoader interface {
+	LoadSpec(ctx context.Context, ref string) (*Spec, error)
+}
+
+// SetLoader makes the registry load refs that aren't registered with
+// loader, for lookups and $ref resolution. Loaded specs aren't registered,
+// so the loader decides how long to cache them.
+func (reg *Registry) SetLoader(loader SpecLoader) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.loader = loader
+}
+
+// NewRegistry creates an empty registry. opts configure the Validator used
+// by the registry's Validate methods.
+func NewRegistry(opts ...Option) *Registry {
+	reg := &Registry{specs: make(map[string]map[string]*Spec)}
+	reg.validator = NewValidator(append(opts, WithRegistry(reg))...)
+	return reg
+}
+
+// WithRegistry resolves "$ref" against reg
+func WithRegistry(reg *Registry) Option {
+	return func(v *Validator) {
+		v.registry = reg
+	}
+}
+
+// Register adds spec under id, which has the form "name@version". A
+// registered version cannot be replaced; register a new version instead.
+func (reg *Registry) Register(id string, spec *Spec) error {
+	name, version, ok := strings.Cut(id, "@")
+	if !ok || name == "" || version == "" {
+		return fmt.Errorf("invalid spec id %q: expected name@version", id)
+	}
+	if _, err := Compile(spec); err != nil {
+		return fmt.Errorf("spec %s: %w", id, err)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	versions := reg.specs[name]
+	if versions == nil {
+		versions = make(map[string]*Spec)
+		reg.specs[name] = versions
+	}
+	if _, exists := versions[version]; exists {
+		return fmt.Errorf("spec %s is already registered", id)
+	}
+	versions[version] = spec
+	return nil
+}
+
+// Lookup returns the spec registered as ref, which is either "name@version"
+// or a bare name for the latest registered version of that name. Refs that
+// aren't registered are loaded with the registry's loader, if it has one.
+func (reg *Registry) Lookup(ref string) (*Spec, error) {
+	spec, err := reg.lookup(ref)
+	if err != nil {
+		reg.mu.RLock()
+		loader := reg.loader
+		reg.mu.RUnlock()
+		if loader != nil {
+			return loader.LoadSpec(context.Background(), ref)
+		}
+	}
+	return spec, err
+}
+
+// lookup returns the registered spec for ref
+func (reg *Registry) lookup(ref string) (*Spec, error) {
+	name, version, pinned := strings.Cut(ref, "@")
+
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	versions := reg.specs[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("unknown spec %q", ref)
+	}
+	if !pinned {
+		version = latestVersion(versions)
+	}
+	spec, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown spec %q", ref)
+	}
+	return spec, nil
+}
+
+// Versions returns the registered versions of name, oldest first
+func (reg *Registry) Versions(name string) []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	versions := make([]string, 0, len(reg.specs[name]))
+	for version := range reg.specs[name] {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+	return versions
+}
+
+// Validate validates data against the spec registered as ref
+func (reg *Registry) Validate(data any, ref string) (*ValidationResult, error) {
+	return reg.validator.ValidateRef(data, ref)
+}
+
+// ValidateJSON validates a JSON byte slice against the spec registered as ref
+func (reg *Registry) ValidateJSON(jsonData []byte, ref string) (*ValidationResult, error) {
+	spec, err := reg.Lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	return reg.validator.validateJSON(ref, jsonData, spec, reg.validator.useNumber, strictDecoding{})
+}
+
+// ValidateRef validates data against the spec registered as ref in the
+// Validator's registry (see WithRegistry)
+func (v *Validator) ValidateRef(data any, ref string) (*ValidationResult, error) {
+	if v.registry == nil {
+		return nil, fmt.Errorf("cannot resolve %q: validator has no registry", ref)
+	}
+	spec, err := v.registry.Lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	return v.validateNamed(ref, data, spec, nil), nil
+}
+
+// resolveRef follows spec's $ref, and those of the specs it refers to, to
+// the spec to validate against
+func (r *ValidationResult) resolveRef(spec *Spec) (*Spec, error) {
+	if r.registry == nil {
+		return nil, fmt.Errorf("cannot resolve $ref %q: validator has no registry", spec.Ref)
+	}
+
+	var chain []string
+	for spec.Ref != "" {
+		for _, seen := range chain {
+			if seen == spec.Ref {
+				return nil, fmt.Errorf("$ref cycle: %s -> %s", strings.Join(chain, " -> "), spec.Ref)
+			}
+		}
+		chain = append(chain, spec.Ref)
+
+		resolved, err := r.registry.Lookup(spec.Ref)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve $ref: %w", err)
+		}
+		spec = resolved
+	}
+	return spec, nil
+}
+
+// latestVersion returns the highest of the given versions
+func latestVersion(versions map[string]*Spec) string {
+	latest := ""
+	for version := range versions {
+		if latest == "" || compareVersions(version, latest) > 0 {
+			latest = version
+		}
+	}
+	return latest
+}
+
+// compareVersions orders dot-separated versions such as "1.10" and "1.9",
+// comparing numeric segments as numbers and others as strings
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return an - bn
+			}
+		case as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
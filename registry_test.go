@@ -0,0 +1,161 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestRegistry(t *testing.T, specs map[string]string) *Registry {
+	t.Helper()
+	reg := NewRegistry()
+	for id, specJSON := range specs {
+		spec, err := ParseSpecString(specJSON)
+		if err != nil {
+			t.Fatalf("Failed to parse spec %s: %v", id, err)
+		}
+		if err := reg.Register(id, spec); err != nil {
+			t.Fatalf("Register(%s) failed: %v", id, err)
+		}
+	}
+	return reg
+}
+
+func TestRegistryRegister(t *testing.T) {
+	reg := NewRegistry()
+	spec := &Spec{Type: "string"}
+
+	if err := reg.Register("name@1", spec); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	for _, tt := range []struct {
+		id      string
+		spec    *Spec
+		wantErr string
+	}{
+		{id: "name", spec: spec, wantErr: "expected name@version"},
+		{id: "@1", spec: spec, wantErr: "expected name@version"},
+		{id: "name@", spec: spec, wantErr: "expected name@version"},
+		{id: "name@1", spec: spec, wantErr: "already registered"},
+		{id: "bad@1", spec: &Spec{Type: "string", Pattern: stringPtr("(")}, wantErr: "invalid pattern"},
+	} {
+		err := reg.Register(tt.id, tt.spec)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Register(%q): expected error containing %q, got %v", tt.id, tt.wantErr, err)
+		}
+	}
+}
+
+func TestRegistryLookup(t *testing.T) {
+	reg := newTestRegistry(t, map[string]string{
+		"user@1.2":  `{"type": "string", "examples": ["1.2"]}`,
+		"user@1.10": `{"type": "string", "examples": ["1.10"]}`,
+		"user@1.9":  `{"type": "string", "examples": ["1.9"]}`,
+	})
+
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "user@1.2", want: "1.2"},
+		{ref: "user", want: "1.10"},
+		{ref: "user@2", wantErr: true},
+		{ref: "order", wantErr: true},
+	}
+	for _, tt := range tests {
+		spec, err := reg.Lookup(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Lookup(%q): expected error", tt.ref)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", tt.ref, err)
+		}
+		if spec.Examples[0] != tt.want {
+			t.Errorf("Lookup(%q): expected version %s, got %v", tt.ref, tt.want, spec.Examples[0])
+		}
+	}
+
+	if got, want := reg.Versions("user"), []string{"1.2", "1.9", "1.10"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Versions: expected %v, got %v", want, got)
+	}
+}
+
+func TestRegistryValidate(t *testing.T) {
+	reg := newTestRegistry(t, map[string]string{
+		"address@1": `{"type": "object", "properties": {"city": {"type": "string"}}, "required": ["city"]}`,
+		"address@2": `{"type": "object", "properties": {"city": {"type": "string"}, "zip": {"type": "string"}}, "required": ["city", "zip"]}`,
+		"order@1": `{"type": "object", "properties": {
+			"shipping": {"$ref": "address@1"},
+			"billing": {"$ref": "address"},
+			"parent": {"$ref": "order@1"}
+		}}`,
+		"loop@1":   `{"$ref": "loop@2"}`,
+		"loop@2":   `{"$ref": "loop@1"}`,
+		"broken@1": `{"type": "object", "properties": {"a": {"$ref": "missing"}}}`,
+	})
+
+	tests := []struct {
+		name       string
+		ref        string
+		dataJSON   string
+		wantErrors []string
+	}{
+		{
+			name:     "valid with pinned and latest refs",
+			ref:      "order@1",
+			dataJSON: `{"shipping": {"city": "Oslo"}, "billing": {"city": "Oslo", "zip": "0150"}}`,
+		},
+		{
+			name:       "bare ref resolves to latest version",
+			ref:        "order@1",
+			dataJSON:   `{"billing": {"city": "Oslo"}}`,
+			wantErrors: []string{"billing.zip: required field is missing"},
+		},
+		{
+			name:       "recursive ref",
+			ref:        "order",
+			dataJSON:   `{"parent": {"parent": {"shipping": {}}}}`,
+			wantErrors: []string{"parent.parent.shipping.city: required field is missing"},
+		},
+		{
+			name:       "ref cycle",
+			ref:        "loop@1",
+			dataJSON:   `{}`,
+			wantErrors: []string{"$ref cycle: loop@2 -> loop@1 -> loop@2"},
+		},
+		{
+			name:       "unknown ref",
+			ref:        "broken@1",
+			dataJSON:   `{"a": 1}`,
+			wantErrors: []string{`a: cannot resolve $ref: unknown spec "missing"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := reg.ValidateJSON([]byte(tt.dataJSON), tt.ref)
+			if err != nil {
+				t.Fatalf("ValidateJSON failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, got)
+			}
+		})
+	}
+
+	if _, err := reg.Validate(map[string]any{}, "unknown"); err == nil {
+		t.Error("expected error validating against an unknown spec")
+	}
+	result := Validate(map[string]any{}, &Spec{Ref: "order@1"})
+	if result.Valid || result.Errors[0].Code != CodeInvalidSpec {
+		t.Errorf("expected invalidSpec error without a registry, got %v", result.Errors)
+	}
+}
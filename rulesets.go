@@ -0,0 +1,79 @@
+package mowgli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ruleSets holds the named tag option clusters registered with RegisterRuleSet
+var ruleSets = struct {
+	sync.RWMutex
+	tags map[string]string
+}{tags: make(map[string]string)}
+
+// RegisterRuleSet registers a named cluster of struct tag options that tags
+// can reference with rules=name, e.g. after
+//
+//	RegisterRuleSet("slug", "minLength=1,maxLength=64,pattern=^[a-z0-9-]+$")
+//
+// a field can be tagged `mowgli:"required,rules=slug"`. The options are
+// expanded in place, so options after rules= override those in the set.
+func RegisterRuleSet(name, tag string) error {
+	if name == "" || strings.ContainsAny(name, ", =") {
+		return fmt.Errorf("invalid rule set name: %q", name)
+	}
+
+	// Check the set parses, including any rule sets it references
+	expanded, err := expandRuleSetsSeen(tag, map[string]bool{name: true})
+	if err == nil {
+		_, err = ParseStructTag(expanded)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid rule set %s: %w", name, err)
+	}
+
+	ruleSets.Lock()
+	defer ruleSets.Unlock()
+	ruleSets.tags[name] = tag
+	return nil
+}
+
+// expandRuleSets replaces rules=name options in tag with the registered options
+func expandRuleSets(tag string) (string, error) {
+	if !strings.Contains(tag, "rules=") {
+		return tag, nil
+	}
+	return expandRuleSetsSeen(tag, map[string]bool{})
+}
+
+func expandRuleSetsSeen(tag string, seen map[string]bool) (string, error) {
+	parts := strings.Split(tag, ",")
+	expanded := make([]string, 0, len(parts))
+	for _, part := range parts {
+		name, ok := strings.CutPrefix(strings.TrimSpace(part), "rules=")
+		if !ok {
+			expanded = append(expanded, part)
+			continue
+		}
+
+		if seen[name] {
+			return "", fmt.Errorf("rule set %s references itself", name)
+		}
+		ruleSets.RLock()
+		setTag, ok := ruleSets.tags[name]
+		ruleSets.RUnlock()
+		if !ok {
+			return "", fmt.Errorf("unknown rule set: %s", name)
+		}
+
+		seen[name] = true
+		setTag, err := expandRuleSetsSeen(setTag, seen)
+		delete(seen, name)
+		if err != nil {
+			return "", err
+		}
+		expanded = append(expanded, setTag)
+	}
+	return strings.Join(expanded, ","), nil
+}
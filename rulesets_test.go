@@ -0,0 +1,97 @@
+package mowgli
+
+import "testing"
+
+func TestRegisterRuleSet(t *testing.T) {
+	if err := RegisterRuleSet("test-slug", "minLength=1,maxLength=64,pattern=^[a-z0-9-]+$"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := RegisterRuleSet("test-short-slug", "rules=test-slug,maxLength=16"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr bool
+		check   func(*StructTagOptions) bool
+	}{
+		{
+			name: "expands rule set",
+			tag:  "required,rules=test-slug",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Required && *opts.MinLength == 1 && *opts.MaxLength == 64 && *opts.Pattern == "^[a-z0-9-]+$"
+			},
+		},
+		{
+			name: "later options override the set",
+			tag:  "rules=test-slug,maxLength=10",
+			check: func(opts *StructTagOptions) bool {
+				return *opts.MaxLength == 10
+			},
+		},
+		{
+			name: "nested rule sets",
+			tag:  "rules=test-short-slug",
+			check: func(opts *StructTagOptions) bool {
+				return *opts.MaxLength == 16 && opts.Pattern != nil
+			},
+		},
+		{
+			name: "after dive",
+			tag:  "dive,rules=test-slug",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Dive != nil && *opts.Dive.MaxLength == 64
+			},
+		},
+		{
+			name:    "unknown rule set",
+			tag:     "rules=test-missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ParseStructTag(tt.tag)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.check(opts) {
+				t.Errorf("unexpected options: %+v", opts)
+			}
+		})
+	}
+}
+
+func TestRegisterRuleSetErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		setName string
+		tag     string
+	}{
+		{name: "empty name", setName: "", tag: "minLength=1"},
+		{name: "invalid name", setName: "a,b", tag: "minLength=1"},
+		{name: "invalid options", setName: "test-invalid", tag: "minLength=abc"},
+		{name: "self reference", setName: "test-loop", tag: "rules=test-loop"},
+		{name: "unknown reference", setName: "test-dangling", tag: "rules=test-nowhere"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterRuleSet(tt.setName, tt.tag); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+
+	if _, err := ParseStructTag("rules=test-invalid"); err == nil {
+		t.Error("invalid rule set should not have been registered")
+	}
+}
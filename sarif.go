@@ -0,0 +1,343 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sarifSchema and sarifVersion identify the SARIF format written
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+)
+
+// Locator returns the 1-based line and column of the value at a document
+// path, e.g. "items[0].price", for reports that point into source files
+type Locator func(path string) (line, column int, ok bool)
+
+// JSONLocator locates paths in a JSON document. Properties are located at
+// their key. A path that isn't in the document, such as that of a missing
+// required property, is located at its nearest ancestor that is.
+func JSONLocator(data []byte) Locator {
+	positions := make(map[string]int)
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	// Paths after a syntax error aren't located
+	walkJSONPositions(data, dec, "", positions)
+	return func(path string) (int, int, bool) {
+		offset, ok := lookupPosition(positions, path)
+		if !ok {
+			return 0, 0, false
+		}
+		line, column := lineColumn(data, offset)
+		return line, column, true
+	}
+}
+
+// walkJSONPositions records the offset of the value the decoder is at and,
+// recursively, of its members and items
+func walkJSONPositions(data []byte, dec *json.Decoder, path string, positions map[string]int) error {
+	if _, ok := positions[path]; !ok {
+		positions[path] = skipJSONSeparators(data, int(dec.InputOffset()))
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		for dec.More() {
+			keyOffset := skipJSONSeparators(data, int(dec.InputOffset()))
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			child := buildPath(path, fmt.Sprint(key))
+			positions[child] = keyOffset
+			if err := walkJSONPositions(data, dec, child, positions); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	case json.Delim('['):
+		for i := 0; dec.More(); i++ {
+			if err := walkJSONPositions(data, dec, buildArrayPath(path, i), positions); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+	}
+	return err
+}
+
+// skipJSONSeparators returns the offset of the first token at or after
+// offset, skipping whitespace and the separators the decoder hasn't consumed
+func skipJSONSeparators(data []byte, offset int) int {
+	for offset < len(data) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// lineColumn converts a byte offset to a 1-based line and column, counting
+// columns in characters
+func lineColumn(data []byte, offset int) (int, int) {
+	offset = min(offset, len(data))
+	line := 1 + bytes.Count(data[:offset], []byte("\n"))
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return line, 1 + utf8.RuneCount(data[lineStart:offset])
+}
+
+// YAMLLocator locates paths in a YAML document, like JSONLocator
+func YAMLLocator(data []byte) Locator {
+	type position struct{ line, column int }
+	positions := make(map[string]position)
+	var walk func(node *yaml.Node, path string)
+	walk = func(node *yaml.Node, path string) {
+		if _, ok := positions[path]; !ok {
+			positions[path] = position{node.Line, node.Column}
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				walk(child, path)
+			}
+		case yaml.AliasNode:
+			walk(node.Alias, path)
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key := node.Content[i]
+				child := buildPath(path, key.Value)
+				positions[child] = position{key.Line, key.Column}
+				walk(node.Content[i+1], child)
+			}
+		case yaml.SequenceNode:
+			for i, item := range node.Content {
+				walk(item, buildArrayPath(path, i))
+			}
+		}
+	}
+
+	var root yaml.Node
+	if yaml.Unmarshal(data, &root) == nil && root.Kind != 0 {
+		walk(&root, "")
+	}
+	return func(path string) (int, int, bool) {
+		p, ok := lookupPosition(positions, path)
+		return p.line, p.column, ok
+	}
+}
+
+// lookupPosition returns the position of path, or of its nearest ancestor
+// with one
+func lookupPosition[P any](positions map[string]P, path string) (P, bool) {
+	for {
+		if p, ok := positions[path]; ok {
+			return p, true
+		}
+		if path == "" {
+			var zero P
+			return zero, false
+		}
+		path = parentPath(path)
+	}
+}
+
+// parentPath returns the path of the value containing the value at path,
+// e.g. "items[0]" for "items[0].price" and "items" for "items[0]"
+func parentPath(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		switch path[i] {
+		case '.':
+			return path[:i]
+		case '[':
+			if i > 0 {
+				return path[:i]
+			}
+		}
+	}
+	return ""
+}
+
+// SARIFDocument is a validated file to report in SARIF
+type SARIFDocument struct {
+	URI    string            // Path or URI of the file, e.g. "config/app.json"
+	Result *ValidationResult // Nil if the file couldn't be validated
+	Err    error             // Why the file couldn't be validated
+	// Locate locates errors in the file, e.g. JSONLocator of its contents.
+	// Without it, errors are reported for the file as a whole.
+	Locate Locator
+}
+
+// WriteSARIF writes a SARIF 2.1.0 log of the documents' errors and
+// warnings, for code scanning views such as GitHub's and GitLab's. Each
+// error code is a rule, so views can group and filter by code; errors are
+// reported at the line of the offending value and warnings, such as
+// deprecated fields, at level "warning". Documents that couldn't be
+// validated are reported as tool execution notifications.
+func WriteSARIF(w io.Writer, docs ...SARIFDocument) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLogOf(docs))
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool         `json:"tool"`
+	Invocations []sarifInvocation `json:"invocations"`
+	Results     []sarifResult     `json:"results"`
+	ColumnKind  string            `json:"columnKind"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLogOf builds the SARIF log of docs
+func sarifLogOf(docs []SARIFDocument) sarifLog {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "mowgli",
+			InformationURI: "https://github.com/matjam/mowgli",
+			Rules:          []sarifRule{},
+		}},
+		Invocations: []sarifInvocation{{ExecutionSuccessful: true}},
+		Results:     []sarifResult{},
+		ColumnKind:  "unicodeCodePoints",
+	}
+	invocation := &run.Invocations[0]
+	rules := make(map[string]bool)
+
+	for _, doc := range docs {
+		if doc.Err != nil || doc.Result == nil {
+			err := doc.Err
+			if err == nil {
+				err = errors.New("document was not validated")
+			}
+			invocation.ExecutionSuccessful = false
+			invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, sarifNotification{
+				Level:     "error",
+				Message:   sarifMessage{Text: err.Error()},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: doc.URI}}}},
+			})
+			continue
+		}
+
+		for _, level := range []string{"error", "warning"} {
+			errs := doc.Result.Errors
+			if level == "warning" {
+				errs = doc.Result.Warnings
+			}
+			for _, e := range errs {
+				rules[e.Code] = true
+				run.Results = append(run.Results, sarifResult{
+					RuleID:    e.Code,
+					Level:     level,
+					Message:   sarifMessage{Text: e.Error()},
+					Locations: []sarifLocation{doc.location(e.Path)},
+				})
+			}
+		}
+	}
+
+	for _, code := range slices.Sorted(maps.Keys(rules)) {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: code, ShortDescription: sarifMessage{Text: ruleDescription(code)}})
+	}
+	return sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+}
+
+// location returns the SARIF location of the value at path in the document
+func (d SARIFDocument) location(path string) sarifLocation {
+	location := sarifLocation{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: d.URI}}}
+	if d.Locate != nil {
+		if line, column, ok := d.Locate(path); ok {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: line, StartColumn: column}
+		}
+	}
+	if path != "" {
+		location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: path}}
+	}
+	return location
+}
+
+// ruleDescription describes the rule of an error code by its error kind
+func ruleDescription(code string) string {
+	if kind, ok := errorKinds[code]; ok {
+		return kind.Error() + " (" + code + ")"
+	}
+	return code
+}
@@ -0,0 +1,147 @@
+package mowgli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSONLocator(t *testing.T) {
+	locate := JSONLocator([]byte(`{
+  "name": "widget",
+  "tags": ["a", "ü", 3],
+  "items": [
+    {"sku": "x", "price": -1}
+  ]
+}`))
+
+	tests := []struct {
+		path         string
+		line, column int
+	}{
+		{"", 1, 1},
+		{"name", 2, 3},
+		{"tags[1]", 3, 17},
+		{"tags[2]", 3, 22},
+		{"items[0]", 5, 5},
+		{"items[0].price", 5, 18},
+		{"items[0].missing", 5, 5},
+		{"missing", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			line, column, ok := locate(tt.path)
+			if !ok || line != tt.line || column != tt.column {
+				t.Errorf("expected %d:%d, got %d:%d (ok=%v)", tt.line, tt.column, line, column, ok)
+			}
+		})
+	}
+}
+
+func TestYAMLLocator(t *testing.T) {
+	locate := YAMLLocator([]byte(`name: widget
+items:
+  - sku: x
+    price: -1
+`))
+
+	tests := []struct {
+		path         string
+		line, column int
+	}{
+		{"", 1, 1},
+		{"name", 1, 1},
+		{"items[0]", 3, 5},
+		{"items[0].price", 4, 5},
+		{"items[1]", 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			line, column, ok := locate(tt.path)
+			if !ok || line != tt.line || column != tt.column {
+				t.Errorf("expected %d:%d, got %d:%d (ok=%v)", tt.line, tt.column, line, column, ok)
+			}
+		})
+	}
+
+	if _, _, ok := YAMLLocator([]byte(""))(""); ok {
+		t.Error("expected an empty document not to locate")
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"port": {"type": "integer", "max": 65535}, "legacy": {"type": "string", "deprecated": true}}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	source := []byte("{\n  \"port\": 70000,\n  \"legacy\": \"x\"\n}")
+	result, err := ValidateJSON(source, spec)
+	if err != nil {
+		t.Fatalf("ValidateJSON failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = WriteSARIF(&buf,
+		SARIFDocument{URI: "config/app.json", Result: result, Locate: JSONLocator(source)},
+		SARIFDocument{URI: "config/broken.json", Err: errors.New("invalid JSON")},
+	)
+	if err != nil {
+		t.Fatalf("WriteSARIF failed: %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("Failed to decode SARIF: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+	run := log.Runs[0]
+
+	type located struct {
+		rule, level string
+		line        int
+	}
+	var got []located
+	for _, r := range run.Results {
+		line := 0
+		if region := r.Locations[0].PhysicalLocation.Region; region != nil {
+			line = region.StartLine
+		}
+		got = append(got, located{r.RuleID, r.Level, line})
+	}
+	expected := []located{
+		{CodeRequired, "error", 1},
+		{CodeMax, "error", 2},
+		{CodeDeprecated, "warning", 3},
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("result %d: expected %v, got %v", i, expected[i], got[i])
+		}
+	}
+
+	var rules []string
+	for _, rule := range run.Tool.Driver.Rules {
+		rules = append(rules, rule.ID)
+	}
+	if len(rules) != 3 || rules[0] != CodeDeprecated || rules[1] != CodeMax || rules[2] != CodeRequired {
+		t.Errorf("unexpected rules %v", rules)
+	}
+
+	invocation := run.Invocations[0]
+	if invocation.ExecutionSuccessful || len(invocation.ToolExecutionNotifications) != 1 ||
+		invocation.ToolExecutionNotifications[0].Message.Text != "invalid JSON" {
+		t.Errorf("expected a notification for the broken file, got %+v", invocation)
+	}
+}
@@ -0,0 +1,157 @@
+package mowgli
+
+import (
+	"sort"
+	"strings"
+)
+
+// QualityReport is the outcome of scoring a document. Scores range from 0
+// (every field failed) to 1 (no errors).
+type QualityReport struct {
+	Score  float64            // Weighted score of the whole document
+	Fields map[string]float64 // Score of each field, keyed by path
+	Result *ValidationResult  // The underlying validation result
+}
+
+// Score validates data against spec and computes a quality score instead of
+// a plain pass/fail
+func Score(data any, spec *Spec) *QualityReport {
+	return defaultValidator.Score(data, spec)
+}
+
+// Score validates data against spec and computes a quality score instead of
+// a plain pass/fail, so partially-bad records can be ranked and triaged.
+//
+// Each field starts at 1 and loses the spec's severity for every error
+// reported on it (by default an error costs 1, dropping the field to 0).
+// Objects and arrays multiply their own score by the weighted mean of their
+// children, using each child's weight (default 1). Errors on paths the spec
+// does not describe count against the nearest described ancestor.
+func (v *Validator) Score(data any, spec *Spec) *QualityReport {
+	result := v.Validate(data, spec)
+	report := &QualityReport{
+		Fields: make(map[string]float64),
+		Result: result,
+	}
+	if spec == nil {
+		return report
+	}
+
+	root := buildScoreNode("", data, spec)
+	root.attribute(result.Errors)
+	report.Score = root.score(report.Fields)
+	delete(report.Fields, "")
+
+	return report
+}
+
+// scoreNode is one field of the document being scored
+type scoreNode struct {
+	path     string
+	spec     *Spec
+	children []*scoreNode
+	errors   []*ValidationError
+}
+
+// buildScoreNode mirrors the parts of the document the spec describes:
+// properties that are present or required, and array items
+func buildScoreNode(path string, value any, spec *Spec) *scoreNode {
+	node := &scoreNode{path: path, spec: spec}
+	if spec == nil {
+		return node
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		names := make(map[string]bool)
+		for name := range spec.Properties {
+			if _, exists := v[name]; exists {
+				names[name] = true
+			}
+		}
+		for _, name := range spec.Required {
+			names[name] = true
+		}
+		sorted := make([]string, 0, len(names))
+		for name := range names {
+			sorted = append(sorted, name)
+		}
+		sort.Strings(sorted)
+		for _, name := range sorted {
+			node.children = append(node.children, buildScoreNode(buildPath(path, name), v[name], spec.Properties[name]))
+		}
+	case []any:
+		for i, item := range v {
+			node.children = append(node.children, buildScoreNode(buildArrayPath(path, i), item, spec.Items))
+		}
+	}
+
+	return node
+}
+
+// attribute assigns each error to the deepest node whose path contains it
+func (n *scoreNode) attribute(errs []*ValidationError) {
+	for _, err := range errs {
+		owner := n.owner(err.Path)
+		owner.errors = append(owner.errors, err)
+	}
+}
+
+func (n *scoreNode) owner(path string) *scoreNode {
+	for _, child := range n.children {
+		if path == child.path || isSubPath(path, child.path) {
+			return child.owner(path)
+		}
+	}
+	return n
+}
+
+// isSubPath reports whether path lies strictly beneath base
+func isSubPath(path, base string) bool {
+	if base == "" {
+		return path != ""
+	}
+	return strings.HasPrefix(path, base+".") || strings.HasPrefix(path, base+"[")
+}
+
+// score computes the node's score and records it, and every descendant's, in fields
+func (n *scoreNode) score(fields map[string]float64) float64 {
+	own := 1.0
+	for _, err := range n.errors {
+		own -= n.severity(err.Code)
+	}
+	if own < 0 {
+		own = 0
+	}
+
+	if len(n.children) > 0 {
+		var total, weights float64
+		for _, child := range n.children {
+			weight := child.weight()
+			total += weight * child.score(fields)
+			weights += weight
+		}
+		if weights > 0 {
+			own *= total / weights
+		}
+	}
+
+	fields[n.path] = own
+	return own
+}
+
+func (n *scoreNode) severity(code string) float64 {
+	if n.spec != nil {
+		if severity, ok := n.spec.Severity[code]; ok {
+			return severity
+		}
+	}
+	return 1
+}
+
+func (n *scoreNode) weight() float64 {
+	if n.spec != nil && n.spec.Weight != nil {
+		return *n.spec.Weight
+	}
+	return 1
+}
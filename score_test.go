@@ -0,0 +1,88 @@
+package mowgli
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "minLength": 1, "weight": 3},
+			"name": {"type": "string", "maxLength": 5, "severity": {"maxLength": 0.25}},
+			"email": {"type": "string", "pattern": "@"},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["id"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   map[string]any
+		score  float64
+		fields map[string]float64
+	}{
+		{
+			name:   "perfect document",
+			data:   map[string]any{"id": "1", "name": "Ada", "email": "a@b"},
+			score:  1,
+			fields: map[string]float64{"id": 1, "name": 1, "email": 1},
+		},
+		{
+			name:   "low severity error",
+			data:   map[string]any{"id": "1", "name": "Adalbert", "email": "a@b"},
+			score:  (3 + 0.75 + 1) / 5.0,
+			fields: map[string]float64{"name": 0.75},
+		},
+		{
+			name:   "heavily weighted field missing",
+			data:   map[string]any{"name": "Ada", "email": "a@b"},
+			score:  (0 + 1 + 1) / 5.0,
+			fields: map[string]float64{"id": 0},
+		},
+		{
+			name:   "array items are scored individually",
+			data:   map[string]any{"id": "1", "tags": []any{"a", 2, "c", 4}},
+			score:  (3 + 0.5) / 4.0,
+			fields: map[string]float64{"tags": 0.5, "tags[0]": 1, "tags[1]": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Score(tt.data, spec)
+			if math.Abs(report.Score-tt.score) > 1e-9 {
+				t.Errorf("expected score %g, got %g (errors: %v)", tt.score, report.Score, report.Result.Errors)
+			}
+			for path, want := range tt.fields {
+				got, ok := report.Fields[path]
+				if !ok {
+					t.Errorf("no score for field %s", path)
+					continue
+				}
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("expected field %s score %g, got %g", path, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreRootErrors(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "object", "properties": {"a": {"type": "string"}}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	report := Score("not an object", spec)
+	if report.Score != 0 {
+		t.Errorf("expected score 0, got %g", report.Score)
+	}
+	if report.Result.Valid {
+		t.Error("expected validation to fail")
+	}
+}
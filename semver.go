@@ -0,0 +1,238 @@
+package mowgli
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// semverPattern matches a Semantic Versioning 2.0.0 version, without a "v"
+// prefix, capturing the core numbers and the pre-release identifiers
+var semverPattern = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)` +
+	`(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?` +
+	`(?:\+[0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*)?$`)
+
+// semVersion is a parsed semantic version. Build metadata is dropped, as it
+// doesn't affect precedence.
+type semVersion struct {
+	core       [3]uint64
+	prerelease []string
+}
+
+// parseSemver parses a semantic version such as "1.2.3-rc.1+build.5"
+func parseSemver(s string) (semVersion, bool) {
+	m := semverPattern.FindStringSubmatch(s)
+	if m == nil {
+		return semVersion{}, false
+	}
+	var v semVersion
+	for i := range v.core {
+		n, err := strconv.ParseUint(m[i+1], 10, 64)
+		if err != nil {
+			return semVersion{}, false
+		}
+		v.core[i] = n
+	}
+	if m[4] != "" {
+		v.prerelease = strings.Split(m[4], ".")
+	}
+	return v, true
+}
+
+func isSemver(value string) bool {
+	_, ok := parseSemver(value)
+	return ok
+}
+
+// compare orders versions by semver precedence: a pre-release comes before
+// its release, and pre-release identifiers compare numerically if both are
+// numbers and lexically otherwise
+func (v semVersion) compare(o semVersion) int {
+	for i := range v.core {
+		if c := cmp.Compare(v.core[i], o.core[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(o.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(o.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(v.prerelease) && i < len(o.prerelease); i++ {
+		a, b := v.prerelease[i], o.prerelease[i]
+		na, errA := strconv.ParseUint(a, 10, 64)
+		nb, errB := strconv.ParseUint(b, 10, 64)
+		var c int
+		switch {
+		case errA == nil && errB == nil:
+			c = cmp.Compare(na, nb)
+		case errA == nil:
+			c = -1
+		case errB == nil:
+			c = 1
+		default:
+			c = strings.Compare(a, b)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(v.prerelease), len(o.prerelease))
+}
+
+// semverComparator is one condition of a range, e.g. ">=1.2.0"
+type semverComparator struct {
+	op      string
+	version semVersion
+}
+
+func (c semverComparator) matches(v semVersion) bool {
+	n := v.compare(c.version)
+	switch c.op {
+	case ">":
+		return n > 0
+	case ">=":
+		return n >= 0
+	case "<":
+		return n < 0
+	case "<=":
+		return n <= 0
+	}
+	return n == 0
+}
+
+// semverRange is a parsed "semverRange": alternatives separated by "||",
+// each a list of comparators that must all match
+type semverRange [][]semverComparator
+
+// parseSemverRange parses a range such as ">=1.2.0 <2.0.0 || ^3.1.0".
+// Comparators use =, >, >=, < or <= with a full version; ^1.2.3 allows
+// changes that don't modify the left-most non-zero number and ~1.2.3 allows
+// patch changes.
+func parseSemverRange(s string) (semverRange, error) {
+	var rng semverRange
+	for _, alternative := range strings.Split(s, "||") {
+		var comparators []semverComparator
+		for _, field := range strings.Fields(alternative) {
+			expanded, err := parseSemverComparator(field)
+			if err != nil {
+				return nil, err
+			}
+			comparators = append(comparators, expanded...)
+		}
+		if len(comparators) == 0 {
+			return nil, fmt.Errorf("empty semver range: %q", s)
+		}
+		rng = append(rng, comparators)
+	}
+	return rng, nil
+}
+
+// parseSemverComparator parses one comparator, expanding ^ and ~ into a
+// lower and upper bound
+func parseSemverComparator(field string) ([]semverComparator, error) {
+	op := field[:len(field)-len(strings.TrimLeft(field, "<>=^~"))]
+	if !slices.Contains([]string{"", "=", ">", ">=", "<", "<=", "^", "~"}, op) {
+		return nil, fmt.Errorf("invalid semver comparator: %s", field)
+	}
+	v, ok := parseSemver(field[len(op):])
+	if !ok {
+		return nil, fmt.Errorf("invalid version in semver range: %s", field)
+	}
+
+	switch op {
+	case "^", "~":
+		upper := semVersion{}
+		switch {
+		case op == "~":
+			upper.core = [3]uint64{v.core[0], v.core[1] + 1, 0}
+		case v.core[0] > 0:
+			upper.core = [3]uint64{v.core[0] + 1, 0, 0}
+		case v.core[1] > 0:
+			upper.core = [3]uint64{0, v.core[1] + 1, 0}
+		default:
+			upper.core = [3]uint64{0, 0, v.core[2] + 1}
+		}
+		// Pre-releases of the upper bound are excluded too
+		upper.prerelease = []string{"0"}
+		return []semverComparator{{op: ">=", version: v}, {op: "<", version: upper}}, nil
+	case "":
+		op = "="
+	}
+	return []semverComparator{{op: op, version: v}}, nil
+}
+
+func (rng semverRange) matches(v semVersion) bool {
+	for _, comparators := range rng {
+		all := true
+		for _, c := range comparators {
+			if !c.matches(v) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSemverRange checks that str is a semantic version within the
+// spec's range
+func (r *ValidationResult) validateSemverRange(path, str, rangeExpr string) {
+	rng, err := parseSemverRange(rangeExpr)
+	if err != nil {
+		r.addError(path, CodeInvalidSpec, err.Error(), nil)
+		return
+	}
+	v, ok := parseSemver(str)
+	if !ok {
+		r.addError(path, CodeSemverRange, "string is not a semantic version",
+			map[string]any{"range": rangeExpr})
+		return
+	}
+	if !rng.matches(v) {
+		r.addError(path, CodeSemverRange, fmt.Sprintf("version %s is not in range %s", str, rangeExpr),
+			map[string]any{"actual": str, "range": rangeExpr})
+	}
+}
+
+// checkSemverRange reports an invalid "semverRange"
+func checkSemverRange(rangeExpr string) error {
+	if rangeExpr == "" {
+		return nil
+	}
+	_, err := parseSemverRange(rangeExpr)
+	return err
+}
+
+// semverExample returns a version inside the range, or outside it if inside
+// is false, trying the versions at the edges of each comparator
+func semverExample(rangeExpr string, inside bool) (string, bool) {
+	rng, err := parseSemverRange(rangeExpr)
+	if err != nil {
+		return "", false
+	}
+	candidates := []semVersion{{}}
+	for _, comparators := range rng {
+		for _, c := range comparators {
+			core := c.version.core
+			candidates = append(candidates, semVersion{core: core}, semVersion{core: [3]uint64{core[0], core[1], core[2] + 1}})
+		}
+	}
+	candidates = append(candidates, semVersion{core: [3]uint64{999999, 0, 0}})
+
+	for _, v := range candidates {
+		if rng.matches(v) == inside {
+			return fmt.Sprintf("%d.%d.%d", v.core[0], v.core[1], v.core[2]), true
+		}
+	}
+	return "", false
+}
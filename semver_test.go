@@ -0,0 +1,114 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSemverFormat(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{"1.0.0", true},
+		{"0.0.0", true},
+		{"1.2.3-rc.1+build.5", true},
+		{"1.0.0-alpha-beta.0.x-y", true},
+		{"v1.0.0", false},
+		{"1.0", false},
+		{"01.0.0", false},
+		{"1.0.0-01", false},
+		{"1.0.0-", false},
+		{"1.0.0+", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := isSemver(tt.value); got != tt.valid {
+				t.Errorf("isSemver(%q) = %v, want %v", tt.value, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestSemverPrecedence(t *testing.T) {
+	// In ascending order, as listed by the Semantic Versioning spec
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2",
+		"1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.1.0", "2.0.0",
+	}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := parseSemver(ordered[i])
+		b, _ := parseSemver(ordered[i+1])
+		if a.compare(b) >= 0 || b.compare(a) <= 0 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := parseSemver("1.0.0+build.1")
+	b, _ := parseSemver("1.0.0+build.2")
+	if a.compare(b) != 0 {
+		t.Error("expected build metadata to be ignored")
+	}
+}
+
+func TestValidateSemverRange(t *testing.T) {
+	tests := []struct {
+		rangeExpr string
+		version   string
+		want      []string // error codes
+	}{
+		{">=1.2.0 <2.0.0", "1.2.0", nil},
+		{">=1.2.0 <2.0.0", "1.9.9", nil},
+		{">=1.2.0 <2.0.0", "2.0.0", []string{CodeSemverRange}},
+		{">=1.2.0 <2.0.0", "1.2.0-rc.1", []string{CodeSemverRange}},
+		{"^1.2.3", "1.9.0", nil},
+		{"^1.2.3", "2.0.0-rc.1", []string{CodeSemverRange}},
+		{"^0.2.3", "0.3.0", []string{CodeSemverRange}},
+		{"~1.2.3", "1.2.9", nil},
+		{"~1.2.3", "1.3.0", []string{CodeSemverRange}},
+		{"1.0.0 || >=3.0.0", "1.0.0", nil},
+		{"1.0.0 || >=3.0.0", "2.0.0", []string{CodeSemverRange}},
+		{"=1.0.0", "1.0.0+build", nil},
+		{">1.0.0", "v1.1.0", []string{CodeSemverRange}},
+		{">>1.0.0", "1.1.0", []string{CodeInvalidSpec}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rangeExpr+" "+tt.version, func(t *testing.T) {
+			result := Validate(tt.version, &Spec{Type: "string", SemverRange: tt.rangeExpr})
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestCompileSemverRange(t *testing.T) {
+	for _, rangeExpr := range []string{">=1.2", "<2.0.0 ||", "!1.0.0"} {
+		if _, err := Compile(&Spec{Type: "string", SemverRange: rangeExpr}); err == nil || !strings.Contains(err.Error(), "semver") {
+			t.Errorf("%q: expected semver error, got %v", rangeExpr, err)
+		}
+	}
+}
+
+func TestSemverExample(t *testing.T) {
+	for _, rangeExpr := range []string{">=1.2.0 <2.0.0", "^0.0.3", ">2.0.0 || <1.0.0", "~3.1.4"} {
+		spec := &Spec{Type: "string", SemverRange: rangeExpr}
+		if result := Validate(GenerateExample(spec), spec); !result.Valid {
+			t.Errorf("%q: expected a valid example, got %v", rangeExpr, result.Errors)
+		}
+		outside, ok := semverExample(rangeExpr, false)
+		if !ok {
+			t.Fatalf("%q: expected a version outside the range", rangeExpr)
+		}
+		if result := Validate(outside, spec); result.Valid {
+			t.Errorf("%q: expected %s to be outside the range", rangeExpr, outside)
+		}
+	}
+}
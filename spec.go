@@ -4,27 +4,128 @@ import "encoding/json"
 
 // Condition defines a conditional validation rule
 type Condition struct {
-	If   string           `json:"if"`             // Expression to evaluate, e.g., "enabled == true", "count > 0"
-	Then map[string]*Spec `json:"then"`           // Spec overrides to apply when condition is true
-	Else map[string]*Spec `json:"else,omitempty"` // Spec overrides to apply when condition is false
+	Name   string           `json:"name,omitempty"`   // Optional label reported in annotations when the condition holds, e.g. "premium"
+	If     string           `json:"if,omitempty"`     // Expression to evaluate, e.g., "enabled == true", "count > 0"
+	Unless string           `json:"unless,omitempty"` // Expression that must be false for the condition to hold, e.g. "guestCheckout"
+	Then   map[string]*Spec `json:"then"`             // Spec overrides to apply when condition is true
+	Else   map[string]*Spec `json:"else,omitempty"`   // Spec overrides to apply when condition is false
+
+	Required []string `json:"required,omitempty"` // Properties that become required when condition is true
+
+	// Specs replacing the object's whole spec, for objects whose shape a
+	// field selects; a condition with either has no other effect
+	ThenSpec *Spec `json:"thenSpec,omitempty"` // Spec to validate the object against when condition is true
+	ElseSpec *Spec `json:"elseSpec,omitempty"` // Spec to validate the object against when condition is false
+}
+
+// Expression returns the expression deciding whether the condition holds:
+// If, negated Unless, or both joined with AND
+func (c Condition) Expression() string {
+	switch {
+	case c.Unless == "":
+		return c.If
+	case c.If == "":
+		return "!(" + c.Unless + ")"
+	default:
+		return "(" + c.If + ") AND !(" + c.Unless + ")"
+	}
+}
+
+// Assert is an invariant of an object spanning its fields. With Select it
+// holds for each value a JSONPath selects instead, e.g. the totals of paid
+// orders, and Spec may take the place of Expr.
+type Assert struct {
+	Expr    string `json:"expr,omitempty"`    // Expression that must be true, e.g. "endDate > startDate"; "@ > 0" with Select
+	Message string `json:"message,omitempty"` // Error message when it is false (default "assertion failed: " and the expression)
+	Path    string `json:"path,omitempty"`    // Property to report the error at, relative to the object, e.g. "endDate"
+
+	Select string `json:"select,omitempty"` // JSONPath from the object ($) to the values to check, e.g. "$.orders[?(@.status == 'paid')].total"
+	Spec   *Spec  `json:"spec,omitempty"`   // Spec each selected value must be valid against
+}
+
+// reshapes reports whether the condition replaces its object's spec
+func (c Condition) reshapes() bool {
+	return c.ThenSpec != nil || c.ElseSpec != nil
+}
+
+// Discriminator selects the spec an object is validated against by the
+// value of one of its properties, for payloads that take one of several
+// shapes
+type Discriminator struct {
+	PropertyName string           `json:"propertyName"` // Property holding the value, e.g. "type"
+	Mapping      map[string]*Spec `json:"mapping"`      // Spec per value, combined with the object's spec; may be a {"$ref": ...}
 }
 
 // Spec defines the validation specification structure
 type Spec struct {
-	Type       string           `json:"type"`                 // string, number, integer, boolean, object, array, null
-	Properties map[string]*Spec `json:"properties,omitempty"` // For object type
-	Items      *Spec            `json:"items,omitempty"`      // For array type
-	Required   []string         `json:"required,omitempty"`   // For object type - list of required property names
-	Conditions []Condition      `json:"conditions,omitempty"` // Conditional validation rules for object type
+	Type       string            `json:"type"`                 // string, number, integer, boolean, object, array, null
+	Properties map[string]*Spec  `json:"properties,omitempty"` // For object type
+	Items      *Spec             `json:"items,omitempty"`      // For array type
+	Required   []string          `json:"required,omitempty"`   // For object type - list of required property names
+	Conditions []Condition       `json:"conditions,omitempty"` // Conditional validation rules for object type
+	RequiredIf map[string]string `json:"requiredIf,omitempty"` // For object type - properties required when their expression is true, e.g. {"verificationCode": "age < 18"}
+	Ref        string            `json:"$ref,omitempty"`       // Registered spec to validate against instead, e.g. "address@2" (see Registry)
+	Formats    map[string]string `json:"formats,omitempty"`    // Formats defined by regular expression for this spec and its children, e.g. {"sku": "^[A-Z0-9-]+$"}
+
+	ConditionDefs map[string]string `json:"conditionDefs,omitempty"` // Named expressions conditions of this spec and its children can use, e.g. {"isMinorUS": "age < 18 AND country == \"US\""}
+	Overrides     map[string]*Spec  `json:"overrides,omitempty"`     // Specs combined with those of the values inside this one a path selector matches, e.g. {"items[*].price": {"min": 0}}
+
+	AdditionalProperties *Spec `json:"additionalProperties,omitempty"` // For object type - spec for values of undeclared properties
+	PropertyNames        *Spec `json:"propertyNames,omitempty"`        // For object type - spec every property name must satisfy
+
+	Discriminator *Discriminator `json:"discriminator,omitempty"` // For object type - selects a spec by the value of a property
+
+	// Conditional subschemas, as in JSON Schema: the spec is combined with
+	// Then if the value is valid against If, and with Else otherwise
+	If   *Spec `json:"if,omitempty"`   // Spec the value is tested against; may leave out types the spec declares
+	Then *Spec `json:"then,omitempty"` // Combined with the spec when the value is valid against If
+	Else *Spec `json:"else,omitempty"` // Combined with the spec when the value isn't valid against If
+
+	AnyRequired []string `json:"anyRequired,omitempty"` // For object type - at least one of these properties is required, e.g. ["email", "phone"]
+	OneRequired []string `json:"oneRequired,omitempty"` // For object type - exactly one of these properties is required
+	Asserts     []Assert `json:"asserts,omitempty"`     // For object type - expressions that must hold, e.g. "discount <= price"
+
+	RefIntegrity []RefIntegrity `json:"refIntegrity,omitempty"` // For object type - values that must be ids found elsewhere in the object
+
+	// Encoded content, for strings carrying another document
+	ContentEncoding  string `json:"contentEncoding,omitempty"`  // Encoding of the string: "base64" or "base64url"
+	ContentMediaType string `json:"contentMediaType,omitempty"` // Media type of the decoded content; JSON types are parsed
+	ContentSchema    *Spec  `json:"contentSchema,omitempty"`    // Spec for the decoded content
 
 	// Constraints
-	Min        *float64      `json:"min,omitempty"`        // For number/integer - minimum value
-	Max        *float64      `json:"max,omitempty"`        // For number/integer - maximum value
-	MinLength  *int          `json:"minLength,omitempty"`  // For string/array - minimum length
-	MaxLength  *int          `json:"maxLength,omitempty"`  // For string/array - maximum length
-	Pattern    *string       `json:"pattern,omitempty"`    // For string - regex pattern (future: could support regex validation)
-	Enum       []any `json:"enum,omitempty"`       // Array of allowed values
-	AllowEmpty *bool         `json:"allowEmpty,omitempty"` // For strings - allows empty string if true
+	Min         *float64    `json:"min,omitempty"`         // For number/integer - minimum value
+	Max         *float64    `json:"max,omitempty"`         // For number/integer - maximum value
+	MinInt      json.Number `json:"minInt,omitempty"`      // For number/integer - exact integer minimum, e.g. -9223372036854775808
+	MaxInt      json.Number `json:"maxInt,omitempty"`      // For number/integer - exact integer maximum, e.g. 18446744073709551615
+	MinLength   *int        `json:"minLength,omitempty"`   // For string/array - minimum length
+	MaxLength   *int        `json:"maxLength,omitempty"`   // For string/array - maximum length
+	LengthUnit  string      `json:"lengthUnit,omitempty"`  // For string - unit of minLength and maxLength: "runes" (default), "bytes" or "graphemes"
+	MinBytes    *int        `json:"minBytes,omitempty"`    // For string - minimum size in UTF-8 bytes
+	MaxBytes    *int        `json:"maxBytes,omitempty"`    // For string - maximum size in UTF-8 bytes
+	Pattern     *string     `json:"pattern,omitempty"`     // For string - regex pattern (future: could support regex validation)
+	Format      *string     `json:"format,omitempty"`      // For string - named format such as "email" or "uuid"
+	TimeFormat  string      `json:"timeFormat,omitempty"`  // For string - Go time layout the string must match, e.g. "02/01/2006 15:04"
+	SemverRange string      `json:"semverRange,omitempty"` // For string - semantic version range the string must be in, e.g. ">=1.2.0 <2.0.0"
+	URISchemes  []string    `json:"uriSchemes,omitempty"`  // For string - schemes a URI may use, e.g. ["https"]
+	PublicHost  *bool       `json:"publicHost,omitempty"`  // For string - rejects URIs with loopback, private and link-local hosts if true
+	Enum        []any       `json:"enum,omitempty"`        // Array of allowed values
+	AllowEmpty  *bool       `json:"allowEmpty,omitempty"`  // For strings - allows empty string if true
+	UniqueItems *bool       `json:"uniqueItems,omitempty"` // For array - items must be distinct if true
+	Nullable    *bool       `json:"nullable,omitempty"`    // Allows null in place of a value of Type if true
+	Checks      []string    `json:"checks,omitempty"`      // Names of async checks registered on the Validator
+	ReadOnly    *bool       `json:"readOnly,omitempty"`    // Rejected in ModeRequest if true, e.g. server-managed IDs
+	WriteOnly   *bool       `json:"writeOnly,omitempty"`   // Rejected in ModeResponse if true, e.g. passwords
+	Deprecated  *bool       `json:"deprecated,omitempty"`  // Reports a warning when the value is present if true
+	Transform   []string    `json:"transform,omitempty"`   // For string - transforms applied before validation, e.g. ["trim", "lower"]
+
+	// Documentation
+	Examples []any             `json:"examples,omitempty"` // Example values, not used for validation
+	Messages map[string]string `json:"messages,omitempty"` // Custom error messages keyed by error code, e.g. {"minLength": "too short"}
+	Default  any               `json:"default,omitempty"`  // Value Fix fills in when the property is missing
+
+	// Quality scoring
+	Weight   *float64           `json:"weight,omitempty"`   // Relative importance of this field in its parent's score (default 1)
+	Severity map[string]float64 `json:"severity,omitempty"` // Score penalty per error code (default 1, i.e. the field scores 0)
 }
 
 // ParseSpec parses a JSON byte slice into a Spec
@@ -0,0 +1,168 @@
+package mowgli
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SpecSource loads a complete set of specs by name, e.g. from a directory,
+// a database or a configuration service
+type SpecSource interface {
+	Load(ctx context.Context) (map[string]*Spec, error)
+}
+
+// SpecSourceFunc adapts a function to a SpecSource
+type SpecSourceFunc func(ctx context.Context) (map[string]*Spec, error)
+
+// Load returns f(ctx)
+func (f SpecSourceFunc) Load(ctx context.Context) (map[string]*Spec, error) {
+	return f(ctx)
+}
+
+// DirSource loads the spec files in fsys matching pattern (see fs.Glob),
+// e.g. os.DirFS("/etc/specs") and "*.json". Specs are named by their path
+// without the extension, e.g. "users/create" for "users/create.json".
+func DirSource(fsys fs.FS, pattern string) SpecSource {
+	return SpecSourceFunc(func(ctx context.Context) (map[string]*Spec, error) {
+		files, err := LoadSpecsFS(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		specs := make(map[string]*Spec, len(files))
+		for file, spec := range files {
+			specs[strings.TrimSuffix(file, path.Ext(file))] = spec
+		}
+		return specs, nil
+	})
+}
+
+// SpecStoreConfig configures a SpecStore
+type SpecStoreConfig struct {
+	Interval time.Duration // How often Watch reloads the specs (default 1s)
+	// OnReload is called after a reload changed specs, with the names of
+	// the specs added, changed or removed, including the first load
+	OnReload func(changed []string)
+	// OnError is called when a reload in Watch fails; the store keeps the
+	// specs it has
+	OnError func(err error)
+}
+
+// SpecStore holds a set of compiled specs from a SpecSource and reloads
+// them while the service runs, so specs can be updated without a restart.
+// A reload takes effect only if every spec loads and compiles, and then
+// replaces the whole set at once, so concurrent readers see either the old
+// or the new specs, never a mix. A SpecStore is safe for concurrent use.
+type SpecStore struct {
+	source SpecSource
+	config SpecStoreConfig
+	specs  atomic.Pointer[map[string]*CompiledSpec]
+	// reloading serializes reloads; readers don't take it
+	reloading sync.Mutex
+}
+
+// NewSpecStore creates a store holding the specs source loads now. Call
+// Watch to keep them up to date.
+func NewSpecStore(ctx context.Context, source SpecSource, config SpecStoreConfig) (*SpecStore, error) {
+	if config.Interval <= 0 {
+		config.Interval = time.Second
+	}
+	s := &SpecStore{source: source, config: config}
+	s.specs.Store(&map[string]*CompiledSpec{})
+	if _, err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get returns the spec named name
+func (s *SpecStore) Get(name string) (*CompiledSpec, bool) {
+	spec, ok := (*s.specs.Load())[name]
+	return spec, ok
+}
+
+// Names returns the names of the specs in the store, sorted
+func (s *SpecStore) Names() []string {
+	specs := *s.specs.Load()
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Validate validates data against the spec named name
+func (s *SpecStore) Validate(data any, name string) (*ValidationResult, error) {
+	spec, ok := s.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown spec %q", name)
+	}
+	return spec.Validate(data), nil
+}
+
+// Reload loads the specs from the source and, if all of them compile,
+// swaps them in. It returns the names of the specs added, changed or
+// removed; specs that didn't change keep their CompiledSpec.
+func (s *SpecStore) Reload(ctx context.Context) ([]string, error) {
+	s.reloading.Lock()
+	defer s.reloading.Unlock()
+
+	loaded, err := s.source.Load(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load specs: %w", err)
+	}
+
+	current := *s.specs.Load()
+	next := make(map[string]*CompiledSpec, len(loaded))
+	var changed []string
+	for _, name := range sortedKeys(loaded) {
+		if existing, ok := current[name]; ok && SpecEqual(existing.spec, loaded[name]) {
+			next[name] = existing
+			continue
+		}
+		compiled, err := Compile(loaded[name])
+		if err != nil {
+			return nil, fmt.Errorf("spec %s: %w", name, err)
+		}
+		next[name] = compiled
+		changed = append(changed, name)
+	}
+	for name := range current {
+		if _, ok := next[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	slices.Sort(changed)
+
+	if len(changed) > 0 {
+		s.specs.Store(&next)
+		if s.config.OnReload != nil {
+			s.config.OnReload(changed)
+		}
+	}
+	return changed, nil
+}
+
+// Watch reloads the specs every Interval until ctx is done, reporting
+// failed reloads to OnError
+func (s *SpecStore) Watch(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.Reload(ctx); err != nil && s.config.OnError != nil {
+			s.config.OnError(err)
+		}
+	}
+}
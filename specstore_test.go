@@ -0,0 +1,162 @@
+package mowgli
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSpecStore(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/user.json":         {Data: []byte(`{"type": "object", "required": ["name"]}`)},
+		"specs/orders/item.json":  {Data: []byte(`{"type": "string"}`)},
+		"specs/orders/notes.yaml": {Data: []byte(`ignored`)},
+	}
+	var reloads [][]string
+	store, err := NewSpecStore(context.Background(), DirSource(fsys, "specs/*/*.json"), SpecStoreConfig{
+		OnReload: func(changed []string) { reloads = append(reloads, changed) },
+	})
+	if err != nil {
+		t.Fatalf("NewSpecStore returned error: %v", err)
+	}
+	if got, want := store.Names(), []string{"specs/orders/item"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected names %v, got %v", want, got)
+	}
+
+	store, err = NewSpecStore(context.Background(), DirSource(fsys, "specs/*.json"), SpecStoreConfig{
+		OnReload: func(changed []string) { reloads = append(reloads, changed) },
+	})
+	if err != nil {
+		t.Fatalf("NewSpecStore returned error: %v", err)
+	}
+	before, _ := store.Get("specs/user")
+	if result, err := store.Validate(map[string]any{}, "specs/user"); err != nil || result.Valid {
+		t.Errorf("expected name to be required, got %v, %v", result, err)
+	}
+
+	// Reloading unchanged specs keeps them
+	if changed, err := store.Reload(context.Background()); err != nil || changed != nil {
+		t.Errorf("expected no changes, got %v, %v", changed, err)
+	}
+	if after, _ := store.Get("specs/user"); after != before {
+		t.Error("expected the unchanged spec to be kept")
+	}
+
+	// A broken spec leaves the store as it was
+	fsys["specs/user.json"] = &fstest.MapFile{Data: []byte(`{"type": "object", "properties": {"name": {"pattern": "["}}}`)}
+	if _, err := store.Reload(context.Background()); err == nil || !strings.Contains(err.Error(), "spec specs/user: name: invalid pattern") {
+		t.Errorf("expected an invalid pattern error, got %v", err)
+	}
+	if after, _ := store.Get("specs/user"); after != before {
+		t.Error("expected the previous spec to stay in use")
+	}
+
+	// Changes, additions and removals are swapped in together
+	fsys["specs/user.json"] = &fstest.MapFile{Data: []byte(`{"type": "object"}`)}
+	fsys["specs/team.json"] = &fstest.MapFile{Data: []byte(`{"type": "object"}`)}
+	delete(fsys, "specs/orders/item.json")
+	changed, err := store.Reload(context.Background())
+	if want := []string{"specs/team", "specs/user"}; err != nil || !reflect.DeepEqual(changed, want) {
+		t.Errorf("expected changes %v, got %v, %v", want, changed, err)
+	}
+	if result, err := store.Validate(map[string]any{}, "specs/user"); err != nil || !result.Valid {
+		t.Errorf("expected the new spec to be used, got %v, %v", result, err)
+	}
+	if _, err := store.Validate(nil, "specs/missing"); err == nil {
+		t.Error("expected an error for an unknown spec")
+	}
+
+	want := [][]string{{"specs/orders/item"}, {"specs/user"}, {"specs/team", "specs/user"}}
+	if !reflect.DeepEqual(reloads, want) {
+		t.Errorf("expected reloads %v, got %v", want, reloads)
+	}
+}
+
+func TestSpecStoreWatch(t *testing.T) {
+	var mu sync.Mutex
+	specType, fail := "string", false
+	source := SpecSourceFunc(func(ctx context.Context) (map[string]*Spec, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			return nil, errors.New("source unavailable")
+		}
+		return map[string]*Spec{"value": {Type: specType}}, nil
+	})
+
+	reloaded := make(chan []string, 10)
+	failed := make(chan error, 10)
+	store, err := NewSpecStore(context.Background(), source, SpecStoreConfig{
+		Interval: time.Millisecond,
+		OnReload: func(changed []string) { reloaded <- changed },
+		OnError:  func(err error) { failed <- err },
+	})
+	if err != nil {
+		t.Fatalf("NewSpecStore returned error: %v", err)
+	}
+	<-reloaded
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		store.Watch(ctx)
+		close(done)
+	}()
+
+	// Readers run concurrently with the swap
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for range 100 {
+				if _, err := store.Validate("x", "value"); err != nil {
+					t.Errorf("Validate returned error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	mu.Lock()
+	specType = "integer"
+	mu.Unlock()
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the changed spec to be reloaded")
+	}
+	if result, _ := store.Validate(1.0, "value"); !result.Valid {
+		t.Errorf("expected the reloaded spec to be used, got %v", result.Errors)
+	}
+
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	select {
+	case err := <-failed:
+		if !strings.Contains(err.Error(), "source unavailable") {
+			t.Errorf("expected the source's error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the failed reload to be reported")
+	}
+
+	readers.Wait()
+	cancel()
+	<-done
+}
+
+func TestNewSpecStoreError(t *testing.T) {
+	source := SpecSourceFunc(func(ctx context.Context) (map[string]*Spec, error) {
+		return map[string]*Spec{"bad": {Type: "widget"}}, nil
+	})
+	if _, err := NewSpecStore(context.Background(), source, SpecStoreConfig{}); err == nil || !strings.Contains(err.Error(), "unknown type: widget") {
+		t.Errorf("expected an unknown type error, got %v", err)
+	}
+}
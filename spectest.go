@@ -0,0 +1,86 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SpecTest is a spec bundled with its test cases in a single document, the
+// format used to share conformance fixtures. It may be written as JSON or,
+// in files ending in .yaml or .yml, as YAML:
+//
+//	{"spec": {"type": "string", "minLength": 1}, "testCases": [{"name": "empty", "data": "", "expectedValid": false}]}
+type SpecTest struct {
+	Description string         `json:"description,omitempty"`
+	Spec        *Spec          `json:"spec"`
+	TestCases   []SpecTestCase `json:"testCases"`
+}
+
+// SpecTestCase is a test case in a SpecTest. Unlike TestCase, its data may
+// be any JSON value.
+type SpecTestCase struct {
+	Name          string `json:"name"`
+	Data          any    `json:"data"`
+	ExpectedValid bool   `json:"expectedValid"`
+
+	ExpectedErrors []ExpectedError `json:"expectedErrors,omitempty"` // If set, the exact errors validation must report
+}
+
+// ParseSpecTest parses a SpecTest from JSON
+func ParseSpecTest(data []byte) (*SpecTest, error) {
+	var specTest SpecTest
+	if err := json.Unmarshal(data, &specTest); err != nil {
+		return nil, err
+	}
+	if specTest.Spec == nil {
+		return nil, fmt.Errorf("missing spec")
+	}
+	return &specTest, nil
+}
+
+// LoadSpecTestFS loads a SpecTest from fsys, reading files ending in .yaml
+// or .yml as YAML and others as JSON
+func LoadSpecTestFS(fsys fs.FS, name string) (*SpecTest, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec test file %s: %w", name, err)
+	}
+	if isYAMLFile(name) {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to parse spec test file %s: %w", name, err)
+		}
+	}
+
+	specTest, err := ParseSpecTest(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec test file %s: %w", name, err)
+	}
+	return specTest, nil
+}
+
+// isYAMLFile reports whether name has a YAML file extension
+func isYAMLFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// yamlToJSON converts a YAML document to JSON
+func yamlToJSON(data []byte) ([]byte, error) {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("YAML document can't be represented as JSON: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,50 @@
+package mowgli
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadSpecTestFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.json":       {Data: []byte(`{"spec": {"type": "integer", "max": 3}, "testCases": [{"name": "big", "data": 4, "expectedValid": false}]}`)},
+		"b.yml":        {Data: []byte("spec:\n  type: array\n  items: {type: integer}\ntestCases:\n  - name: ints\n    data: [1, 2]\n    expectedValid: true\n")},
+		"no_spec.json": {Data: []byte(`{"testCases": []}`)},
+		"bad.yaml":     {Data: []byte("spec: [")},
+		"keys.yaml":    {Data: []byte("spec: {type: object}\ntestCases:\n  - name: int keys\n    data: {true: a}\n")},
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "json", file: "a.json"},
+		{name: "yaml", file: "b.yml"},
+		{name: "missing spec", file: "no_spec.json", wantErr: "missing spec"},
+		{name: "invalid yaml", file: "bad.yaml", wantErr: "invalid YAML"},
+		{name: "non-string keys", file: "keys.yaml", wantErr: "can't be represented as JSON"},
+		{name: "missing file", file: "c.json", wantErr: "failed to read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specTest, err := LoadSpecTestFS(fsys, tt.file)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadSpecTestFS failed: %v", err)
+			}
+			for _, tc := range specTest.TestCases {
+				if result := Validate(tc.Data, specTest.Spec); result.Valid != tc.ExpectedValid {
+					t.Errorf("%s: expected valid=%v, got %v", tc.Name, tc.ExpectedValid, result.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package mowgli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SQLDialect is a SQL dialect ExportSQL can render
+type SQLDialect string
+
+// SQLPostgres is the PostgreSQL dialect
+const SQLPostgres SQLDialect = "postgres"
+
+// ExportSQL renders an object spec as a CREATE TABLE statement for table in
+// the given dialect, with a column per property so that the database
+// enforces the same constraints as the application. Required properties that
+// aren't nullable are NOT NULL, and each column's scalar constraints become
+// a CHECK: min/max, length and byte size, pattern and enum, with allowEmpty
+// exempting the empty string. Strings with the uuid, date-time or date
+// format get the matching column type; objects, arrays and untyped
+// properties are stored as jsonb.
+// Conditions, other formats and grapheme lengths have no SQL equivalent and
+// are not exported, and patterns are copied as they are, so they must be
+// valid in the database's regular expression syntax too.
+func ExportSQL(table string, spec *Spec, dialect SQLDialect) (string, error) {
+	if dialect != SQLPostgres {
+		return "", fmt.Errorf("unsupported SQL dialect: %q", dialect)
+	}
+	if table == "" {
+		return "", fmt.Errorf("table name is empty")
+	}
+	if spec == nil {
+		return "", fmt.Errorf("spec is nil")
+	}
+	if spec.Type != "object" || len(spec.Properties) == 0 {
+		return "", fmt.Errorf("spec must be an object with properties")
+	}
+
+	required := make(map[string]bool, len(spec.Required))
+	for _, name := range spec.Required {
+		required[name] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("CREATE TABLE " + sqlTableName(table) + " (\n")
+	names := sortedKeys(spec.Properties)
+	for i, name := range names {
+		prop := spec.Properties[name]
+		if prop == nil {
+			prop = &Spec{}
+		}
+		column := sqlIdentifier(name)
+		b.WriteString("    " + column + " " + sqlColumnType(prop))
+		if required[name] && (prop.Nullable == nil || !*prop.Nullable) {
+			b.WriteString(" NOT NULL")
+		}
+		if checks := sqlChecks(column, prop); len(checks) > 0 {
+			b.WriteString(" CHECK (" + strings.Join(checks, " AND ") + ")")
+		}
+		if i < len(names)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(");\n")
+	return b.String(), nil
+}
+
+// sqlColumnType returns the column type for a property
+func sqlColumnType(spec *Spec) string {
+	switch spec.Type {
+	case "string":
+		switch sqlFormat(spec) {
+		case "uuid":
+			return "uuid"
+		case "date-time":
+			return "timestamptz"
+		case "date":
+			return "date"
+		}
+		return "text"
+	case "integer":
+		// Bounds beyond int64 need an arbitrary precision column
+		for _, bound := range []string{spec.MinInt.String(), spec.MaxInt.String()} {
+			if _, err := strconv.ParseInt(bound, 10, 64); bound != "" && err != nil {
+				return "numeric"
+			}
+		}
+		return "bigint"
+	case "number":
+		return "double precision"
+	case "boolean":
+		return "boolean"
+	}
+	return "jsonb"
+}
+
+// sqlFormat returns the format of a string spec, if any
+func sqlFormat(spec *Spec) string {
+	if spec.Format == nil {
+		return ""
+	}
+	return *spec.Format
+}
+
+// sqlChecks returns the conditions of a column's CHECK constraint
+func sqlChecks(column string, spec *Spec) []string {
+	var checks []string
+	switch {
+	case spec.Type == "integer" || spec.Type == "number":
+		if spec.Min != nil {
+			checks = append(checks, column+" >= "+formatJSNumber(*spec.Min))
+		}
+		if spec.MinInt != "" {
+			checks = append(checks, column+" >= "+spec.MinInt.String())
+		}
+		if spec.Max != nil {
+			checks = append(checks, column+" <= "+formatJSNumber(*spec.Max))
+		}
+		if spec.MaxInt != "" {
+			checks = append(checks, column+" <= "+spec.MaxInt.String())
+		}
+	case spec.Type == "string" && sqlColumnType(spec) == "text":
+		checks = sqlStringChecks(column, spec)
+	}
+
+	if enum := sqlEnum(spec); enum != "" {
+		checks = append(checks, column+" IN ("+enum+")")
+	}
+	return checks
+}
+
+// sqlStringChecks returns the conditions on a text column. Unless the spec
+// allows empty strings, they apply to the empty string too.
+func sqlStringChecks(column string, spec *Spec) []string {
+	var checks []string
+	length := ""
+	switch spec.LengthUnit {
+	case LengthRunes, "":
+		length = "char_length(" + column + ")"
+	case LengthBytes:
+		length = "octet_length(" + column + ")"
+	}
+	if length != "" && spec.MinLength != nil {
+		checks = append(checks, length+" >= "+strconv.Itoa(*spec.MinLength))
+	}
+	if length != "" && spec.MaxLength != nil {
+		checks = append(checks, length+" <= "+strconv.Itoa(*spec.MaxLength))
+	}
+	if spec.MinBytes != nil {
+		checks = append(checks, "octet_length("+column+") >= "+strconv.Itoa(*spec.MinBytes))
+	}
+	if spec.MaxBytes != nil {
+		checks = append(checks, "octet_length("+column+") <= "+strconv.Itoa(*spec.MaxBytes))
+	}
+	if spec.Pattern != nil {
+		checks = append(checks, column+" ~ "+sqlString(*spec.Pattern))
+	}
+
+	if len(checks) > 0 && spec.AllowEmpty != nil && *spec.AllowEmpty {
+		return []string{"(" + column + " = '' OR (" + strings.Join(checks, " AND ") + "))"}
+	}
+	return checks
+}
+
+// sqlEnum renders the enum values that fit the column type as a list of
+// literals
+func sqlEnum(spec *Spec) string {
+	var values []string
+	for _, v := range spec.Enum {
+		switch v := v.(type) {
+		case string:
+			if spec.Type == "string" {
+				values = append(values, sqlString(v))
+			}
+		case float64:
+			if spec.Type == "number" || spec.Type == "integer" {
+				values = append(values, formatJSNumber(v))
+			}
+		case int:
+			if spec.Type == "number" || spec.Type == "integer" {
+				values = append(values, strconv.Itoa(v))
+			}
+		case bool:
+			if spec.Type == "boolean" {
+				values = append(values, strconv.FormatBool(v))
+			}
+		}
+	}
+	return strings.Join(values, ", ")
+}
+
+// sqlTableName quotes each part of a table name such as "public.users"
+func sqlTableName(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = sqlIdentifier(part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// sqlIdentifier quotes a column or table name
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlString renders a string literal
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
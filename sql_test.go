@@ -0,0 +1,101 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExportSQL(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "format": "uuid"},
+			"name": {"type": "string", "minLength": 1, "maxLength": 100},
+			"code": {"type": "string", "pattern": "^[A-Z]{3}$", "allowEmpty": true},
+			"bio": {"type": "string", "maxBytes": 1000, "nullable": true},
+			"age": {"type": "integer", "min": 0, "max": 150},
+			"score": {"type": "number", "min": 0.5},
+			"role": {"type": "string", "enum": ["admin", "it's me"]},
+			"active": {"type": "boolean"},
+			"createdAt": {"type": "string", "format": "date-time"},
+			"tags": {"type": "array", "items": {"type": "string"}},
+			"address": {"type": "object"}
+		},
+		"required": ["id", "name", "bio", "createdAt"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	got, err := ExportSQL("app.users", spec, SQLPostgres)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `CREATE TABLE "app"."users" (
+    "active" boolean,
+    "address" jsonb,
+    "age" bigint CHECK ("age" >= 0 AND "age" <= 150),
+    "bio" text CHECK (octet_length("bio") <= 1000),
+    "code" text CHECK (("code" = '' OR ("code" ~ '^[A-Z]{3}$'))),
+    "createdAt" timestamptz NOT NULL,
+    "id" uuid NOT NULL,
+    "name" text NOT NULL CHECK (char_length("name") >= 1 AND char_length("name") <= 100),
+    "role" text CHECK ("role" IN ('admin', 'it''s me')),
+    "score" double precision CHECK ("score" >= 0.5),
+    "tags" jsonb
+);
+`
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportSQLColumns(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *Spec
+		want string
+	}{
+		{name: "byte length", spec: &Spec{Type: "string", MaxLength: intPtr(10), LengthUnit: LengthBytes}, want: `"c" text CHECK (octet_length("c") <= 10)`},
+		{name: "grapheme length", spec: &Spec{Type: "string", MaxLength: intPtr(10), LengthUnit: LengthGraphemes}, want: `"c" text`},
+		{name: "big integer", spec: &Spec{Type: "integer", MaxInt: json.Number("18446744073709551615")}, want: `"c" numeric CHECK ("c" <= 18446744073709551615)`},
+		{name: "integer enum", spec: &Spec{Type: "integer", Enum: []any{1.0, 2.0}}, want: `"c" bigint CHECK ("c" IN (1, 2))`},
+		{name: "date ignores pattern", spec: &Spec{Type: "string", Format: stringPtr("date"), Pattern: stringPtr("^2")}, want: `"c" date`},
+		{name: "untyped", spec: &Spec{}, want: `"c" jsonb`},
+		{name: "boolean", spec: &Spec{Type: "boolean"}, want: `"c" boolean`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExportSQL("t", &Spec{Type: "object", Properties: map[string]*Spec{"c": tt.spec}}, SQLPostgres)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := "CREATE TABLE \"t\" (\n    " + tt.want + "\n);\n"
+			if got != want {
+				t.Errorf("expected %q, got %q", want, got)
+			}
+		})
+	}
+}
+
+func TestExportSQLErrors(t *testing.T) {
+	object := &Spec{Type: "object", Properties: map[string]*Spec{"a": {Type: "string"}}}
+	tests := []struct {
+		name    string
+		table   string
+		spec    *Spec
+		dialect SQLDialect
+	}{
+		{name: "dialect", table: "t", spec: object, dialect: "oracle"},
+		{name: "table", table: "", spec: object, dialect: SQLPostgres},
+		{name: "nil spec", table: "t", spec: nil, dialect: SQLPostgres},
+		{name: "not an object", table: "t", spec: &Spec{Type: "string"}, dialect: SQLPostgres},
+	}
+	for _, tt := range tests {
+		if _, err := ExportSQL(tt.table, tt.spec, tt.dialect); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
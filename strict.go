@@ -0,0 +1,64 @@
+package mowgli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WithStrictExpressions makes conditions, requiredIf expressions and asserts
+// that reference a field missing from the document fail with a condition
+// error naming the field, instead of evaluating it as null. Fields that are
+// present with a null value are not missing.
+func WithStrictExpressions() Option {
+	return func(v *Validator) {
+		v.strictExpressions = true
+	}
+}
+
+// MissingFieldError reports a field an expression references that is
+// missing from the document, in strict expression mode
+type MissingFieldError struct {
+	Field string // Reference as written in the expression, e.g. "age" or "$root.country"
+}
+
+func (e *MissingFieldError) Error() string {
+	return fmt.Sprintf("field %s is missing", e.Field)
+}
+
+// missingReference returns the first field expression references that env
+// doesn't hold, or "" if it holds all of them. Expressions that don't
+// compile are left to evaluation to report.
+func (r *ValidationResult) missingReference(expression string, env map[string]any) (string, error) {
+	compiled, err := withTimeout(r.exprLimits.Timeout, func() (any, error) {
+		return compileExpression(expression, r.exprLimits, r.conditionDefs, r.exprFuncs, r.programs), nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate expression '%s': %w", expression, err)
+	}
+	for _, ref := range compiled.(*exprProgram).references {
+		var value any = env
+		for segment := range strings.SplitSeq(ref, ".") {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				// Fields of null and of values that aren't objects are left to evaluation
+				break
+			}
+			if value, ok = obj[segment]; !ok {
+				return ref, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// conditionFailed reports an expression that couldn't be evaluated at path.
+// what describes the expression, e.g. "condition".
+func (r *ValidationResult) conditionFailed(path, what, expression string, err error) {
+	params := map[string]any{"condition": expression}
+	var missing *MissingFieldError
+	if errors.As(err, &missing) {
+		params["field"] = missing.Field
+	}
+	r.addError(path, CodeCondition, fmt.Sprintf("error evaluating %s '%s': %v", what, expression, err), params)
+}
@@ -0,0 +1,107 @@
+package mowgli
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStrictExpressions(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"mode": {"type": "string", "nullable": true},
+			"address": {
+				"type": "object",
+				"properties": {"country": {"type": "string", "nullable": true}, "zip": {"type": "string"}},
+				"requiredIf": {"zip": "country == \"US\" AND $root.mode == \"strict\""}
+			}
+		},
+		"conditions": [{"if": "discount > 0", "required": ["code"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		data   map[string]any
+		errors []string // path: field
+	}{
+		{"all present", map[string]any{"mode": "strict", "discount": 0.0, "address": map[string]any{"country": "FR"}}, nil},
+		{"null counts as present", map[string]any{"mode": nil, "discount": 0.0, "address": map[string]any{"country": nil}}, nil},
+		{"missing top-level field", map[string]any{"mode": "strict"}, []string{": discount"}},
+		{"missing nested field", map[string]any{"mode": "strict", "discount": 0.0, "address": map[string]any{}}, []string{"address: country"}},
+		{"missing root field", map[string]any{"discount": 0.0, "address": map[string]any{"country": "US"}}, []string{"address: $root.mode"}},
+	}
+
+	v := NewValidator(WithStrictExpressions())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.data, spec)
+			var got []string
+			for _, e := range result.Errors {
+				if e.Code != CodeCondition {
+					t.Errorf("unexpected error: %v", e)
+					continue
+				}
+				got = append(got, e.Path+": "+e.Params["field"].(string))
+				if !strings.Contains(e.Message, "is missing") {
+					t.Errorf("expected the message to name the missing field, got %q", e.Message)
+				}
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.errors, "; ") {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestStrictExpressionsDefsAndPredicates(t *testing.T) {
+	spec := &Spec{
+		Type:          "object",
+		ConditionDefs: map[string]string{"bulk": "any(items, .quantity > 10)"},
+		Asserts:       []Assert{{Expr: "bulk == false OR approved"}},
+	}
+	v := NewValidator(WithStrictExpressions())
+
+	result := v.Validate(map[string]any{"items": []any{map[string]any{"quantity": 20.0}}}, spec)
+	var missing *MissingFieldError
+	if len(result.Errors) != 1 || result.Errors[0].Params["field"] != "approved" {
+		t.Fatalf("expected approved to be missing, got %v", result.Errors)
+	}
+
+	result = v.Validate(map[string]any{"approved": true}, spec)
+	if len(result.Errors) != 1 || result.Errors[0].Params["field"] != "items" {
+		t.Errorf("expected items to be missing, got %v", result.Errors)
+	}
+
+	_, err := v.newResult(nil).evalCondition("a > 1", map[string]any{})
+	if !errors.As(err, &missing) || missing.Field != "a" {
+		t.Errorf("expected a MissingFieldError, got %v", err)
+	}
+}
+
+func TestConditionErrorPath(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"address": {
+			"type": "object",
+			"requiredIf": {"zip": "country =="},
+			"conditions": [{"if": "country ==", "required": ["zip"]}]
+		}}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"address": map[string]any{}}, spec)
+	if len(result.Errors) != 2 {
+		t.Fatalf("expected 2 errors, got %v", result.Errors)
+	}
+	for _, e := range result.Errors {
+		if e.Code != CodeCondition || e.Path != "address" {
+			t.Errorf("expected a condition error at address, got %v", e)
+		}
+	}
+}
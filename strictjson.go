@@ -0,0 +1,146 @@
+package mowgli
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DisallowUnknownFields reports properties that don't match a field of the
+// struct being decoded into, at any depth, as errors with code
+// unknownField, instead of dropping them as encoding/json does
+func DisallowUnknownFields() DecodeOption {
+	return func(c *decodeConfig) {
+		c.unknownFields = true
+	}
+}
+
+// RejectIntegerOverflow reports numbers that don't fit the integer type of
+// the field being decoded into, e.g. 300 for an int8 or -1 for a uint, as
+// range errors, and fractions such as 1.5 as type errors, instead of
+// failing to decode
+func RejectIntegerOverflow() DecodeOption {
+	return func(c *decodeConfig) {
+		c.integerOverflow = true
+	}
+}
+
+// strictDecoding checks a JSON document against the Go type it is decoded
+// into; the zero value checks nothing
+type strictDecoding struct {
+	target          reflect.Type
+	unknownFields   bool
+	integerOverflow bool
+}
+
+// strict returns the checks configured for decoding into target
+func (c decodeConfig) strict(target reflect.Type) strictDecoding {
+	return strictDecoding{target: target, unknownFields: c.unknownFields, integerOverflow: c.integerOverflow}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// checkDecoding reports the values of data that the strict checks reject.
+// Numbers are checked as decoded with UseNumber, so large integers are
+// checked exactly.
+func (r *ValidationResult) checkDecoding(data []byte, strict strictDecoding) error {
+	if strict.target == nil || (!strict.unknownFields && !strict.integerOverflow) {
+		return nil
+	}
+	document := r.root
+	if !r.useNumber {
+		var err error
+		if document, err = decodeJSON(data, true); err != nil {
+			return err
+		}
+	}
+	r.checkDecodedValue("", document, strict.target, strict)
+	return nil
+}
+
+// checkDecodedValue checks value, at path, against the type it decodes into
+func (r *ValidationResult) checkDecodedValue(path string, value any, t reflect.Type, strict strictDecoding) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Types decoding themselves, e.g. time.Time, accept what they accept
+	if reflect.PointerTo(t).Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				field, ok := jsonField(t, key)
+				if !ok {
+					if strict.unknownFields {
+						r.addError(buildPath(path, key), CodeUnknownField, fmt.Sprintf("unknown field %q for %s", key, t),
+							map[string]any{"field": key, "type": t.String()})
+					}
+					continue
+				}
+				r.checkDecodedValue(buildPath(path, key), v[key], field.Type, strict)
+			}
+		case reflect.Map:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				r.checkDecodedValue(buildPath(path, key), v[key], t.Elem(), strict)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				r.checkDecodedValue(buildArrayPath(path, i), item, t.Elem(), strict)
+			}
+		}
+	case json.Number:
+		if strict.integerOverflow {
+			r.checkInteger(path, v, t)
+		}
+	}
+}
+
+// checkInteger reports a number that doesn't fit the integer type t
+func (r *ValidationResult) checkInteger(path string, num json.Number, t reflect.Type) {
+	s := string(num)
+	negative := strings.HasPrefix(s, "-")
+	var err error
+	var lowest, highest any
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(s, 10, t.Bits())
+		lowest, highest = int64(math.MinInt64>>(64-t.Bits())), int64(math.MaxInt64>>(64-t.Bits()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err = strconv.ParseUint(s, 10, t.Bits())
+		lowest, highest = uint64(0), uint64(math.MaxUint64>>(64-t.Bits()))
+		if negative && !strings.ContainsAny(s, ".eE") {
+			err = strconv.ErrRange
+		}
+	default:
+		return
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, strconv.ErrRange) && negative:
+		r.addError(path, CodeMin, fmt.Sprintf("integer %s overflows %s (minimum %v)", s, t, lowest),
+			map[string]any{"actual": num, "limit": lowest, "type": t.String()})
+	case errors.Is(err, strconv.ErrRange):
+		r.addError(path, CodeMax, fmt.Sprintf("integer %s overflows %s (maximum %v)", s, t, highest),
+			map[string]any{"actual": num, "limit": highest, "type": t.String()})
+	default:
+		r.addError(path, CodeType, fmt.Sprintf("expected integer for %s, got %s", t, s),
+			map[string]any{"expected": "integer", "actual": num, "type": t.String()})
+	}
+}
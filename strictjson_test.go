@@ -0,0 +1,117 @@
+package mowgli
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type strictLine struct {
+	SKU      string `json:"sku"`
+	Quantity int8   `json:"quantity"`
+}
+
+type strictAudit struct {
+	CreatedBy string `json:"createdBy"`
+}
+
+type strictOrder struct {
+	strictAudit
+	ID       uint64            `json:"id"`
+	Lines    []strictLine      `json:"lines"`
+	Labels   map[string]uint16 `json:"labels"`
+	Extra    map[string]any    `json:"extra"`
+	Placed   time.Time         `json:"placed"`
+	Internal string            `json:"-"`
+}
+
+func TestStrictDecoding(t *testing.T) {
+	spec := &Spec{Type: "object"}
+
+	tests := []struct {
+		name   string
+		data   string
+		errors []string // path: code
+	}{
+		{"valid", `{"id": 18446744073709551615, "CreatedBy": "ann", "lines": [{"SKU": "a", "quantity": -128}],
+			"labels": {"a": 65535}, "extra": {"anything": 1.5}, "placed": "2026-01-02T03:04:05Z"}`, nil},
+		{"unknown fields", `{"idd": 1, "Internal": "x", "lines": [{"sku": "a", "qty": 2}], "extra": {"free": true}}`,
+			[]string{"Internal: unknownField", "idd: unknownField", "lines[0].qty: unknownField"}},
+		{"overflow", `{"id": -1, "lines": [{"quantity": 128}, {"quantity": -129}], "labels": {"a": 65536}}`,
+			[]string{"id: min", "labels.a: max", "lines[0].quantity: max", "lines[1].quantity: min"}},
+		{"beyond float64 precision", `{"id": 18446744073709551616}`, []string{"id: max"}},
+		{"fractions", `{"lines": [{"quantity": 1.5}, {"quantity": 1e2}]}`,
+			[]string{"lines[0].quantity: type", "lines[1].quantity: type"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := DecodeAndValidate[strictOrder](strings.NewReader(tt.data),
+				WithSpec(spec), DisallowUnknownFields(), RejectIntegerOverflow())
+			if err != nil {
+				t.Fatalf("DecodeAndValidate failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Path+": "+e.Code)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.errors, "; ") {
+				t.Errorf("expected %v, got %v", tt.errors, got)
+			}
+		})
+	}
+}
+
+func TestStrictDecodingMessages(t *testing.T) {
+	result, _, err := ValidateJSONAs[strictLine]([]byte(`{"sku": "a", "quantity": 300, "colour": "red"}`),
+		&Spec{Type: "object"}, DisallowUnknownFields(), RejectIntegerOverflow())
+	if err != nil {
+		t.Fatalf("ValidateJSONAs failed: %v", err)
+	}
+	var got []string
+	for _, e := range result.Errors {
+		got = append(got, e.Error())
+	}
+	expected := []string{
+		`colour: unknown field "colour" for mowgli.strictLine`,
+		"quantity: integer 300 overflows int8 (maximum 127)",
+	}
+	if strings.Join(got, "; ") != strings.Join(expected, "; ") {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if result.Errors[1].Params["limit"] != int64(127) {
+		t.Errorf("expected limit 127, got %v", result.Errors[1].Params["limit"])
+	}
+}
+
+func TestStrictDecodingDefaults(t *testing.T) {
+	// Without the options, unknown fields are dropped and overflowing
+	// numbers fail decoding
+	result, line, err := ValidateJSONAs[strictLine]([]byte(`{"sku": "a", "colour": "red"}`), &Spec{Type: "object"})
+	if err != nil || !result.Valid || line.SKU != "a" {
+		t.Errorf("expected unknown fields to be ignored, got %v, %v", result, err)
+	}
+	if _, _, err := ValidateJSONAs[strictLine]([]byte(`{"quantity": 300}`), &Spec{Type: "object"}); err == nil {
+		t.Error("expected overflowing numbers to fail decoding")
+	}
+}
+
+func TestTrailingData(t *testing.T) {
+	spec := &Spec{Type: "object"}
+	for _, data := range []string{`{} {}`, `{"a": 1} x`, `[] ]`} {
+		t.Run(data, func(t *testing.T) {
+			if _, err := ValidateJSON([]byte(data), spec); err == nil {
+				t.Error("expected ValidateJSON to reject trailing data")
+			}
+			if _, err := NewValidator(WithUseNumber()).ValidateJSON([]byte(data), spec); err == nil {
+				t.Error("expected ValidateJSON with WithUseNumber to reject trailing data")
+			}
+			if _, _, err := DecodeAndValidate[map[string]any](strings.NewReader(data), WithSpec(spec)); err == nil {
+				t.Error("expected DecodeAndValidate to reject trailing data")
+			}
+		})
+	}
+	if _, err := ValidateJSON([]byte("{}\n\t "), spec); err != nil {
+		t.Errorf("expected trailing whitespace to be accepted, got %v", err)
+	}
+}
@@ -10,19 +10,83 @@ import (
 
 // StructTagOptions holds parsed validation options from struct tags
 type StructTagOptions struct {
-	Required   bool
-	Min        *float64
-	Max        *float64
-	MinLength  *int
-	MaxLength  *int
-	Pattern    *string
-	Enum       []any
-	AllowEmpty *bool
+	Required    bool
+	Min         *float64
+	Max         *float64
+	MinInt      json.Number // Exact integer minimum, e.g. for int64 fields
+	MaxInt      json.Number // Exact integer maximum, e.g. for uint64 fields
+	MinLength   *int
+	MaxLength   *int
+	LengthUnit  string // Unit of minLength and maxLength for strings, e.g. "bytes"
+	MinBytes    *int
+	MaxBytes    *int
+	Pattern     *string
+	Format      *string
+	TimeFormat  string   // Go time layout, e.g. "2006-01-02"; replaces the date-time format of time.Time fields
+	SemverRange string   // Semantic version range, e.g. ">=1.2.0 <2.0.0"
+	URISchemes  []string // Schemes a URI may use, e.g. ["https"]
+	PublicHost  bool     // Rejects URIs with loopback, private and link-local hosts
+	Enum        []any
+	AllowEmpty  *bool
+	Unique      bool
+	MinItems    *int
+	MaxItems    *int
+	KeyPattern  *string  // Pattern every key of a map must match
+	Transform   []string // String transforms applied before validation, e.g. ["trim", "lower"]
+	ReadOnly    bool     // Rejected when validating requests
+	WriteOnly   bool     // Rejected when validating responses
+	Deprecated  bool     // Reports a warning when the field is present
+
+	// Field/value pairs, e.g. ["Type", "card"], making the field required
+	// if (or unless) every named field has the given value
+	RequiredIf     []string
+	RequiredUnless []string
+
+	Messages map[string]string // Custom error messages keyed by error code, from msg= options
+
+	Dive *StructTagOptions // Options following "dive", applied to array elements or map values
 }
 
 // ParseStructTag parses a mowgli struct tag and returns validation options
-// Example: `mowgli:"required,min=0,max=100,minLength=1"`
+// Example: `mowgli:"required,min=0,max=100,minLength=1"` or `mowgli:"required,format=email"`
+//
+// required_if and required_unless take space-separated field/value pairs, e.g.
+// `mowgli:"required_if=Type card"`, and make the field required if (or unless)
+// every named field has the given value. Fields are named by Go or JSON name.
+//
+// msg sets a custom error message for the rule before it, e.g.
+// `mowgli:"minLength=8,msg=Password must be at least 8 characters"`. The
+// message runs until the next option, so it may contain commas.
+//
+// Options after a "dive" section apply to the elements of a slice or array,
+// or to the values of a map, e.g. `mowgli:"maxLength=10,dive,minLength=3,maxLength=20"`.
+//
+// transform takes space-separated string transforms applied before the
+// other rules, e.g. `mowgli:"transform=trim lower,format=email"`.
+//
+// rules=name expands to the options of a rule set registered with RegisterRuleSet.
 func ParseStructTag(tag string) (*StructTagOptions, error) {
+	tag, err := expandRuleSets(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	if before, after, ok := splitDive(tag); ok {
+		options, err := ParseStructTag(before)
+		if err != nil {
+			return nil, err
+		}
+		dive, err := ParseStructTag(after)
+		if err != nil {
+			return nil, err
+		}
+		if dive.Required || dive.RequiredIf != nil || dive.RequiredUnless != nil {
+			return nil, fmt.Errorf("required options are not valid after dive")
+		}
+		options.Dive = dive
+		return options, nil
+	}
+
 	options := &StructTagOptions{}
 
 	if tag == "" {
@@ -50,13 +114,43 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 				options.Required = beforeOpts.Required
 				options.Min = beforeOpts.Min
 				options.Max = beforeOpts.Max
+				options.MinInt = beforeOpts.MinInt
+				options.MaxInt = beforeOpts.MaxInt
 				options.MinLength = beforeOpts.MinLength
 				options.MaxLength = beforeOpts.MaxLength
+				options.LengthUnit = beforeOpts.LengthUnit
+				options.MinBytes = beforeOpts.MinBytes
+				options.MaxBytes = beforeOpts.MaxBytes
 				options.Pattern = beforeOpts.Pattern
+				options.Format = beforeOpts.Format
+				options.TimeFormat = beforeOpts.TimeFormat
+				options.SemverRange = beforeOpts.SemverRange
+				options.URISchemes = beforeOpts.URISchemes
+				options.PublicHost = beforeOpts.PublicHost
 				options.AllowEmpty = beforeOpts.AllowEmpty
+				options.Unique = beforeOpts.Unique
+				options.MinItems = beforeOpts.MinItems
+				options.MaxItems = beforeOpts.MaxItems
+				options.KeyPattern = beforeOpts.KeyPattern
+				options.Transform = beforeOpts.Transform
+				options.ReadOnly = beforeOpts.ReadOnly
+				options.WriteOnly = beforeOpts.WriteOnly
+				options.Deprecated = beforeOpts.Deprecated
+				options.RequiredIf = beforeOpts.RequiredIf
+				options.RequiredUnless = beforeOpts.RequiredUnless
+				options.Messages = beforeOpts.Messages
 			}
 		}
 
+		// A message for the enum rule follows its values
+		if values, message, ok := strings.Cut(after, ",msg="); ok {
+			after = values
+			if options.Messages == nil {
+				options.Messages = make(map[string]string)
+			}
+			options.Messages[CodeEnum] = strings.TrimSpace(message)
+		}
+
 		// Parse enum value (may contain commas, so don't split)
 		enumValues, err := parseEnumValues(after)
 		if err != nil {
@@ -67,7 +161,8 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 	}
 
 	// No enum, proceed with normal comma-separated parsing
-	parts := strings.Split(tag, ",")
+	parts := joinMessageParts(strings.Split(tag, ","))
+	lastCode := "" // Error code of the previous rule, for msg
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
 		if part == "" {
@@ -76,12 +171,44 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 
 		if part == "required" {
 			options.Required = true
+			lastCode = CodeRequired
 			continue
 		}
 
 		if part == "allowEmpty" {
 			trueVal := true
 			options.AllowEmpty = &trueVal
+			lastCode = ""
+			continue
+		}
+
+		if part == "unique" {
+			options.Unique = true
+			lastCode = CodeUniqueItems
+			continue
+		}
+
+		if part == "readOnly" {
+			options.ReadOnly = true
+			lastCode = CodeReadOnly
+			continue
+		}
+
+		if part == "writeOnly" {
+			options.WriteOnly = true
+			lastCode = CodeWriteOnly
+			continue
+		}
+
+		if part == "deprecated" {
+			options.Deprecated = true
+			lastCode = CodeDeprecated
+			continue
+		}
+
+		if part == "publicHost" {
+			options.PublicHost = true
+			lastCode = CodePublicHost
 			continue
 		}
 
@@ -94,6 +221,18 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 		key := strings.TrimSpace(kv[0])
 		value := strings.TrimSpace(kv[1])
 
+		if key == "msg" {
+			if lastCode == "" {
+				return nil, fmt.Errorf("msg must follow a validation rule: %s", part)
+			}
+			if options.Messages == nil {
+				options.Messages = make(map[string]string)
+			}
+			options.Messages[lastCode] = value
+			continue
+		}
+		lastCode = tagOptionCodes[key]
+
 		switch key {
 		case "min":
 			val, err := strconv.ParseFloat(value, 64)
@@ -107,6 +246,15 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 				return nil, fmt.Errorf("invalid max value: %s", value)
 			}
 			options.Max = &val
+		case "minInt", "maxInt":
+			if _, err := intBound(json.Number(value)); err != nil {
+				return nil, fmt.Errorf("invalid %s value: %s", key, value)
+			}
+			if key == "minInt" {
+				options.MinInt = json.Number(value)
+			} else {
+				options.MaxInt = json.Number(value)
+			}
 		case "minLength":
 			val, err := strconv.Atoi(value)
 			if err != nil {
@@ -119,8 +267,82 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 				return nil, fmt.Errorf("invalid maxLength value: %s", value)
 			}
 			options.MaxLength = &val
+		case "minItems":
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid minItems value: %s", value)
+			}
+			options.MinItems = &val
+		case "maxItems":
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid maxItems value: %s", value)
+			}
+			options.MaxItems = &val
+		case "minBytes":
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid minBytes value: %s", value)
+			}
+			options.MinBytes = &val
+		case "maxBytes":
+			val, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid maxBytes value: %s", value)
+			}
+			options.MaxBytes = &val
+		case "lengthUnit":
+			if err := checkLengthUnit(value); err != nil {
+				return nil, err
+			}
+			options.LengthUnit = value
 		case "pattern":
 			options.Pattern = &value
+		case "keyPattern":
+			options.KeyPattern = &value
+		case "transform":
+			names := strings.Fields(value)
+			if len(names) == 0 {
+				return nil, fmt.Errorf("invalid transform value: %s", value)
+			}
+			if err := checkTransforms(names); err != nil {
+				return nil, err
+			}
+			options.Transform = names
+		case "required_if", "required_unless":
+			pairs := strings.Fields(value)
+			if len(pairs) == 0 || len(pairs)%2 != 0 {
+				return nil, fmt.Errorf("invalid %s value: %s (expected field/value pairs)", key, value)
+			}
+			if key == "required_if" {
+				options.RequiredIf = pairs
+			} else {
+				options.RequiredUnless = pairs
+			}
+		case "format":
+			if _, ok := lookupFormat(value); !ok {
+				return nil, fmt.Errorf("unknown format: %s", value)
+			}
+			options.Format = &value
+		case "timeFormat":
+			if err := checkTimeFormat(value); err != nil {
+				return nil, err
+			}
+			options.TimeFormat = value
+		case "semverRange":
+			if err := checkSemverRange(value); err != nil {
+				return nil, err
+			}
+			options.SemverRange = value
+		case "uriSchemes":
+			schemes := strings.Fields(value)
+			if len(schemes) == 0 {
+				return nil, fmt.Errorf("invalid uriSchemes value: %s", value)
+			}
+			if err := checkURISchemes(schemes); err != nil {
+				return nil, err
+			}
+			options.URISchemes = schemes
 		default:
 			return nil, fmt.Errorf("unknown tag option: %s", key)
 		}
@@ -129,6 +351,117 @@ func ParseStructTag(tag string) (*StructTagOptions, error) {
 	return options, nil
 }
 
+// tagOptionCodes maps tag options to the error code reported when the rule
+// fails, or "" for options that can't carry a msg
+var tagOptionCodes = map[string]string{
+	"required":        CodeRequired,
+	"required_if":     CodeRequired,
+	"required_unless": CodeRequired,
+	"allowEmpty":      "",
+	"min":             CodeMin,
+	"max":             CodeMax,
+	"minInt":          CodeMinInt,
+	"maxInt":          CodeMaxInt,
+	"minLength":       CodeMinLength,
+	"maxLength":       CodeMaxLength,
+	"lengthUnit":      "",
+	"minBytes":        CodeMinBytes,
+	"maxBytes":        CodeMaxBytes,
+	"minItems":        CodeMinLength,
+	"maxItems":        CodeMaxLength,
+	"unique":          CodeUniqueItems,
+	"pattern":         CodePattern,
+	"format":          CodeFormat,
+	"timeFormat":      CodeTimeFormat,
+	"semverRange":     CodeSemverRange,
+	"uriSchemes":      CodeURISchemes,
+	"publicHost":      CodePublicHost,
+	"keyPattern":      "",
+	"transform":       "",
+	"readOnly":        CodeReadOnly,
+	"writeOnly":       CodeWriteOnly,
+	"deprecated":      CodeDeprecated,
+	"enum":            CodeEnum,
+	"msg":             "",
+	"dive":            "",
+}
+
+// joinMessageParts rejoins msg values that were split at commas, e.g.
+// ["msg=Too short", " try again"] -> ["msg=Too short, try again"]
+func joinMessageParts(parts []string) []string {
+	joined := make([]string, 0, len(parts))
+	inMessage := false
+	for _, part := range parts {
+		key, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if _, isOption := tagOptionCodes[key]; inMessage && !isOption {
+			joined[len(joined)-1] += "," + part
+			continue
+		}
+		inMessage = key == "msg"
+		joined = append(joined, part)
+	}
+	return joined
+}
+
+// splitDive splits a tag at its first "dive" option
+func splitDive(tag string) (before, after string, ok bool) {
+	start := 0
+	for start <= len(tag) {
+		end := strings.IndexByte(tag[start:], ',')
+		if end == -1 {
+			end = len(tag)
+		} else {
+			end += start
+		}
+		if strings.TrimSpace(tag[start:end]) == "dive" {
+			return tag[:start], strings.TrimPrefix(tag[end:], ","), true
+		}
+		start = end + 1
+	}
+	return "", "", false
+}
+
+// requiredCondition builds the expression for required_if/required_unless pairs,
+// e.g. ["Type", "card"] -> `type == "card"`
+func requiredCondition(pairs []string, jsonNames map[string]string) (string, error) {
+	clauses := make([]string, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		name, value := pairs[i], pairs[i+1]
+		if jsonName, ok := jsonNames[name]; ok {
+			name = jsonName
+		} else if !containsValue(jsonNames, name) {
+			return "", fmt.Errorf("unknown field %s", name)
+		}
+		if !identifierPattern.MatchString(name) {
+			return "", fmt.Errorf("field %s cannot be used in a condition", name)
+		}
+		clauses = append(clauses, name+" == "+conditionLiteral(value))
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+func containsValue(m map[string]string, value string) bool {
+	for _, v := range m {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionLiteral renders a tag value as an expression literal: numbers,
+// booleans and null as-is, anything else as a quoted string
+func conditionLiteral(value string) string {
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	switch value {
+	case "true", "false", "null":
+		return value
+	}
+	return strconv.Quote(value)
+}
+
 func parseEnumValues(value string) ([]any, error) {
 	// Try parsing as JSON array first
 	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
@@ -161,7 +494,9 @@ func parseEnumValues(value string) ([]any, error) {
 	return result, nil
 }
 
-// SpecFromStruct generates a Spec from a struct type using reflection and struct tags
+// SpecFromStruct generates a Spec from a struct type using reflection and struct tags.
+// Fields whose type has a mapping registered with RegisterTypeMapping, or
+// implements SpecProvider, use that spec instead of one derived from the type.
 func SpecFromStruct(v any) (*Spec, error) {
 	rt := reflect.TypeOf(v)
 	if rt.Kind() == reflect.Ptr {
@@ -178,6 +513,11 @@ func SpecFromStruct(v any) (*Spec, error) {
 		Required:   []string{},
 	}
 
+	// Go field names mapped to JSON names, for required_if and required_unless
+	jsonNames := make(map[string]string)
+	var conditional []string
+	conditionalOptions := make(map[string]*StructTagOptions)
+
 	for i := 0; i < rt.NumField(); i++ {
 		field := rt.Field(i)
 
@@ -208,96 +548,209 @@ func SpecFromStruct(v any) (*Spec, error) {
 			return nil, fmt.Errorf("error parsing struct tag for field %s: %w", fieldName, err)
 		}
 
-		// Determine field type
-		fieldType := field.Type
-		if fieldType.Kind() == reflect.Ptr {
-			fieldType = fieldType.Elem()
-		}
-
-		fieldSpec := &Spec{}
-
-		// Map Go types to JSON types
-		switch fieldType.Kind() {
-		case reflect.String:
-			fieldSpec.Type = "string"
-			fieldSpec.MinLength = options.MinLength
-			fieldSpec.MaxLength = options.MaxLength
-			fieldSpec.Pattern = options.Pattern
-			fieldSpec.AllowEmpty = options.AllowEmpty
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			fieldSpec.Type = "integer"
-			fieldSpec.Min = options.Min
-			fieldSpec.Max = options.Max
-		case reflect.Float32, reflect.Float64:
-			fieldSpec.Type = "number"
-			fieldSpec.Min = options.Min
-			fieldSpec.Max = options.Max
-		case reflect.Bool:
-			fieldSpec.Type = "boolean"
-		case reflect.Slice, reflect.Array:
-			fieldSpec.Type = "array"
-			fieldSpec.MinLength = options.MinLength
-			fieldSpec.MaxLength = options.MaxLength
-			// Handle array item types
-			elemType := fieldType.Elem()
-			if elemType.Kind() == reflect.Ptr {
-				elemType = elemType.Elem()
-			}
-			switch elemType.Kind() {
-			case reflect.String:
-				fieldSpec.Items = &Spec{Type: "string"}
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-				fieldSpec.Items = &Spec{Type: "integer"}
-			case reflect.Float32, reflect.Float64:
-				fieldSpec.Items = &Spec{Type: "number"}
-			case reflect.Bool:
-				fieldSpec.Items = &Spec{Type: "boolean"}
-			case reflect.Struct:
-				// Recursively generate spec for array item type
-				nestedSpec, err := SpecFromStruct(reflect.New(elemType).Interface())
-				if err != nil {
-					return nil, fmt.Errorf("error generating spec for array item type %s: %w", fieldName, err)
-				}
-				fieldSpec.Items = nestedSpec
+		fieldSpec, err := specFromType(fieldName, field.Type, options)
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Properties[fieldName] = fieldSpec
+		jsonNames[field.Name] = fieldName
+
+		if options.Required {
+			spec.Required = append(spec.Required, fieldName)
+		}
+		if options.RequiredIf != nil || options.RequiredUnless != nil {
+			conditional = append(conditional, fieldName)
+			conditionalOptions[fieldName] = options
+		}
+	}
+
+	// Conditionally required fields become conditions, in field order
+	for _, name := range conditional {
+		options := conditionalOptions[name]
+		if options.RequiredIf != nil {
+			expr, err := requiredCondition(options.RequiredIf, jsonNames)
+			if err != nil {
+				return nil, fmt.Errorf("invalid required_if for field %s: %w", name, err)
 			}
-		case reflect.Struct:
-			// Recursively generate spec for nested struct
-			nestedSpec, err := SpecFromStruct(reflect.New(fieldType).Interface())
+			spec.Conditions = append(spec.Conditions, Condition{If: expr, Required: []string{name}})
+		}
+		if options.RequiredUnless != nil {
+			expr, err := requiredCondition(options.RequiredUnless, jsonNames)
 			if err != nil {
-				return nil, fmt.Errorf("error generating spec for nested struct %s: %w", fieldName, err)
+				return nil, fmt.Errorf("invalid required_unless for field %s: %w", name, err)
 			}
-			fieldSpec = nestedSpec
-		case reflect.Map:
-			fieldSpec.Type = "object"
-			// For maps, we treat them as generic objects
-		default:
-			return nil, fmt.Errorf("unsupported field type for %s: %s", fieldName, fieldType.Kind())
+			spec.Conditions = append(spec.Conditions, Condition{Unless: expr, Required: []string{name}})
 		}
+	}
+
+	return spec, nil
+}
 
-		// Apply common options
-		if options.Min != nil && fieldSpec.Min == nil {
-			fieldSpec.Min = options.Min
+// specFromType maps a Go type to a spec, applying the field's tag options
+func specFromType(fieldName string, t reflect.Type, options *StructTagOptions) (*Spec, error) {
+	// Determine field type. Pointers are nullable unless the field is
+	// required, in which case it must be present and non-null.
+	fieldType := t
+	var nullable *bool
+	if fieldType.Kind() == reflect.Ptr {
+		fieldType = fieldType.Elem()
+		if !options.Required {
+			trueVal := true
+			nullable = &trueVal
 		}
-		if options.Max != nil && fieldSpec.Max == nil {
-			fieldSpec.Max = options.Max
+	}
+
+	// Registered mappings and SpecProvider types describe themselves
+	if mapped, ok := mappedSpec(fieldType); ok {
+		if options.Dive != nil || options.KeyPattern != nil {
+			return nil, fmt.Errorf("dive and keyPattern are not supported on mapped type %s: %s", fieldType, fieldName)
 		}
-		if options.Enum != nil {
-			fieldSpec.Enum = options.Enum
+		mappedOptions := tagOptionsSpec(mapped.Type, options)
+		mappedOptions.Nullable = nullable
+		merged := MergeSpecs(mapped, mappedOptions)
+		if options.TimeFormat != "" && options.Format == nil {
+			// A layout replaces the mapped format, e.g. time.Time's date-time
+			merged.Format = nil
 		}
+		return merged, nil
+	}
 
-		spec.Properties[fieldName] = fieldSpec
-
-		if options.Required {
-			spec.Required = append(spec.Required, fieldName)
+	fieldSpec := &Spec{}
+
+	// Map Go types to JSON types
+	switch fieldType.Kind() {
+	case reflect.String:
+		fieldSpec.Type = "string"
+		fieldSpec.MinLength = options.MinLength
+		fieldSpec.MaxLength = options.MaxLength
+		fieldSpec.LengthUnit = options.LengthUnit
+		fieldSpec.MinBytes = options.MinBytes
+		fieldSpec.MaxBytes = options.MaxBytes
+		fieldSpec.Pattern = options.Pattern
+		fieldSpec.Format = options.Format
+		fieldSpec.TimeFormat = options.TimeFormat
+		fieldSpec.SemverRange = options.SemverRange
+		fieldSpec.URISchemes = options.URISchemes
+		if options.PublicHost {
+			fieldSpec.PublicHost = &options.PublicHost
+		}
+		fieldSpec.AllowEmpty = options.AllowEmpty
+		fieldSpec.Transform = options.Transform
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		fieldSpec.Type = "integer"
+		fieldSpec.Min = options.Min
+		fieldSpec.Max = options.Max
+		fieldSpec.MinInt = options.MinInt
+		fieldSpec.MaxInt = options.MaxInt
+	case reflect.Float32, reflect.Float64:
+		fieldSpec.Type = "number"
+		fieldSpec.Min = options.Min
+		fieldSpec.Max = options.Max
+		fieldSpec.MinInt = options.MinInt
+		fieldSpec.MaxInt = options.MaxInt
+	case reflect.Bool:
+		fieldSpec.Type = "boolean"
+	case reflect.Slice, reflect.Array:
+		fieldSpec.Type = "array"
+		fieldSpec.MinLength = options.MinLength
+		fieldSpec.MaxLength = options.MaxLength
+		if options.MinItems != nil {
+			fieldSpec.MinLength = options.MinItems
+		}
+		if options.MaxItems != nil {
+			fieldSpec.MaxLength = options.MaxItems
+		}
+		if options.Unique {
+			fieldSpec.UniqueItems = &options.Unique
+		}
+		// Handle array item types, applying any options after "dive"
+		itemOptions := options.Dive
+		if itemOptions == nil {
+			itemOptions = &StructTagOptions{}
+		}
+		elemType := fieldType.Elem()
+		if elemType.Kind() != reflect.Interface && !(elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Interface) {
+			itemSpec, err := specFromType(fieldName+"[]", elemType, itemOptions)
+			if err != nil {
+				return nil, err
+			}
+			fieldSpec.Items = itemSpec
+		}
+	case reflect.Struct:
+		// Recursively generate spec for nested struct
+		nestedSpec, err := SpecFromStruct(reflect.New(fieldType).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("error generating spec for nested struct %s: %w", fieldName, err)
+		}
+		fieldSpec = nestedSpec
+	case reflect.Map:
+		fieldSpec.Type = "object"
+		// Map values are validated against a spec for the value type, with any
+		// options after "dive" applied to it
+		valueOptions := options.Dive
+		if valueOptions == nil {
+			valueOptions = &StructTagOptions{}
 		}
+		valueType := fieldType.Elem()
+		if valueType.Kind() != reflect.Interface && !(valueType.Kind() == reflect.Ptr && valueType.Elem().Kind() == reflect.Interface) {
+			valueSpec, err := specFromType(fieldName+".*", valueType, valueOptions)
+			if err != nil {
+				return nil, err
+			}
+			fieldSpec.AdditionalProperties = valueSpec
+		}
+		if options.KeyPattern != nil {
+			fieldSpec.PropertyNames = &Spec{Type: "string", Pattern: options.KeyPattern}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported field type for %s: %s", fieldName, fieldType.Kind())
 	}
 
-	return spec, nil
+	// Apply common options
+	if options.Min != nil && fieldSpec.Min == nil {
+		fieldSpec.Min = options.Min
+	}
+	if options.Max != nil && fieldSpec.Max == nil {
+		fieldSpec.Max = options.Max
+	}
+	if options.Enum != nil {
+		fieldSpec.Enum = options.Enum
+	}
+	if options.Messages != nil {
+		fieldSpec.Messages = options.Messages
+	}
+	if options.ReadOnly {
+		fieldSpec.ReadOnly = &options.ReadOnly
+	}
+	if options.WriteOnly {
+		fieldSpec.WriteOnly = &options.WriteOnly
+	}
+	if options.Deprecated {
+		fieldSpec.Deprecated = &options.Deprecated
+	}
+	if nullable != nil {
+		fieldSpec.Nullable = nullable
+	}
+	isCollection := fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array || fieldType.Kind() == reflect.Map
+	if options.Dive != nil && !isCollection {
+		return nil, fmt.Errorf("dive is only valid on slice, array and map fields: %s", fieldName)
+	}
+	isArray := fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array
+	if (options.Unique || options.MinItems != nil || options.MaxItems != nil) && !isArray {
+		return nil, fmt.Errorf("unique, minItems and maxItems are only valid on slice and array fields: %s", fieldName)
+	}
+	if options.KeyPattern != nil && fieldType.Kind() != reflect.Map {
+		return nil, fmt.Errorf("keyPattern is only valid on map fields: %s", fieldName)
+	}
+
+	return fieldSpec, nil
 }
 
-// MergeSpecs merges two specs, with override taking precedence
+// MergeSpecs merges two specs, with override taking precedence: every
+// keyword set in override replaces base's, including required. Properties
+// are merged by name. MergeSpecsWith can combine keywords instead.
 func MergeSpecs(base, override *Spec) *Spec {
 	if base == nil {
 		return override
@@ -312,20 +765,61 @@ func MergeSpecs(base, override *Spec) *Spec {
 		Items:      base.Items,
 		Required:   base.Required,
 		Conditions: base.Conditions,
-		Min:        base.Min,
-		Max:        base.Max,
-		MinLength:  base.MinLength,
-		MaxLength:  base.MaxLength,
-		Pattern:    base.Pattern,
-		Enum:       base.Enum,
-		AllowEmpty: base.AllowEmpty,
+		RequiredIf: base.RequiredIf,
+		Ref:        base.Ref,
+
+		AdditionalProperties: base.AdditionalProperties,
+		PropertyNames:        base.PropertyNames,
+		Discriminator:        base.Discriminator,
+		If:                   base.If,
+		Then:                 base.Then,
+		Else:                 base.Else,
+		AnyRequired:          base.AnyRequired,
+		OneRequired:          base.OneRequired,
+		ContentEncoding:      base.ContentEncoding,
+		ContentMediaType:     base.ContentMediaType,
+		ContentSchema:        base.ContentSchema,
+		UniqueItems:          base.UniqueItems,
+
+		Min:         base.Min,
+		Max:         base.Max,
+		MinInt:      base.MinInt,
+		MaxInt:      base.MaxInt,
+		MinLength:   base.MinLength,
+		MaxLength:   base.MaxLength,
+		LengthUnit:  base.LengthUnit,
+		MinBytes:    base.MinBytes,
+		MaxBytes:    base.MaxBytes,
+		Pattern:     base.Pattern,
+		Format:      base.Format,
+		TimeFormat:  base.TimeFormat,
+		SemverRange: base.SemverRange,
+		URISchemes:  base.URISchemes,
+		PublicHost:  base.PublicHost,
+		Enum:        base.Enum,
+		AllowEmpty:  base.AllowEmpty,
+		Nullable:    base.Nullable,
+		Checks:      base.Checks,
+		ReadOnly:    base.ReadOnly,
+		WriteOnly:   base.WriteOnly,
+		Deprecated:  base.Deprecated,
+		Transform:   base.Transform,
+		Examples:    base.Examples,
+		Messages:    base.Messages,
+		Formats:     base.Formats,
+		Weight:      base.Weight,
+		Severity:    base.Severity,
+
+		ConditionDefs: base.ConditionDefs,
+		Asserts:       base.Asserts,
+		Default:       base.Default,
+		Overrides:     base.Overrides,
+		RefIntegrity:  base.RefIntegrity,
 	}
 
-	// Merge properties
+	// Merge properties into a new map so that base is left unchanged
 	if override.Properties != nil {
-		if merged.Properties == nil {
-			merged.Properties = make(map[string]*Spec)
-		}
+		merged.Properties = make(map[string]*Spec, len(base.Properties)+len(override.Properties))
 		for k, v := range base.Properties {
 			merged.Properties[k] = v
 		}
@@ -347,27 +841,143 @@ func MergeSpecs(base, override *Spec) *Spec {
 	if override.Conditions != nil {
 		merged.Conditions = override.Conditions
 	}
+	if override.RequiredIf != nil {
+		merged.RequiredIf = override.RequiredIf
+	}
+	if override.AnyRequired != nil {
+		merged.AnyRequired = override.AnyRequired
+	}
+	if override.OneRequired != nil {
+		merged.OneRequired = override.OneRequired
+	}
+	if override.Discriminator != nil {
+		merged.Discriminator = override.Discriminator
+	}
+	if override.If != nil {
+		merged.If = override.If
+		merged.Then = override.Then
+		merged.Else = override.Else
+	}
+	if override.Ref != "" {
+		merged.Ref = override.Ref
+	}
+	if override.AdditionalProperties != nil {
+		merged.AdditionalProperties = override.AdditionalProperties
+	}
+	if override.PropertyNames != nil {
+		merged.PropertyNames = override.PropertyNames
+	}
+	if override.ContentEncoding != "" {
+		merged.ContentEncoding = override.ContentEncoding
+	}
+	if override.ContentMediaType != "" {
+		merged.ContentMediaType = override.ContentMediaType
+	}
+	if override.ContentSchema != nil {
+		merged.ContentSchema = override.ContentSchema
+	}
 	if override.Min != nil {
 		merged.Min = override.Min
 	}
 	if override.Max != nil {
 		merged.Max = override.Max
 	}
+	if override.MinInt != "" {
+		merged.MinInt = override.MinInt
+	}
+	if override.MaxInt != "" {
+		merged.MaxInt = override.MaxInt
+	}
 	if override.MinLength != nil {
 		merged.MinLength = override.MinLength
 	}
 	if override.MaxLength != nil {
 		merged.MaxLength = override.MaxLength
 	}
+	if override.LengthUnit != "" {
+		merged.LengthUnit = override.LengthUnit
+	}
+	if override.MinBytes != nil {
+		merged.MinBytes = override.MinBytes
+	}
+	if override.MaxBytes != nil {
+		merged.MaxBytes = override.MaxBytes
+	}
 	if override.Pattern != nil {
 		merged.Pattern = override.Pattern
 	}
+	if override.Format != nil {
+		merged.Format = override.Format
+	}
+	if override.TimeFormat != "" {
+		merged.TimeFormat = override.TimeFormat
+	}
+	if override.SemverRange != "" {
+		merged.SemverRange = override.SemverRange
+	}
+	if override.URISchemes != nil {
+		merged.URISchemes = override.URISchemes
+	}
+	if override.PublicHost != nil {
+		merged.PublicHost = override.PublicHost
+	}
 	if override.Enum != nil {
 		merged.Enum = override.Enum
 	}
 	if override.AllowEmpty != nil {
 		merged.AllowEmpty = override.AllowEmpty
 	}
+	if override.Nullable != nil {
+		merged.Nullable = override.Nullable
+	}
+	if override.UniqueItems != nil {
+		merged.UniqueItems = override.UniqueItems
+	}
+	if override.Checks != nil {
+		merged.Checks = override.Checks
+	}
+	if override.ReadOnly != nil {
+		merged.ReadOnly = override.ReadOnly
+	}
+	if override.WriteOnly != nil {
+		merged.WriteOnly = override.WriteOnly
+	}
+	if override.Deprecated != nil {
+		merged.Deprecated = override.Deprecated
+	}
+	if override.Transform != nil {
+		merged.Transform = override.Transform
+	}
+	if override.Examples != nil {
+		merged.Examples = override.Examples
+	}
+	if override.Default != nil {
+		merged.Default = override.Default
+	}
+	if override.Messages != nil {
+		merged.Messages = override.Messages
+	}
+	if override.Formats != nil {
+		merged.Formats = override.Formats
+	}
+	if override.ConditionDefs != nil {
+		merged.ConditionDefs = override.ConditionDefs
+	}
+	if override.Asserts != nil {
+		merged.Asserts = override.Asserts
+	}
+	if override.Overrides != nil {
+		merged.Overrides = override.Overrides
+	}
+	if override.RefIntegrity != nil {
+		merged.RefIntegrity = override.RefIntegrity
+	}
+	if override.Weight != nil {
+		merged.Weight = override.Weight
+	}
+	if override.Severity != nil {
+		merged.Severity = override.Severity
+	}
 
 	return merged
 }
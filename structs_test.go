@@ -53,6 +53,123 @@ func TestParseStructTag(t *testing.T) {
 				return opts.AllowEmpty != nil && *opts.AllowEmpty == true
 			},
 		},
+		{
+			name: "format",
+			tag:  "required,format=email",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Required && opts.Format != nil && *opts.Format == "email"
+			},
+		},
+		{
+			name: "format before enum",
+			tag:  "format=uuid,enum=a,b",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Format != nil && *opts.Format == "uuid" && len(opts.Enum) == 2
+			},
+		},
+		{
+			name: "dive",
+			tag:  "required,maxLength=10,dive,minLength=3,maxLength=20",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Required && *opts.MaxLength == 10 && opts.Dive != nil &&
+					*opts.Dive.MinLength == 3 && *opts.Dive.MaxLength == 20
+			},
+		},
+		{
+			name: "dive with enum",
+			tag:  "dive,enum=red,green",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Dive != nil && len(opts.Dive.Enum) == 2 && opts.Enum == nil
+			},
+		},
+		{
+			name: "keyPattern",
+			tag:  "keyPattern=^[a-z]+$",
+			check: func(opts *StructTagOptions) bool {
+				return opts.KeyPattern != nil && *opts.KeyPattern == "^[a-z]+$"
+			},
+		},
+		{
+			name: "byte sizes",
+			tag:  "minBytes=1,maxBytes=255,msg=Too large",
+			check: func(opts *StructTagOptions) bool {
+				return *opts.MinBytes == 1 && *opts.MaxBytes == 255 && opts.Messages[CodeMaxBytes] == "Too large"
+			},
+		},
+		{
+			name: "exact integer bounds",
+			tag:  "minInt=-9223372036854775808,maxInt=18446744073709551615",
+			check: func(opts *StructTagOptions) bool {
+				return opts.MinInt == "-9223372036854775808" && opts.MaxInt == "18446744073709551615"
+			},
+		},
+		{
+			name: "timeFormat",
+			tag:  "timeFormat=2006-01-02 15:04",
+			check: func(opts *StructTagOptions) bool {
+				return opts.TimeFormat == "2006-01-02 15:04"
+			},
+		},
+		{
+			name: "semverRange",
+			tag:  "format=semver,semverRange=>=1.2.0 <2.0.0",
+			check: func(opts *StructTagOptions) bool {
+				return *opts.Format == "semver" && opts.SemverRange == ">=1.2.0 <2.0.0"
+			},
+		},
+		{
+			name: "lengthUnit",
+			tag:  "maxLength=10,lengthUnit=bytes",
+			check: func(opts *StructTagOptions) bool {
+				return opts.LengthUnit == LengthBytes && *opts.MaxLength == 10
+			},
+		},
+		{
+			name: "readOnly and writeOnly",
+			tag:  "readOnly,msg=Assigned by the server,writeOnly",
+			check: func(opts *StructTagOptions) bool {
+				return opts.ReadOnly && opts.WriteOnly && opts.Messages[CodeReadOnly] == "Assigned by the server"
+			},
+		},
+		{
+			name: "messages",
+			tag:  "required,msg=Password is required,minLength=8,msg=Too short, use at least 8 characters,pattern=[0-9]",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Messages[CodeRequired] == "Password is required" &&
+					opts.Messages[CodeMinLength] == "Too short, use at least 8 characters" &&
+					opts.Pattern != nil && *opts.Pattern == "[0-9]"
+			},
+		},
+		{
+			name: "enum message",
+			tag:  "minLength=1,msg=Empty,enum=red,green,msg=Pick red or green",
+			check: func(opts *StructTagOptions) bool {
+				return len(opts.Enum) == 2 && opts.Messages[CodeEnum] == "Pick red or green" &&
+					opts.Messages[CodeMinLength] == "Empty"
+			},
+		},
+		{
+			name:    "msg without rule",
+			tag:     "msg=Nothing to describe",
+			wantErr: true,
+		},
+		{
+			name: "unique and item counts",
+			tag:  "unique,minItems=1,maxItems=5",
+			check: func(opts *StructTagOptions) bool {
+				return opts.Unique && *opts.MinItems == 1 && *opts.MaxItems == 5
+			},
+		},
+		{
+			name:    "required after dive",
+			tag:     "dive,required",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			tag:     "format=nope",
+			wantErr: true,
+		},
 		{
 			name:    "invalid format",
 			tag:     "invalid",
@@ -122,6 +239,110 @@ func TestSpecFromStruct(t *testing.T) {
 	}
 }
 
+func TestSpecFromStructFormat(t *testing.T) {
+	type Contact struct {
+		Email string `json:"email" mowgli:"required,format=email"`
+	}
+
+	spec, err := SpecFromStruct(Contact{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	emailSpec := spec.Properties["email"]
+	if emailSpec.Format == nil || *emailSpec.Format != "email" {
+		t.Fatalf("expected email format, got %v", emailSpec.Format)
+	}
+
+	if result := Validate(map[string]any{"email": "nope"}, spec); result.Valid || result.Errors[0].Code != CodeFormat {
+		t.Errorf("expected format error, got %v", result.Errors)
+	}
+}
+
+func TestSpecFromStructDive(t *testing.T) {
+	type Post struct {
+		Tags   []string  `json:"tags" mowgli:"maxLength=10,dive,minLength=3,maxLength=20"`
+		Scores []float64 `json:"scores" mowgli:"dive,min=0,max=5"`
+		Grid   [][]int   `json:"grid" mowgli:"dive,maxLength=3,dive,min=1"`
+	}
+
+	spec, err := SpecFromStruct(Post{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tags := spec.Properties["tags"]
+	if *tags.MaxLength != 10 || tags.Items.MinLength == nil || *tags.Items.MinLength != 3 || *tags.Items.MaxLength != 20 {
+		t.Errorf("unexpected tags spec: %+v, items %+v", tags, tags.Items)
+	}
+	if scores := spec.Properties["scores"]; scores.Min != nil || *scores.Items.Min != 0 || *scores.Items.Max != 5 {
+		t.Errorf("unexpected scores spec: %+v, items %+v", scores, scores.Items)
+	}
+	if grid := spec.Properties["grid"]; *grid.Items.MaxLength != 3 || *grid.Items.Items.Min != 1 {
+		t.Errorf("unexpected grid spec: %+v", grid.Items)
+	}
+
+	result := Validate(map[string]any{"tags": []any{"go", "golang"}}, spec)
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Path != "tags[0]" {
+		t.Errorf("expected one error at tags[0], got %v", result.Errors)
+	}
+}
+
+func TestSpecFromStructDiveNonArray(t *testing.T) {
+	type Invalid struct {
+		Name string `json:"name" mowgli:"dive,minLength=1"`
+	}
+
+	if _, err := SpecFromStruct(Invalid{}); err == nil {
+		t.Error("expected error for dive on a string field")
+	}
+}
+
+func TestSpecFromStructMap(t *testing.T) {
+	type Address struct {
+		City string `json:"city" mowgli:"required"`
+	}
+	type Profile struct {
+		Labels    map[string]string  `json:"labels" mowgli:"keyPattern=^[a-z]+$,dive,maxLength=5"`
+		Addresses map[string]Address `json:"addresses"`
+		Extra     map[string]any     `json:"extra"`
+	}
+
+	spec, err := SpecFromStruct(Profile{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	labels := spec.Properties["labels"]
+	if labels.AdditionalProperties == nil || *labels.AdditionalProperties.MaxLength != 5 {
+		t.Errorf("expected labels value spec with maxLength 5, got %+v", labels.AdditionalProperties)
+	}
+	if labels.PropertyNames == nil || *labels.PropertyNames.Pattern != "^[a-z]+$" {
+		t.Errorf("expected labels key pattern, got %+v", labels.PropertyNames)
+	}
+	if addresses := spec.Properties["addresses"]; addresses.AdditionalProperties == nil || addresses.AdditionalProperties.Type != "object" {
+		t.Errorf("expected addresses value spec, got %+v", addresses.AdditionalProperties)
+	}
+	if extra := spec.Properties["extra"]; extra.AdditionalProperties != nil {
+		t.Errorf("expected no value spec for map[string]any, got %+v", extra.AdditionalProperties)
+	}
+
+	data := map[string]any{
+		"labels":    map[string]any{"env": "production", "Team": "a"},
+		"addresses": map[string]any{"home": map[string]any{}},
+	}
+	result := Validate(data, spec)
+	wantPaths := map[string]bool{"labels.env": true, "labels.Team": true, "addresses.home.city": true}
+	if len(result.Errors) != len(wantPaths) {
+		t.Fatalf("expected %d errors, got %v", len(wantPaths), result.Errors)
+	}
+	for _, err := range result.Errors {
+		if !wantPaths[err.Path] {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
 func TestSpecFromStructNested(t *testing.T) {
 	type Address struct {
 		Street string `json:"street" mowgli:"required"`
@@ -221,8 +442,211 @@ func TestMergeSpecs(t *testing.T) {
 	if len(merged.Required) != 1 || merged.Required[0] != "name" {
 		t.Errorf("expected required fields to be preserved")
 	}
+
+	if base.Properties["name"].MaxLength != nil {
+		t.Errorf("expected base spec to be left unchanged")
+	}
 }
 
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestSpecFromStructRequiredIf(t *testing.T) {
+	type Payment struct {
+		Type       string `json:"type" mowgli:"required,enum=card,bank,cash"`
+		CardNumber string `json:"cardNumber" mowgli:"required_if=Type card"`
+		IBAN       string `json:"iban" mowgli:"required_if=type bank"`
+		Receipt    string `json:"receipt" mowgli:"required_unless=Type cash"`
+	}
+
+	spec, err := SpecFromStruct(Payment{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(spec.Conditions) != 3 {
+		t.Fatalf("expected 3 conditions, got %+v", spec.Conditions)
+	}
+	if spec.Conditions[0].If != `type == "card"` {
+		t.Errorf("unexpected condition: %s", spec.Conditions[0].If)
+	}
+	if receipt := spec.Conditions[2]; receipt.If != "" || receipt.Unless != `type == "cash"` {
+		t.Errorf("expected required_unless to become an unless condition, got %+v", receipt)
+	}
+
+	tests := []struct {
+		name        string
+		data        map[string]any
+		wantMissing []string
+	}{
+		{name: "card without number", data: map[string]any{"type": "card", "receipt": "r"}, wantMissing: []string{"cardNumber"}},
+		{name: "card with number", data: map[string]any{"type": "card", "receipt": "r", "cardNumber": "4111"}},
+		{name: "bank without iban", data: map[string]any{"type": "bank", "receipt": "r"}, wantMissing: []string{"iban"}},
+		{name: "cash needs no receipt", data: map[string]any{"type": "cash"}},
+		{name: "card needs receipt", data: map[string]any{"type": "card", "cardNumber": "4111"}, wantMissing: []string{"receipt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			if len(result.Errors) != len(tt.wantMissing) {
+				t.Fatalf("expected %d errors, got %v", len(tt.wantMissing), result.Errors)
+			}
+			for i, name := range tt.wantMissing {
+				if result.Errors[i].Path != name || result.Errors[i].Code != CodeRequired {
+					t.Errorf("expected %s to be required, got %v", name, result.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSpecFromStructRequiredIfErrors(t *testing.T) {
+	type UnknownField struct {
+		A string `json:"a" mowgli:"required_if=Missing x"`
+	}
+	type OddPairs struct {
+		A string `json:"a" mowgli:"required_if=A"`
+	}
+
+	if _, err := SpecFromStruct(UnknownField{}); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := SpecFromStruct(OddPairs{}); err == nil {
+		t.Error("expected error for incomplete field/value pair")
+	}
+}
+
+func TestSpecFromStructMessages(t *testing.T) {
+	type Signup struct {
+		Password string   `json:"password" mowgli:"required,msg=Choose a password,minLength=8,msg=Password must be at least 8 characters"`
+		Tags     []string `json:"tags" mowgli:"dive,maxLength=3,msg=Tags are at most 3 characters"`
+	}
+
+	spec, err := SpecFromStruct(Signup{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		data        map[string]any
+		wantMessage string
+	}{
+		{name: "required", data: map[string]any{}, wantMessage: "Choose a password"},
+		{name: "minLength", data: map[string]any{"password": "short"}, wantMessage: "Password must be at least 8 characters"},
+		{name: "dive", data: map[string]any{"password": "long enough", "tags": []any{"long"}}, wantMessage: "Tags are at most 3 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			if len(result.Errors) != 1 || result.Errors[0].Message != tt.wantMessage {
+				t.Errorf("expected message %q, got %v", tt.wantMessage, result.Errors)
+			}
+		})
+	}
+}
+
+func TestSpecFromStructPointerFields(t *testing.T) {
+	type Profile struct {
+		Nickname *string   `json:"nickname" mowgli:"minLength=2"`
+		Age      *int      `json:"age" mowgli:"required,min=0"`
+		Tags     []*string `json:"tags"`
+		Name     string    `json:"name"`
+	}
+
+	spec, err := SpecFromStruct(Profile{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := spec.Properties["nickname"].Nullable; n == nil || !*n {
+		t.Error("expected optional pointer field to be nullable")
+	}
+	if spec.Properties["age"].Nullable != nil {
+		t.Error("expected required pointer field not to be nullable")
+	}
+	if n := spec.Properties["tags"].Items.Nullable; n == nil || !*n {
+		t.Error("expected pointer items to be nullable")
+	}
+	if spec.Properties["name"].Nullable != nil {
+		t.Error("expected value field not to be nullable")
+	}
+
+	tests := []struct {
+		name      string
+		data      map[string]any
+		wantPaths []string
+	}{
+		{name: "null optional pointer", data: map[string]any{"nickname": nil, "age": 3}},
+		{name: "absent optional pointer", data: map[string]any{"age": 3}},
+		{name: "optional pointer constraints still apply", data: map[string]any{"nickname": "x", "age": 3}, wantPaths: []string{"nickname"}},
+		{name: "null required pointer", data: map[string]any{"age": nil}, wantPaths: []string{"age"}},
+		{name: "missing required pointer", data: map[string]any{}, wantPaths: []string{"age"}},
+		{name: "null value field", data: map[string]any{"age": 3, "name": nil}, wantPaths: []string{"name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			if len(result.Errors) != len(tt.wantPaths) {
+				t.Fatalf("expected %d errors, got %v", len(tt.wantPaths), result.Errors)
+			}
+			for i, path := range tt.wantPaths {
+				if result.Errors[i].Path != path {
+					t.Errorf("expected error at %s, got %v", path, result.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSpecFromStructUniqueItems(t *testing.T) {
+	type Team struct {
+		Members []string `json:"members" mowgli:"unique,minItems=1,maxItems=3,dive,minLength=2"`
+	}
+
+	spec, err := SpecFromStruct(Team{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	members := spec.Properties["members"]
+	if members.UniqueItems == nil || !*members.UniqueItems || *members.MinLength != 1 || *members.MaxLength != 3 {
+		t.Fatalf("unexpected members spec: %+v", members)
+	}
+
+	tests := []struct {
+		name     string
+		members  []any
+		wantCode string
+	}{
+		{name: "distinct", members: []any{"ann", "bob"}},
+		{name: "duplicate", members: []any{"ann", "bob", "ann"}, wantCode: CodeUniqueItems},
+		{name: "too few", members: []any{}, wantCode: CodeMinLength},
+		{name: "too many", members: []any{"ann", "bob", "cat", "dan"}, wantCode: CodeMaxLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(map[string]any{"members": tt.members}, spec)
+			if tt.wantCode == "" {
+				if !result.Valid {
+					t.Errorf("expected validation to pass, got %v", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Code != tt.wantCode {
+				t.Errorf("expected %s error, got %v", tt.wantCode, result.Errors)
+			}
+		})
+	}
+
+	type Invalid struct {
+		Name string `json:"name" mowgli:"unique"`
+	}
+	if _, err := SpecFromStruct(Invalid{}); err == nil {
+		t.Error("expected error for unique on a string field")
+	}
+}
@@ -0,0 +1,151 @@
+package mowgli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidateAt validates only the part of a document at path, e.g. the field a
+// PATCH request changes. See Validator.ValidateAt.
+func ValidateAt(data any, spec *Spec, path string) (*ValidationResult, error) {
+	return defaultValidator.ValidateAt(data, spec, path)
+}
+
+// ValidateAt navigates data and spec to path and validates only that
+// subtree. path is either dotted ("address.city", "items[0].name") or a JSON
+// pointer ("/address/city", "/items/0/name"); "" is the whole document.
+// Conditions on the enclosing objects are applied as they would be when
+// validating the whole document, and errors carry paths from the document
+// root. An error is returned if the path doesn't exist in data or isn't
+// described by the spec.
+func (v *Validator) ValidateAt(data any, spec *Spec, path string) (*ValidationResult, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("spec is nil")
+	}
+	segments, err := parseDocumentPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := v.newResult(data)
+	value := data
+	current := ""
+	for _, segment := range segments {
+		if spec, err = r.enterContainer(current, value, spec); err != nil {
+			return nil, err
+		}
+
+		switch container := value.(type) {
+		case map[string]any:
+			child, exists := container[segment]
+			if !exists {
+				return nil, fmt.Errorf("path %s not found in document", buildPath(current, segment))
+			}
+			effectiveSpecs, _ := r.buildEffectiveSpecs(current, container, spec)
+			childSpec := spec.Properties[segment]
+			if override, ok := effectiveSpecs[segment]; ok && childSpec != nil {
+				childSpec = override
+			}
+			if childSpec == nil {
+				childSpec = spec.AdditionalProperties
+			}
+			value, spec, current = child, childSpec, buildPath(current, segment)
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, fmt.Errorf("path %s[%s] not found in document", current, segment)
+			}
+			value, spec, current = container[index], spec.Items, buildArrayPath(current, index)
+		default:
+			return nil, fmt.Errorf("path %s not found in document: %s is not an object or array", path, displayPath(current))
+		}
+
+		if spec == nil {
+			return nil, fmt.Errorf("path %s is not described by the spec", current)
+		}
+	}
+
+	r.segments = segments
+	r.validate(current, value, spec)
+	r.buildDocument()
+	if err := r.LimitError(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// enterContainer returns the spec value, a container on the path to the
+// subtree, is validated against, applying what validate does before it
+// validates children: the $ref, the overrides in scope, the if/then/else
+// subschemas and, for objects, the conditions and discriminator selecting
+// their shape. The overrides, formats and condition definitions of the spec
+// are entered for the rest of the path and are never left, as the result
+// only validates the subtree.
+func (r *ValidationResult) enterContainer(path string, value any, spec *Spec) (*Spec, error) {
+	if spec.Ref != "" {
+		resolved, err := r.resolveRef(spec)
+		if err != nil {
+			return nil, err
+		}
+		spec = resolved
+	}
+	if len(r.overrides) > 0 {
+		spec = r.overrides.apply(r, path, spec)
+	}
+	if spec.Overrides != nil {
+		if _, err := r.overrides.push(path, spec.Overrides); err != nil {
+			return nil, err
+		}
+	}
+	if spec.Formats != nil {
+		r.formats.push(spec.Formats)
+	}
+	if spec.ConditionDefs != nil {
+		r.conditionDefs.push(spec.ConditionDefs)
+	}
+	if spec.If != nil {
+		spec = r.conditional(path, value, spec)
+	}
+
+	if obj, ok := value.(map[string]any); ok {
+		r.objects = append(r.objects, obj)
+		r.exprCache = append(r.exprCache, nil)
+		spec = r.reshaped(path, obj, spec)
+		if spec.Discriminator != nil {
+			spec = r.discriminated(path, obj, spec)
+		}
+	}
+	return spec, nil
+}
+
+// parseDocumentPath splits a dotted path or JSON pointer into segments
+func parseDocumentPath(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	if strings.HasPrefix(path, "/") {
+		return parsePointer(path)
+	}
+
+	var segments []string
+	for _, part := range strings.Split(path, ".") {
+		key, rest, _ := strings.Cut(part, "[")
+		if key == "" && rest == "" {
+			return nil, fmt.Errorf("invalid path %q: empty segment", path)
+		}
+		if key != "" {
+			segments = append(segments, key)
+		}
+		for rest != "" {
+			index, after, ok := strings.Cut(rest, "]")
+			if !ok || index == "" || (after != "" && !strings.HasPrefix(after, "[")) {
+				return nil, fmt.Errorf("invalid path %q: malformed index", path)
+			}
+			segments = append(segments, index)
+			rest = strings.TrimPrefix(after, "[")
+		}
+	}
+	return segments, nil
+}
@@ -0,0 +1,142 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateAt(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"country": {"type": "string"},
+			"address": {
+				"type": "object",
+				"properties": {
+					"city": {"type": "string", "minLength": 2},
+					"zip": {"type": "string"}
+				},
+				"conditions": [{"if": "$root.country == \"US\"", "then": {"zip": {"pattern": "^[0-9]{5}$"}}}]
+			},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"qty": {"type": "integer", "min": 1}}}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string", "maxLength": 3}},
+			"a/b": {"type": "integer"}
+		},
+		"required": ["country", "missing"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	doc := map[string]any{
+		"country": "US",
+		"address": map[string]any{"city": "X", "zip": "ABCDE"},
+		"items":   []any{map[string]any{"qty": float64(1)}, map[string]any{"qty": float64(0)}},
+		"labels":  map[string]any{"env": "production"},
+		"a/b":     "1",
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantErrors []string
+		wantErr    string
+	}{
+		{name: "dotted field", path: "address.city", wantErrors: []string{"address.city: string length 1 is less than minimum 2"}},
+		{name: "condition from enclosing object", path: "address.zip", wantErrors: []string{"address.zip: string does not match pattern: ^[0-9]{5}$"}},
+		{name: "json pointer", path: "/address/zip", wantErrors: []string{"address.zip: string does not match pattern: ^[0-9]{5}$"}},
+		{name: "valid array item", path: "items[0]"},
+		{name: "invalid array item", path: "items[1].qty", wantErrors: []string{"items[1].qty: integer 0 is less than minimum 1"}},
+		{name: "json pointer index", path: "/items/1", wantErrors: []string{"items[1].qty: integer 0 is less than minimum 1"}},
+		{name: "additional property", path: "labels.env", wantErrors: []string{"labels.env: string length 10 is greater than maximum 3"}},
+		{name: "escaped json pointer", path: "/a~1b", wantErrors: []string{"a/b: expected integer, got string"}},
+		{name: "subtree skips root required", path: "country"},
+		{name: "missing field", path: "address.street", wantErr: "path address.street not found in document"},
+		{name: "index out of range", path: "items[5]", wantErr: "path items[5] not found"},
+		{name: "through a scalar", path: "country.code", wantErr: "country is not an object or array"},
+		{name: "malformed index", path: "items[0", wantErr: "malformed index"},
+		{name: "empty segment", path: "address..city", wantErr: "empty segment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ValidateAt(doc, spec, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateAt failed: %v", err)
+			}
+			var got []string
+			for _, e := range result.Errors {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, got)
+			}
+		})
+	}
+
+	if _, err := ValidateAt(map[string]any{"extra": 1}, spec, "extra"); err == nil || !strings.Contains(err.Error(), "not described by the spec") {
+		t.Errorf("expected error for a field the spec doesn't describe, got %v", err)
+	}
+	if result, err := ValidateAt(doc, spec, ""); err != nil || result.Valid {
+		t.Errorf("expected the empty path to validate the whole document, got %v %v", result, err)
+	}
+}
+
+func TestValidateAtReshapedSpecs(t *testing.T) {
+	doc := map[string]any{"payload": map[string]any{"kind": "count", "n": 3.0}}
+
+	tests := []struct {
+		name string
+		spec string
+	}{
+		{
+			name: "thenSpec",
+			spec: `{"type": "object", "properties": {"payload": {
+				"type": "object",
+				"properties": {"kind": {"type": "string"}, "n": {"type": "string"}},
+				"conditions": [{"if": "kind == \"count\"", "thenSpec": {"properties": {"kind": {"type": "string"}, "n": {"type": "integer"}}}}]
+			}}}`,
+		},
+		{
+			name: "if subschema",
+			spec: `{"type": "object", "properties": {"payload": {
+				"type": "object",
+				"properties": {"kind": {"type": "string"}, "n": {"type": "string"}},
+				"if": {"properties": {"kind": {"type": "string", "enum": ["count"]}}},
+				"then": {"properties": {"kind": {"type": "string"}, "n": {"type": "integer"}}}
+			}}}`,
+		},
+		{
+			name: "override",
+			spec: `{"type": "object", "overrides": {"payload.n": {"type": "integer"}}, "properties": {"payload": {
+				"type": "object",
+				"properties": {"kind": {"type": "string"}, "n": {"type": "string"}}
+			}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			if result := Validate(doc, spec); !result.Valid {
+				t.Fatalf("expected the document to be valid, got %v", result.Errors)
+			}
+			result, err := ValidateAt(doc, spec, "payload.n")
+			if err != nil {
+				t.Fatalf("ValidateAt failed: %v", err)
+			}
+			if !result.Valid {
+				t.Errorf("expected payload.n to be valid, got %v", result.Errors)
+			}
+		})
+	}
+}
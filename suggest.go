@@ -0,0 +1,74 @@
+package mowgli
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// suggestRequired returns the key of obj that a missing required property
+// was most likely misspelled as, e.g. "usrname" for "username": a key the
+// spec doesn't declare, within a couple of edits of the property's name
+func suggestRequired(name string, obj map[string]any, spec *Spec, effectiveSpecs map[string]*Spec) (string, bool) {
+	limit := 1
+	if len([]rune(name)) > 5 {
+		limit = 2
+	}
+	best, bestDistance := "", limit+1
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		if _, declared := spec.Properties[key]; declared {
+			continue
+		}
+		if _, declared := effectiveSpecs[key]; declared {
+			continue
+		}
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDistance {
+			best, bestDistance = key, d
+		}
+	}
+	return best, best != ""
+}
+
+// suggestEnum returns the allowed string one edit away from value, or
+// differing from it only in case, e.g. "prod" for "prdo" or "Prod"
+func suggestEnum(value any, enum []any) (string, bool) {
+	str, ok := value.(string)
+	if !ok {
+		return "", false
+	}
+	for _, allowed := range enum {
+		if s, ok := allowed.(string); ok && editDistance(strings.ToLower(str), strings.ToLower(s)) <= 1 {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// editDistance returns the number of single-character insertions,
+// deletions, substitutions and transpositions of adjacent characters that
+// turn a into b
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// rows[i][j] is the distance between s[:i] and t[:j]
+	rows := make([][]int, len(s)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(t)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(s)][len(t)]
+}
@@ -0,0 +1,111 @@
+package mowgli
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"username", "usrname", 1},
+		{"username", "usernmae", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"required": ["username", "env", "id"],
+		"properties": {
+			"username": {"type": "string"},
+			"user_name": {"type": "string"},
+			"env": {"type": "string", "enum": ["prod", "staging"]},
+			"id": {"type": "string"},
+			"region": {"type": "string", "enum": ["eu", "us"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       map[string]any
+		path       string
+		message    string
+		suggestion any
+	}{
+		{
+			name:       "misspelled required property",
+			data:       map[string]any{"usrname": "ada", "env": "prod", "id": "1"},
+			path:       "username",
+			message:    `required field is missing; did you mean "username" instead of "usrname"?`,
+			suggestion: "username",
+		},
+		{
+			name:       "transposed letters",
+			data:       map[string]any{"usernmae": "ada", "env": "prod", "id": "1"},
+			path:       "username",
+			message:    `required field is missing; did you mean "username" instead of "usernmae"?`,
+			suggestion: "username",
+		},
+		{
+			name:    "declared properties are not suggested",
+			data:    map[string]any{"user_name": "ada", "env": "prod", "id": "1"},
+			path:    "username",
+			message: "required field is missing",
+		},
+		{
+			name:    "short names need a close match",
+			data:    map[string]any{"username": "ada", "env": "prod", "xy": "1"},
+			path:    "id",
+			message: "required field is missing",
+		},
+		{
+			name:       "enum value one edit away",
+			data:       map[string]any{"username": "ada", "env": "prdo", "id": "1"},
+			path:       "env",
+			message:    `value not in enum: prdo (allowed: [prod staging]); did you mean "prod"?`,
+			suggestion: "prod",
+		},
+		{
+			name:       "enum value in the wrong case",
+			data:       map[string]any{"username": "ada", "env": "Staging", "id": "1"},
+			path:       "env",
+			message:    `value not in enum: Staging (allowed: [prod staging]); did you mean "staging"?`,
+			suggestion: "staging",
+		},
+		{
+			name:    "no enum value close enough",
+			data:    map[string]any{"username": "ada", "env": "dev", "id": "1"},
+			path:    "env",
+			message: "value not in enum: dev (allowed: [prod staging])",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			if len(result.Errors) != 1 {
+				t.Fatalf("expected one error, got %v", result.Errors)
+			}
+			err := result.Errors[0]
+			if err.Path != tt.path || err.Message != tt.message {
+				t.Errorf("expected %s: %s, got %v", tt.path, tt.message, err)
+			}
+			if err.Params["suggestion"] != tt.suggestion {
+				t.Errorf("expected suggestion %v, got %v", tt.suggestion, err.Params["suggestion"])
+			}
+		})
+	}
+}
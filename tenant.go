@@ -0,0 +1,171 @@
+package mowgli
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SpecResolver resolves the spec a tenant validates a kind of document
+// against. version may be empty for the latest version.
+type SpecResolver interface {
+	Resolve(ctx context.Context, tenant, name, version string) (*CompiledSpec, error)
+}
+
+// TenantOverrides provides tenants' overrides of base specs
+type TenantOverrides interface {
+	// Override returns the tenant's override of the spec name at version,
+	// or nil if the tenant uses the base spec as it is
+	Override(ctx context.Context, tenant, name, version string) (*Spec, error)
+}
+
+// TenantOverridesFunc adapts a function to TenantOverrides
+type TenantOverridesFunc func(ctx context.Context, tenant, name, version string) (*Spec, error)
+
+// Override returns f(ctx, tenant, name, version)
+func (f TenantOverridesFunc) Override(ctx context.Context, tenant, name, version string) (*Spec, error) {
+	return f(ctx, tenant, name, version)
+}
+
+// TenantResolverConfig configures a TenantResolver
+type TenantResolverConfig struct {
+	MaxEntries int          // Maximum number of compiled specs cached (default 1000)
+	Merge      MergeOptions // How overrides are merged into base specs (default: override keywords replace the base's)
+}
+
+// TenantResolver resolves per-tenant specs for SaaS deployments: each is a
+// base spec from a Registry with the tenant's override merged in, compiled
+// and kept in an LRU cache. Specs of tenants without an override are
+// compiled once and shared. A TenantResolver is safe for concurrent use.
+type TenantResolver struct {
+	base      *Registry
+	overrides TenantOverrides
+	config    TenantResolverConfig
+
+	mu      sync.Mutex
+	entries map[tenantKey]*list.Element
+	recency *list.List // Of *tenantEntry, most recently used first
+}
+
+// tenantKey identifies a resolved spec
+type tenantKey struct {
+	tenant, name, version string
+}
+
+// tenantEntry is a cached resolved spec
+type tenantEntry struct {
+	key  tenantKey
+	spec *CompiledSpec
+}
+
+// NewTenantResolver creates a resolver merging overrides into the specs
+// registered in base
+func NewTenantResolver(base *Registry, overrides TenantOverrides, config TenantResolverConfig) *TenantResolver {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 1000
+	}
+	return &TenantResolver{
+		base:      base,
+		overrides: overrides,
+		config:    config,
+		entries:   make(map[tenantKey]*list.Element),
+		recency:   list.New(),
+	}
+}
+
+// Resolve returns the tenant's spec name at version, or at the latest
+// version if version is empty, merging and compiling it on first use
+func (t *TenantResolver) Resolve(ctx context.Context, tenant, name, version string) (*CompiledSpec, error) {
+	key := tenantKey{tenant, name, version}
+	if spec, ok := t.cached(key); ok {
+		return spec, nil
+	}
+
+	ref := name
+	if version != "" {
+		ref = name + "@" + version
+	}
+	base, err := t.base.Lookup(ref)
+	if err != nil {
+		return nil, err
+	}
+	override, err := t.overrides.Override(ctx, tenant, name, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load override of %s for tenant %s: %w", ref, tenant, err)
+	}
+	if override == nil {
+		// Tenants without an override share the compiled base spec
+		shared, ok := t.cached(tenantKey{"", name, version})
+		if !ok {
+			if shared, err = Compile(base); err != nil {
+				return nil, fmt.Errorf("spec %s: %w", ref, err)
+			}
+			t.store(tenantKey{"", name, version}, shared)
+		}
+		t.store(key, shared)
+		return shared, nil
+	}
+
+	merged, err := MergeSpecsWith(base, override, t.config.Merge)
+	if err != nil {
+		return nil, fmt.Errorf("spec %s for tenant %s: %w", ref, tenant, err)
+	}
+	compiled, err := Compile(merged)
+	if err != nil {
+		return nil, fmt.Errorf("spec %s for tenant %s: %w", ref, tenant, err)
+	}
+	t.store(key, compiled)
+	return compiled, nil
+}
+
+// Invalidate drops the tenant's cached specs, e.g. after its overrides
+// changed. An empty tenant drops every cached spec, e.g. after new versions
+// of base specs were registered, which an empty version resolves to.
+func (t *TenantResolver) Invalidate(tenant string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key, elem := range t.entries {
+		if tenant == "" || key.tenant == tenant {
+			t.recency.Remove(elem)
+			delete(t.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached specs
+func (t *TenantResolver) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.entries)
+}
+
+// cached returns the cached spec for key, marking it recently used
+func (t *TenantResolver) cached(key tenantKey) (*CompiledSpec, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	elem, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	t.recency.MoveToFront(elem)
+	return elem.Value.(*tenantEntry).spec, true
+}
+
+// store caches spec for key, evicting the least recently used spec if the
+// cache is full
+func (t *TenantResolver) store(key tenantKey, spec *CompiledSpec) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if elem, ok := t.entries[key]; ok {
+		elem.Value.(*tenantEntry).spec = spec
+		t.recency.MoveToFront(elem)
+		return
+	}
+	t.entries[key] = t.recency.PushFront(&tenantEntry{key: key, spec: spec})
+	if t.recency.Len() > t.config.MaxEntries {
+		oldest := t.recency.Back()
+		t.recency.Remove(oldest)
+		delete(t.entries, oldest.Value.(*tenantEntry).key)
+	}
+}
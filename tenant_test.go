@@ -0,0 +1,138 @@
+package mowgli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TenantResolver implements SpecResolver
+var _ SpecResolver = (*TenantResolver)(nil)
+
+func TestTenantResolver(t *testing.T) {
+	reg := NewRegistry()
+	for ref, spec := range map[string]*Spec{
+		"order@1": {Type: "object", Properties: map[string]*Spec{"total": {Type: "number", Max: float64Ref(1000)}}},
+		"order@2": {Type: "object", Properties: map[string]*Spec{"total": {Type: "number"}}, Required: []string{"total"}},
+	} {
+		if err := reg.Register(ref, spec); err != nil {
+			t.Fatalf("Register returned error: %v", err)
+		}
+	}
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	overrides := TenantOverridesFunc(func(ctx context.Context, tenant, name, version string) (*Spec, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[tenant]++
+		switch tenant {
+		case "acme":
+			return &Spec{Properties: map[string]*Spec{"total": {Max: float64Ref(50)}, "po": {Type: "string"}}}, nil
+		case "broken":
+			return nil, errors.New("overrides unavailable")
+		}
+		return nil, nil
+	})
+	resolver := NewTenantResolver(reg, overrides, TenantResolverConfig{MaxEntries: 3})
+	ctx := context.Background()
+
+	tests := []struct {
+		tenant, version string
+		doc             map[string]any
+		valid           bool
+	}{
+		{tenant: "acme", version: "1", doc: map[string]any{"total": 100.0}, valid: false},
+		{tenant: "acme", version: "1", doc: map[string]any{"total": 10.0, "po": "PO-1"}, valid: true},
+		{tenant: "globex", version: "1", doc: map[string]any{"total": 100.0}, valid: true},
+		{tenant: "globex", version: "1", doc: map[string]any{"total": 2000.0}, valid: false},
+		{tenant: "globex", version: "", doc: map[string]any{}, valid: false},
+	}
+	for _, tt := range tests {
+		spec, err := resolver.Resolve(ctx, tt.tenant, "order", tt.version)
+		if err != nil {
+			t.Fatalf("Resolve(%s, %s) returned error: %v", tt.tenant, tt.version, err)
+		}
+		if result := spec.Validate(tt.doc); result.Valid != tt.valid {
+			t.Errorf("%s@%s: expected valid %v for %v, got errors %v", tt.tenant, tt.version, tt.valid, tt.doc, result.Errors)
+		}
+	}
+
+	// Tenants without overrides share the base spec, and the base is unchanged
+	initech, _ := resolver.Resolve(ctx, "initech", "order", "1")
+	globex, _ := resolver.Resolve(ctx, "globex", "order", "1")
+	if initech != globex {
+		t.Error("expected tenants without overrides to share the compiled base spec")
+	}
+	base, _ := reg.Lookup("order@1")
+	if *base.Properties["total"].Max != 1000 || base.Properties["po"] != nil {
+		t.Error("expected the base spec to be unchanged")
+	}
+
+	resolver.Invalidate("")
+	if resolver.Len() != 0 {
+		t.Errorf("expected an empty cache, got %d entries", resolver.Len())
+	}
+
+	if _, err := resolver.Resolve(ctx, "broken", "order", "1"); err == nil || !strings.Contains(err.Error(), "overrides unavailable") {
+		t.Errorf("expected the override error, got %v", err)
+	}
+	if _, err := resolver.Resolve(ctx, "acme", "invoice", "1"); err == nil || !strings.Contains(err.Error(), "unknown spec") {
+		t.Errorf("expected an unknown spec error, got %v", err)
+	}
+}
+
+func TestTenantResolverCache(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register("order@1", &Spec{Type: "object"})
+	reg.Register("order@2", &Spec{Type: "object"})
+	resolved := 0
+	overrides := TenantOverridesFunc(func(ctx context.Context, tenant, name, version string) (*Spec, error) {
+		resolved++
+		return &Spec{Required: []string{"po"}}, nil
+	})
+	resolver := NewTenantResolver(reg, overrides, TenantResolverConfig{MaxEntries: 2})
+	ctx := context.Background()
+
+	steps := []struct {
+		version  string
+		resolved int // Overrides loaded so far
+	}{
+		{version: "1", resolved: 1},
+		{version: "1", resolved: 1}, // Cached
+		{version: "2", resolved: 2},
+		{version: "1", resolved: 2}, // Cached, and now most recently used
+		{version: "", resolved: 3},  // Evicts order@2
+		{version: "1", resolved: 3},
+		{version: "2", resolved: 4},
+	}
+	for i, step := range steps {
+		if _, err := resolver.Resolve(ctx, "acme", "order", step.version); err != nil {
+			t.Fatalf("step %d: Resolve returned error: %v", i, err)
+		}
+		if resolved != step.resolved {
+			t.Errorf("step %d: expected %d overrides loaded, got %d", i, step.resolved, resolved)
+		}
+	}
+	if resolver.Len() != 2 {
+		t.Errorf("expected 2 cached specs, got %d", resolver.Len())
+	}
+
+	resolver.Invalidate("other")
+	resolver.Resolve(ctx, "acme", "order", "2")
+	if resolved != 4 {
+		t.Error("expected invalidating another tenant to keep acme's specs")
+	}
+	resolver.Invalidate("acme")
+	resolver.Resolve(ctx, "acme", "order", "2")
+	if resolved != 5 {
+		t.Error("expected invalidated specs to be resolved again")
+	}
+}
+
+// float64Ref returns a pointer to f
+func float64Ref(f float64) *float64 {
+	return &f
+}
@@ -3,8 +3,10 @@ package mowgli
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"path"
+	"sort"
 )
 
 // TestCase represents a single test case
@@ -12,42 +14,87 @@ type TestCase struct {
 	Name          string         `json:"name"`
 	Data          map[string]any `json:"data"`
 	ExpectedValid bool           `json:"expectedValid"`
+
+	ExpectedErrors []ExpectedError `json:"expectedErrors,omitempty"` // If set, the exact errors validation must report
 }
 
 // TestCaseFile represents a file containing multiple test cases
 type TestCaseFile struct {
+	Spec      string     `json:"spec,omitempty"` // Optional name of the spec file the cases are validated against
 	TestCases []TestCase `json:"testCases"`
 }
 
 // LoadSpec loads a spec JSON file from the testdata directory
 func LoadSpec(filename string) (*Spec, error) {
-	path := filepath.Join("testdata", "specs", filename)
-	data, err := os.ReadFile(path)
+	return LoadSpecFS(os.DirFS("."), path.Join("testdata", "specs", filename))
+}
+
+// LoadTestCases loads test cases from a JSON file in the testdata directory
+func LoadTestCases(filename string) ([]TestCase, error) {
+	return LoadTestCasesFS(os.DirFS("."), path.Join("testdata", "cases", filename))
+}
+
+// LoadSpecFS loads a spec JSON file from fsys, such as an embed.FS, so specs
+// can be shipped inside a binary:
+//
+//	//go:embed specs
+//	var specFS embed.FS
+//
+//	spec, err := mowgli.LoadSpecFS(specFS, "specs/user.json")
+func LoadSpecFS(fsys fs.FS, name string) (*Spec, error) {
+	data, err := fs.ReadFile(fsys, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read spec file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to read spec file %s: %w", name, err)
 	}
 
-	spec, err := ParseSpecString(string(data))
+	spec, err := ParseSpec(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse spec from %s: %w", path, err)
+		return nil, fmt.Errorf("failed to parse spec from %s: %w", name, err)
 	}
 
 	return spec, nil
 }
 
-// LoadTestCases loads test cases from a JSON file in the testdata directory
-func LoadTestCases(filename string) ([]TestCase, error) {
-	path := filepath.Join("testdata", "cases", filename)
-	data, err := os.ReadFile(path)
+// LoadSpecsFS loads every spec file in fsys matching pattern (see fs.Glob),
+// keyed by path
+func LoadSpecsFS(fsys fs.FS, pattern string) (map[string]*Spec, error) {
+	names, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	specs := make(map[string]*Spec, len(names))
+	for _, name := range names {
+		spec, err := LoadSpecFS(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		specs[name] = spec
+	}
+	return specs, nil
+}
+
+// LoadTestCaseFileFS loads a test case file from fsys
+func LoadTestCaseFileFS(fsys fs.FS, name string) (*TestCaseFile, error) {
+	data, err := fs.ReadFile(fsys, name)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read test case file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to read test case file %s: %w", name, err)
 	}
 
 	var testFile TestCaseFile
 	if err := json.Unmarshal(data, &testFile); err != nil {
-		return nil, fmt.Errorf("failed to parse test case file %s: %w", path, err)
+		return nil, fmt.Errorf("failed to parse test case file %s: %w", name, err)
 	}
 
-	return testFile.TestCases, nil
+	return &testFile, nil
 }
 
+// LoadTestCasesFS loads test cases from a JSON file in fsys
+func LoadTestCasesFS(fsys fs.FS, name string) ([]TestCase, error) {
+	testFile, err := LoadTestCaseFileFS(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return testFile.TestCases, nil
+}
@@ -1,7 +1,9 @@
 package mowgli
 
 import (
+	"os"
 	"testing"
+	"testing/fstest"
 )
 
 func TestLoadSpec(t *testing.T) {
@@ -58,6 +60,11 @@ func TestSharedDataValidation(t *testing.T) {
 					t.Errorf("expected validation to pass, but it failed: %v", result.Errors)
 				}
 			}
+			if tc.ExpectedErrors != nil {
+				if err := CompareErrors(tc.ExpectedErrors, result); err != nil {
+					t.Error(err)
+				}
+			}
 		})
 	}
 }
@@ -83,6 +90,11 @@ func TestSharedConditionalValidation(t *testing.T) {
 					t.Errorf("expected validation to pass, but it failed: %v", result.Errors)
 				}
 			}
+			if tc.ExpectedErrors != nil {
+				if err := CompareErrors(tc.ExpectedErrors, result); err != nil {
+					t.Error(err)
+				}
+			}
 		})
 	}
 }
@@ -108,6 +120,11 @@ func TestSharedNestedObjects(t *testing.T) {
 					t.Errorf("expected validation to pass, but it failed: %v", result.Errors)
 				}
 			}
+			if tc.ExpectedErrors != nil {
+				if err := CompareErrors(tc.ExpectedErrors, result); err != nil {
+					t.Error(err)
+				}
+			}
 		})
 	}
 }
@@ -133,7 +150,63 @@ func TestSharedAdvancedConditional(t *testing.T) {
 					t.Errorf("expected validation to pass, but it failed: %v", result.Errors)
 				}
 			}
+			if tc.ExpectedErrors != nil {
+				if err := CompareErrors(tc.ExpectedErrors, result); err != nil {
+					t.Error(err)
+				}
+			}
 		})
 	}
 }
 
+func TestLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"specs/user.json":  {Data: []byte(`{"type": "object", "required": ["name"]}`)},
+		"specs/order.json": {Data: []byte(`{"type": "object"}`)},
+		"specs/bad.txt":    {Data: []byte(`{`)},
+		"cases/user.json":  {Data: []byte(`{"spec": "user.json", "testCases": [{"name": "empty", "data": {}, "expectedValid": false}]}`)},
+	}
+
+	spec, err := LoadSpecFS(fsys, "specs/user.json")
+	if err != nil {
+		t.Fatalf("LoadSpecFS failed: %v", err)
+	}
+	if len(spec.Required) != 1 {
+		t.Errorf("expected 1 required field, got %v", spec.Required)
+	}
+
+	specs, err := LoadSpecsFS(fsys, "specs/*.json")
+	if err != nil {
+		t.Fatalf("LoadSpecsFS failed: %v", err)
+	}
+	if len(specs) != 2 || specs["specs/order.json"] == nil {
+		t.Errorf("expected the two JSON specs, got %v", specs)
+	}
+
+	testFile, err := LoadTestCaseFileFS(fsys, "cases/user.json")
+	if err != nil {
+		t.Fatalf("LoadTestCaseFileFS failed: %v", err)
+	}
+	if testFile.Spec != "user.json" || len(testFile.TestCases) != 1 {
+		t.Errorf("unexpected test case file: %+v", testFile)
+	}
+
+	if _, err := LoadSpecFS(fsys, "specs/bad.txt"); err == nil {
+		t.Error("expected error parsing an invalid spec")
+	}
+	if _, err := LoadSpecsFS(fsys, "specs/*"); err == nil {
+		t.Error("expected error when a matching spec is invalid")
+	}
+	if _, err := LoadTestCasesFS(fsys, "cases/missing.json"); err == nil {
+		t.Error("expected error loading a missing file")
+	}
+
+	// The testdata loaders are LoadSpecFS and LoadTestCasesFS on the working directory
+	testCases, err := LoadTestCasesFS(os.DirFS("testdata"), "cases/user_registration.json")
+	if err != nil {
+		t.Fatalf("LoadTestCasesFS failed: %v", err)
+	}
+	if want, _ := LoadTestCases("user_registration.json"); len(testCases) != len(want) {
+		t.Errorf("expected %d test cases, got %d", len(want), len(testCases))
+	}
+}
@@ -0,0 +1,131 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// timeExample is the time GenerateExample formats with a spec's timeFormat
+var timeExample = time.Date(2024, 1, 15, 9, 30, 0, 0, time.UTC)
+
+// timeType is the Go type timeFormat strings can be decoded into
+var timeType = reflect.TypeOf(time.Time{})
+
+// parsedTime is a string parsed with a "timeFormat" layout, and where it is
+// in the document
+type parsedTime struct {
+	segments []string
+	value    time.Time
+}
+
+// validateTimeFormat checks that str matches the spec's Go time layout,
+// recording the parsed time for typed conversion
+func (r *ValidationResult) validateTimeFormat(path, str, layout string) {
+	t, err := time.Parse(layout, str)
+	if err != nil {
+		r.addError(path, CodeTimeFormat, fmt.Sprintf("string does not match time format %s", layout),
+			map[string]any{"layout": layout})
+		return
+	}
+	if !r.propertyName {
+		r.times = append(r.times, parsedTime{segments: append([]string(nil), r.segments...), value: t})
+	}
+}
+
+// typedDocument returns the Document to decode into target. Strings parsed
+// with a timeFormat layout are rewritten as RFC 3339 where target decodes
+// them into a time.Time, so encoding/json accepts them; elsewhere they are
+// left as they were. changed reports whether any string was rewritten.
+func (r *ValidationResult) typedDocument(target reflect.Type) (doc any, changed bool) {
+	doc = r.Document
+	for _, t := range r.times {
+		if !timeTarget(target, t.segments) {
+			continue
+		}
+		if !changed {
+			// Document may be the caller's data
+			doc, changed = deepCopy(doc), true
+		}
+		if updated, err := pointerAdd(doc, t.segments, t.value.Format(time.RFC3339Nano), true); err == nil {
+			doc = updated
+		}
+	}
+	return doc, changed
+}
+
+// typedJSON returns the JSON encoding of the document to decode into
+// target, or body if neither transforms nor time layouts changed it
+func (r *ValidationResult) typedJSON(body []byte, target reflect.Type) ([]byte, error) {
+	doc, changed := r.typedDocument(target)
+	if len(r.transformed) == 0 && !changed {
+		return body, nil
+	}
+	return json.Marshal(doc)
+}
+
+// timeTarget reports whether the value at segments decodes into a time.Time
+// when the document is decoded into target
+func timeTarget(target reflect.Type, segments []string) bool {
+	t := target
+	for _, segment := range segments {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Struct:
+			field, ok := jsonField(t, segment)
+			if !ok {
+				return false
+			}
+			t = field.Type
+		case reflect.Map, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == timeType
+}
+
+// jsonField finds the struct field encoding/json decodes name into,
+// including fields of embedded structs
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	folded := false
+	for _, field := range reflect.VisibleFields(t) {
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		fieldName, _, _ := strings.Cut(tag, ",")
+		if fieldName == "" {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				continue
+			}
+			fieldName = field.Name
+		}
+		if fieldName == name {
+			return field, true
+		}
+		// encoding/json falls back to a case-insensitive match
+		if !folded && strings.EqualFold(fieldName, name) {
+			fold, folded = field, true
+		}
+	}
+	return fold, folded
+}
+
+// checkTimeFormat reports a layout without any Go time elements, which
+// only matches itself
+func checkTimeFormat(layout string) error {
+	if layout != "" && timeExample.Format(layout) == layout {
+		return fmt.Errorf("time format has no layout elements: %s", layout)
+	}
+	return nil
+}
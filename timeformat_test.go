@@ -0,0 +1,117 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTimeFormat(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"day": {"type": "string", "timeFormat": "2006-01-02"},
+			"exported": {"type": "string", "timeFormat": "02/01/2006 15:04"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       map[string]any
+		wantErrors []string
+	}{
+		{name: "matching layouts", data: map[string]any{"day": "2024-02-29", "exported": "15/01/2024 09:30"}},
+		{name: "RFC 3339 is not the layout", data: map[string]any{"day": "2024-01-15T09:30:00Z"},
+			wantErrors: []string{"day: string does not match time format 2006-01-02"}},
+		{name: "out of range day", data: map[string]any{"day": "2023-02-29"},
+			wantErrors: []string{"day: string does not match time format 2006-01-02"}},
+		{name: "day and month swapped", data: map[string]any{"exported": "01/15/2024 09:30"},
+			wantErrors: []string{"exported: string does not match time format 02/01/2006 15:04"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			var errors []string
+			for _, e := range result.Errors {
+				errors = append(errors, e.Error())
+			}
+			if !reflect.DeepEqual(errors, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, errors)
+			}
+		})
+	}
+}
+
+func TestTimeFormatConversion(t *testing.T) {
+	type export struct {
+		Day      time.Time   `json:"day" mowgli:"required,timeFormat=2006-01-02"`
+		Label    string      `json:"label" mowgli:"timeFormat=2006-01-02"`
+		Previous *time.Time  `json:"previous" mowgli:"timeFormat=02/01/2006"`
+		History  []time.Time `json:"history" mowgli:"dive,timeFormat=2006-01-02"`
+	}
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	body := `{"day": "2024-01-15", "label": "2024-01-15", "previous": "14/01/2024", "history": ["2024-01-13"]}`
+
+	check := func(t *testing.T, value export) {
+		t.Helper()
+		if !value.Day.Equal(day) || value.Label != "2024-01-15" {
+			t.Errorf("unexpected day or label: %+v", value)
+		}
+		if value.Previous == nil || !value.Previous.Equal(day.AddDate(0, 0, -1)) {
+			t.Errorf("unexpected previous: %v", value.Previous)
+		}
+		if len(value.History) != 1 || !value.History[0].Equal(day.AddDate(0, 0, -2)) {
+			t.Errorf("unexpected history: %v", value.History)
+		}
+	}
+
+	t.Run("DecodeAndValidate", func(t *testing.T) {
+		result, value, err := DecodeAndValidate[export](strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("DecodeAndValidate failed: %v", err)
+		}
+		if !result.Valid {
+			t.Fatalf("expected valid, got %v", result.Errors)
+		}
+		check(t, value)
+	})
+
+	t.Run("ValidateStruct", func(t *testing.T) {
+		data := map[string]any{"day": "2024-01-15", "label": "2024-01-15", "previous": "14/01/2024", "history": []any{"2024-01-13"}}
+		result, value, err := ValidateStruct[export](data)
+		if err != nil {
+			t.Fatalf("ValidateStruct failed: %v", err)
+		}
+		if !result.Valid {
+			t.Fatalf("expected valid, got %v", result.Errors)
+		}
+		check(t, value)
+		if data["day"] != "2024-01-15" {
+			t.Errorf("input was modified: %v", data["day"])
+		}
+	})
+
+	t.Run("invalid layout", func(t *testing.T) {
+		result, _, err := DecodeAndValidate[export](strings.NewReader(`{"day": "15/01/2024"}`))
+		if err != nil {
+			t.Fatalf("DecodeAndValidate failed: %v", err)
+		}
+		if result.Valid || result.Errors[0].Code != CodeTimeFormat {
+			t.Errorf("expected timeFormat error, got %v", result.Errors)
+		}
+	})
+}
+
+func TestCompileTimeFormat(t *testing.T) {
+	if _, err := Compile(&Spec{Type: "string", TimeFormat: "yyyy-mm-dd"}); err == nil || !strings.Contains(err.Error(), "no layout elements") {
+		t.Errorf("expected layout error, got %v", err)
+	}
+	if _, err := Compile(&Spec{Type: "string", TimeFormat: time.Kitchen}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
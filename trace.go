@@ -0,0 +1,74 @@
+package mowgli
+
+import "fmt"
+
+// Kinds of trace events
+const (
+	TraceSpec          = "spec"          // A spec was applied to a value; Detail is its type
+	TraceRef           = "ref"           // A $ref was resolved; Detail is the ref
+	TraceDiscriminator = "discriminator" // A discriminator selected a branch; Detail is "property=value"
+	TraceCondition     = "condition"     // A condition was evaluated; Detail is its expression
+	TraceRequiredIf    = "requiredIf"    // A requiredIf expression was evaluated for the property at Path
+	TraceOverride      = "override"      // A condition's then or else spec was merged into the property at Path
+	TraceIf            = "if"            // An if subschema selected a branch; Detail is "then" or "else"
+	TraceAssert        = "assert"        // An assert was evaluated; Detail is its expression
+)
+
+// TraceEvent is a step of validation recorded with WithTrace
+type TraceEvent struct {
+	Path   string // Path of the value, "" for the document
+	Kind   string // One of the Trace* kinds
+	Detail string
+	// Result is the outcome of expressions: true, false, or the error
+	// evaluating them. For overrides it is "then" or "else".
+	Result any
+	// Cached is set for expressions already evaluated on the same object,
+	// whose outcome was reused
+	Cached bool
+}
+
+func (e TraceEvent) String() string {
+	path := e.Path
+	if path == "" {
+		path = "(root)"
+	}
+	if e.Result == nil {
+		return fmt.Sprintf("%s: %s %s", path, e.Kind, e.Detail)
+	}
+	if e.Cached {
+		return fmt.Sprintf("%s: %s %s -> %v (cached)", path, e.Kind, e.Detail, e.Result)
+	}
+	return fmt.Sprintf("%s: %s %s -> %v", path, e.Kind, e.Detail, e.Result)
+}
+
+// WithTrace records which specs were applied to which paths, what
+// conditions and requiredIf expressions evaluated to and which overrides
+// were merged, for debugging specs. The events are returned by
+// ValidationResult.Trace. Tracing slows validation down and is meant for
+// development.
+func WithTrace() Option {
+	return func(v *Validator) {
+		v.tracing = true
+	}
+}
+
+// Trace returns the events recorded during validation, in order, if the
+// Validator was created WithTrace
+func (r *ValidationResult) Trace() []TraceEvent {
+	return r.trace
+}
+
+// addTrace records an event if tracing is enabled
+func (r *ValidationResult) addTrace(path, kind, detail string, result any) {
+	if r.tracing {
+		r.trace = append(r.trace, TraceEvent{Path: path, Kind: kind, Detail: detail, Result: result})
+	}
+}
+
+// traceResult returns the trace result of an expression
+func traceResult(result bool, err error) any {
+	if err != nil {
+		return err
+	}
+	return result
+}
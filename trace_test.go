@@ -0,0 +1,105 @@
+package mowgli
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithTrace(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"country": {"type": "string"},
+			"zip": {"type": "string"},
+			"age": {"type": "integer"},
+			"guardian": {"type": "string"}
+		},
+		"conditions": [
+			{"if": "country == 'US'", "then": {"zip": {"pattern": "^[0-9]{5}$"}}, "else": {"zip": {"maxLength": 10}}}
+		],
+		"requiredIf": {"guardian": "age < 18"}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	v := NewValidator(WithTrace())
+	result := v.Validate(map[string]any{"country": "US", "zip": "1234", "age": 30.0}, spec)
+
+	var got []TraceEvent
+	for _, event := range result.Trace() {
+		// Properties are validated in no particular order
+		if event.Kind != TraceSpec || event.Path == "" {
+			got = append(got, event)
+		}
+	}
+	want := []TraceEvent{
+		{Path: "", Kind: TraceSpec, Detail: "object"},
+		{Path: "guardian", Kind: TraceRequiredIf, Detail: "age < 18", Result: false},
+		{Path: "", Kind: TraceCondition, Detail: "country == 'US'", Result: true},
+		{Path: "zip", Kind: TraceOverride, Detail: "country == 'US'", Result: "then"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	specs := map[string]string{}
+	for _, event := range result.Trace() {
+		if event.Kind == TraceSpec {
+			specs[event.Path] = event.Detail
+		}
+	}
+	wantSpecs := map[string]string{"": "object", "country": "string", "zip": "string", "age": "integer"}
+	if !reflect.DeepEqual(specs, wantSpecs) {
+		t.Errorf("expected specs %v, got %v", wantSpecs, specs)
+	}
+
+	if trace := Validate(map[string]any{}, spec).Trace(); trace != nil {
+		t.Errorf("expected no trace without WithTrace, got %v", trace)
+	}
+}
+
+func TestWithTraceRefsAndDiscriminator(t *testing.T) {
+	reg := NewRegistry(WithTrace())
+	if err := reg.Register("card@1", &Spec{Type: "object", Properties: map[string]*Spec{"number": {Type: "string"}}}); err != nil {
+		t.Fatal(err)
+	}
+	spec := &Spec{
+		Type:          "object",
+		Properties:    map[string]*Spec{"kind": {Type: "string"}},
+		Discriminator: &Discriminator{PropertyName: "kind", Mapping: map[string]*Spec{"card": {Ref: "card"}}},
+	}
+	if err := reg.Register("payment@1", spec); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := reg.Validate(map[string]any{"kind": "card", "number": "4242"}, "payment")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, event := range result.Trace()[:2] {
+		kinds = append(kinds, event.String())
+	}
+	want := []string{"(root): spec object", "(root): discriminator kind=card"}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("expected %v, got %v", want, kinds)
+	}
+}
+
+func TestTraceEventString(t *testing.T) {
+	tests := []struct {
+		event TraceEvent
+		want  string
+	}{
+		{TraceEvent{Path: "a.b", Kind: TraceSpec, Detail: "string"}, "a.b: spec string"},
+		{TraceEvent{Kind: TraceCondition, Detail: "x > 1", Result: true}, "(root): condition x > 1 -> true"},
+		{TraceEvent{Kind: TraceCondition, Detail: "x >", Result: errors.New("syntax error")}, "(root): condition x > -> syntax error"},
+	}
+	for _, tt := range tests {
+		if got := tt.event.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
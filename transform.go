@@ -0,0 +1,90 @@
+package mowgli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// transforms are the string transforms available to the spec's "transform"
+// keyword, applied in the order listed
+var transforms = map[string]func(string) string{
+	"trim":  strings.TrimSpace,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	// normalizeWhitespace collapses runs of whitespace to a single space and trims
+	"normalizeWhitespace": func(s string) string { return strings.Join(strings.Fields(s), " ") },
+}
+
+// transformedValue is a value changed by a transform, and where it is in the document
+type transformedValue struct {
+	segments []string
+	value    any
+}
+
+// applyTransforms runs spec's transforms on a string value, recording the
+// result for the transformed document. Other values are returned unchanged
+// and fail type validation as usual.
+func (r *ValidationResult) applyTransforms(path string, value any, spec *Spec) any {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	transformed := str
+	for _, name := range spec.Transform {
+		transform, ok := transforms[name]
+		if !ok {
+			r.addError(path, CodeInvalidSpec, fmt.Sprintf("unknown transform: %s", name),
+				map[string]any{"transform": name})
+			return value
+		}
+		transformed = transform(transformed)
+	}
+
+	// Property names are validated but never rewritten
+	if transformed != str && !r.propertyName {
+		r.transformed = append(r.transformed, transformedValue{
+			segments: append([]string(nil), r.segments...),
+			value:    transformed,
+		})
+	}
+	return transformed
+}
+
+// pushSegment enters a child of the value being validated
+func (r *ValidationResult) pushSegment(segment string) {
+	r.segments = append(r.segments, segment)
+}
+
+func (r *ValidationResult) popSegment() {
+	r.segments = r.segments[:len(r.segments)-1]
+}
+
+// buildDocument sets Document to the root with transformed values applied.
+// The root is copied rather than modified, so callers' data is left intact.
+func (r *ValidationResult) buildDocument() {
+	if len(r.transformed) == 0 {
+		r.Document = r.root
+		return
+	}
+
+	doc := deepCopy(r.root)
+	for _, t := range r.transformed {
+		// Values in Go slices and maps other than JSON's can't be replaced
+		// and are left as they were
+		if updated, err := pointerAdd(doc, t.segments, t.value, true); err == nil {
+			doc = updated
+		}
+	}
+	r.Document = doc
+}
+
+// checkTransforms reports unknown transform names
+func checkTransforms(names []string) error {
+	for _, name := range names {
+		if _, ok := transforms[name]; !ok {
+			return fmt.Errorf("unknown transform: %s", name)
+		}
+	}
+	return nil
+}
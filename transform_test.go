@@ -0,0 +1,182 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name       string
+		spec       string
+		data       any
+		wantDoc    any
+		wantErrors []string
+	}{
+		{
+			name:    "trim before minLength",
+			spec:    `{"type": "string", "transform": ["trim"], "minLength": 1}`,
+			data:    "  ",
+			wantDoc: "",
+			wantErrors: []string{
+				"string length 0 is less than minimum 1",
+			},
+		},
+		{
+			name:    "transforms run in order",
+			spec:    `{"type": "string", "transform": ["normalizeWhitespace", "upper"], "enum": ["NEW YORK"]}`,
+			data:    "  new \t york ",
+			wantDoc: "NEW YORK",
+		},
+		{
+			name:    "lower before format",
+			spec:    `{"type": "object", "properties": {"email": {"type": "string", "transform": ["trim", "lower"], "pattern": "^[a-z@.]+$"}}}`,
+			data:    map[string]any{"email": " Ada@Example.COM "},
+			wantDoc: map[string]any{"email": "ada@example.com"},
+		},
+		{
+			name:    "array items and additional properties",
+			spec:    `{"type": "object", "properties": {"tags": {"type": "array", "items": {"type": "string", "transform": ["lower"]}}}, "additionalProperties": {"type": "string", "transform": ["trim"]}}`,
+			data:    map[string]any{"tags": []any{"A", "b"}, "note": " hi "},
+			wantDoc: map[string]any{"tags": []any{"a", "b"}, "note": "hi"},
+		},
+		{
+			name:    "property names are not rewritten",
+			spec:    `{"type": "object", "propertyNames": {"type": "string", "transform": ["lower"], "pattern": "^[a-z]+$"}}`,
+			data:    map[string]any{"Key": "Value"},
+			wantDoc: map[string]any{"Key": "Value"},
+		},
+		{
+			name:       "non-string values fail type validation",
+			spec:       `{"type": "string", "transform": ["trim"]}`,
+			data:       float64(1),
+			wantDoc:    float64(1),
+			wantErrors: []string{"expected string, got float64"},
+		},
+		{
+			name:       "unknown transform",
+			spec:       `{"type": "string", "transform": ["reverse"]}`,
+			data:       "abc",
+			wantDoc:    "abc",
+			wantErrors: []string{"unknown transform: reverse"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			result := Validate(tt.data, spec)
+
+			var errors []string
+			for _, e := range result.Errors {
+				errors = append(errors, e.Error())
+			}
+			if !reflect.DeepEqual(errors, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, errors)
+			}
+			if !reflect.DeepEqual(result.Document, tt.wantDoc) {
+				t.Errorf("expected document %#v, got %#v", tt.wantDoc, result.Document)
+			}
+		})
+	}
+}
+
+func TestTransformsLeaveInputUnchanged(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "object", "properties": {"name": {"type": "string", "transform": ["trim"]}}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	data := map[string]any{"name": " Ada "}
+
+	result := Validate(data, spec)
+	if data["name"] != " Ada " {
+		t.Errorf("expected input to be unchanged, got %q", data["name"])
+	}
+	if doc := result.Document.(map[string]any); doc["name"] != "Ada" {
+		t.Errorf("expected transformed name, got %q", doc["name"])
+	}
+}
+
+func TestTransformsConditionsSeeOriginal(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"code": {"type": "string", "transform": ["upper"]},
+			"note": {"type": "string"}
+		},
+		"conditions": [{"if": "code == \"abc\"", "then": {"note": {"minLength": 5}}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"code": "abc", "note": "hi"}, spec)
+	if result.Valid {
+		t.Error("expected the condition to match the untransformed value")
+	}
+}
+
+func TestTransformsValidateAt(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "object", "properties": {"user": {"type": "object", "properties": {"name": {"type": "string", "transform": ["trim"]}}}}}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result, err := ValidateAt(map[string]any{"user": map[string]any{"name": " Ada "}}, spec, "user.name")
+	if err != nil {
+		t.Fatalf("ValidateAt failed: %v", err)
+	}
+	want := map[string]any{"user": map[string]any{"name": "Ada"}}
+	if !reflect.DeepEqual(result.Document, want) {
+		t.Errorf("expected document %v, got %v", want, result.Document)
+	}
+}
+
+func TestTransformsDecode(t *testing.T) {
+	type signup struct {
+		Email string   `json:"email" mowgli:"required,transform=trim lower,format=email"`
+		Tags  []string `json:"tags" mowgli:"dive,transform=upper"`
+	}
+
+	result, value, err := DecodeAndValidate[signup](strings.NewReader(`{"email": " Ada@Example.com ", "tags": ["go"]}`))
+	if err != nil {
+		t.Fatalf("DecodeAndValidate failed: %v", err)
+	}
+	if !result.Valid {
+		t.Fatalf("expected valid, got %v", result.Errors)
+	}
+	if value.Email != "ada@example.com" || !reflect.DeepEqual(value.Tags, []string{"GO"}) {
+		t.Errorf("expected transformed values, got %+v", value)
+	}
+
+	_, converted, err := ValidateJSONAs[signup]([]byte(`{"email": "ADA@EXAMPLE.COM"}`), &Spec{
+		Type:       "object",
+		Properties: map[string]*Spec{"email": {Type: "string", Transform: []string{"lower"}}},
+	})
+	if err != nil || converted.Email != "ada@example.com" {
+		t.Errorf("expected ValidateJSONAs to decode the transformed value, got %+v (%v)", converted, err)
+	}
+}
+
+func TestTransformsSpecChecks(t *testing.T) {
+	if _, err := ParseStructTag("transform=trim shout"); err == nil || !strings.Contains(err.Error(), "unknown transform: shout") {
+		t.Errorf("expected unknown transform error from tag, got %v", err)
+	}
+	if _, err := ParseStructTag("transform="); err == nil {
+		t.Error("expected error for an empty transform list")
+	}
+
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{"name": {Type: "string", Transform: []string{"shout"}}}}
+	if _, err := Compile(spec); err == nil || !strings.Contains(err.Error(), "name: unknown transform: shout") {
+		t.Errorf("expected Compile to reject unknown transform, got %v", err)
+	}
+
+	changes := DiffSpecs(&Spec{Type: "string"}, &Spec{Type: "string", Transform: []string{"trim"}}).Changes
+	if len(changes) != 1 || changes[0].Constraint != "transform" || !changes[0].Breaking {
+		t.Errorf("expected a breaking transform change, got %+v", changes)
+	}
+}
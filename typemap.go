@@ -0,0 +1,103 @@
+package mowgli
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SpecProvider is implemented by types that describe their own spec.
+// SpecFromStruct uses it for fields of the type instead of mapping the
+// type's Go kind, e.g. for a UUID type backed by [16]byte.
+type SpecProvider interface {
+	MowgliSpec() *Spec
+}
+
+// typeMappings holds the specs registered for custom Go types
+var typeMappings = struct {
+	sync.RWMutex
+	specs map[reflect.Type]*Spec
+}{specs: map[reflect.Type]*Spec{
+	reflect.TypeOf(time.Time{}): {Type: "string", Format: stringPtr("date-time")},
+}}
+
+// RegisterTypeMapping makes SpecFromStruct use spec for fields of type t,
+// replacing any existing mapping. Tag options on a field are applied on top
+// of spec. Mappings take precedence over SpecProvider.
+func RegisterTypeMapping(t reflect.Type, spec *Spec) {
+	typeMappings.Lock()
+	defer typeMappings.Unlock()
+	typeMappings.specs[t] = spec
+}
+
+// mappedSpec returns the registered or self-described spec for t
+func mappedSpec(t reflect.Type) (*Spec, bool) {
+	typeMappings.RLock()
+	spec, ok := typeMappings.specs[t]
+	typeMappings.RUnlock()
+	if ok {
+		return spec, true
+	}
+
+	// A pointer has both value and pointer receiver methods
+	if provider, ok := reflect.New(t).Interface().(SpecProvider); ok {
+		if spec := provider.MowgliSpec(); spec != nil {
+			return spec, true
+		}
+	}
+	return nil, false
+}
+
+// tagOptionsSpec holds the tag options that apply to a spec of the given type
+func tagOptionsSpec(specType string, options *StructTagOptions) *Spec {
+	spec := &Spec{Enum: options.Enum, Messages: options.Messages}
+	if options.ReadOnly {
+		spec.ReadOnly = &options.ReadOnly
+	}
+	if options.WriteOnly {
+		spec.WriteOnly = &options.WriteOnly
+	}
+	if options.Deprecated {
+		spec.Deprecated = &options.Deprecated
+	}
+	switch specType {
+	case "string":
+		spec.MinLength = options.MinLength
+		spec.MaxLength = options.MaxLength
+		spec.LengthUnit = options.LengthUnit
+		spec.MinBytes = options.MinBytes
+		spec.MaxBytes = options.MaxBytes
+		spec.Pattern = options.Pattern
+		spec.Format = options.Format
+		spec.TimeFormat = options.TimeFormat
+		spec.SemverRange = options.SemverRange
+		spec.URISchemes = options.URISchemes
+		if options.PublicHost {
+			spec.PublicHost = &options.PublicHost
+		}
+		spec.AllowEmpty = options.AllowEmpty
+		spec.Transform = options.Transform
+	case "integer", "number":
+		spec.Min = options.Min
+		spec.Max = options.Max
+		spec.MinInt = options.MinInt
+		spec.MaxInt = options.MaxInt
+	case "array":
+		spec.MinLength = options.MinLength
+		spec.MaxLength = options.MaxLength
+		if options.MinItems != nil {
+			spec.MinLength = options.MinItems
+		}
+		if options.MaxItems != nil {
+			spec.MaxLength = options.MaxItems
+		}
+		if options.Unique {
+			spec.UniqueItems = &options.Unique
+		}
+	}
+	return spec
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,90 @@
+package mowgli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testUUID [16]byte
+
+type testDecimal struct {
+	unscaled int64
+	scale    int32
+}
+
+func (testDecimal) MowgliSpec() *Spec {
+	return &Spec{Type: "string", Pattern: stringPtr(`^-?[0-9]+(\.[0-9]+)?$`)}
+}
+
+type testColor int
+
+func (*testColor) MowgliSpec() *Spec {
+	return &Spec{Type: "string", Enum: []any{"red", "green", "blue"}}
+}
+
+func TestSpecFromStructTypeMapping(t *testing.T) {
+	RegisterTypeMapping(reflect.TypeOf(testUUID{}), &Spec{Type: "string", Format: stringPtr("uuid")})
+
+	type Order struct {
+		ID        testUUID               `json:"id" mowgli:"required"`
+		ParentID  *testUUID              `json:"parentId"`
+		Amount    testDecimal            `json:"amount" mowgli:"maxLength=12"`
+		Color     testColor              `json:"color"`
+		Tags      []testColor            `json:"tags"`
+		CreatedAt time.Time              `json:"createdAt"`
+		Lines     []testUUID             `json:"lines"`
+		ByColor   map[string]testDecimal `json:"byColor"`
+	}
+
+	spec, err := SpecFromStruct(Order{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		spec  *Spec
+		check func(*Spec) bool
+	}{
+		{"registered mapping", spec.Properties["id"], func(s *Spec) bool { return s.Type == "string" && *s.Format == "uuid" }},
+		{"pointer to mapped type", spec.Properties["parentId"], func(s *Spec) bool { return *s.Format == "uuid" }},
+		{"value receiver provider with tag options", spec.Properties["amount"], func(s *Spec) bool {
+			return s.Pattern != nil && s.MaxLength != nil && *s.MaxLength == 12
+		}},
+		{"pointer receiver provider", spec.Properties["color"], func(s *Spec) bool { return len(s.Enum) == 3 }},
+		{"provider as array item", spec.Properties["tags"].Items, func(s *Spec) bool { return len(s.Enum) == 3 }},
+		{"time.Time", spec.Properties["createdAt"], func(s *Spec) bool { return *s.Format == "date-time" }},
+		{"mapped array item", spec.Properties["lines"].Items, func(s *Spec) bool { return *s.Format == "uuid" }},
+		{"mapped map value", spec.Properties["byColor"].AdditionalProperties, func(s *Spec) bool { return s.Pattern != nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.spec == nil || !tt.check(tt.spec) {
+				t.Errorf("unexpected spec: %+v", tt.spec)
+			}
+		})
+	}
+
+	data := map[string]any{"id": "not-a-uuid", "amount": "12.50", "color": "purple"}
+	result := Validate(data, spec)
+	if len(result.Errors) != 2 {
+		t.Errorf("expected errors for id and color, got %v", result.Errors)
+	}
+}
+
+func TestTypeMappingNotShared(t *testing.T) {
+	type Item struct {
+		A testDecimal `json:"a" mowgli:"minLength=1"`
+		B testDecimal `json:"b"`
+	}
+
+	spec, err := SpecFromStruct(Item{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Properties["b"].MinLength != nil {
+		t.Error("tag options of one field leaked into another")
+	}
+}
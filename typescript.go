@@ -0,0 +1,284 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// tsIdentifierPattern matches names usable unquoted as TypeScript identifiers
+var tsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// ExportTypeScript renders spec as a TypeScript type declaration named name,
+// e.g. "export interface User { name: string; age?: number; }". Properties
+// that are not required become optional, enums become literal unions and
+// nullable specs allow null. Conditions cannot be expressed as static types
+// and are not exported.
+func ExportTypeScript(name string, spec *Spec) (string, error) {
+	if !tsIdentifierPattern.MatchString(name) {
+		return "", fmt.Errorf("invalid TypeScript type name: %q", name)
+	}
+	if spec == nil {
+		return "", fmt.Errorf("spec is nil")
+	}
+
+	if spec.Type == "object" && len(spec.Enum) == 0 && spec.AdditionalProperties == nil {
+		return "export interface " + name + " " + tsObject(spec, "") + "\n", nil
+	}
+	return "export type " + name + " = " + tsType(spec, "") + ";\n", nil
+}
+
+func tsType(spec *Spec, indent string) string {
+	var t string
+	switch {
+	case len(spec.Enum) > 0:
+		literals := make([]string, len(spec.Enum))
+		for i, v := range spec.Enum {
+			literals[i] = jsLiteral(v)
+		}
+		t = strings.Join(literals, " | ")
+	case spec.Type == "string":
+		t = "string"
+	case spec.Type == "number", spec.Type == "integer":
+		t = "number"
+	case spec.Type == "boolean":
+		t = "boolean"
+	case spec.Type == "null":
+		t = "null"
+	case spec.Type == "array":
+		item := "unknown"
+		if spec.Items != nil {
+			item = tsType(spec.Items, indent)
+		}
+		if strings.ContainsAny(item, "| ") {
+			item = "(" + item + ")"
+		}
+		t = item + "[]"
+	case spec.Type == "object":
+		t = tsObject(spec, indent)
+	default:
+		t = "unknown"
+	}
+
+	if spec.Nullable != nil && *spec.Nullable && t != "null" {
+		t += " | null"
+	}
+	return t
+}
+
+func tsObject(spec *Spec, indent string) string {
+	if len(spec.Properties) == 0 {
+		if spec.AdditionalProperties != nil {
+			return "Record<string, " + tsType(spec.AdditionalProperties, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+
+	required := make(map[string]bool, len(spec.Required))
+	for _, name := range spec.Required {
+		required[name] = true
+	}
+
+	inner := indent + "  "
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(spec.Properties) {
+		prop := spec.Properties[name]
+		if prop == nil {
+			continue
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		if prop.Deprecated != nil && *prop.Deprecated {
+			fmt.Fprintf(&b, "%s/** @deprecated */\n", inner)
+		}
+		readonly := ""
+		if prop.ReadOnly != nil && *prop.ReadOnly {
+			readonly = "readonly "
+		}
+		fmt.Fprintf(&b, "%s%s%s%s: %s;\n", inner, readonly, jsPropertyName(name), optional, tsType(prop, inner))
+	}
+	if spec.AdditionalProperties != nil {
+		fmt.Fprintf(&b, "%s[key: string]: %s;\n", inner, tsType(spec.AdditionalProperties, inner))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// ExportZod renders spec as a zod schema declaration, e.g.
+// "export const userSchema = z.object({ ... });". Besides the shape it carries
+// over min/max, length, pattern, enum and the formats zod knows (email, uuid,
+// date-time, uri, ipv4, ipv6). Conditions are not exported.
+func ExportZod(name string, spec *Spec) (string, error) {
+	if !tsIdentifierPattern.MatchString(name) {
+		return "", fmt.Errorf("invalid zod schema name: %q", name)
+	}
+	if spec == nil {
+		return "", fmt.Errorf("spec is nil")
+	}
+	return "export const " + name + " = " + zodSchema(spec, "") + ";\n", nil
+}
+
+func zodSchema(spec *Spec, indent string) string {
+	var s string
+	switch {
+	case len(spec.Enum) > 0:
+		s = zodEnum(spec.Enum)
+	case spec.Type == "string":
+		s = "z.string()" + zodStringChecks(spec)
+	case spec.Type == "number", spec.Type == "integer":
+		s = "z.number()"
+		if spec.Type == "integer" {
+			s += ".int()"
+		}
+		if spec.Min != nil {
+			s += ".min(" + formatJSNumber(*spec.Min) + ")"
+		}
+		if spec.Max != nil {
+			s += ".max(" + formatJSNumber(*spec.Max) + ")"
+		}
+		// JavaScript numbers round bounds beyond 2^53; the Go side is exact
+		if spec.MinInt != "" {
+			s += ".min(" + string(spec.MinInt) + ")"
+		}
+		if spec.MaxInt != "" {
+			s += ".max(" + string(spec.MaxInt) + ")"
+		}
+	case spec.Type == "boolean":
+		s = "z.boolean()"
+	case spec.Type == "null":
+		s = "z.null()"
+	case spec.Type == "array":
+		item := "z.unknown()"
+		if spec.Items != nil {
+			item = zodSchema(spec.Items, indent)
+		}
+		s = "z.array(" + item + ")"
+		if spec.MinLength != nil {
+			s += ".min(" + strconv.Itoa(*spec.MinLength) + ")"
+		}
+		if spec.MaxLength != nil {
+			s += ".max(" + strconv.Itoa(*spec.MaxLength) + ")"
+		}
+	case spec.Type == "object":
+		s = zodObject(spec, indent)
+	default:
+		s = "z.unknown()"
+	}
+
+	if spec.Nullable != nil && *spec.Nullable && spec.Type != "null" {
+		s += ".nullable()"
+	}
+	return s
+}
+
+func zodStringChecks(spec *Spec) string {
+	var s string
+	if spec.MinLength != nil && !(spec.AllowEmpty != nil && *spec.AllowEmpty) {
+		s += ".min(" + strconv.Itoa(*spec.MinLength) + ")"
+	}
+	if spec.MaxLength != nil {
+		s += ".max(" + strconv.Itoa(*spec.MaxLength) + ")"
+	}
+	if spec.Pattern != nil {
+		s += ".regex(new RegExp(" + jsLiteral(*spec.Pattern) + "))"
+	}
+	if spec.Format != nil {
+		switch *spec.Format {
+		case "email":
+			s += ".email()"
+		case "uuid":
+			s += ".uuid()"
+		case "date-time":
+			s += ".datetime({ offset: true })"
+		case "uri":
+			s += ".url()"
+		case "ipv4":
+			s += `.ip({ version: "v4" })`
+		case "ipv6":
+			s += `.ip({ version: "v6" })`
+		}
+	}
+	return s
+}
+
+func zodEnum(enum []any) string {
+	allStrings := true
+	literals := make([]string, len(enum))
+	for i, v := range enum {
+		if _, ok := v.(string); !ok {
+			allStrings = false
+		}
+		literals[i] = jsLiteral(v)
+	}
+	if allStrings {
+		return "z.enum([" + strings.Join(literals, ", ") + "])"
+	}
+	if len(literals) == 1 {
+		return "z.literal(" + literals[0] + ")"
+	}
+	for i, literal := range literals {
+		literals[i] = "z.literal(" + literal + ")"
+	}
+	return "z.union([" + strings.Join(literals, ", ") + "])"
+}
+
+func zodObject(spec *Spec, indent string) string {
+	if len(spec.Properties) == 0 {
+		value := "z.unknown()"
+		if spec.AdditionalProperties != nil {
+			value = zodSchema(spec.AdditionalProperties, indent)
+		}
+		return "z.record(z.string(), " + value + ")"
+	}
+
+	required := make(map[string]bool, len(spec.Required))
+	for _, name := range spec.Required {
+		required[name] = true
+	}
+
+	inner := indent + "  "
+	var b strings.Builder
+	b.WriteString("z.object({\n")
+	for _, name := range sortedKeys(spec.Properties) {
+		prop := spec.Properties[name]
+		if prop == nil {
+			continue
+		}
+		schema := zodSchema(prop, inner)
+		if !required[name] {
+			schema += ".optional()"
+		}
+		fmt.Fprintf(&b, "%s%s: %s,\n", inner, jsPropertyName(name), schema)
+	}
+	b.WriteString(indent + "})")
+	if spec.AdditionalProperties != nil {
+		b.WriteString(".catchall(" + zodSchema(spec.AdditionalProperties, indent) + ")")
+	}
+	return b.String()
+}
+
+// jsPropertyName quotes property names that aren't valid identifiers
+func jsPropertyName(name string) string {
+	if tsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	return jsLiteral(name)
+}
+
+// jsLiteral renders a JSON value as a JavaScript literal
+func jsLiteral(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "undefined"
+	}
+	return string(data)
+}
+
+func formatJSNumber(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
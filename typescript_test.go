@@ -0,0 +1,136 @@
+package mowgli
+
+import "testing"
+
+const typeScriptTestSpec = `{
+	"type": "object",
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "maxLength": 50},
+		"email": {"type": "string", "format": "email"},
+		"age": {"type": "integer", "min": 0},
+		"role": {"type": "string", "enum": ["admin", "user"]},
+		"nickname": {"type": "string", "nullable": true},
+		"tags": {"type": "array", "items": {"type": "string"}, "maxLength": 5},
+		"address": {
+			"type": "object",
+			"properties": {"city": {"type": "string"}, "zip-code": {"type": "string", "pattern": "^[0-9]{5}$"}},
+			"required": ["city"]
+		},
+		"labels": {"type": "object", "additionalProperties": {"type": "number"}}
+	},
+	"required": ["name", "email"]
+}`
+
+func TestExportTypeScript(t *testing.T) {
+	spec, err := ParseSpecString(typeScriptTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	got, err := ExportTypeScript("User", spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `export interface User {
+  address?: {
+    city: string;
+    "zip-code"?: string;
+  };
+  age?: number;
+  email: string;
+  labels?: Record<string, number>;
+  name: string;
+  nickname?: string | null;
+  role?: "admin" | "user";
+  tags?: string[];
+}
+`
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestExportTypeScriptAlias(t *testing.T) {
+	tests := []struct {
+		name string
+		spec *Spec
+		want string
+	}{
+		{name: "enum", spec: &Spec{Type: "integer", Enum: []any{1.0, 2.0}}, want: "export type T = 1 | 2;\n"},
+		{name: "array of unions", spec: &Spec{Type: "array", Items: &Spec{Type: "string", Enum: []any{"a", "b"}}}, want: "export type T = (\"a\" | \"b\")[];\n"},
+		{name: "map", spec: &Spec{Type: "object", AdditionalProperties: &Spec{Type: "boolean"}}, want: "export type T = Record<string, boolean>;\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExportTypeScript("T", tt.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := ExportTypeScript("not a name", &Spec{Type: "string"}); err == nil {
+		t.Error("expected error for invalid name")
+	}
+}
+
+func TestExportZod(t *testing.T) {
+	spec, err := ParseSpecString(typeScriptTestSpec)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	got, err := ExportZod("userSchema", spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `export const userSchema = z.object({
+  address: z.object({
+    city: z.string(),
+    "zip-code": z.string().regex(new RegExp("^[0-9]{5}$")).optional(),
+  }).optional(),
+  age: z.number().int().min(0).optional(),
+  email: z.string().email(),
+  labels: z.record(z.string(), z.number()).optional(),
+  name: z.string().min(1).max(50),
+  nickname: z.string().nullable().optional(),
+  role: z.enum(["admin", "user"]).optional(),
+  tags: z.array(z.string()).max(5).optional(),
+});
+`
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+
+	mixed, err := ExportZod("s", &Spec{Enum: []any{1.0, "a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "export const s = z.union([z.literal(1), z.literal(\"a\")]);\n"; mixed != want {
+		t.Errorf("expected %q, got %q", want, mixed)
+	}
+}
+
+func TestExportTypeScriptFlags(t *testing.T) {
+	trueVal := true
+	spec := &Spec{Type: "object", Properties: map[string]*Spec{
+		"id":       {Type: "string", ReadOnly: &trueVal},
+		"password": {Type: "string", WriteOnly: &trueVal},
+		"fax":      {Type: "string", Deprecated: &trueVal},
+	}, Required: []string{"id"}}
+
+	got, err := ExportTypeScript("User", spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "export interface User {\n  /** @deprecated */\n  fax?: string;\n  readonly id: string;\n  password?: string;\n}\n"
+	if got != want {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", got, want)
+	}
+}
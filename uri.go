@@ -0,0 +1,118 @@
+package mowgli
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// schemePattern matches a URI scheme as defined by RFC 3986
+var schemePattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*$`)
+
+// sharedAddressSpace is 100.64.0.0/10, used for carrier-grade NAT and by
+// some clouds for internal services
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// validateURI checks a URI's scheme against the spec's uriSchemes and, if
+// publicHost is set, rejects hosts on loopback, private, link-local and
+// similar internal networks. The check is on the URI text only: hostnames
+// are not resolved, so use a dialer that checks addresses too to guard
+// against DNS that points at internal hosts.
+func (r *ValidationResult) validateURI(path, str string, spec *Spec) {
+	u, err := url.Parse(str)
+	if err != nil || u.Scheme == "" {
+		// A "uri" format reports this already
+		if spec.Format == nil || *spec.Format != "uri" {
+			r.addError(path, CodeFormat, "string is not a valid uri", map[string]any{"format": "uri"})
+		}
+		return
+	}
+
+	if len(spec.URISchemes) > 0 && !slices.ContainsFunc(spec.URISchemes, func(s string) bool { return strings.EqualFold(s, u.Scheme) }) {
+		r.addError(path, CodeURISchemes, fmt.Sprintf("URI scheme %s is not one of %s", u.Scheme, strings.Join(spec.URISchemes, ", ")),
+			map[string]any{"actual": u.Scheme, "allowed": spec.URISchemes})
+	}
+
+	if spec.PublicHost != nil && *spec.PublicHost {
+		if u.Hostname() == "" {
+			r.addError(path, CodePublicHost, "URI has no host", map[string]any{"host": ""})
+		} else if reason := internalHost(u.Hostname()); reason != "" {
+			r.addError(path, CodePublicHost, fmt.Sprintf("URI host %s is %s", u.Hostname(), reason),
+				map[string]any{"host": u.Hostname()})
+		}
+	}
+}
+
+// internalHost describes why host isn't a public host, or returns "" if it
+// may be one
+func internalHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "a loopback address"
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		// Resolvers accept IPv4 in forms such as 2130706433, 0x7f.1 and
+		// 0177.0.0.1, which would bypass the checks below
+		if numericHost(host) {
+			return "a non-canonical IP address"
+		}
+		return ""
+	}
+	addr = addr.Unmap().WithZone("")
+
+	switch {
+	case addr.IsLoopback():
+		return "a loopback address"
+	case addr.IsPrivate(), sharedAddressSpace.Contains(addr):
+		return "a private address"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		return "a link-local address"
+	case addr.IsUnspecified():
+		return "an unspecified address"
+	case addr.IsMulticast():
+		return "a multicast address"
+	}
+	return ""
+}
+
+// numericHost reports whether every label of host is a decimal, octal or
+// hexadecimal number, as in the IPv4 forms inet_aton accepts
+func numericHost(host string) bool {
+	for _, label := range strings.Split(host, ".") {
+		digits := strings.TrimPrefix(label, "0x")
+		if digits == "" {
+			return false
+		}
+		for _, c := range digits {
+			isHex := (c >= 'a' && c <= 'f') && len(digits) < len(label)
+			if !(c >= '0' && c <= '9') && !isHex {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkURISchemes reports scheme names that aren't valid URI schemes
+func checkURISchemes(schemes []string) error {
+	for _, scheme := range schemes {
+		if !schemePattern.MatchString(scheme) {
+			return fmt.Errorf("invalid URI scheme: %q", scheme)
+		}
+	}
+	return nil
+}
+
+// uriExample returns a URI using the first allowed scheme on host
+func uriExample(spec *Spec, host string) string {
+	scheme := "https"
+	if len(spec.URISchemes) > 0 {
+		scheme = strings.ToLower(spec.URISchemes[0])
+	}
+	return scheme + "://" + host + "/"
+}
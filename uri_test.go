@@ -0,0 +1,79 @@
+package mowgli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateURIRestrictions(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "string", "format": "uri", "uriSchemes": ["https"], "publicHost": true}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		url  string
+		want []string // error codes
+	}{
+		{url: "https://hooks.example.com/notify"},
+		{url: "HTTPS://example.com:8443/path?q=1"},
+		{url: "https://93.184.216.34/"},
+		{url: "http://example.com/", want: []string{CodeURISchemes}},
+		{url: "javascript:alert(1)", want: []string{CodeURISchemes, CodePublicHost}},
+		{url: "https://localhost/", want: []string{CodePublicHost}},
+		{url: "https://api.localhost./", want: []string{CodePublicHost}},
+		{url: "https://127.0.0.1:8080/", want: []string{CodePublicHost}},
+		{url: "https://10.1.2.3/", want: []string{CodePublicHost}},
+		{url: "https://192.168.0.1/", want: []string{CodePublicHost}},
+		{url: "https://100.64.0.1/", want: []string{CodePublicHost}},
+		{url: "https://169.254.169.254/latest/meta-data", want: []string{CodePublicHost}},
+		{url: "https://[::1]/", want: []string{CodePublicHost}},
+		{url: "https://[::ffff:127.0.0.1]/", want: []string{CodePublicHost}},
+		{url: "https://[fd00::1]/", want: []string{CodePublicHost}},
+		{url: "https://0.0.0.0/", want: []string{CodePublicHost}},
+		{url: "https://2130706433/", want: []string{CodePublicHost}},
+		{url: "https://0x7f.1/", want: []string{CodePublicHost}},
+		{url: "https://0177.0.0.1/", want: []string{CodePublicHost}},
+		{url: "not a uri", want: []string{CodeFormat}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			result := Validate(tt.url, spec)
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateURIWithoutFormat(t *testing.T) {
+	result := Validate("example.com/path", &Spec{Type: "string", URISchemes: []string{"https"}})
+	if len(result.Errors) != 1 || result.Errors[0].Code != CodeFormat {
+		t.Errorf("expected one format error, got %v", result.Errors)
+	}
+}
+
+func TestURIRestrictionTags(t *testing.T) {
+	type webhook struct {
+		URL string `json:"url" mowgli:"required,format=uri,uriSchemes=https,publicHost,msg=Use a public https URL"`
+	}
+
+	spec, err := SpecFromStruct(webhook{})
+	if err != nil {
+		t.Fatalf("SpecFromStruct failed: %v", err)
+	}
+	result := Validate(map[string]any{"url": "https://127.0.0.1/"}, spec)
+	if result.Valid || result.Errors[0].Message != "Use a public https URL" {
+		t.Errorf("expected custom message, got %v", result.Errors)
+	}
+
+	if _, err := ParseStructTag("uriSchemes=ht_tp"); err == nil || !strings.Contains(err.Error(), "invalid URI scheme") {
+		t.Errorf("expected invalid scheme error, got %v", err)
+	}
+}
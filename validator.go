@@ -3,15 +3,70 @@ package mowgli
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"maps"
 	"reflect"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
+	"sync"
+	"time"
+)
+
+// Error codes identify the kind of constraint a ValidationError reports.
+// Codes are stable and can be used as keys in message catalogs.
+const (
+	CodeRequired         = "required"
+	CodeAnyRequired      = "anyRequired"
+	CodeOneRequired      = "oneRequired"
+	CodeType             = "type"
+	CodeMin              = "min"
+	CodeMax              = "max"
+	CodeMinInt           = "minInt"
+	CodeMaxInt           = "maxInt"
+	CodeTimeFormat       = "timeFormat"
+	CodeSemverRange      = "semverRange"
+	CodeURISchemes       = "uriSchemes"
+	CodePublicHost       = "publicHost"
+	CodeMinLength        = "minLength"
+	CodeMaxLength        = "maxLength"
+	CodeMinBytes         = "minBytes"
+	CodeMaxBytes         = "maxBytes"
+	CodeContentEncoding  = "contentEncoding"
+	CodeContentMediaType = "contentMediaType"
+	CodePattern          = "pattern"
+	CodeFormat           = "format"
+	CodeEnum             = "enum"
+	CodeUniqueItems      = "uniqueItems"
+	CodeCondition        = "condition"
+	CodeDiscriminator    = "discriminator"
+	CodeCheck            = "check"
+	CodeInvalidSpec      = "invalidSpec"
+	CodeReadOnly         = "readOnly"
+	CodeWriteOnly        = "writeOnly"
+	CodeDeprecated       = "deprecated"
+	CodeLimitExceeded    = "limitExceeded"
+	CodeAssert           = "assert"
+	CodeRefIntegrity     = "refIntegrity"
+	CodeDuplicateKey     = "duplicateKey"
+	CodeUnknownField     = "unknownField"
 )
 
 // ValidationError represents a validation error with a path to the field
 type ValidationError struct {
 	Path    string
-	Message string
+	Code    string         // Machine-readable error code, one of the Code* constants
+	Message string         // Default (English) message
+	Params  map[string]any // Values referenced by the message, e.g. "actual" and "limit"
+
+	// Set with WithErrorValues
+	Actual   any `json:",omitempty"` // The offending value, e.g. 200; nil if missing or too large
+	Expected any `json:",omitempty"` // What the constraint expects, e.g. 150 for a maximum of 150
+
+	// custom is set if Message is the spec's custom message for Code,
+	// which Localize keeps
+	custom bool
 }
 
 func (e *ValidationError) Error() string {
@@ -25,31 +80,298 @@ func (e *ValidationError) Error() string {
 type ValidationResult struct {
 	Valid  bool
 	Errors []*ValidationError
+
+	// Warnings report problems that don't make the document invalid, such
+	// as deprecated fields being present
+	Warnings []*ValidationError
+
+	// Document is the validated document with the spec's transforms applied.
+	// It is the document that was validated if no transform changed a value.
+	Document any
+
+	// root is the top-level document being validated, exposed to conditions as $root
+	root any
+	// objects is the stack of enclosing objects, used to resolve $parent in conditions
+	objects []map[string]any
+	// exprCache holds the outcomes of the expressions evaluated on each of
+	// objects, by expression
+	exprCache []map[string]exprOutcome
+	// exprFuncs are the custom functions available to conditions
+	exprFuncs map[string]any
+	// exprLimits bounds the cost of evaluating conditions
+	exprLimits ExprLimits
+	// programs holds the compiled conditions of the CompiledSpec being
+	// validated against; nil for plain specs
+	programs *programSet
+	// strictExpressions makes expressions referencing missing fields fail
+	strictExpressions bool
+	// errorValueSize caps the Actual and Expected values of errors; 0 leaves them out
+	errorValueSize int
+	// current is the value being validated, for the Actual value of its errors
+	current currentValue
+	// asyncChecks are the checks available to the spec's "checks" keyword
+	asyncChecks map[string]AsyncCheck
+	// pendingChecks collects async checks to run after validation; nil disables collection
+	pendingChecks []pendingCheck
+	// registry resolves $ref; nil if the Validator has no registry
+	registry *Registry
+	// segments locates the value being validated, for recording transformed values
+	segments []string
+	// propertyName is set while property names are validated
+	propertyName bool
+	// transformed collects the values changed by transforms
+	transformed []transformedValue
+	// times collects the strings parsed by timeFormat layouts
+	times []parsedTime
+	// formats holds the formats defined by the specs being validated
+	formats formatScope
+	// conditionDefs holds the conditions defined by the specs being validated
+	conditionDefs conditionDefScope
+	// overrides holds the overrides of the specs being validated
+	overrides overrideScope
+	// mode decides whether readOnly and writeOnly fields are rejected
+	mode Mode
+	// limits bounds the depth and size of the validated document
+	limits Limits
+	// lengthUnit measures strings for specs without a lengthUnit
+	lengthUnit string
+	// useNumber is set if JSON numbers are decoded as json.Number
+	useNumber bool
+	// formatter renders error messages; nil keeps the default messages
+	formatter Formatter
+	// tracing enables recording trace, the steps of validation
+	tracing bool
+	trace   []TraceEvent
+	// annotations records validation decisions; nil unless annotating
+	annotations *Annotations
+	// observer is notified of the outcome; nil if not observed
+	observer Observer
+	// logger logs invalid specs, failed conditions and slow validations; nil
+	// disables logging
+	logger        *slog.Logger
+	logLevels     LogLevels
+	slowThreshold time.Duration
+	// depth and nodes count the values being and already validated
+	depth, nodes int
+	// limitErr is set when a limit stops validation
+	limitErr error
+}
+
+// Validator holds configuration shared by all validations it performs, such
+// as custom expression functions. A Validator is safe for concurrent use.
+type Validator struct {
+	mu          sync.RWMutex
+	exprFuncs   map[string]any
+	asyncChecks map[string]AsyncCheck
+	exprLimits  ExprLimits
+	registry    *Registry
+	mode        Mode
+	limits      Limits
+	lengthUnit  string
+	useNumber   bool
+	formatter   Formatter
+	tracing     bool
+	annotating  bool
+	observer    Observer
+	// strictExpressions makes expressions referencing missing fields fail
+	strictExpressions bool
+	// errorValueSize caps the Actual and Expected values of errors; 0 leaves them out
+	errorValueSize int
+	// duplicateKeys reports keys repeated in the objects of raw JSON
+	duplicateKeys bool
+
+	logger        *slog.Logger
+	logLevels     LogLevels
+	slowThreshold time.Duration
+}
+
+// Option configures a Validator
+type Option func(*Validator)
+
+// WithExprLimits bounds the cost of evaluating condition expressions.
+// Conditions that exceed a limit fail with a condition error.
+func WithExprLimits(limits ExprLimits) Option {
+	return func(v *Validator) {
+		v.exprLimits = limits
+	}
+}
+
+// Mode is the direction of the documents a Validator validates, which
+// decides how readOnly and writeOnly fields are treated
+type Mode int
+
+const (
+	// ModeAny ignores readOnly and writeOnly (the default)
+	ModeAny Mode = iota
+	// ModeRequest validates documents sent by clients: readOnly fields are
+	// rejected and are not required
+	ModeRequest
+	// ModeResponse validates documents sent to clients: writeOnly fields are
+	// rejected and are not required
+	ModeResponse
+)
+
+// WithMode validates documents in the given direction, so one spec can
+// describe both the requests and the responses of an API
+func WithMode(mode Mode) Option {
+	return func(v *Validator) {
+		v.mode = mode
+	}
+}
+
+// defaultValidator backs the package-level Validate functions
+var defaultValidator = NewValidator()
+
+// NewValidator creates a Validator configured by opts
+func NewValidator(opts ...Option) *Validator {
+	v := &Validator{
+		exprFuncs:   make(map[string]any),
+		asyncChecks: make(map[string]AsyncCheck),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// RegisterExprFunc makes fn callable by name from condition expressions, e.g.
+// RegisterExprFunc("isBusinessDay", func(date string) bool { ... }) enables
+// "if": "isBusinessDay(date)". Functions receive JSON-decoded values (numbers
+// are float64) and take precedence over document fields with the same name.
+// fn may return a single value, or a value and an error.
+func (v *Validator) RegisterExprFunc(name string, fn any) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid expression function name: %q", name)
+	}
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func {
+		return fmt.Errorf("expression function %s must be a func, got %T", name, fn)
+	}
+	if fnType.NumOut() == 0 || fnType.NumOut() > 2 ||
+		(fnType.NumOut() == 2 && fnType.Out(1) != reflect.TypeOf((*error)(nil)).Elem()) {
+		return fmt.Errorf("expression function %s must return a value, or a value and an error", name)
+	}
+
+	// Copy on write so validations in flight keep a consistent set of functions
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	funcs := make(map[string]any, len(v.exprFuncs)+1)
+	for k, f := range v.exprFuncs {
+		funcs[k] = f
+	}
+	funcs[name] = fn
+	v.exprFuncs = funcs
+	return nil
 }
 
 // Validate validates a JSON value against a spec
-func Validate(data any, spec *Spec) *ValidationResult {
+func (v *Validator) Validate(data any, spec *Spec) *ValidationResult {
+	return v.validateNamed(specName(spec), data, spec, nil)
+}
+
+// validateNamed validates data against spec, whose compiled conditions
+// programs holds if it is not nil, reporting the outcome to the observer as
+// that of the spec named name
+func (v *Validator) validateNamed(name string, data any, spec *Spec, programs *programSet) *ValidationResult {
+	start := time.Now()
+	result := v.newResult(data)
+	result.programs = programs
+	result.run(spec)
+	result.observe(name, start)
+	return result
+}
+
+// newResult creates a result configured with the validator's settings
+func (v *Validator) newResult(data any) *ValidationResult {
 	result := &ValidationResult{
-		Valid:  true,
-		Errors: []*ValidationError{},
+		Valid:    true,
+		Errors:   []*ValidationError{},
+		Warnings: []*ValidationError{},
+		root:     data,
 	}
 
-	if spec == nil {
-		result.addError("", "spec is nil")
-		return result
+	v.mu.RLock()
+	result.exprFuncs = v.exprFuncs
+	result.asyncChecks = v.asyncChecks
+	v.mu.RUnlock()
+	result.exprLimits = v.exprLimits
+	result.strictExpressions = v.strictExpressions
+	result.errorValueSize = v.errorValueSize
+	result.registry = v.registry
+	result.mode = v.mode
+	result.limits = v.limits
+	result.lengthUnit = v.lengthUnit
+	result.useNumber = v.useNumber
+	result.formatter = v.formatter
+	result.tracing = v.tracing
+	result.observer = v.observer
+	result.logger = v.logger
+	result.logLevels = v.logLevels
+	result.slowThreshold = v.slowThreshold
+	if v.annotating {
+		result.annotations = &Annotations{
+			Conditions: make(map[string][]string),
+			Branches:   make(map[string][]string),
+			Specs:      make(map[string]*Spec),
+		}
 	}
 
-	result.validate("", data, spec)
 	return result
 }
 
-// ValidateJSON validates a JSON byte slice against a spec
-func ValidateJSON(jsonData []byte, spec *Spec) (*ValidationResult, error) {
-	var data any
-	if err := json.Unmarshal(jsonData, &data); err != nil {
+// run validates the result's document against spec
+func (r *ValidationResult) run(spec *Spec) {
+	if spec == nil {
+		r.addError("", CodeInvalidSpec, "spec is nil", nil)
+		return
+	}
+	r.validate("", r.root, spec)
+	r.buildDocument()
+}
+
+// ValidateJSON validates a JSON byte slice against a spec. Exceeding the
+// Validator's Limits returns an error wrapping ErrDepthExceeded or
+// ErrNodeLimitExceeded.
+func (v *Validator) ValidateJSON(jsonData []byte, spec *Spec) (*ValidationResult, error) {
+	return v.validateJSON(specName(spec), jsonData, spec, v.useNumber, strictDecoding{})
+}
+
+// validateJSON decodes and validates jsonData, keeping numbers as
+// json.Number if useNumber is set, checks it against the type it is decoded
+// into as strict configures, and reports the outcome to the observer as that
+// of the spec named name
+func (v *Validator) validateJSON(name string, jsonData []byte, spec *Spec, useNumber bool, strict strictDecoding) (*ValidationResult, error) {
+	start := time.Now()
+	data, err := decodeJSON(jsonData, useNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	result := v.newResult(data)
+	result.useNumber = useNumber
+	result.run(spec)
+	if v.duplicateKeys {
+		if err := result.addDuplicateKeys(jsonData); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	}
+	if err := result.checkDecoding(jsonData, strict); err != nil {
 		return nil, fmt.Errorf("invalid JSON: %w", err)
 	}
-	return Validate(data, spec), nil
+	result.observe(name, start)
+	if err := result.LimitError(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Validate validates a JSON value against a spec
+func Validate(data any, spec *Spec) *ValidationResult {
+	return defaultValidator.Validate(data, spec)
+}
+
+// ValidateJSON validates a JSON byte slice against a spec
+func ValidateJSON(jsonData []byte, spec *Spec) (*ValidationResult, error) {
+	return defaultValidator.ValidateJSON(jsonData, spec)
 }
 
 // ValidateJSONString validates a JSON string against a spec
@@ -57,12 +379,40 @@ func ValidateJSONString(jsonStr string, spec *Spec) (*ValidationResult, error) {
 	return ValidateJSON([]byte(jsonStr), spec)
 }
 
-func (r *ValidationResult) addError(path, message string) {
+func (r *ValidationResult) addError(path, code, message string, params map[string]any) {
+	if code == CodeInvalidSpec {
+		r.logInvalidSpec(path, message)
+	}
 	r.Valid = false
-	r.Errors = append(r.Errors, &ValidationError{
+	r.Errors = append(r.Errors, r.newError(path, code, message, params))
+}
+
+func (r *ValidationResult) addWarning(path, code, message string, params map[string]any) {
+	r.Warnings = append(r.Warnings, r.newError(path, code, message, params))
+}
+
+// newError creates an error with its values set and its message formatted
+func (r *ValidationResult) newError(path, code, message string, params map[string]any) *ValidationError {
+	err := &ValidationError{
 		Path:    path,
+		Code:    code,
 		Message: message,
-	})
+		Params:  params,
+	}
+	r.setErrorValues(err)
+	return r.format(err)
+}
+
+// applyMessages replaces the messages of errors reported at path since index
+// start with the spec's custom messages for their codes
+func (r *ValidationResult) applyMessages(start int, path string, messages map[string]string) {
+	for _, err := range r.Errors[start:] {
+		if message, ok := messages[err.Code]; ok && err.Path == path {
+			err.Message = message
+			err.custom = true
+			r.format(err)
+		}
+	}
 }
 
 func buildPath(base, field string) string {
@@ -84,14 +434,106 @@ func (r *ValidationResult) validate(path string, value any, spec *Spec) {
 		return
 	}
 
+	if !r.enter(path) {
+		return
+	}
+	defer r.leave()
+
+	if spec.Ref != "" {
+		resolved, err := r.resolveRef(spec)
+		if err != nil {
+			r.addError(path, CodeInvalidSpec, err.Error(), map[string]any{"ref": spec.Ref})
+			return
+		}
+		r.addTrace(path, TraceRef, spec.Ref, nil)
+		spec = resolved
+	}
+	if len(r.overrides) > 0 {
+		spec = r.overrides.apply(r, path, spec)
+	}
+	if spec.Overrides != nil {
+		leave, err := r.overrides.push(path, spec.Overrides)
+		defer leave()
+		if err != nil {
+			r.addError(path, CodeInvalidSpec, err.Error(), nil)
+			return
+		}
+	}
+	if r.tracing {
+		specType := spec.Type
+		if specType == "" {
+			specType = "any"
+		}
+		r.addTrace(path, TraceSpec, specType, nil)
+	}
+	r.annotateSpec(path, spec)
+	if r.errorValueSize > 0 {
+		defer r.enterValue(path, value, spec)()
+	}
+
+	if spec.Formats != nil {
+		defer r.formats.push(spec.Formats)()
+	}
+	if spec.ConditionDefs != nil {
+		defer r.conditionDefs.push(spec.ConditionDefs)()
+	}
+
+	if len(spec.Checks) > 0 && r.pendingChecks != nil {
+		// Async checks only run for values that passed synchronous validation
+		before := len(r.Errors)
+		defer func() {
+			if len(r.Errors) == before {
+				r.queueChecks(path, value, spec.Checks)
+			}
+		}()
+	}
+
+	if len(spec.Messages) > 0 {
+		before := len(r.Errors)
+		defer r.applyMessages(before, path, spec.Messages)
+	}
+
+	if spec.Deprecated != nil && *spec.Deprecated {
+		message, custom := spec.Messages[CodeDeprecated]
+		if !custom {
+			message = "field is deprecated"
+		}
+		r.addWarning(path, CodeDeprecated, message, nil)
+		r.Warnings[len(r.Warnings)-1].custom = custom
+	}
+
+	if r.excluded(spec) {
+		if r.mode == ModeRequest {
+			r.addError(path, CodeReadOnly, "field is read-only", nil)
+		} else {
+			r.addError(path, CodeWriteOnly, "field is write-only", nil)
+		}
+		return
+	}
+
+	// Validate against the branch the if subschema selects, if any
+	if spec.If != nil {
+		spec = r.conditional(path, value, spec)
+		r.annotateSpec(path, spec)
+	}
+
 	// Handle null values
 	if value == nil {
-		if spec.Type != "null" {
-			r.addError(path, fmt.Sprintf("expected type %s, got null", spec.Type))
+		if spec.Type != "null" && (spec.Nullable == nil || !*spec.Nullable) {
+			r.addError(path, CodeType, fmt.Sprintf("expected type %s, got null", spec.Type),
+				map[string]any{"expected": spec.Type, "actual": "null"})
 		}
 		return
 	}
 
+	// Conditions see the original value; every other constraint sees the transformed one
+	if len(spec.Transform) > 0 {
+		value = r.applyTransforms(path, value, spec)
+		if r.errorValueSize > 0 {
+			r.current.value = value
+		}
+	}
+
 	switch spec.Type {
 	case "string":
 		r.validateString(path, value, spec)
@@ -107,9 +549,10 @@ func (r *ValidationResult) validate(path string, value any, spec *Spec) {
 		r.validateArray(path, value, spec)
 	case "null":
 		// value is guaranteed to be non-nil at this point (checked above)
-		r.addError(path, "expected null, got non-null value")
+		r.addError(path, CodeType, "expected null, got non-null value",
+			map[string]any{"expected": "null", "actual": fmt.Sprintf("%T", value)})
 	default:
-		r.addError(path, fmt.Sprintf("unknown type: %s", spec.Type))
+		r.addError(path, CodeInvalidSpec, fmt.Sprintf("unknown type: %s", spec.Type), nil)
 	}
 
 	// Validate enum constraint if specified
@@ -121,7 +564,8 @@ func (r *ValidationResult) validate(path string, value any, spec *Spec) {
 func (r *ValidationResult) validateString(path string, value any, spec *Spec) {
 	str, ok := value.(string)
 	if !ok {
-		r.addError(path, fmt.Sprintf("expected string, got %T", value))
+		r.addError(path, CodeType, fmt.Sprintf("expected string, got %T", value),
+			map[string]any{"expected": "string", "actual": fmt.Sprintf("%T", value)})
 		return
 	}
 
@@ -131,115 +575,234 @@ func (r *ValidationResult) validateString(path string, value any, spec *Spec) {
 	}
 
 	// If allowEmpty is false or not set, empty strings must pass minLength check
-	if spec.MinLength != nil && len(str) < *spec.MinLength {
-		r.addError(path, fmt.Sprintf("string length %d is less than minimum %d", len(str), *spec.MinLength))
+	if spec.MinLength != nil || spec.MaxLength != nil {
+		r.validateStringLength(path, str, spec)
+	}
+
+	if spec.MinBytes != nil && len(str) < *spec.MinBytes {
+		r.addError(path, CodeMinBytes, fmt.Sprintf("string size %d bytes is less than minimum %d", len(str), *spec.MinBytes),
+			map[string]any{"actual": len(str), "limit": *spec.MinBytes})
 	}
 
-	if spec.MaxLength != nil && len(str) > *spec.MaxLength {
-		r.addError(path, fmt.Sprintf("string length %d is greater than maximum %d", len(str), *spec.MaxLength))
+	if spec.MaxBytes != nil && len(str) > *spec.MaxBytes {
+		r.addError(path, CodeMaxBytes, fmt.Sprintf("string size %d bytes is greater than maximum %d", len(str), *spec.MaxBytes),
+			map[string]any{"actual": len(str), "limit": *spec.MaxBytes})
 	}
 
 	if spec.Pattern != nil {
-		matched, err := regexp.MatchString(*spec.Pattern, str)
+		re, err := compilePattern(*spec.Pattern)
 		if err != nil {
-			r.addError(path, fmt.Sprintf("invalid pattern: %v", err))
-		} else if !matched {
-			r.addError(path, fmt.Sprintf("string does not match pattern: %s", *spec.Pattern))
+			r.addError(path, CodeInvalidSpec, fmt.Sprintf("invalid pattern: %v", err), nil)
+		} else if !re.MatchString(str) {
+			r.addError(path, CodePattern, fmt.Sprintf("string does not match pattern: %s", *spec.Pattern),
+				map[string]any{"pattern": *spec.Pattern})
 		}
 	}
+
+	if spec.Format != nil {
+		r.validateFormat(path, str, *spec.Format)
+	}
+
+	if spec.TimeFormat != "" {
+		r.validateTimeFormat(path, str, spec.TimeFormat)
+	}
+
+	if spec.SemverRange != "" {
+		r.validateSemverRange(path, str, spec.SemverRange)
+	}
+
+	if len(spec.URISchemes) > 0 || (spec.PublicHost != nil && *spec.PublicHost) {
+		r.validateURI(path, str, spec)
+	}
+
+	if spec.ContentEncoding != "" || spec.ContentMediaType != "" || spec.ContentSchema != nil {
+		r.validateContent(path, str, spec)
+	}
 }
 
-func (r *ValidationResult) validateNumber(path string, value any, spec *Spec) {
-	var num float64
-	switch v := value.(type) {
-	case float64:
-		num = v
-	case int:
-		num = float64(v)
-	case int64:
-		num = float64(v)
-	case float32:
-		num = float64(v)
-	default:
-		r.addError(path, fmt.Sprintf("expected number, got %T", value))
+// validateStringLength checks minLength and maxLength, measuring str in the
+// spec's length unit
+func (r *ValidationResult) validateStringLength(path, str string, spec *Spec) {
+	unit := spec.LengthUnit
+	if unit == "" {
+		unit = r.lengthUnit
+	}
+	length, err := stringLength(str, unit)
+	if err != nil {
+		r.addError(path, CodeInvalidSpec, err.Error(), nil)
 		return
 	}
 
-	if spec.Min != nil && num < *spec.Min {
-		r.addError(path, fmt.Sprintf("number %g is less than minimum %g", num, *spec.Min))
+	if spec.MinLength != nil && length < *spec.MinLength {
+		r.addError(path, CodeMinLength, fmt.Sprintf("string length %d is less than minimum %d", length, *spec.MinLength),
+			map[string]any{"actual": length, "limit": *spec.MinLength})
+	}
+
+	if spec.MaxLength != nil && length > *spec.MaxLength {
+		r.addError(path, CodeMaxLength, fmt.Sprintf("string length %d is greater than maximum %d", length, *spec.MaxLength),
+			map[string]any{"actual": length, "limit": *spec.MaxLength})
 	}
+}
+
+// patternCacheSize is the number of compiled regular expressions kept
+const patternCacheSize = 1000
+
+// patternCache holds compiled regular expressions keyed by their source. It
+// is bounded, as patterns passed to matches() may come from documents.
+var patternCache = newLRUCache[string, *regexp.Regexp](patternCacheSize)
+
+// compilePattern compiles a regular expression, reusing recently compiled patterns
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.get(pattern); ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.add(pattern, re)
+	return re, nil
+}
 
-	if spec.Max != nil && num > *spec.Max {
-		r.addError(path, fmt.Sprintf("number %g is greater than maximum %g", num, *spec.Max))
+func (r *ValidationResult) validateNumber(path string, value any, spec *Spec) {
+	num, _, ok := numberValue(value)
+	if !ok {
+		r.addError(path, CodeType, fmt.Sprintf("expected number, got %T", value),
+			map[string]any{"expected": "number", "actual": fmt.Sprintf("%T", value)})
+		return
 	}
+
+	r.validateRange(path, "number", value, num, spec)
 }
 
 func (r *ValidationResult) validateInteger(path string, value any, spec *Spec) {
-	var num float64
-	isInt := false
-
-	switch v := value.(type) {
-	case float64:
-		num = v
-		// Check if it's actually an integer (no fractional part)
-		if num == float64(int64(num)) {
-			isInt = true
-		}
-	case int:
-		num = float64(v)
-		isInt = true
-	case int64:
-		num = float64(v)
-		isInt = true
-	default:
-		r.addError(path, fmt.Sprintf("expected integer, got %T", value))
+	num, isInt, ok := numberValue(value)
+	if !ok {
+		r.addError(path, CodeType, fmt.Sprintf("expected integer, got %T", value),
+			map[string]any{"expected": "integer", "actual": fmt.Sprintf("%T", value)})
 		return
 	}
 
 	if !isInt {
-		r.addError(path, fmt.Sprintf("expected integer, got float: %g", num))
+		r.addError(path, CodeType, fmt.Sprintf("expected integer, got float: %s", formatNumber(value, num)),
+			map[string]any{"expected": "integer", "actual": "float"})
 		return
 	}
 
-	if spec.Min != nil && num < *spec.Min {
-		r.addError(path, fmt.Sprintf("integer %g is less than minimum %g", num, *spec.Min))
+	r.validateRange(path, "integer", value, num, spec)
+}
+
+// validateRange checks min and max, comparing integers beyond 2^53 exactly
+func (r *ValidationResult) validateRange(path, kind string, value any, num float64, spec *Spec) {
+	if spec.Min != nil && compareNumber(value, num, *spec.Min) < 0 {
+		r.addError(path, CodeMin, fmt.Sprintf("%s %s is less than minimum %g", kind, formatNumber(value, num), *spec.Min),
+			map[string]any{"actual": numberParam(value, num), "limit": *spec.Min})
+	}
+
+	if spec.Max != nil && compareNumber(value, num, *spec.Max) > 0 {
+		r.addError(path, CodeMax, fmt.Sprintf("%s %s is greater than maximum %g", kind, formatNumber(value, num), *spec.Max),
+			map[string]any{"actual": numberParam(value, num), "limit": *spec.Max})
+	}
+
+	r.validateIntBound(path, kind, value, num, CodeMinInt, spec.MinInt)
+	r.validateIntBound(path, kind, value, num, CodeMaxInt, spec.MaxInt)
+}
+
+// validateIntBound checks a minInt or maxInt bound, which is compared
+// exactly rather than as float64
+func (r *ValidationResult) validateIntBound(path, kind string, value any, num float64, code string, bound json.Number) {
+	if bound == "" {
+		return
+	}
+	limit, err := intBound(bound)
+	if err != nil {
+		r.addError(path, CodeInvalidSpec, fmt.Sprintf("invalid %s: %v", code, err), nil)
+		return
+	}
+	cmp, ok := compareInt(value, limit)
+	if !ok {
+		return
 	}
 
-	if spec.Max != nil && num > *spec.Max {
-		r.addError(path, fmt.Sprintf("integer %g is greater than maximum %g", num, *spec.Max))
+	if code == CodeMinInt && cmp < 0 {
+		r.addError(path, code, fmt.Sprintf("%s %s is less than minimum %s", kind, formatNumber(value, num), bound),
+			map[string]any{"actual": numberParam(value, num), "limit": bound})
+	}
+	if code == CodeMaxInt && cmp > 0 {
+		r.addError(path, code, fmt.Sprintf("%s %s is greater than maximum %s", kind, formatNumber(value, num), bound),
+			map[string]any{"actual": numberParam(value, num), "limit": bound})
 	}
 }
 
 func (r *ValidationResult) validateBoolean(path string, value any, spec *Spec) {
 	_, ok := value.(bool)
 	if !ok {
-		r.addError(path, fmt.Sprintf("expected boolean, got %T", value))
+		r.addError(path, CodeType, fmt.Sprintf("expected boolean, got %T", value),
+			map[string]any{"expected": "boolean", "actual": fmt.Sprintf("%T", value)})
 	}
 }
 
 func (r *ValidationResult) validateObject(path string, value any, spec *Spec) {
 	obj, ok := value.(map[string]any)
 	if !ok {
-		r.addError(path, fmt.Sprintf("expected object, got %T", value))
+		r.addError(path, CodeType, fmt.Sprintf("expected object, got %T", value),
+			map[string]any{"expected": "object", "actual": fmt.Sprintf("%T", value)})
 		return
 	}
 
+	r.objects = append(r.objects, obj)
+	r.exprCache = append(r.exprCache, nil)
+	defer func() {
+		r.objects = r.objects[:len(r.objects)-1]
+		r.exprCache = r.exprCache[:len(r.exprCache)-1]
+	}()
+
+	// Validate against the shape a condition or the discriminator selects, if any
+	shaped := r.reshaped(path, obj, spec)
+	if shaped.Discriminator != nil {
+		shaped = r.discriminated(path, obj, shaped)
+	}
+	if shaped != spec {
+		spec = shaped
+		r.annotateSpec(path, spec)
+	}
+
 	// Validate properties with conditional overrides
 	// We need to do this first to get the effective specs for required field checking
-	effectiveSpecs := make(map[string]*Spec)
-	if spec.Properties != nil {
-		effectiveSpecs = r.buildEffectiveSpecs(obj, spec)
-	}
+	effectiveSpecs, conditionalRequired := r.buildEffectiveSpecs(path, obj, spec)
 
-	// Check required fields (use base spec required fields)
+	// Check required fields (use base spec required fields plus those required by conditions)
 	// Required fields from nested object overrides are handled when validating those nested objects
-	if spec.Required != nil {
-		for _, req := range spec.Required {
-			if _, exists := obj[req]; !exists {
-				r.addError(buildPath(path, req), "required field is missing")
+	checked := make(map[string]bool, len(spec.Required)+len(conditionalRequired))
+	for _, req := range append(append([]string(nil), spec.Required...), conditionalRequired...) {
+		if checked[req] {
+			continue
+		}
+		checked[req] = true
+		propSpec := spec.Properties[req]
+		if override, ok := effectiveSpecs[req]; ok {
+			propSpec = override
+		}
+		if r.excluded(propSpec) {
+			// Fields that can't be sent in this direction are never required
+			continue
+		}
+		if _, exists := obj[req]; !exists {
+			message := "required field is missing"
+			var params map[string]any
+			if found, ok := suggestRequired(req, obj, spec, effectiveSpecs); ok {
+				message += fmt.Sprintf("; did you mean %q instead of %q?", req, found)
+				params = map[string]any{"suggestion": req, "found": found}
 			}
+			custom := propSpec != nil && propSpec.Messages[CodeRequired] != ""
+			if custom {
+				message = propSpec.Messages[CodeRequired]
+			}
+			r.addError(buildPath(path, req), CodeRequired, message, params)
+			r.Errors[len(r.Errors)-1].custom = custom
 		}
 	}
+	r.validatePropertyGroups(path, obj, spec)
 
 	// Validate properties with conditional overrides
 	if spec.Properties != nil {
@@ -253,7 +816,9 @@ func (r *ValidationResult) validateObject(path string, value any, spec *Spec) {
 			}
 
 			if exists {
+				r.pushSegment(key)
 				r.validate(buildPath(path, key), propValue, effectiveSpec)
+				r.popSegment()
 			}
 		}
 
@@ -268,29 +833,88 @@ func (r *ValidationResult) validateObject(path string, value any, spec *Spec) {
 			}
 		}
 	}
+
+	// Validate property names, and the values of properties the spec doesn't declare
+	if spec.AdditionalProperties != nil || spec.PropertyNames != nil {
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if spec.PropertyNames != nil {
+				r.propertyName = true
+				r.validate(buildPath(path, key), key, spec.PropertyNames)
+				r.propertyName = false
+			}
+			if _, declared := spec.Properties[key]; declared || spec.AdditionalProperties == nil {
+				continue
+			}
+			r.pushSegment(key)
+			r.validate(buildPath(path, key), obj[key], spec.AdditionalProperties)
+			r.popSegment()
+		}
+	}
+
+	r.validateAsserts(path, obj, spec)
+	r.validateRefIntegrity(path, obj, spec)
 }
 
-// buildEffectiveSpecs evaluates conditions and returns effective specs for each property
-func (r *ValidationResult) buildEffectiveSpecs(obj map[string]any, spec *Spec) map[string]*Spec {
+// buildEffectiveSpecs evaluates the conditions of the object at path and
+// returns effective specs for each property, along with the properties that
+// conditions and requiredIf make required
+func (r *ValidationResult) buildEffectiveSpecs(path string, obj map[string]any, spec *Spec) (map[string]*Spec, []string) {
 	effectiveSpecs := make(map[string]*Spec)
+	var required []string
 
-	if len(spec.Conditions) == 0 {
-		return effectiveSpecs
+	if len(spec.Conditions) == 0 && len(spec.RequiredIf) == 0 {
+		return effectiveSpecs, required
+	}
+
+	env := r.conditionEnv(obj)
+
+	for _, name := range slices.Sorted(maps.Keys(spec.RequiredIf)) {
+		expr := spec.RequiredIf[name]
+		result, err := r.evalObjectExpr(buildPath(path, name), TraceRequiredIf, expr, env)
+		if err != nil {
+			r.logCondition(buildPath(path, name), expr, err)
+			r.conditionFailed(path, "requiredIf for "+name, expr, err)
+			continue
+		}
+		if result {
+			required = append(required, name)
+		}
 	}
 
 	// Collect all overrides first, then merge them all together
 	for _, condition := range spec.Conditions {
-		result, err := evalExpression(condition.If, obj)
+		if condition.reshapes() {
+			// reshaped evaluated it already
+			continue
+		}
+		expression := condition.Expression()
+		result, err := r.evalObjectExpr(path, TraceCondition, expression, env)
 		if err != nil {
-			r.addError("", fmt.Sprintf("error evaluating condition '%s': %v", condition.If, err))
+			r.logCondition(path, expression, err)
+			r.conditionFailed(path, "condition", expression, err)
 			continue
 		}
 
 		var overrides map[string]*Spec
+		branch := "then"
 		if result {
 			overrides = condition.Then
+			required = append(required, condition.Required...)
+			r.annotateCondition(path, condition)
 		} else {
 			overrides = condition.Else
+			branch = "else"
+		}
+		if r.tracing {
+			for _, fieldName := range sortedKeys(overrides) {
+				r.addTrace(buildPath(path, fieldName), TraceOverride, expression, branch)
+			}
 		}
 
 		if overrides != nil {
@@ -313,7 +937,35 @@ func (r *ValidationResult) buildEffectiveSpecs(obj map[string]any, spec *Spec) m
 		}
 	}
 
-	return effectiveSpecs
+	return effectiveSpecs, required
+}
+
+// conditionEnv returns the expression environment for conditions on obj.
+// Besides the object's own fields it exposes $root (the top-level document),
+// $parent (the nearest enclosing object, nil at the top level) and any
+// functions registered on the Validator.
+func (r *ValidationResult) conditionEnv(obj map[string]any) map[string]any {
+	env := make(map[string]any, len(obj)+len(r.exprFuncs)+2)
+	for k, v := range obj {
+		env[k] = v
+	}
+
+	var parent any
+	if len(r.objects) >= 2 {
+		parent = r.objects[len(r.objects)-2]
+	}
+	env[rootIdentifier] = r.root
+	env[parentIdentifier] = parent
+	if r.useNumber {
+		for k, v := range env {
+			env[k] = exprValue(v)
+		}
+	}
+	for name, fn := range r.exprFuncs {
+		env[name] = fn
+	}
+
+	return env
 }
 
 // mergeSpecs merges overrideSpec into baseSpec, with overrideSpec taking precedence
@@ -331,45 +983,185 @@ func (r *ValidationResult) mergeSpecs(base, override *Spec) *Spec {
 		Items:      base.Items,
 		Required:   base.Required,
 		Conditions: base.Conditions,
-		Min:        base.Min,
-		Max:        base.Max,
-		MinLength:  base.MinLength,
-		MaxLength:  base.MaxLength,
-		Pattern:    base.Pattern,
-		Enum:       base.Enum,
-		AllowEmpty: base.AllowEmpty,
+		RequiredIf: base.RequiredIf,
+		Ref:        base.Ref,
+
+		AdditionalProperties: base.AdditionalProperties,
+		PropertyNames:        base.PropertyNames,
+		Discriminator:        base.Discriminator,
+		If:                   base.If,
+		Then:                 base.Then,
+		Else:                 base.Else,
+		AnyRequired:          base.AnyRequired,
+		OneRequired:          base.OneRequired,
+		ContentEncoding:      base.ContentEncoding,
+		ContentMediaType:     base.ContentMediaType,
+		ContentSchema:        base.ContentSchema,
+		UniqueItems:          base.UniqueItems,
+
+		Min:         base.Min,
+		Max:         base.Max,
+		MinInt:      base.MinInt,
+		MaxInt:      base.MaxInt,
+		MinLength:   base.MinLength,
+		MaxLength:   base.MaxLength,
+		LengthUnit:  base.LengthUnit,
+		MinBytes:    base.MinBytes,
+		MaxBytes:    base.MaxBytes,
+		Pattern:     base.Pattern,
+		Format:      base.Format,
+		TimeFormat:  base.TimeFormat,
+		SemverRange: base.SemverRange,
+		URISchemes:  base.URISchemes,
+		PublicHost:  base.PublicHost,
+		Enum:        base.Enum,
+		AllowEmpty:  base.AllowEmpty,
+		Nullable:    base.Nullable,
+		Checks:      base.Checks,
+		ReadOnly:    base.ReadOnly,
+		WriteOnly:   base.WriteOnly,
+		Deprecated:  base.Deprecated,
+		Transform:   base.Transform,
+		Examples:    base.Examples,
+		Messages:    base.Messages,
+		Formats:     base.Formats,
+		Weight:      base.Weight,
+		Severity:    base.Severity,
+
+		ConditionDefs: base.ConditionDefs,
+		Asserts:       base.Asserts,
+		Default:       base.Default,
+		Overrides:     base.Overrides,
+		RefIntegrity:  base.RefIntegrity,
 	}
 
 	// Apply overrides
+	if override.Ref != "" {
+		merged.Ref = override.Ref
+	}
+	if override.AdditionalProperties != nil {
+		merged.AdditionalProperties = override.AdditionalProperties
+	}
+	if override.PropertyNames != nil {
+		merged.PropertyNames = override.PropertyNames
+	}
+	if override.ContentEncoding != "" {
+		merged.ContentEncoding = override.ContentEncoding
+	}
+	if override.ContentMediaType != "" {
+		merged.ContentMediaType = override.ContentMediaType
+	}
+	if override.ContentSchema != nil {
+		merged.ContentSchema = override.ContentSchema
+	}
 	if override.Min != nil {
 		merged.Min = override.Min
 	}
 	if override.Max != nil {
 		merged.Max = override.Max
 	}
+	if override.MinInt != "" {
+		merged.MinInt = override.MinInt
+	}
+	if override.MaxInt != "" {
+		merged.MaxInt = override.MaxInt
+	}
 	if override.MinLength != nil {
 		merged.MinLength = override.MinLength
 	}
 	if override.MaxLength != nil {
 		merged.MaxLength = override.MaxLength
 	}
+	if override.LengthUnit != "" {
+		merged.LengthUnit = override.LengthUnit
+	}
+	if override.MinBytes != nil {
+		merged.MinBytes = override.MinBytes
+	}
+	if override.MaxBytes != nil {
+		merged.MaxBytes = override.MaxBytes
+	}
 	if override.Pattern != nil {
 		merged.Pattern = override.Pattern
 	}
+	if override.Format != nil {
+		merged.Format = override.Format
+	}
+	if override.TimeFormat != "" {
+		merged.TimeFormat = override.TimeFormat
+	}
+	if override.SemverRange != "" {
+		merged.SemverRange = override.SemverRange
+	}
+	if override.URISchemes != nil {
+		merged.URISchemes = override.URISchemes
+	}
+	if override.PublicHost != nil {
+		merged.PublicHost = override.PublicHost
+	}
 	if override.Enum != nil {
 		merged.Enum = override.Enum
 	}
 	if override.AllowEmpty != nil {
 		merged.AllowEmpty = override.AllowEmpty
 	}
+	if override.Nullable != nil {
+		merged.Nullable = override.Nullable
+	}
+	if override.UniqueItems != nil {
+		merged.UniqueItems = override.UniqueItems
+	}
+	if override.Checks != nil {
+		merged.Checks = override.Checks
+	}
+	if override.ReadOnly != nil {
+		merged.ReadOnly = override.ReadOnly
+	}
+	if override.WriteOnly != nil {
+		merged.WriteOnly = override.WriteOnly
+	}
+	if override.Deprecated != nil {
+		merged.Deprecated = override.Deprecated
+	}
+	if override.Transform != nil {
+		merged.Transform = override.Transform
+	}
+	if override.Examples != nil {
+		merged.Examples = override.Examples
+	}
+	if override.Default != nil {
+		merged.Default = override.Default
+	}
+	if override.Messages != nil {
+		merged.Messages = override.Messages
+	}
+	if override.Formats != nil {
+		merged.Formats = override.Formats
+	}
+	if override.ConditionDefs != nil {
+		merged.ConditionDefs = override.ConditionDefs
+	}
+	if override.Asserts != nil {
+		merged.Asserts = override.Asserts
+	}
+	if override.Overrides != nil {
+		merged.Overrides = override.Overrides
+	}
+	if override.RefIntegrity != nil {
+		merged.RefIntegrity = override.RefIntegrity
+	}
+	if override.Weight != nil {
+		merged.Weight = override.Weight
+	}
+	if override.Severity != nil {
+		merged.Severity = override.Severity
+	}
 	if override.Type != "" {
 		merged.Type = override.Type
 	}
-	// Merge properties
+	// Merge properties into a new map so that base is left unchanged
 	if override.Properties != nil {
-		if merged.Properties == nil {
-			merged.Properties = make(map[string]*Spec)
-		}
+		merged.Properties = make(map[string]*Spec, len(base.Properties)+len(override.Properties))
 		for k, v := range base.Properties {
 			merged.Properties[k] = v
 		}
@@ -385,6 +1177,23 @@ func (r *ValidationResult) mergeSpecs(base, override *Spec) *Spec {
 	if override.Required != nil {
 		merged.Required = override.Required
 	}
+	if override.RequiredIf != nil {
+		merged.RequiredIf = override.RequiredIf
+	}
+	if override.AnyRequired != nil {
+		merged.AnyRequired = override.AnyRequired
+	}
+	if override.OneRequired != nil {
+		merged.OneRequired = override.OneRequired
+	}
+	if override.Discriminator != nil {
+		merged.Discriminator = override.Discriminator
+	}
+	if override.If != nil {
+		merged.If = override.If
+		merged.Then = override.Then
+		merged.Else = override.Else
+	}
 
 	return merged
 }
@@ -395,7 +1204,8 @@ func (r *ValidationResult) validateArray(path string, value any, spec *Spec) {
 		// Try to handle arrays of other types
 		val := reflect.ValueOf(value)
 		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
-			r.addError(path, fmt.Sprintf("expected array, got %T", value))
+			r.addError(path, CodeType, fmt.Sprintf("expected array, got %T", value),
+				map[string]any{"expected": "array", "actual": fmt.Sprintf("%T", value)})
 			return
 		}
 
@@ -407,23 +1217,44 @@ func (r *ValidationResult) validateArray(path string, value any, spec *Spec) {
 	}
 
 	if spec.MinLength != nil && len(arr) < *spec.MinLength {
-		r.addError(path, fmt.Sprintf("array length %d is less than minimum %d", len(arr), *spec.MinLength))
+		r.addError(path, CodeMinLength, fmt.Sprintf("array length %d is less than minimum %d", len(arr), *spec.MinLength),
+			map[string]any{"actual": len(arr), "limit": *spec.MinLength})
 	}
 
 	if spec.MaxLength != nil && len(arr) > *spec.MaxLength {
-		r.addError(path, fmt.Sprintf("array length %d is greater than maximum %d", len(arr), *spec.MaxLength))
+		r.addError(path, CodeMaxLength, fmt.Sprintf("array length %d is greater than maximum %d", len(arr), *spec.MaxLength),
+			map[string]any{"actual": len(arr), "limit": *spec.MaxLength})
+	}
+
+	if spec.UniqueItems != nil && *spec.UniqueItems {
+		r.validateUniqueItems(path, arr)
 	}
 
 	if spec.Items != nil {
 		for i, item := range arr {
+			r.pushSegment(strconv.Itoa(i))
 			r.validate(buildArrayPath(path, i), item, spec.Items)
+			r.popSegment()
+		}
+	}
+}
+
+// validateUniqueItems reports the first pair of equal items in arr
+func (r *ValidationResult) validateUniqueItems(path string, arr []any) {
+	for i := 1; i < len(arr); i++ {
+		for j := 0; j < i; j++ {
+			if valuesEqual(arr[i], arr[j]) {
+				r.addError(path, CodeUniqueItems, fmt.Sprintf("array items %d and %d are equal", j, i),
+					map[string]any{"first": j, "duplicate": i})
+				return
+			}
 		}
 	}
 }
 
 func (r *ValidationResult) validateEnum(path string, value any, enum []any) {
 	for _, allowed := range enum {
-		if reflect.DeepEqual(value, allowed) {
+		if valuesEqual(value, allowed) {
 			return
 		}
 	}
@@ -433,5 +1264,25 @@ func (r *ValidationResult) validateEnum(path string, value any, enum []any) {
 	for i, v := range enum {
 		enumStrs[i] = fmt.Sprintf("%v", v)
 	}
-	r.addError(path, fmt.Sprintf("value not in enum: %v (allowed: %v)", value, enumStrs))
+	message := fmt.Sprintf("value not in enum: %v (allowed: %v)", value, enumStrs)
+	params := map[string]any{"actual": value, "allowed": enumStrs}
+	if suggestion, ok := suggestEnum(value, enum); ok {
+		message += fmt.Sprintf("; did you mean %q?", suggestion)
+		params["suggestion"] = suggestion
+	}
+	r.addError(path, CodeEnum, message, params)
+}
+
+// excluded reports whether values of spec are rejected in the result's mode
+func (r *ValidationResult) excluded(spec *Spec) bool {
+	if spec == nil {
+		return false
+	}
+	switch r.mode {
+	case ModeRequest:
+		return spec.ReadOnly != nil && *spec.ReadOnly
+	case ModeResponse:
+		return spec.WriteOnly != nil && *spec.WriteOnly
+	}
+	return false
 }
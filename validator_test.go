@@ -2,7 +2,10 @@ package mowgli
 
 import (
 	"encoding/json"
+	"reflect"
+	"sort"
 	"testing"
+	"time"
 )
 
 func TestValidateString(t *testing.T) {
@@ -316,6 +319,42 @@ func TestValidateObject(t *testing.T) {
 			valueJSON: `{"age": 200}`,
 			shouldErr: true,
 		},
+		{
+			name:      "nullable property accepts null",
+			specJSON:  `{"type": "object", "properties": {"nickname": {"type": "string", "nullable": true}}}`,
+			valueJSON: `{"nickname": null}`,
+			shouldErr: false,
+		},
+		{
+			name:      "non-nullable property rejects null",
+			specJSON:  `{"type": "object", "properties": {"nickname": {"type": "string"}}}`,
+			valueJSON: `{"nickname": null}`,
+			shouldErr: true,
+		},
+		{
+			name:      "additional properties valid",
+			specJSON:  `{"type": "object", "properties": {"id": {"type": "string"}}, "additionalProperties": {"type": "integer", "min": 0}}`,
+			valueJSON: `{"id": "x", "a": 1, "b": 2}`,
+			shouldErr: false,
+		},
+		{
+			name:      "additional property value invalid",
+			specJSON:  `{"type": "object", "additionalProperties": {"type": "integer", "min": 0}}`,
+			valueJSON: `{"a": 1, "b": -2}`,
+			shouldErr: true,
+		},
+		{
+			name:      "declared property not checked against additional properties",
+			specJSON:  `{"type": "object", "properties": {"id": {"type": "string"}}, "additionalProperties": {"type": "integer"}}`,
+			valueJSON: `{"id": "x"}`,
+			shouldErr: false,
+		},
+		{
+			name:      "property name does not match",
+			specJSON:  `{"type": "object", "propertyNames": {"type": "string", "pattern": "^[a-z]+$"}}`,
+			valueJSON: `{"ok": 1, "Not-OK": 2}`,
+			shouldErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -379,6 +418,18 @@ func TestValidateArray(t *testing.T) {
 			valueJSON: `[1, 2, 3]`,
 			shouldErr: true,
 		},
+		{
+			name:      "array with unique items valid",
+			specJSON:  `{"type": "array", "uniqueItems": true}`,
+			valueJSON: `[1, "1", {"a": 1}, {"a": 2}]`,
+			shouldErr: false,
+		},
+		{
+			name:      "array with duplicate objects",
+			specJSON:  `{"type": "array", "uniqueItems": true}`,
+			valueJSON: `[{"a": 1}, {"a": 1}]`,
+			shouldErr: true,
+		},
 		{
 			name:      "array with item spec valid",
 			specJSON:  `{"type": "array", "items": {"type": "string"}}`,
@@ -909,3 +960,567 @@ func TestValidateConditionalComplex(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConditionalRootParent(t *testing.T) {
+	specJSON := `{
+		"type": "object",
+		"properties": {
+			"validationLevel": {"type": "string", "enum": ["basic", "strict"]},
+			"account": {
+				"type": "object",
+				"properties": {
+					"verified": {"type": "boolean"},
+					"profile": {
+						"type": "object",
+						"properties": {
+							"bio": {"type": "string"}
+						},
+						"conditions": [
+							{
+								"if": "$root.validationLevel == \"strict\"",
+								"then": {
+									"bio": {"minLength": 10}
+								}
+							},
+							{
+								"if": "$parent.verified == true",
+								"then": {
+									"bio": {"maxLength": 20}
+								}
+							}
+						]
+					}
+				}
+			}
+		}
+	}`
+
+	spec, err := ParseSpecString(specJSON)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		valueJSON string
+		shouldErr bool
+	}{
+		{
+			name:      "valid - basic level allows short bio",
+			valueJSON: `{"validationLevel": "basic", "account": {"verified": false, "profile": {"bio": "hi"}}}`,
+			shouldErr: false,
+		},
+		{
+			name:      "invalid - strict level at root requires longer bio",
+			valueJSON: `{"validationLevel": "strict", "account": {"verified": false, "profile": {"bio": "hi"}}}`,
+			shouldErr: true,
+		},
+		{
+			name:      "valid - strict level with long bio",
+			valueJSON: `{"validationLevel": "strict", "account": {"verified": false, "profile": {"bio": "a long enough biography"}}}`,
+			shouldErr: false,
+		},
+		{
+			name:      "invalid - verified parent caps bio length",
+			valueJSON: `{"validationLevel": "strict", "account": {"verified": true, "profile": {"bio": "a long enough biography"}}}`,
+			shouldErr: true,
+		},
+		{
+			name:      "valid - verified parent with bio in range",
+			valueJSON: `{"validationLevel": "strict", "account": {"verified": true, "profile": {"bio": "just long enough"}}}`,
+			shouldErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value any
+			if err := json.Unmarshal([]byte(tt.valueJSON), &value); err != nil {
+				t.Fatalf("Failed to parse value JSON: %v", err)
+			}
+
+			result := Validate(value, spec)
+			if result.Valid == tt.shouldErr {
+				if tt.shouldErr {
+					t.Errorf("Expected validation to fail, but it passed")
+				} else {
+					t.Errorf("Expected validation to pass, but it failed: %v", result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConditionalMatches(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"email": {"type": "string"},
+			"employeeId": {"type": "string"}
+		},
+		"conditions": [
+			{
+				"if": "matches(email, \"@internal\\\\.corp$\")",
+				"then": {"employeeId": {"minLength": 6}}
+			}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		value     map[string]any
+		shouldErr bool
+	}{
+		{
+			name:      "internal email requires employee id",
+			value:     map[string]any{"email": "ops@internal.corp", "employeeId": "123"},
+			shouldErr: true,
+		},
+		{
+			name:      "internal email with employee id",
+			value:     map[string]any{"email": "ops@internal.corp", "employeeId": "123456"},
+			shouldErr: false,
+		},
+		{
+			name:      "external email",
+			value:     map[string]any{"email": "someone@example.com", "employeeId": "1"},
+			shouldErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.value, spec)
+			if result.Valid == tt.shouldErr {
+				if tt.shouldErr {
+					t.Errorf("Expected validation to fail, but it passed")
+				} else {
+					t.Errorf("Expected validation to pass, but it failed: %v", result.Errors)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRequiredIf(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer"},
+			"country": {"type": "string"},
+			"verificationCode": {"type": "string", "messages": {"required": "Minors in the US need a verification code"}},
+			"guardian": {"type": "string"}
+		},
+		"requiredIf": {
+			"verificationCode": "age < 18 AND country == \"US\"",
+			"guardian": "age < $root.minAge"
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		value map[string]any
+		want  []string // paths of errors
+	}{
+		{
+			name:  "adult",
+			value: map[string]any{"age": 30, "country": "US", "minAge": 16},
+		},
+		{
+			name:  "minor outside the US",
+			value: map[string]any{"age": 17, "country": "FR", "minAge": 16},
+		},
+		{
+			name:  "minor in the US",
+			value: map[string]any{"age": 17, "country": "US", "minAge": 16},
+			want:  []string{"verificationCode"},
+		},
+		{
+			name:  "minor in the US with code",
+			value: map[string]any{"age": 17, "country": "US", "minAge": 16, "verificationCode": "1234"},
+		},
+		{
+			name:  "young minor in the US",
+			value: map[string]any{"age": 12, "country": "US", "minAge": 16},
+			want:  []string{"guardian", "verificationCode"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.value, spec)
+			var paths []string
+			for _, err := range result.Errors {
+				if err.Code != CodeRequired {
+					t.Errorf("expected a required error, got %v", err)
+				}
+				paths = append(paths, err.Path)
+			}
+			sort.Strings(paths)
+			if !reflect.DeepEqual(paths, tt.want) {
+				t.Errorf("expected errors at %v, got %v", tt.want, result.Errors)
+			}
+			if len(result.Errors) > 0 && result.Errors[len(result.Errors)-1].Message != "Minors in the US need a verification code" {
+				t.Errorf("expected custom message, got %v", result.Errors)
+			}
+		})
+	}
+
+	result := Validate(map[string]any{"age": 1}, &Spec{Type: "object", RequiredIf: map[string]string{"x": "age <"}})
+	if len(result.Errors) != 1 || result.Errors[0].Code != CodeCondition {
+		t.Errorf("expected a condition error, got %v", result.Errors)
+	}
+}
+
+func TestValidatorRegisterExprFunc(t *testing.T) {
+	v := NewValidator()
+	if err := v.RegisterExprFunc("inRegion", func(country string, region string) bool {
+		return region == "EU" && (country == "DE" || country == "FR")
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := v.RegisterExprFunc("isBusinessDay", func(date string) (bool, error) {
+		parsed, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return false, err
+		}
+		return parsed.Weekday() != time.Saturday && parsed.Weekday() != time.Sunday, nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"country": {"type": "string"},
+			"date": {"type": "string"},
+			"vatId": {"type": "string"},
+			"courier": {"type": "string"}
+		},
+		"conditions": [
+			{
+				"if": "inRegion(country, \"EU\")",
+				"then": {"vatId": {"minLength": 8}}
+			},
+			{
+				"if": "isBusinessDay(date)",
+				"else": {"courier": {"enum": ["weekend-express"]}}
+			}
+		]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		value     map[string]any
+		shouldErr bool
+	}{
+		{
+			name:      "EU country requires VAT id",
+			value:     map[string]any{"country": "DE", "date": "2024-01-03", "vatId": "123"},
+			shouldErr: true,
+		},
+		{
+			name:      "non-EU country",
+			value:     map[string]any{"country": "US", "date": "2024-01-03", "vatId": "123"},
+			shouldErr: false,
+		},
+		{
+			name:      "weekend restricts courier",
+			value:     map[string]any{"country": "US", "date": "2024-01-06", "courier": "standard"},
+			shouldErr: true,
+		},
+		{
+			name:      "function error is reported",
+			value:     map[string]any{"country": "US", "date": "not a date"},
+			shouldErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.Validate(tt.value, spec)
+			if result.Valid == tt.shouldErr {
+				if tt.shouldErr {
+					t.Errorf("Expected validation to fail, but it passed")
+				} else {
+					t.Errorf("Expected validation to pass, but it failed: %v", result.Errors)
+				}
+			}
+		})
+	}
+
+	// Functions are scoped to the validator they were registered on
+	if result := Validate(map[string]any{"country": "DE", "date": "2024-01-03"}, spec); result.Valid {
+		t.Error("expected unknown function to fail with the default validator")
+	}
+}
+
+func TestValidatorRegisterExprFuncErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		id   string
+	}{
+		{name: "not a func", id: "f", fn: 42},
+		{name: "nil", id: "f", fn: nil},
+		{name: "no return value", id: "f", fn: func() {}},
+		{name: "second return not error", id: "f", fn: func() (bool, bool) { return true, true }},
+		{name: "invalid name", id: "has space", fn: func() bool { return true }},
+	}
+
+	v := NewValidator()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.RegisterExprFunc(tt.id, tt.fn); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
+func TestValidatorExprLimits(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {"a": {"type": "string"}},
+		"conditions": [{"if": "now() != nil", "then": {"a": {"minLength": 1}}}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	if result := Validate(map[string]any{"a": "x"}, spec); !result.Valid {
+		t.Fatalf("expected validation to pass without limits, got %v", result.Errors)
+	}
+
+	v := NewValidator(WithExprLimits(ExprLimits{BannedFunctions: []string{"now"}}))
+	result := v.Validate(map[string]any{"a": "x"}, spec)
+	if result.Valid {
+		t.Fatal("expected banned function to fail validation")
+	}
+	if result.Errors[0].Code != CodeCondition {
+		t.Errorf("expected code %s, got %s", CodeCondition, result.Errors[0].Code)
+	}
+}
+
+func TestValidateCustomMessages(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"age": {"type": "integer", "min": 18, "messages": {"min": "You must be an adult"}},
+			"address": {
+				"type": "object",
+				"properties": {"zip": {"type": "string"}},
+				"messages": {"type": "Address must be an object"}
+			}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	result := Validate(map[string]any{"age": 12, "address": map[string]any{"zip": 12345}}, spec)
+	messages := make(map[string]string)
+	for _, err := range result.Errors {
+		messages[err.Path] = err.Message
+	}
+
+	if messages["age"] != "You must be an adult" {
+		t.Errorf("expected custom age message, got %q", messages["age"])
+	}
+	// Messages only apply to errors at the spec's own path
+	if messages["address.zip"] == "Address must be an object" {
+		t.Error("custom message leaked to a nested field")
+	}
+}
+
+func TestValidateModes(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "readOnly": true},
+			"password": {"type": "string", "writeOnly": true, "minLength": 8},
+			"name": {"type": "string"},
+			"tokens": {"type": "array", "items": {"type": "string", "writeOnly": true}}
+		},
+		"required": ["id", "password", "name"]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		mode       Mode
+		data       map[string]any
+		wantErrors []string // path:code
+	}{
+		{
+			name:       "any mode ignores the flags",
+			mode:       ModeAny,
+			data:       map[string]any{"name": "Ada"},
+			wantErrors: []string{"id:required", "password:required"},
+		},
+		{
+			name: "request without read-only field",
+			mode: ModeRequest,
+			data: map[string]any{"name": "Ada", "password": "secret-password"},
+		},
+		{
+			name:       "request setting read-only field",
+			mode:       ModeRequest,
+			data:       map[string]any{"id": "u1", "name": "Ada", "password": "secret-password"},
+			wantErrors: []string{"id:readOnly"},
+		},
+		{
+			name: "response without write-only fields",
+			mode: ModeResponse,
+			data: map[string]any{"id": "u1", "name": "Ada"},
+		},
+		{
+			name:       "response leaking write-only fields",
+			mode:       ModeResponse,
+			data:       map[string]any{"id": "u1", "name": "Ada", "password": "x", "tokens": []any{"t1"}},
+			wantErrors: []string{"password:writeOnly", "tokens[0]:writeOnly"},
+		},
+		{
+			name:       "null write-only field in response",
+			mode:       ModeResponse,
+			data:       map[string]any{"id": "u1", "name": "Ada", "password": nil},
+			wantErrors: []string{"password:writeOnly"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NewValidator(WithMode(tt.mode)).Validate(tt.data, spec)
+			var got []string
+			for _, err := range result.Errors {
+				got = append(got, err.Path+":"+err.Code)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.wantErrors) {
+				t.Errorf("expected errors %v, got %v", tt.wantErrors, got)
+			}
+		})
+	}
+}
+
+func TestValidateDeprecated(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"fax": {"type": "string", "deprecated": true},
+			"legacyId": {"type": "integer", "deprecated": true, "min": 1, "messages": {"deprecated": "use id instead"}},
+			"name": {"type": "string"}
+		}
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		data         map[string]any
+		wantValid    bool
+		wantWarnings []string
+	}{
+		{name: "absent", data: map[string]any{"name": "Ada"}, wantValid: true},
+		{name: "present", data: map[string]any{"fax": "555"}, wantValid: true, wantWarnings: []string{"fax: field is deprecated"}},
+		{name: "custom message", data: map[string]any{"legacyId": 7}, wantValid: true, wantWarnings: []string{"legacyId: use id instead"}},
+		{name: "still validated", data: map[string]any{"legacyId": 0}, wantWarnings: []string{"legacyId: use id instead"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Validate(tt.data, spec)
+			if result.Valid != tt.wantValid {
+				t.Errorf("expected valid=%v, got %v (%v)", tt.wantValid, result.Valid, result.Errors)
+			}
+			var warnings []string
+			for _, w := range result.Warnings {
+				warnings = append(warnings, w.Error())
+			}
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Errorf("expected warnings %v, got %v", tt.wantWarnings, warnings)
+			}
+		})
+	}
+}
+
+func TestValidateStringLengthUnits(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      string
+		opts      []Option
+		value     string
+		wantCodes []string
+	}{
+		{name: "runes by default", spec: `{"type": "string", "maxLength": 5}`, value: "héllo"},
+		{name: "cjk counted per character", spec: `{"type": "string", "minLength": 3, "maxLength": 3}`, value: "日本語"},
+		{name: "bytes", spec: `{"type": "string", "maxLength": 5, "lengthUnit": "bytes"}`, value: "héllo", wantCodes: []string{CodeMaxLength}},
+		{name: "validator default", spec: `{"type": "string", "maxLength": 5}`, opts: []Option{WithLengthUnit(LengthBytes)}, value: "héllo", wantCodes: []string{CodeMaxLength}},
+		{name: "spec overrides validator", spec: `{"type": "string", "maxLength": 5, "lengthUnit": "runes"}`, opts: []Option{WithLengthUnit(LengthBytes)}, value: "héllo"},
+		{name: "emoji runes", spec: `{"type": "string", "maxLength": 1}`, value: "👍🏽", wantCodes: []string{CodeMaxLength}},
+		{name: "emoji graphemes", spec: `{"type": "string", "maxLength": 1, "lengthUnit": "graphemes"}`, value: "👍🏽"},
+		{name: "combining mark graphemes", spec: `{"type": "string", "minLength": 2, "lengthUnit": "graphemes"}`, value: "é", wantCodes: []string{CodeMinLength}},
+		{name: "unknown unit", spec: `{"type": "string", "maxLength": 1, "lengthUnit": "words"}`, value: "a", wantCodes: []string{CodeInvalidSpec}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpecString(tt.spec)
+			if err != nil {
+				t.Fatalf("Failed to parse spec: %v", err)
+			}
+			result := NewValidator(tt.opts...).Validate(tt.value, spec)
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("expected codes %v, got %v (%v)", tt.wantCodes, codes, result.Errors)
+			}
+		})
+	}
+}
+
+func TestValidateStringBytes(t *testing.T) {
+	spec, err := ParseSpecString(`{"type": "string", "maxLength": 4, "minBytes": 2, "maxBytes": 6}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	tests := []struct {
+		value     string
+		wantCodes []string
+	}{
+		{value: "abcd"},
+		{value: "a", wantCodes: []string{CodeMinBytes}},
+		{value: "日本", wantCodes: nil},
+		{value: "日本語", wantCodes: []string{CodeMaxBytes}},
+		{value: "abcdé", wantCodes: []string{CodeMaxLength}},
+		{value: "日本語日本", wantCodes: []string{CodeMaxLength, CodeMaxBytes}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			result := Validate(tt.value, spec)
+			var codes []string
+			for _, err := range result.Errors {
+				codes = append(codes, err.Code)
+			}
+			if !reflect.DeepEqual(codes, tt.wantCodes) {
+				t.Errorf("expected codes %v, got %v", tt.wantCodes, codes)
+			}
+		})
+	}
+}
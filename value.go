@@ -0,0 +1,310 @@
+package mowgli
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ValidateValue validates a native Go value, such as an already-decoded
+// struct, against a spec
+func ValidateValue(v any, spec *Spec) (*ValidationResult, error) {
+	return defaultValidator.ValidateValue(v, spec)
+}
+
+// ValidateValue validates a native Go value against a spec. Structs are
+// walked with reflection, honoring json tags (field names, "-", omitempty and
+// embedded structs) the way encoding/json would see them, so the value need
+// not be marshaled first. Integers keep their full precision. Types
+// implementing json.Marshaler or encoding.TextMarshaler, such as time.Time,
+// are validated in their marshaled form.
+func (v *Validator) ValidateValue(value any, spec *Spec) (*ValidationResult, error) {
+	data, err := genericValue(reflect.ValueOf(value))
+	if err != nil {
+		return nil, fmt.Errorf("cannot validate %T: %w", value, err)
+	}
+	result := v.Validate(data, spec)
+	if err := result.LimitError(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// genericValue converts a Go value to the form produced by decoding JSON
+// into an any, except that integers are kept as int64, or uint64 beyond
+// math.MaxInt64. Cyclic values are rejected with an error.
+func genericValue(rv reflect.Value) (any, error) {
+	c := &valueConverter{visiting: make(map[visitKey]bool)}
+	return c.value(rv)
+}
+
+// valueConverter converts Go values with genericValue, tracking the
+// pointers, maps and slices being converted to detect cycles
+type valueConverter struct {
+	visiting map[visitKey]bool
+}
+
+// visitKey identifies a pointer, map or slice; slices sharing an array
+// differ by length
+type visitKey struct {
+	ptr    uintptr
+	length int
+	typ    reflect.Type
+}
+
+// enter marks a reference value as being converted, failing if it already
+// is, i.e. it contains itself. The returned func unmarks it.
+func (c *valueConverter) enter(rv reflect.Value) (func(), error) {
+	key := visitKey{ptr: rv.Pointer(), typ: rv.Type()}
+	if rv.Kind() == reflect.Slice {
+		key.length = rv.Len()
+	}
+	if c.visiting[key] {
+		return nil, fmt.Errorf("encountered a cycle via %s", rv.Type())
+	}
+	c.visiting[key] = true
+	return func() { delete(c.visiting, key) }, nil
+}
+
+func (c *valueConverter) value(rv reflect.Value) (any, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	// Marshalers decide their own representation
+	if rv.Kind() != reflect.Pointer && rv.Kind() != reflect.Interface {
+		if rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+			return marshaledValue(rv)
+		}
+		if rv.CanAddr() && (rv.Addr().Type().Implements(jsonMarshalerType) || rv.Addr().Type().Implements(textMarshalerType)) {
+			return marshaledValue(rv.Addr())
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		leave, err := c.enter(rv)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return c.value(rv.Elem())
+	case reflect.Interface:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return c.value(rv.Elem())
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if u := rv.Uint(); u <= math.MaxInt64 {
+			return int64(u), nil
+		}
+		return rv.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// encoding/json encodes []byte as a base64 string
+			return marshaledValue(rv)
+		}
+		leave, err := c.enter(rv)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return c.slice(rv)
+	case reflect.Array:
+		return c.slice(rv)
+	case reflect.Map:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		leave, err := c.enter(rv)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		return c.mapValue(rv)
+	case reflect.Struct:
+		obj := make(map[string]any, rv.NumField())
+		if err := c.addStructFields(obj, rv); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", rv.Type())
+	}
+}
+
+func (c *valueConverter) slice(rv reflect.Value) ([]any, error) {
+	arr := make([]any, rv.Len())
+	for i := range arr {
+		item, err := c.value(rv.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = item
+	}
+	return arr, nil
+}
+
+func (c *valueConverter) mapValue(rv reflect.Value) (map[string]any, error) {
+	obj := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := mapKeyString(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+		value, err := c.value(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = value
+	}
+	return obj, nil
+}
+
+// mapKeyString renders a map key the way encoding/json does
+func mapKeyString(key reflect.Value) (string, error) {
+	if key.Kind() == reflect.String {
+		return key.String(), nil
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", key.Type())
+}
+
+// addStructFields adds the fields of a struct to obj under their JSON names.
+// Fields of embedded structs are promoted unless the struct declares a field
+// with the same name.
+func (c *valueConverter) addStructFields(obj map[string]any, rv reflect.Value) error {
+	rt := rv.Type()
+	var embedded []reflect.Value
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(jsonTag, ",")
+
+		fv := rv.Field(i)
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if fv.Kind() == reflect.Pointer {
+					if fv.IsNil() {
+						continue
+					}
+					fv = fv.Elem()
+				}
+				embedded = append(embedded, fv)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if hasTagOption(opts, "omitempty") && isEmptyValue(fv) {
+			continue
+		}
+
+		value, err := c.value(fv)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		obj[name] = value
+	}
+
+	// Promote embedded fields without overriding the outer struct's own
+	for _, ev := range embedded {
+		inner := make(map[string]any)
+		if err := c.addStructFields(inner, ev); err != nil {
+			return err
+		}
+		for k, v := range inner {
+			if _, exists := obj[k]; !exists {
+				obj[k] = v
+			}
+		}
+	}
+	return nil
+}
+
+func hasTagOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether v is empty in the sense of json's omitempty
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// marshaledValue converts a value through its JSON encoding
+func marshaledValue(rv reflect.Value) (any, error) {
+	data, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,182 @@
+package mowgli
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type valueTestAudit struct {
+	CreatedBy string `json:"createdBy"`
+	Note      string `json:"note,omitempty"`
+}
+
+type valueTestStatus string
+
+type valueTestOrder struct {
+	valueTestAudit
+	ID       int64             `json:"id"`
+	Status   valueTestStatus   `json:"status"`
+	Quantity uint8             `json:"quantity"`
+	Price    float32           `json:"price"`
+	Coupon   *string           `json:"coupon"`
+	Items    []valueTestItem   `json:"items"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	PlacedAt time.Time         `json:"placedAt"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+type valueTestItem struct {
+	SKU string `json:"sku"`
+	Qty int    `json:"qty"`
+}
+
+func TestValidateValue(t *testing.T) {
+	spec, err := ParseSpecString(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "integer", "min": 1},
+			"status": {"type": "string", "enum": ["open", "closed"]},
+			"quantity": {"type": "integer", "max": 10},
+			"price": {"type": "number", "min": 0},
+			"coupon": {"type": "string", "nullable": true},
+			"createdBy": {"type": "string", "minLength": 1},
+			"items": {"type": "array", "minLength": 1, "items": {
+				"type": "object",
+				"properties": {"sku": {"type": "string"}, "qty": {"type": "integer", "min": 1, "enum": [1, 2, 3]}},
+				"required": ["sku", "qty"]
+			}},
+			"placedAt": {"type": "string", "format": "date-time"}
+		},
+		"required": ["id", "status", "createdBy", "placedAt"],
+		"conditions": [{"if": "status == \"closed\"", "required": ["labels"]}]
+	}`)
+	if err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	valid := valueTestOrder{
+		valueTestAudit: valueTestAudit{CreatedBy: "ann"},
+		ID:             42,
+		Status:         "open",
+		Quantity:       2,
+		Price:          9.5,
+		Items:          []valueTestItem{{SKU: "A-1", Qty: 2}},
+		PlacedAt:       time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*valueTestOrder)
+		wantPaths []string
+	}{
+		{name: "valid", modify: func(o *valueTestOrder) {}},
+		{name: "pointer to struct", modify: nil},
+		{name: "integer bounds", modify: func(o *valueTestOrder) { o.ID = 0; o.Quantity = 11 }, wantPaths: []string{"id", "quantity"}},
+		{name: "named string enum", modify: func(o *valueTestOrder) { o.Status = "lost" }, wantPaths: []string{"status"}},
+		{name: "embedded field", modify: func(o *valueTestOrder) { o.CreatedBy = "" }, wantPaths: []string{"createdBy"}},
+		{name: "nested items", modify: func(o *valueTestOrder) { o.Items = append(o.Items, valueTestItem{SKU: "B", Qty: 0}) }, wantPaths: []string{"items[1].qty", "items[1].qty"}},
+		{name: "omitempty drops field", modify: func(o *valueTestOrder) { o.Status = "closed" }, wantPaths: []string{"labels"}},
+		{name: "nil slice is null", modify: func(o *valueTestOrder) { o.Items = nil }, wantPaths: []string{"items"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := valid
+			var value any = order
+			if tt.modify != nil {
+				tt.modify(&order)
+				value = order
+			} else {
+				value = &order
+			}
+
+			result, err := ValidateValue(value, spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Errors) != len(tt.wantPaths) {
+				t.Fatalf("expected %d errors, got %v", len(tt.wantPaths), result.Errors)
+			}
+			got := make(map[string]int)
+			for _, err := range result.Errors {
+				got[err.Path]++
+			}
+			for _, path := range tt.wantPaths {
+				if got[path] == 0 {
+					t.Errorf("expected error at %s, got %v", path, result.Errors)
+				}
+				got[path]--
+			}
+		})
+	}
+}
+
+func TestGenericValue(t *testing.T) {
+	type withIgnored struct {
+		Kept    string `json:"kept"`
+		Ignored string `json:"-"`
+		hidden  string
+	}
+
+	value, err := genericValue(reflect.ValueOf(withIgnored{Kept: "a", Ignored: "b", hidden: "c"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj := value.(map[string]any)
+	if len(obj) != 1 || obj["kept"] != "a" {
+		t.Errorf("unexpected object: %v", obj)
+	}
+
+	big, err := genericValue(reflect.ValueOf(int64(1<<62 + 1)))
+	if err != nil || big != int64(1<<62+1) {
+		t.Errorf("expected integer precision to be kept, got %v", big)
+	}
+
+	if _, err := ValidateValue(map[string]any{"f": func() {}}, &Spec{Type: "object"}); err == nil {
+		t.Error("expected error for unsupported type")
+	}
+}
+
+func TestValidateValueUint64Bounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   uint64
+		maxInt  string
+		minInt  string
+		wantErr string
+	}{
+		{name: "max uint64 at bound", value: math.MaxUint64, maxInt: "18446744073709551615"},
+		{name: "max uint64 over bound", value: math.MaxUint64, maxInt: "18446744073709551614", wantErr: "integer 18446744073709551615 is greater than maximum 18446744073709551614"},
+		{name: "beyond float64 precision", value: 18446744073709550001, maxInt: "18446744073709550000", wantErr: "integer 18446744073709550001 is greater than maximum 18446744073709550000"},
+		{name: "above int64 under min", value: 1<<63 + 1, minInt: "9223372036854775810", wantErr: "integer 9223372036854775809 is less than minimum 9223372036854775810"},
+		{name: "above int64 at min", value: 1<<63 + 1, minInt: "9223372036854775809"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &Spec{Type: "integer", MaxInt: json.Number(tt.maxInt), MinInt: json.Number(tt.minInt)}
+			result, err := ValidateValue(tt.value, spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr == "" {
+				if !result.Valid {
+					t.Errorf("expected valid, got %v", result.Errors)
+				}
+				return
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Message != tt.wantErr {
+				t.Errorf("expected %q, got %v", tt.wantErr, result.Errors)
+			}
+		})
+	}
+
+	value, err := genericValue(reflect.ValueOf(uint64(math.MaxUint64)))
+	if err != nil || value != uint64(math.MaxUint64) {
+		t.Errorf("expected uint64 precision to be kept, got %v", value)
+	}
+}